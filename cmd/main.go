@@ -13,14 +13,20 @@ package main
 import (
 	"context"
 	"health-checker/internal/app"
+	"health-checker/internal/app/apikey"
 	"health-checker/internal/app/auth"
+	"health-checker/internal/app/incident"
+	"health-checker/internal/app/notification"
+	"health-checker/internal/app/org"
 	"health-checker/internal/database"
 	"health-checker/internal/logger"
+	"health-checker/internal/middleware"
 	"health-checker/internal/migrations"
 	"health-checker/internal/monitor"
 	"log"
 	"os"
 	"os/signal"
+	"strconv"
 	"syscall"
 	"time"
 
@@ -82,6 +88,9 @@ func main() {
 		}
 	}
 
+	// Access tokens revoked on logout stay denied until they expire
+	denylist := middleware.NewRedisDenylist(database.RdbInstance)
+
 	// Initialize event bus and hub
 	eventBus := monitor.NewInMemoryEventBus(log.Named("EventBus"))
 
@@ -103,19 +112,65 @@ func main() {
 
 	monitorRepo := monitor.NewRepository(dbPool)
 	monitorService := monitor.NewService(monitorRepo, log.Named("Monitoring service"))
-	monitorHandler := monitor.NewHandler(monitorService, hub, log.Named("MonitorHandler"))
+	monitorService.SetEventBus(eventBus)
+
+	orgRepo := org.NewRepository(dbPool)
+	orgService := org.NewService(orgRepo, log.Named("Organization service"))
+	orgHandler := org.NewHandler(orgService, denylist, log.Named("OrganizationHandler"))
+
+	monitorHandler := monitor.NewHandler(monitorService, hub, denylist, log.Named("MonitorHandler"))
+	monitorHandler.SetRoleResolver(orgService)
+
+	apiKeyRepo := apikey.NewRepository(dbPool)
+	apiKeyService := apikey.NewService(apiKeyRepo, orgService, log.Named("API key service"))
+	apiKeyHandler := apikey.NewHandler(apiKeyService, denylist, log.Named("APIKeyHandler"))
+	monitorHandler.SetAPIKeyVerifier(apiKeyService)
+
+	notificationRepo := notification.NewRepository(dbPool)
+	notificationService := notification.NewService(notificationRepo, log.Named("Notification service"))
+	notificationHandler := notification.NewHandler(notificationService, orgService, denylist, log.Named("NotificationHandler"))
+	notifier := notification.NewNotifier(notificationRepo, log.Named("Notifier"))
+	notifier.SetPublicURL(os.Getenv("PUBLIC_URL"))
+	if smtpHost := os.Getenv("SMTP_HOST"); smtpHost != "" {
+		smtpPort := os.Getenv("SMTP_PORT")
+		if smtpPort == "" {
+			smtpPort = "587"
+		}
+		notifier.RegisterSender(notification.ChannelTypeEmail, notification.NewEmailSender(notification.SMTPConfig{
+			Host:     smtpHost,
+			Port:     smtpPort,
+			Username: os.Getenv("SMTP_USERNAME"),
+			Password: os.Getenv("SMTP_PASSWORD"),
+			From:     os.Getenv("SMTP_FROM"),
+		}))
+	}
+	eventBus.Subscribe("StatusChange", notifier.HandleStatusChange)
+	go notifier.RunEscalations(ctx)
+
+	incidentRepo := incident.NewRepository(dbPool)
+	incidentService := incident.NewService(incidentRepo, log.Named("Incident service"))
+	incidentService.SetEscalations(notificationService)
+	incidentHandler := incident.NewHandler(incidentService, orgService, denylist, log.Named("IncidentHandler"))
+	eventBus.Subscribe("StatusChange", incidentService.HandleStatusChange)
 
 	userRepo := auth.NewRepository(dbPool)
-	userService := auth.NewService(userRepo, log.Named("User service"))
-	authHandler := auth.NewHandler(userService, log.Named("AuthHandler"))
+	userService := auth.NewService(userRepo, denylist, log.Named("User service"))
+	authHandler := auth.NewHandler(userService, denylist, log.Named("AuthHandler"))
 
 	srv := app.NewServer(log)
 
 	v1 := srv.Group("/api/v1")
 	authHandler.RegisterRoutes(v1.Group("/auth"))
+	orgHandler.RegisterRoutes(v1.Group("/organizations"))
+	apiKeyHandler.RegisterRoutes(v1.Group("/api-keys"))
+	notificationHandler.RegisterRoutes(v1.Group("/notification-channels"))
+	notificationHandler.RegisterPolicyRoutes(v1.Group("/notification-policies"))
+	notificationHandler.RegisterEscalationRoutes(v1.Group("/escalations"))
+	incidentHandler.RegisterRoutes(v1.Group("/incidents"))
 
 	servicesGroup := v1.Group("/services")
 	monitorHandler.RegisterRoutes(servicesGroup)
+	monitorHandler.RegisterHeartbeatRoutes(v1.Group("/heartbeats"))
 
 	scheduler := monitor.NewScheduler(database.RdbInstance, monitorRepo, 1, log.Named("Scheduler"))
 	go scheduler.Start(ctx)
@@ -123,6 +178,20 @@ func main() {
 	worker := monitor.NewWorker(database.RdbInstance, monitorRepo, log.Named("Worker"), eventBus)
 	go worker.Run(ctx)
 
+	// Raw health checks are rolled up hourly and daily, and deleted after
+	// HEALTH_CHECK_RETENTION_DAYS, 30 by default; 0 keeps them forever
+	retentionDays := 30
+	if value := os.Getenv("HEALTH_CHECK_RETENTION_DAYS"); value != "" {
+		retentionDays, err = strconv.Atoi(value)
+		if err != nil || retentionDays < 0 {
+			log.Fatal("Invalid HEALTH_CHECK_RETENTION_DAYS", zap.String("value", value))
+		}
+	}
+	rawRetention := time.Duration(retentionDays) * 24 * time.Hour
+	monitorService.SetRawRetention(rawRetention)
+	retention := monitor.NewRetention(monitorRepo, rawRetention, log.Named("Retention"))
+	go retention.Run(ctx)
+
 	port := os.Getenv("PORT")
 	if port == "" {
 		port = ":8080"
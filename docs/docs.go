@@ -15,9 +15,49 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/auth/login": {
+        "/api-keys": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List the caller's API keys, including revoked and expired ones",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "api-keys"
+                ],
+                "summary": "List API keys",
+                "responses": {
+                    "200": {
+                        "description": "API keys",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/apikey.APIKey"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
             "post": {
-                "description": "Authenticate a user and return a JWT token",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Create an API key for machine clients. Send it as \"Authorization: Bearer hc_...\" to the service routes. The key is only returned by this call.",
                 "consumes": [
                     "application/json"
                 ],
@@ -25,29 +65,81 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "auth"
+                    "api-keys"
                 ],
-                "summary": "Login a user",
+                "summary": "Create an API key",
                 "parameters": [
                     {
-                        "description": "User login data",
-                        "name": "user",
+                        "description": "Key settings",
+                        "name": "key",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/auth.LoginUserDTO"
+                            "$ref": "#/definitions/apikey.CreateAPIKeyDTO"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "Access token",
+                    "201": {
+                        "description": "Created key",
+                        "schema": {
+                            "$ref": "#/definitions/apikey.CreatedAPIKey"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
                                 "type": "string"
                             }
                         }
+                    }
+                }
+            }
+        },
+        "/api-keys/{keyId}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Revoke one of the caller's API keys; requests using it are rejected from then on",
+                "tags": [
+                    "api-keys"
+                ],
+                "summary": "Revoke an API key",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "API key ID",
+                        "name": "keyId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "API key revoked"
                     },
                     "400": {
                         "description": "Bad request",
@@ -58,6 +150,15 @@ const docTemplate = `{
                             }
                         }
                     },
+                    "404": {
+                        "description": "API key not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
@@ -70,9 +171,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/auth/register": {
+        "/auth/login": {
             "post": {
-                "description": "Register a new user with username and password",
+                "description": "Authenticate a user and return a short-lived JWT access token with a refresh token",
                 "consumes": [
                     "application/json"
                 ],
@@ -82,26 +183,23 @@ const docTemplate = `{
                 "tags": [
                     "auth"
                 ],
-                "summary": "Register a new user",
+                "summary": "Login a user",
                 "parameters": [
                     {
-                        "description": "User registration data",
+                        "description": "User login data",
                         "name": "user",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/auth.RegisterUserDTO"
+                            "$ref": "#/definitions/auth.LoginUserDTO"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "User registered successfully",
+                    "200": {
+                        "description": "Access and refresh tokens",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/auth.TokenPair"
                         }
                     },
                     "400": {
@@ -125,28 +223,50 @@ const docTemplate = `{
                 }
             }
         },
-        "/services": {
-            "get": {
+        "/auth/logout": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Retrieve a list of all services registered for health monitoring",
-                "produces": [
+                "description": "Revoke the access token used for this request and, when given, the refresh token",
+                "consumes": [
                     "application/json"
                 ],
                 "tags": [
-                    "services"
+                    "auth"
+                ],
+                "summary": "Logout",
+                "parameters": [
+                    {
+                        "description": "Refresh token to revoke",
+                        "name": "token",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/auth.LogoutDTO"
+                        }
+                    }
                 ],
-                "summary": "List all registered services",
                 "responses": {
-                    "200": {
-                        "description": "List of registered services",
+                    "204": {
+                        "description": "Logged out"
+                    },
+                    "400": {
+                        "description": "Bad request",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/monitor.Service"
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
                             }
                         }
                     },
@@ -160,14 +280,11 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
+            }
+        },
+        "/auth/refresh": {
             "post": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "Register a new service for health monitoring",
+                "description": "Exchange a refresh token for a new access token and a new refresh token. Each refresh token works once; reusing one revokes every token issued from the same login.",
                 "consumes": [
                     "application/json"
                 ],
@@ -175,23 +292,29 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "services"
+                    "auth"
                 ],
-                "summary": "Register a new service",
+                "summary": "Refresh an access token",
                 "parameters": [
                     {
-                        "description": "Service data",
-                        "name": "service",
+                        "description": "Refresh token",
+                        "name": "token",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/monitor.RegisterServiceDTO"
+                            "$ref": "#/definitions/auth.RefreshTokenDTO"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Service registered successfully",
+                        "description": "Access and refresh tokens",
+                        "schema": {
+                            "$ref": "#/definitions/auth.TokenPair"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -199,8 +322,8 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "400": {
-                        "description": "Bad request",
+                    "401": {
+                        "description": "Invalid refresh token",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -220,23 +343,38 @@ const docTemplate = `{
                 }
             }
         },
-        "/services/ws": {
-            "get": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
+        "/auth/register": {
+            "post": {
+                "description": "Register a new user with username and password",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
                 ],
-                "description": "Establish a WebSocket connection to receive real-time status updates",
                 "tags": [
-                    "services"
+                    "auth"
+                ],
+                "summary": "Register a new user",
+                "parameters": [
+                    {
+                        "description": "User registration data",
+                        "name": "user",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/auth.RegisterUserDTO"
+                        }
+                    }
                 ],
-                "summary": "Handle WebSocket connections for real-time updates",
                 "responses": {
-                    "101": {
-                        "description": "Switching Protocols",
+                    "201": {
+                        "description": "User registered successfully",
                         "schema": {
-                            "type": "string"
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     },
                     "400": {
@@ -247,60 +385,54 @@ const docTemplate = `{
                                 "type": "string"
                             }
                         }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
                     }
                 }
             }
         },
-        "/services/{serviceId}/health-checks": {
+        "/escalations": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Retrieve health check records for a specific service by its ID",
+                "description": "List the DOWN alerts of escalating policies whose service has not recovered yet, oldest first",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "services"
+                    "notifications"
                 ],
-                "summary": "Get health checks for a service",
+                "summary": "List open escalations",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Service ID",
-                        "name": "serviceId",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "default": 1,
-                        "description": "Page number",
-                        "name": "page",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "default": 10,
-                        "description": "Number of records per page",
-                        "name": "limit",
-                        "in": "query"
+                        "description": "Organization to act in",
+                        "name": "X-Organization-ID",
+                        "in": "header"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "List of health checks",
+                        "description": "Escalations",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/monitor.HealthCheck"
+                                "$ref": "#/definitions/notification.Escalation"
                             }
                         }
                     },
-                    "400": {
-                        "description": "Bad request",
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -319,107 +451,3964 @@ const docTemplate = `{
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "auth.LoginUserDTO": {
-            "type": "object",
-            "required": [
-                "password",
-                "username"
-            ],
-            "properties": {
-                "password": {
-                    "type": "string",
-                    "example": "securepassword"
-                },
-                "username": {
-                    "type": "string",
-                    "example": "johndoe"
-                }
-            }
         },
-        "auth.RegisterUserDTO": {
-            "type": "object",
+        "/escalations/{escalationId}/acknowledge": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Acknowledge a DOWN alert so it is not escalated to the second tier",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "notifications"
+                ],
+                "summary": "Acknowledge an alert",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Escalation ID",
+                        "name": "escalationId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Organization to act in",
+                        "name": "X-Organization-ID",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Acknowledged escalation",
+                        "schema": {
+                            "$ref": "#/definitions/notification.Escalation"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Escalation not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/heartbeats/{token}": {
+            "post": {
+                "description": "Record a heartbeat for the service owning the token. A heartbeat service goes DOWN when no ping arrives within its check interval plus grace period.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "heartbeats"
+                ],
+                "summary": "Ping a heartbeat service",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Heartbeat token",
+                        "name": "token",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Heartbeat received",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Unknown heartbeat token",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/incidents": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List the incidents of your services, or an organization's, latest first. An incident opens when a service goes DOWN and is resolved when it recovers.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "incidents"
+                ],
+                "summary": "List incidents",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Only incidents of this service",
+                        "name": "service_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "open or resolved",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Started at or after, RFC 3339",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Started before, RFC 3339",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Incidents per page",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Organization to act in",
+                        "name": "X-Organization-ID",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Incidents",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/incident.Incident"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/incidents/{incidentId}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get an incident together with its notes",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "incidents"
+                ],
+                "summary": "Get an incident",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Incident ID",
+                        "name": "incidentId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Organization to act in",
+                        "name": "X-Organization-ID",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Incident",
+                        "schema": {
+                            "$ref": "#/definitions/incident.Incident"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Incident not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/incidents/{incidentId}/acknowledge": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Record that you are handling an incident. The DOWN alerts of its service are acknowledged too, so they are not escalated.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "incidents"
+                ],
+                "summary": "Acknowledge an incident",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Incident ID",
+                        "name": "incidentId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Organization to act in",
+                        "name": "X-Organization-ID",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Acknowledged incident",
+                        "schema": {
+                            "$ref": "#/definitions/incident.Incident"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Incident not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/incidents/{incidentId}/notes": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Leave a note on an incident, such as what was found or done",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "incidents"
+                ],
+                "summary": "Add a note to an incident",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Incident ID",
+                        "name": "incidentId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Note",
+                        "name": "note",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/incident.CreateNoteDTO"
+                        }
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Organization to act in",
+                        "name": "X-Organization-ID",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created note",
+                        "schema": {
+                            "$ref": "#/definitions/incident.Note"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Incident not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/incidents/{incidentId}/resolve": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Resolve an incident before its service recovers. Requires the editor role. Resolving a resolved incident changes nothing.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "incidents"
+                ],
+                "summary": "Resolve an incident",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Incident ID",
+                        "name": "incidentId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Organization to act in",
+                        "name": "X-Organization-ID",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Resolved incident",
+                        "schema": {
+                            "$ref": "#/definitions/incident.Incident"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Incident not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/notification-channels": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List your notification channels, or an organization's",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "notifications"
+                ],
+                "summary": "List notification channels",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Organization to act in",
+                        "name": "X-Organization-ID",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Channels",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/notification.Channel"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Create a channel notified about status changes of your services, or of an organization's services; service_ids limits it to some of them. Webhooks receive a signed JSON POST, and their signing secret is only returned by this call. Email channels are sent to their recipients over SMTP, and slack, discord, teams and telegram channels post a formatted chat message. Incoming webhook URLs and Telegram bot tokens are stored encrypted and never returned; chat channels show a url_hint instead.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "notifications"
+                ],
+                "summary": "Create a notification channel",
+                "parameters": [
+                    {
+                        "description": "Channel settings",
+                        "name": "channel",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/notification.CreateChannelDTO"
+                        }
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Organization to act in",
+                        "name": "X-Organization-ID",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created channel",
+                        "schema": {
+                            "$ref": "#/definitions/notification.CreatedChannel"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/notification-channels/{channelId}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Delete a notification channel together with its delivery log",
+                "tags": [
+                    "notifications"
+                ],
+                "summary": "Delete a notification channel",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Channel ID",
+                        "name": "channelId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Organization to act in",
+                        "name": "X-Organization-ID",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Channel deleted"
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Channel not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/notification-channels/{channelId}/deliveries": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List the latest delivery attempts of a channel, newest first, including failed ones and their error",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "notifications"
+                ],
+                "summary": "List delivery attempts",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Channel ID",
+                        "name": "channelId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 50,
+                        "description": "Number of attempts",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Organization to act in",
+                        "name": "X-Organization-ID",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Delivery attempts",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/notification.Delivery"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Channel not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/notification-policies": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List your notification policies, or an organization's",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "notifications"
+                ],
+                "summary": "List notification policies",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Organization to act in",
+                        "name": "X-Organization-ID",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Policies",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/notification.Policy"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Route the status changes of services with some tags, of some severities (critical: DOWN and recovering from it, warning: DEGRADED, info: the rest) or inside a schedule to some channels. Once a policy exists, changes only go to the channels of the policies they match. With escalation, a DOWN alert not acknowledged within escalate_after_minutes is sent to the escalation channels too.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "notifications"
+                ],
+                "summary": "Create a notification policy",
+                "parameters": [
+                    {
+                        "description": "Policy settings",
+                        "name": "policy",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/notification.CreatePolicyDTO"
+                        }
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Organization to act in",
+                        "name": "X-Organization-ID",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created policy",
+                        "schema": {
+                            "$ref": "#/definitions/notification.Policy"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/notification-policies/{policyId}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Delete a notification policy and its escalations",
+                "tags": [
+                    "notifications"
+                ],
+                "summary": "Delete a notification policy",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Policy ID",
+                        "name": "policyId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Organization to act in",
+                        "name": "X-Organization-ID",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Policy deleted"
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Policy not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/organizations": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List the organizations the caller belongs to, with the caller's role in each",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "organizations"
+                ],
+                "summary": "List organizations",
+                "responses": {
+                    "200": {
+                        "description": "Organizations",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/org.Organization"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Create an organization owned by the caller",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "organizations"
+                ],
+                "summary": "Create an organization",
+                "parameters": [
+                    {
+                        "description": "Organization data",
+                        "name": "organization",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/org.CreateOrganizationDTO"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created organization",
+                        "schema": {
+                            "$ref": "#/definitions/org.Organization"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/organizations/{orgId}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Delete an organization together with its services. Requires the owner role.",
+                "tags": [
+                    "organizations"
+                ],
+                "summary": "Delete an organization",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Organization ID",
+                        "name": "orgId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Organization deleted"
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/organizations/{orgId}/members": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List the members of an organization and their roles. Requires the viewer role.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "organizations"
+                ],
+                "summary": "List members",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Organization ID",
+                        "name": "orgId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Members",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/org.Member"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Add an existing user to an organization. Requires the admin role; only owners can add owners.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "organizations"
+                ],
+                "summary": "Add a member",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Organization ID",
+                        "name": "orgId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Member data",
+                        "name": "member",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/org.AddMemberDTO"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Added member",
+                        "schema": {
+                            "$ref": "#/definitions/org.Member"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Already a member",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/organizations/{orgId}/members/{userId}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Remove a member from an organization. Requires the admin role; only owners can remove owners.",
+                "tags": [
+                    "organizations"
+                ],
+                "summary": "Remove a member",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Organization ID",
+                        "name": "orgId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "userId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Member removed"
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Member not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Last owner",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Change a member's role. Requires the admin role; only owners can grant or revoke the owner role.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "organizations"
+                ],
+                "summary": "Change a member's role",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Organization ID",
+                        "name": "orgId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "userId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "New role",
+                        "name": "member",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/org.UpdateMemberDTO"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Updated member",
+                        "schema": {
+                            "$ref": "#/definitions/org.Member"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Member not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Last owner",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/services": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieve a list of all services registered for health monitoring",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "services"
+                ],
+                "summary": "List all registered services",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Organization to act in",
+                        "name": "X-Organization-ID",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "List of registered services",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/monitor.Service"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Register a new service for health monitoring",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "services"
+                ],
+                "summary": "Register a new service",
+                "parameters": [
+                    {
+                        "description": "Service data",
+                        "name": "service",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/monitor.RegisterServiceDTO"
+                        }
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Organization to act in",
+                        "name": "X-Organization-ID",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Registered service",
+                        "schema": {
+                            "$ref": "#/definitions/monitor.Service"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/services/ws": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Establish a WebSocket connection to receive real-time status updates of your services, or of an organization's services when org_id is given",
+                "tags": [
+                    "services"
+                ],
+                "summary": "Handle WebSocket connections for real-time updates",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Organization to watch",
+                        "name": "org_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "101": {
+                        "description": "Switching Protocols",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/services/{serviceId}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieve a single registered service by its ID",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "services"
+                ],
+                "summary": "Get a service",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Service ID",
+                        "name": "serviceId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Organization to act in",
+                        "name": "X-Organization-ID",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Service",
+                        "schema": {
+                            "$ref": "#/definitions/monitor.Service"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Service not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Stop monitoring a service and delete its health check history",
+                "tags": [
+                    "services"
+                ],
+                "summary": "Delete a service",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Service ID",
+                        "name": "serviceId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Organization to act in",
+                        "name": "X-Organization-ID",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Service deleted"
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Service not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Change the fields present in the request body; the check type cannot be changed. Changing check_interval or grace_period reschedules the next check, or a heartbeat's deadline from its last ping",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "services"
+                ],
+                "summary": "Update a service",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Service ID",
+                        "name": "serviceId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Fields to change",
+                        "name": "service",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/monitor.UpdateServiceDTO"
+                        }
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Organization to act in",
+                        "name": "X-Organization-ID",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Updated service",
+                        "schema": {
+                            "$ref": "#/definitions/monitor.Service"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Service not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/services/{serviceId}/health-checks": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieve health check records for a specific service by its ID",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "services"
+                ],
+                "summary": "Get health checks for a service",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Service ID",
+                        "name": "serviceId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Number of records per page",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Organization to act in",
+                        "name": "X-Organization-ID",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "List of health checks",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/monitor.HealthCheck"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Service not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/services/{serviceId}/latency": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Compute the min, max, mean, p50, p90, p95 and p99 latency of a service over the last 24h, 7d or 30d or between from and to, overall and in time buckets for charting",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "services"
+                ],
+                "summary": "Get the latency percentiles of a service",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Service ID",
+                        "name": "serviceId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "default": "24h",
+                        "description": "24h, 7d or 30d, ending now",
+                        "name": "window",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Start of a custom window, RFC 3339",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "End of a custom window, RFC 3339; defaults to now",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "1m, 5m, 15m, 1h, 6h or 1d; defaults to the smallest giving at most 300 buckets",
+                        "name": "bucket",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Organization to act in",
+                        "name": "X-Organization-ID",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Latency report",
+                        "schema": {
+                            "$ref": "#/definitions/monitor.LatencyReport"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request, or a bucket under 1h reaching past the retention period",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Service not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/services/{serviceId}/pause": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Stop scheduling checks for a service until it is resumed",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "services"
+                ],
+                "summary": "Pause a service",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Service ID",
+                        "name": "serviceId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Organization to act in",
+                        "name": "X-Organization-ID",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Paused service",
+                        "schema": {
+                            "$ref": "#/definitions/monitor.Service"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Service not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/services/{serviceId}/resume": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Resume checks for a paused service; it is checked on the next scheduler tick",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "services"
+                ],
+                "summary": "Resume a service",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Service ID",
+                        "name": "serviceId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Organization to act in",
+                        "name": "X-Organization-ID",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Resumed service",
+                        "schema": {
+                            "$ref": "#/definitions/monitor.Service"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Service not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/services/{serviceId}/uptime": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Compute the uptime percentage of a service from its health checks, and its incidents, MTTR and MTBF from its incident log, over the last 24h, 7d or 30d or between from and to",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "services"
+                ],
+                "summary": "Get the uptime of a service",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Service ID",
+                        "name": "serviceId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "default": "24h",
+                        "description": "24h, 7d or 30d, ending now",
+                        "name": "window",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Start of a custom window, RFC 3339",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "End of a custom window, RFC 3339; defaults to now",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Organization to act in",
+                        "name": "X-Organization-ID",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Uptime report",
+                        "schema": {
+                            "$ref": "#/definitions/monitor.UptimeReport"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Service not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "apikey.APIKey": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "last_used_at": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "organization_id": {
+                    "type": "integer"
+                },
+                "prefix": {
+                    "type": "string",
+                    "example": "hc_Zm9vYmFy"
+                },
+                "revoked_at": {
+                    "type": "string"
+                },
+                "scopes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "apikey.CreateAPIKeyDTO": {
+            "type": "object",
+            "required": [
+                "name",
+                "scopes"
+            ],
+            "properties": {
+                "expires_at": {
+                    "type": "string",
+                    "example": "2027-01-01T00:00:00Z"
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 255,
+                    "example": "CI deploys"
+                },
+                "organization_id": {
+                    "description": "OrganizationID makes an organization key, which only acts in that organization.",
+                    "type": "integer",
+                    "minimum": 1,
+                    "example": 1
+                },
+                "scopes": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "services:read",
+                        "services:write"
+                    ]
+                }
+            }
+        },
+        "apikey.CreatedAPIKey": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "key": {
+                    "type": "string",
+                    "example": "hc_Zm9vYmFyYmF6cXV4cXV1eGNvcmdlZ3JhdWx0Z2FycGx5"
+                },
+                "last_used_at": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "organization_id": {
+                    "type": "integer"
+                },
+                "prefix": {
+                    "type": "string",
+                    "example": "hc_Zm9vYmFy"
+                },
+                "revoked_at": {
+                    "type": "string"
+                },
+                "scopes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "auth.LoginUserDTO": {
+            "type": "object",
+            "required": [
+                "password",
+                "username"
+            ],
+            "properties": {
+                "password": {
+                    "type": "string",
+                    "example": "securepassword"
+                },
+                "username": {
+                    "type": "string",
+                    "example": "johndoe"
+                }
+            }
+        },
+        "auth.LogoutDTO": {
+            "type": "object",
+            "properties": {
+                "refresh_token": {
+                    "description": "RefreshToken, when given, is revoked together with the tokens rotated from it.",
+                    "type": "string"
+                }
+            }
+        },
+        "auth.RefreshTokenDTO": {
+            "type": "object",
+            "required": [
+                "refresh_token"
+            ],
+            "properties": {
+                "refresh_token": {
+                    "type": "string"
+                }
+            }
+        },
+        "auth.RegisterUserDTO": {
+            "type": "object",
+            "required": [
+                "password",
+                "username"
+            ],
+            "properties": {
+                "password": {
+                    "type": "string",
+                    "minLength": 6,
+                    "example": "securepassword"
+                },
+                "username": {
+                    "type": "string",
+                    "example": "johndoe"
+                }
+            }
+        },
+        "auth.TokenPair": {
+            "type": "object",
+            "properties": {
+                "access_token": {
+                    "type": "string"
+                },
+                "expires_in": {
+                    "description": "ExpiresIn is the access token lifetime in seconds.",
+                    "type": "integer",
+                    "example": 900
+                },
+                "refresh_token": {
+                    "type": "string"
+                },
+                "token_type": {
+                    "type": "string",
+                    "example": "Bearer"
+                }
+            }
+        },
+        "incident.CreateNoteDTO": {
+            "type": "object",
+            "required": [
+                "body"
+            ],
+            "properties": {
+                "body": {
+                    "type": "string",
+                    "maxLength": 10000,
+                    "example": "Rolled back the 14:02 deploy"
+                }
+            }
+        },
+        "incident.Incident": {
+            "type": "object",
+            "properties": {
+                "acknowledged_at": {
+                    "type": "string"
+                },
+                "acknowledged_by": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "duration_seconds": {
+                    "description": "DurationSeconds runs until now while the incident is open.",
+                    "type": "integer",
+                    "example": 754
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "notes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/incident.Note"
+                    }
+                },
+                "resolved_at": {
+                    "type": "string"
+                },
+                "resolved_by": {
+                    "description": "ResolvedBy is set when a user resolved the incident before the service recovered.",
+                    "type": "integer"
+                },
+                "root_error": {
+                    "description": "RootError is what the check that took the service DOWN reported.",
+                    "type": "string",
+                    "example": "connection refused"
+                },
+                "service_id": {
+                    "type": "integer"
+                },
+                "service_name": {
+                    "type": "string"
+                },
+                "started_at": {
+                    "description": "StartedAt is the time of the first failed check.",
+                    "type": "string"
+                }
+            }
+        },
+        "incident.Note": {
+            "type": "object",
+            "properties": {
+                "body": {
+                    "type": "string",
+                    "example": "Rolled back the 14:02 deploy"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "incident_id": {
+                    "type": "integer"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "monitor.CheckConfig": {
+            "type": "object",
+            "properties": {
+                "dns": {
+                    "$ref": "#/definitions/monitor.DNSCheckConfig"
+                },
+                "grpc": {
+                    "$ref": "#/definitions/monitor.GRPCCheckConfig"
+                },
+                "http": {
+                    "$ref": "#/definitions/monitor.HTTPCheckConfig"
+                },
+                "tcp": {
+                    "$ref": "#/definitions/monitor.TCPCheckConfig"
+                },
+                "tls": {
+                    "$ref": "#/definitions/monitor.TLSCheckConfig"
+                }
+            }
+        },
+        "monitor.DNSCheckConfig": {
+            "type": "object",
+            "properties": {
+                "expected": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "93.184.216.34"
+                    ]
+                },
+                "match": {
+                    "type": "string",
+                    "enum": [
+                        "contains",
+                        "exact"
+                    ],
+                    "example": "contains"
+                },
+                "max_response_ms": {
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 500
+                },
+                "min_count": {
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 1
+                },
+                "record_type": {
+                    "type": "string",
+                    "enum": [
+                        "A",
+                        "AAAA",
+                        "CNAME",
+                        "MX",
+                        "TXT"
+                    ],
+                    "example": "A"
+                },
+                "resolver": {
+                    "type": "string",
+                    "example": "1.1.1.1:53"
+                }
+            }
+        },
+        "monitor.ErrorClass": {
+            "type": "string",
+            "enum": [
+                "dns",
+                "connection_refused",
+                "connection",
+                "timeout",
+                "tls",
+                "http_status",
+                "assertion_failed",
+                "unhealthy",
+                "slow_response",
+                "missed_heartbeat",
+                "error"
+            ],
+            "x-enum-varnames": [
+                "ErrorClassDNS",
+                "ErrorClassConnectionRefused",
+                "ErrorClassConnection",
+                "ErrorClassTimeout",
+                "ErrorClassTLS",
+                "ErrorClassHTTPStatus",
+                "ErrorClassAssertion",
+                "ErrorClassUnhealthy",
+                "ErrorClassSlowResponse",
+                "ErrorClassMissedHeartbeat",
+                "ErrorClassOther"
+            ]
+        },
+        "monitor.GRPCCheckConfig": {
+            "type": "object",
+            "properties": {
+                "server_name": {
+                    "type": "string",
+                    "example": "orders.internal"
+                },
+                "service": {
+                    "description": "Service is the name passed to grpc.health.v1.Health/Check; empty asks about the server as a whole.",
+                    "type": "string",
+                    "example": "orders.v1.OrderService"
+                },
+                "skip_verify": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "tls": {
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "monitor.HTTPAssertions": {
+            "type": "object",
+            "properties": {
+                "body_contains": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "healthy"
+                    ]
+                },
+                "body_regex": {
+                    "type": "string",
+                    "example": "version: [0-9]+"
+                },
+                "headers": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/monitor.HeaderAssertion"
+                    }
+                },
+                "json_path": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/monitor.JSONPathAssertion"
+                    }
+                },
+                "max_body_bytes": {
+                    "type": "integer",
+                    "minimum": 1,
+                    "example": 65536
+                },
+                "status_codes": {
+                    "description": "StatusCodes accepts exact codes, classes and ranges such as \"200\", \"2xx\" or \"200-299\". Defaults to 2xx.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "2xx"
+                    ]
+                }
+            }
+        },
+        "monitor.HTTPAuth": {
+            "type": "object",
+            "required": [
+                "type"
+            ],
+            "properties": {
+                "password": {
+                    "type": "string"
+                },
+                "token": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string",
+                    "enum": [
+                        "basic",
+                        "bearer"
+                    ],
+                    "example": "bearer"
+                },
+                "username": {
+                    "type": "string",
+                    "example": "monitor"
+                }
+            }
+        },
+        "monitor.HTTPCheckConfig": {
+            "type": "object",
+            "properties": {
+                "assertions": {
+                    "$ref": "#/definitions/monitor.HTTPAssertions"
+                },
+                "body": {
+                    "type": "string",
+                    "example": "{\"ping\":true}"
+                },
+                "headers": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "method": {
+                    "type": "string",
+                    "enum": [
+                        "GET",
+                        "HEAD",
+                        "POST",
+                        "PUT",
+                        "PATCH",
+                        "DELETE",
+                        "OPTIONS"
+                    ],
+                    "example": "GET"
+                }
+            }
+        },
+        "monitor.HeaderAssertion": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "matches": {
+                    "type": "string",
+                    "example": "^application/json"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Content-Type"
+                }
+            }
+        },
+        "monitor.HealthCheck": {
+            "type": "object",
+            "properties": {
+                "body_snippet": {
+                    "type": "string",
+                    "example": "upstream connect error"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "details": {
+                    "type": "object",
+                    "additionalProperties": true
+                },
+                "error_class": {
+                    "description": "ErrorClass, Message and BodySnippet explain a check that was not UP.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/monitor.ErrorClass"
+                        }
+                    ],
+                    "example": "http_status"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "latency": {
+                    "type": "integer"
+                },
+                "message": {
+                    "type": "string",
+                    "example": "unexpected status 503 Service Unavailable, expected 2xx"
+                },
+                "service_id": {
+                    "type": "integer"
+                },
+                "status": {
+                    "$ref": "#/definitions/monitor.Status"
+                },
+                "status_code": {
+                    "description": "StatusCode is the HTTP status of the response, if there was one.",
+                    "type": "integer",
+                    "example": 503
+                },
+                "timing": {
+                    "description": "Timing is only recorded by HTTP checks.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/monitor.Timing"
+                        }
+                    ]
+                }
+            }
+        },
+        "monitor.JSONPathAssertion": {
+            "type": "object",
+            "required": [
+                "path"
+            ],
+            "properties": {
+                "equals": {
+                    "description": "Equals compares the value at Path with any JSON value."
+                },
+                "exists": {
+                    "description": "Exists defaults to true; set it to false to assert the path is absent.",
+                    "type": "boolean"
+                },
+                "path": {
+                    "type": "string",
+                    "example": "$.status"
+                }
+            }
+        },
+        "monitor.LatencyBucket": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer",
+                    "example": 288
+                },
+                "max_ms": {
+                    "type": "integer",
+                    "example": 2310
+                },
+                "mean_ms": {
+                    "type": "number",
+                    "example": 161.4
+                },
+                "min_ms": {
+                    "type": "integer",
+                    "example": 84
+                },
+                "p50_ms": {
+                    "type": "integer",
+                    "example": 120
+                },
+                "p90_ms": {
+                    "type": "integer",
+                    "example": 240
+                },
+                "p95_ms": {
+                    "type": "integer",
+                    "example": 410
+                },
+                "p99_ms": {
+                    "type": "integer",
+                    "example": 1900
+                },
+                "start": {
+                    "type": "string"
+                }
+            }
+        },
+        "monitor.LatencyReport": {
+            "type": "object",
+            "properties": {
+                "bucket_seconds": {
+                    "type": "integer",
+                    "example": 300
+                },
+                "buckets": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/monitor.LatencyBucket"
+                    }
+                },
+                "from": {
+                    "type": "string"
+                },
+                "overall": {
+                    "$ref": "#/definitions/monitor.LatencyStats"
+                },
+                "service_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "to": {
+                    "type": "string"
+                }
+            }
+        },
+        "monitor.LatencyStats": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer",
+                    "example": 288
+                },
+                "max_ms": {
+                    "type": "integer",
+                    "example": 2310
+                },
+                "mean_ms": {
+                    "type": "number",
+                    "example": 161.4
+                },
+                "min_ms": {
+                    "type": "integer",
+                    "example": 84
+                },
+                "p50_ms": {
+                    "type": "integer",
+                    "example": 120
+                },
+                "p90_ms": {
+                    "type": "integer",
+                    "example": 240
+                },
+                "p95_ms": {
+                    "type": "integer",
+                    "example": 410
+                },
+                "p99_ms": {
+                    "type": "integer",
+                    "example": 1900
+                }
+            }
+        },
+        "monitor.RegisterServiceDTO": {
+            "type": "object",
+            "required": [
+                "check_interval",
+                "name"
+            ],
+            "properties": {
+                "auth": {
+                    "$ref": "#/definitions/monitor.HTTPAuth"
+                },
+                "check_interval": {
+                    "type": "integer",
+                    "minimum": 1,
+                    "example": 60
+                },
+                "config": {
+                    "$ref": "#/definitions/monitor.CheckConfig"
+                },
+                "failure_threshold": {
+                    "description": "FailureThreshold is how many consecutive checks must fail before the service goes DOWN. Defaults to 1.",
+                    "type": "integer",
+                    "maximum": 10,
+                    "minimum": 1,
+                    "example": 3
+                },
+                "grace_period": {
+                    "description": "GracePeriod is how many seconds a heartbeat may be late before the service goes DOWN.",
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 300
+                },
+                "host": {
+                    "type": "string",
+                    "example": "db.internal"
+                },
+                "latency_critical_ms": {
+                    "description": "LatencyCriticalMs is the response time in milliseconds over which the service is DOWN. 0 disables it.",
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 8000
+                },
+                "latency_warn_ms": {
+                    "description": "LatencyWarnMs is the response time in milliseconds over which the service is DEGRADED. 0 disables it.",
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 2000
+                },
+                "name": {
+                    "type": "string",
+                    "example": "My Service"
+                },
+                "port": {
+                    "type": "integer",
+                    "maximum": 65535,
+                    "minimum": 1,
+                    "example": 5432
+                },
+                "recheck": {
+                    "description": "Recheck confirms a change of status with an immediate re-check by another worker.",
+                    "type": "boolean",
+                    "example": true
+                },
+                "success_threshold": {
+                    "description": "SuccessThreshold is how many consecutive checks must succeed before the service is UP again. Defaults to 1.",
+                    "type": "integer",
+                    "maximum": 10,
+                    "minimum": 1,
+                    "example": 2
+                },
+                "tags": {
+                    "description": "Tags label the service for notification policies.",
+                    "type": "array",
+                    "maxItems": 20,
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "payments",
+                        "prod"
+                    ]
+                },
+                "timeout": {
+                    "type": "integer",
+                    "maximum": 60,
+                    "minimum": 1,
+                    "example": 5
+                },
+                "type": {
+                    "type": "string",
+                    "enum": [
+                        "http",
+                        "tcp",
+                        "dns",
+                        "tls",
+                        "grpc",
+                        "heartbeat"
+                    ],
+                    "example": "http"
+                },
+                "url": {
+                    "type": "string",
+                    "example": "https://example.com"
+                }
+            }
+        },
+        "monitor.Service": {
+            "type": "object",
+            "properties": {
+                "check_interval": {
+                    "type": "integer"
+                },
+                "config": {
+                    "$ref": "#/definitions/monitor.CheckConfig"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "failure_threshold": {
+                    "description": "FailureThreshold and SuccessThreshold are how many consecutive checks must\nfail before the service goes DOWN, and succeed before it is UP again.",
+                    "type": "integer"
+                },
+                "grace_period": {
+                    "type": "integer"
+                },
+                "heartbeat_token": {
+                    "description": "HeartbeatToken identifies a heartbeat service in its ping URL.",
+                    "type": "string"
+                },
+                "host": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "last_ping_at": {
+                    "type": "string"
+                },
+                "latency_critical_ms": {
+                    "type": "integer"
+                },
+                "latency_warn_ms": {
+                    "description": "LatencyWarnMs and LatencyCriticalMs are the response times over which a\nresponding service is DEGRADED and DOWN; 0 disables them.",
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "next_run_at": {
+                    "type": "string"
+                },
+                "organization_id": {
+                    "type": "integer"
+                },
+                "paused": {
+                    "type": "boolean"
+                },
+                "port": {
+                    "type": "integer"
+                },
+                "recheck": {
+                    "description": "Recheck runs a check again right away while a change of status awaits\nconfirmation, instead of waiting for the next interval.",
+                    "type": "boolean"
+                },
+                "status": {
+                    "description": "Status is the confirmed status of the service; it is empty until the\nfirst check.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/monitor.Status"
+                        }
+                    ]
+                },
+                "success_threshold": {
+                    "type": "integer"
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "timeout": {
+                    "type": "integer"
+                },
+                "type": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "monitor.Status": {
+            "type": "string",
+            "enum": [
+                "UP",
+                "DEGRADED",
+                "DOWN"
+            ],
+            "x-enum-varnames": [
+                "StatusUp",
+                "StatusDegraded",
+                "StatusDown"
+            ]
+        },
+        "monitor.TCPCheckConfig": {
+            "type": "object",
+            "properties": {
+                "expect": {
+                    "type": "string",
+                    "example": "+PONG"
+                },
+                "send": {
+                    "type": "string",
+                    "example": "PING\r\n"
+                }
+            }
+        },
+        "monitor.TLSCheckConfig": {
+            "type": "object",
+            "properties": {
+                "critical_days": {
+                    "type": "integer",
+                    "minimum": 1,
+                    "example": 7
+                },
+                "server_name": {
+                    "type": "string",
+                    "example": "example.com"
+                },
+                "warn_days": {
+                    "type": "integer",
+                    "minimum": 1,
+                    "example": 14
+                }
+            }
+        },
+        "monitor.Timing": {
+            "type": "object",
+            "properties": {
+                "connect_ms": {
+                    "type": "integer",
+                    "example": 30
+                },
+                "dns_ms": {
+                    "type": "integer",
+                    "example": 12
+                },
+                "tls_ms": {
+                    "type": "integer",
+                    "example": 45
+                },
+                "transfer_ms": {
+                    "type": "integer",
+                    "example": 4
+                },
+                "ttfb_ms": {
+                    "description": "TTFBMs is from the request being sent to the first byte of the response,\ni.e. how long the application took to answer.",
+                    "type": "integer",
+                    "example": 850
+                }
+            }
+        },
+        "monitor.UpdateServiceDTO": {
+            "type": "object",
+            "properties": {
+                "auth": {
+                    "$ref": "#/definitions/monitor.HTTPAuth"
+                },
+                "check_interval": {
+                    "type": "integer",
+                    "minimum": 1,
+                    "example": 60
+                },
+                "config": {
+                    "$ref": "#/definitions/monitor.CheckConfig"
+                },
+                "failure_threshold": {
+                    "type": "integer",
+                    "maximum": 10,
+                    "minimum": 1,
+                    "example": 3
+                },
+                "grace_period": {
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 300
+                },
+                "host": {
+                    "type": "string",
+                    "example": "db.internal"
+                },
+                "latency_critical_ms": {
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 8000
+                },
+                "latency_warn_ms": {
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 2000
+                },
+                "name": {
+                    "type": "string",
+                    "minLength": 1,
+                    "example": "My Service"
+                },
+                "port": {
+                    "type": "integer",
+                    "maximum": 65535,
+                    "minimum": 1,
+                    "example": 5432
+                },
+                "recheck": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "success_threshold": {
+                    "type": "integer",
+                    "maximum": 10,
+                    "minimum": 1,
+                    "example": 2
+                },
+                "tags": {
+                    "type": "array",
+                    "maxItems": 20,
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "timeout": {
+                    "type": "integer",
+                    "maximum": 60,
+                    "minimum": 1,
+                    "example": 5
+                },
+                "url": {
+                    "type": "string",
+                    "example": "https://example.com"
+                }
+            }
+        },
+        "monitor.UptimeReport": {
+            "type": "object",
+            "properties": {
+                "degraded_seconds": {
+                    "type": "integer",
+                    "example": 1200
+                },
+                "downtime_seconds": {
+                    "type": "integer",
+                    "example": 302
+                },
+                "from": {
+                    "type": "string"
+                },
+                "incidents": {
+                    "description": "Incidents is how many incidents were open during the window, counting\none already under way when it started.",
+                    "type": "integer",
+                    "example": 2
+                },
+                "monitored_seconds": {
+                    "description": "MonitoredSeconds is the part of the window covered by checks; it is\nshorter than the window when the service was created during it.",
+                    "type": "integer",
+                    "example": 604800
+                },
+                "mtbf_seconds": {
+                    "description": "MTBFSeconds is the mean time between failures: the time the service was\nup divided by the number of incidents. It is null without incidents.",
+                    "type": "integer",
+                    "example": 302249
+                },
+                "mttr_seconds": {
+                    "description": "MTTRSeconds is the mean time to resolve the incidents that were resolved\nin the window, from start to resolution. It is null without any.",
+                    "type": "integer",
+                    "example": 151
+                },
+                "service_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "to": {
+                    "type": "string"
+                },
+                "uptime_percent": {
+                    "description": "UptimePercent is the share of the monitored time the service was not\nDOWN; DEGRADED counts as up. It is null when no check covers the window.",
+                    "type": "number",
+                    "example": 99.95
+                }
+            }
+        },
+        "notification.Channel": {
+            "type": "object",
+            "properties": {
+                "config": {
+                    "$ref": "#/definitions/notification.ChannelConfig"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "organization_id": {
+                    "type": "integer"
+                },
+                "service_ids": {
+                    "description": "ServiceIDs limits the channel to these services; empty means all of them.",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "type": {
+                    "type": "string",
+                    "example": "webhook"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "notification.ChannelConfig": {
+            "type": "object",
+            "properties": {
+                "discord": {
+                    "$ref": "#/definitions/notification.IncomingWebhookConfig"
+                },
+                "email": {
+                    "$ref": "#/definitions/notification.EmailConfig"
+                },
+                "slack": {
+                    "$ref": "#/definitions/notification.IncomingWebhookConfig"
+                },
+                "teams": {
+                    "$ref": "#/definitions/notification.IncomingWebhookConfig"
+                },
+                "telegram": {
+                    "$ref": "#/definitions/notification.TelegramConfig"
+                },
+                "webhook": {
+                    "$ref": "#/definitions/notification.WebhookConfig"
+                }
+            }
+        },
+        "notification.CreateChannelDTO": {
+            "type": "object",
             "required": [
-                "password",
-                "username"
+                "name",
+                "type"
             ],
             "properties": {
-                "password": {
+                "config": {
+                    "$ref": "#/definitions/notification.ChannelConfig"
+                },
+                "name": {
                     "type": "string",
-                    "minLength": 6,
-                    "example": "securepassword"
+                    "maxLength": 255,
+                    "example": "Ops webhook"
                 },
-                "username": {
+                "service_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "type": {
                     "type": "string",
-                    "example": "johndoe"
+                    "enum": [
+                        "webhook",
+                        "email",
+                        "slack",
+                        "discord",
+                        "teams",
+                        "telegram"
+                    ],
+                    "example": "webhook"
                 }
             }
         },
-        "monitor.HealthCheck": {
+        "notification.CreatePolicyDTO": {
+            "type": "object",
+            "required": [
+                "channel_ids",
+                "name"
+            ],
+            "properties": {
+                "channel_ids": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "integer"
+                    },
+                    "example": [
+                        1
+                    ]
+                },
+                "escalate_after_minutes": {
+                    "type": "integer",
+                    "maximum": 1440,
+                    "minimum": 1,
+                    "example": 15
+                },
+                "escalation_channel_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    },
+                    "example": [
+                        2
+                    ]
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 255,
+                    "example": "Payments business hours"
+                },
+                "schedule": {
+                    "$ref": "#/definitions/notification.Schedule"
+                },
+                "severities": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "critical"
+                    ]
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "payments"
+                    ]
+                }
+            }
+        },
+        "notification.CreatedChannel": {
             "type": "object",
             "properties": {
+                "config": {
+                    "$ref": "#/definitions/notification.ChannelConfig"
+                },
                 "created_at": {
                     "type": "string"
                 },
                 "id": {
                     "type": "integer"
                 },
-                "latency": {
+                "name": {
+                    "type": "string"
+                },
+                "organization_id": {
+                    "type": "integer"
+                },
+                "secret": {
+                    "type": "string",
+                    "example": "whsec_3f1c9a0e5b7d42c8a6e1f0b9d3c7a5e2b4d6f8a0c2e4f6a8"
+                },
+                "service_ids": {
+                    "description": "ServiceIDs limits the channel to these services; empty means all of them.",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "type": {
+                    "type": "string",
+                    "example": "webhook"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "notification.Delivery": {
+            "type": "object",
+            "properties": {
+                "attempt": {
+                    "type": "integer"
+                },
+                "channel_id": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "duration_ms": {
+                    "type": "integer"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "id": {
                     "type": "integer"
                 },
+                "payload": {
+                    "$ref": "#/definitions/notification.Notification"
+                },
                 "service_id": {
                     "type": "integer"
                 },
-                "status": {
+                "status_code": {
+                    "type": "integer"
+                },
+                "success": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "notification.EmailConfig": {
+            "type": "object",
+            "required": [
+                "recipients"
+            ],
+            "properties": {
+                "recipients": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "oncall@example.com"
+                    ]
+                }
+            }
+        },
+        "notification.Escalation": {
+            "type": "object",
+            "properties": {
+                "acknowledged_at": {
+                    "type": "string"
+                },
+                "acknowledged_by": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "due_at": {
+                    "type": "string"
+                },
+                "escalated_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "notification": {
+                    "$ref": "#/definitions/notification.Notification"
+                },
+                "policy_id": {
+                    "type": "integer"
+                },
+                "resolved_at": {
                     "type": "string"
+                },
+                "service_id": {
+                    "type": "integer"
                 }
             }
         },
-        "monitor.RegisterServiceDTO": {
+        "notification.IncomingWebhookConfig": {
             "type": "object",
             "required": [
-                "check_interval",
-                "name",
                 "url"
             ],
             "properties": {
-                "check_interval": {
+                "url": {
+                    "type": "string",
+                    "example": "https://hooks.slack.com/services/T000/B000/XXXX"
+                },
+                "url_hint": {
+                    "type": "string",
+                    "example": "hooks.slack.com/…XXXX"
+                }
+            }
+        },
+        "notification.Notification": {
+            "type": "object",
+            "properties": {
+                "event": {
+                    "type": "string",
+                    "example": "status_change"
+                },
+                "latency_ms": {
                     "type": "integer",
-                    "minimum": 1,
-                    "example": 60
+                    "example": 1203
                 },
-                "name": {
+                "new_status": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/monitor.Status"
+                        }
+                    ],
+                    "example": "DOWN"
+                },
+                "old_status": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/monitor.Status"
+                        }
+                    ],
+                    "example": "UP"
+                },
+                "service_id": {
+                    "type": "integer"
+                },
+                "service_name": {
+                    "type": "string"
+                },
+                "severity": {
                     "type": "string",
-                    "example": "My Service"
+                    "example": "critical"
+                },
+                "timestamp": {
+                    "type": "string"
                 },
                 "url": {
+                    "description": "URL links back to the service; it is empty unless a public URL is set.",
                     "type": "string",
-                    "example": "https://example.com"
+                    "example": "https://health.example.com/api/v1/services/1"
                 }
             }
         },
-        "monitor.Service": {
+        "notification.Policy": {
             "type": "object",
             "properties": {
-                "check_interval": {
-                    "type": "integer"
+                "channel_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
                 },
                 "created_at": {
                     "type": "string"
                 },
+                "escalate_after_minutes": {
+                    "type": "integer"
+                },
+                "escalation_channel_ids": {
+                    "description": "EscalationChannelIDs are notified when a DOWN alert routed by the policy\nis not acknowledged within EscalateAfterMinutes.",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
                 "id": {
                     "type": "integer"
                 },
                 "name": {
                     "type": "string"
                 },
-                "next_run_at": {
-                    "type": "string"
+                "organization_id": {
+                    "type": "integer"
+                },
+                "schedule": {
+                    "$ref": "#/definitions/notification.Schedule"
+                },
+                "severities": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "critical"
+                    ]
+                },
+                "tags": {
+                    "description": "Tags match services carrying any of them.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "payments"
+                    ]
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "notification.Schedule": {
+            "type": "object",
+            "properties": {
+                "days": {
+                    "description": "Days are the weekdays the window opens on, 0 being Sunday; empty means every day.",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    },
+                    "example": [
+                        1,
+                        2,
+                        3,
+                        4,
+                        5
+                    ]
+                },
+                "end": {
+                    "type": "string",
+                    "example": "18:00"
                 },
+                "start": {
+                    "type": "string",
+                    "example": "09:00"
+                },
+                "timezone": {
+                    "description": "Timezone is an IANA zone name; it defaults to UTC.",
+                    "type": "string",
+                    "example": "Europe/Berlin"
+                }
+            }
+        },
+        "notification.TelegramConfig": {
+            "type": "object",
+            "required": [
+                "bot_token",
+                "chat_id"
+            ],
+            "properties": {
+                "bot_token": {
+                    "type": "string",
+                    "example": "123456:ABC-DEF1234ghIkl-zyx57W2v1u123ew11"
+                },
+                "chat_id": {
+                    "type": "string",
+                    "example": "-1001234567890"
+                }
+            }
+        },
+        "notification.WebhookConfig": {
+            "type": "object",
+            "required": [
+                "url"
+            ],
+            "properties": {
                 "url": {
+                    "type": "string",
+                    "example": "https://example.com/hooks/health"
+                }
+            }
+        },
+        "org.AddMemberDTO": {
+            "type": "object",
+            "required": [
+                "role",
+                "username"
+            ],
+            "properties": {
+                "role": {
+                    "type": "string",
+                    "enum": [
+                        "owner",
+                        "admin",
+                        "editor",
+                        "viewer"
+                    ],
+                    "example": "viewer"
+                },
+                "username": {
+                    "type": "string",
+                    "example": "johndoe"
+                }
+            }
+        },
+        "org.CreateOrganizationDTO": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "name": {
+                    "type": "string",
+                    "maxLength": 255,
+                    "example": "Platform team"
+                }
+            }
+        },
+        "org.Member": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "role": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "integer"
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
+        "org.Organization": {
+            "type": "object",
+            "properties": {
+                "created_at": {
                     "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "role": {
+                    "description": "Role is the caller's role, filled in when listing their organizations.",
+                    "type": "string"
+                }
+            }
+        },
+        "org.UpdateMemberDTO": {
+            "type": "object",
+            "required": [
+                "role"
+            ],
+            "properties": {
+                "role": {
+                    "type": "string",
+                    "enum": [
+                        "owner",
+                        "admin",
+                        "editor",
+                        "viewer"
+                    ],
+                    "example": "editor"
                 }
             }
         }
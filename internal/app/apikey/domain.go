@@ -0,0 +1,43 @@
+package apikey
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	ErrNotFound      = errors.New("API key not found")
+	ErrForbidden     = errors.New("organization keys can only be created by organization admins")
+	ErrExpiryInPast  = errors.New("expires_at must be in the future")
+	ErrInvalidScopes = errors.New("scopes must be services:read or services:write")
+)
+
+type APIKey struct {
+	ID             int        `json:"id" db:"id"`
+	UserID         int        `json:"user_id" db:"user_id"`
+	OrganizationID int        `json:"organization_id,omitempty" db:"organization_id"`
+	Name           string     `json:"name" db:"name"`
+	Prefix         string     `json:"prefix" db:"prefix" example:"hc_Zm9vYmFy"`
+	Scopes         []string   `json:"scopes" db:"scopes"`
+	ExpiresAt      *time.Time `json:"expires_at,omitempty" db:"expires_at"`
+	LastUsedAt     *time.Time `json:"last_used_at,omitempty" db:"last_used_at"`
+	RevokedAt      *time.Time `json:"revoked_at,omitempty" db:"revoked_at"`
+	CreatedAt      time.Time  `json:"created_at" db:"created_at"`
+
+	// Hash is the SHA-256 of the key; the key itself is never stored.
+	Hash string `json:"-" db:"key_hash"`
+}
+
+// CreatedAPIKey is returned once, on creation; it is the only time the key is shown.
+type CreatedAPIKey struct {
+	APIKey
+	Key string `json:"key" example:"hc_Zm9vYmFyYmF6cXV4cXV1eGNvcmdlZ3JhdWx0Z2FycGx5"`
+}
+
+type CreateAPIKeyDTO struct {
+	Name   string   `json:"name" binding:"required,max=255" example:"CI deploys"`
+	Scopes []string `json:"scopes" binding:"required,min=1" example:"services:read,services:write"`
+	// OrganizationID makes an organization key, which only acts in that organization.
+	OrganizationID int        `json:"organization_id,omitempty" binding:"omitempty,min=1" example:"1"`
+	ExpiresAt      *time.Time `json:"expires_at,omitempty" example:"2027-01-01T00:00:00Z"`
+}
@@ -0,0 +1,151 @@
+package apikey
+
+import (
+	"errors"
+	"health-checker/internal/middleware"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type APIKeyHandler struct {
+	service  *APIKeyService
+	denylist middleware.Denylist
+	logger   *zap.Logger
+}
+
+func NewHandler(service *APIKeyService, denylist middleware.Denylist, logger *zap.Logger) *APIKeyHandler {
+	return &APIKeyHandler{
+		service:  service,
+		denylist: denylist,
+		logger:   logger,
+	}
+}
+
+// RegisterRoutes registers the key management routes. They only accept JWTs,
+// so a leaked key cannot mint further keys.
+func (h *APIKeyHandler) RegisterRoutes(rg *gin.RouterGroup) {
+	authorized := rg.Group("", middleware.AuthMiddleware(h.denylist, nil))
+	authorized.POST("", h.CreateAPIKey)
+	authorized.GET("", h.ListAPIKeys)
+	authorized.DELETE("/:keyId", h.RevokeAPIKey)
+}
+
+// CreateAPIKey godoc
+//
+//	@Security		BearerAuth
+//	@Summary		Create an API key
+//	@Description	Create an API key for machine clients. Send it as "Authorization: Bearer hc_..." to the service routes. The key is only returned by this call.
+//	@Tags			api-keys
+//	@Accept			json
+//	@Produce		json
+//	@Param			key	body		CreateAPIKeyDTO		true	"Key settings"
+//	@Success		201	{object}	CreatedAPIKey		"Created key"
+//	@Failure		400	{object}	map[string]string	"Bad request"
+//	@Failure		403	{object}	map[string]string	"Forbidden"
+//	@Failure		500	{object}	map[string]string	"Internal server error"
+//	@Router			/api-keys [post]
+func (h *APIKeyHandler) CreateAPIKey(ctx *gin.Context) {
+	userID, ok := h.userID(ctx)
+	if !ok {
+		return
+	}
+
+	var body CreateAPIKeyDTO
+	if err := ctx.ShouldBindJSON(&body); err != nil {
+		h.logger.Error("failed to bind json", zap.Error(err))
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	key, err := h.service.Create(ctx.Request.Context(), userID, body)
+	if err != nil {
+		h.respondError(ctx, "failed to create API key", err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, key)
+}
+
+// ListAPIKeys godoc
+//
+//	@Security		BearerAuth
+//	@Summary		List API keys
+//	@Description	List the caller's API keys, including revoked and expired ones
+//	@Tags			api-keys
+//	@Produce		json
+//	@Success		200	{array}		APIKey				"API keys"
+//	@Failure		500	{object}	map[string]string	"Internal server error"
+//	@Router			/api-keys [get]
+func (h *APIKeyHandler) ListAPIKeys(ctx *gin.Context) {
+	userID, ok := h.userID(ctx)
+	if !ok {
+		return
+	}
+
+	keys, err := h.service.List(ctx.Request.Context(), userID)
+	if err != nil {
+		h.respondError(ctx, "failed to list API keys", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, keys)
+}
+
+// RevokeAPIKey godoc
+//
+//	@Security		BearerAuth
+//	@Summary		Revoke an API key
+//	@Description	Revoke one of the caller's API keys; requests using it are rejected from then on
+//	@Tags			api-keys
+//	@Param			keyId	path	int	true	"API key ID"
+//	@Success		204		"API key revoked"
+//	@Failure		400		{object}	map[string]string	"Bad request"
+//	@Failure		404		{object}	map[string]string	"API key not found"
+//	@Failure		500		{object}	map[string]string	"Internal server error"
+//	@Router			/api-keys/{keyId} [delete]
+func (h *APIKeyHandler) RevokeAPIKey(ctx *gin.Context) {
+	userID, ok := h.userID(ctx)
+	if !ok {
+		return
+	}
+	keyID, err := strconv.Atoi(ctx.Param("keyId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "keyId must be an integer"})
+		return
+	}
+
+	if err := h.service.Revoke(ctx.Request.Context(), userID, keyID); err != nil {
+		h.respondError(ctx, "failed to revoke API key", err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// userID returns the authenticated user, responding 401 when the request carries none.
+func (h *APIKeyHandler) userID(ctx *gin.Context) (int, bool) {
+	userID, ok := middleware.UserID(ctx.Request.Context())
+	if !ok {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return 0, false
+	}
+	return userID, true
+}
+
+// respondError maps API key errors onto HTTP status codes.
+func (h *APIKeyHandler) respondError(ctx *gin.Context, msg string, err error) {
+	switch {
+	case errors.Is(err, ErrInvalidScopes), errors.Is(err, ErrExpiryInPast):
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, ErrForbidden):
+		ctx.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, ErrNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	default:
+		h.logger.Error(msg, zap.Error(err))
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -0,0 +1,130 @@
+package apikey
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"health-checker/internal/middleware/middlewaretest"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+)
+
+type MockRepository struct {
+	mock.Mock
+}
+
+func (m *MockRepository) Create(ctx context.Context, key APIKey) (APIKey, error) {
+	args := m.Called(ctx, key)
+	return args.Get(0).(APIKey), args.Error(1)
+}
+
+func (m *MockRepository) ListByUser(ctx context.Context, userID int) ([]APIKey, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).([]APIKey), args.Error(1)
+}
+
+func (m *MockRepository) Revoke(ctx context.Context, userID, id int) error {
+	args := m.Called(ctx, userID, id)
+	return args.Error(0)
+}
+
+func (m *MockRepository) UseActive(ctx context.Context, hash string) (APIKey, error) {
+	args := m.Called(ctx, hash)
+	return args.Get(0).(APIKey), args.Error(1)
+}
+
+// setupRouter serves the API key routes behind the real auth middleware.
+func setupRouter(mockRepo *MockRepository) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	os.Setenv("JWT_SECRET", "test-secret")
+
+	r := gin.New()
+	NewHandler(NewService(mockRepo, stubRoles{}, zap.L()), middlewaretest.Denylist{}, zap.NewNop()).RegisterRoutes(r.Group("/api-keys"))
+	return r
+}
+
+func authorizedRequest(method, path string, body interface{}, userID int) *http.Request {
+	token, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"user_id": userID,
+		"exp":     time.Now().Add(time.Hour).Unix(),
+	}).SignedString([]byte("test-secret"))
+
+	var payload []byte
+	if body != nil {
+		payload, _ = json.Marshal(body)
+	}
+	req, _ := http.NewRequest(method, path, bytes.NewBuffer(payload))
+	req.Header.Set("Authorization", "Bearer "+token)
+	return req
+}
+
+func TestCreateAPIKey(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		mockRepo.On("Create", mock.Anything, mock.AnythingOfType("APIKey")).Return(APIKey{ID: 1, UserID: 4, Name: "ci"}, nil)
+
+		w := httptest.NewRecorder()
+		body := CreateAPIKeyDTO{Name: "ci", Scopes: []string{"services:read"}}
+		setupRouter(mockRepo).ServeHTTP(w, authorizedRequest("POST", "/api-keys", body, 4))
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		assert.Contains(t, w.Body.String(), `"key":"hc_`)
+		assert.NotContains(t, w.Body.String(), "hash")
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("InvalidScope", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+
+		w := httptest.NewRecorder()
+		body := CreateAPIKeyDTO{Name: "ci", Scopes: []string{"admin"}}
+		setupRouter(mockRepo).ServeHTTP(w, authorizedRequest("POST", "/api-keys", body, 4))
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		mockRepo.AssertNotCalled(t, "Create")
+	})
+
+	t.Run("RejectsAPIKeyAuth", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+
+		req, _ := http.NewRequest("POST", "/api-keys", bytes.NewBufferString(`{"name":"ci","scopes":["services:read"]}`))
+		req.Header.Set("Authorization", "Bearer hc_leaked")
+		w := httptest.NewRecorder()
+		setupRouter(mockRepo).ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+		mockRepo.AssertNotCalled(t, "UseActive")
+	})
+}
+
+func TestRevokeAPIKey(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		mockRepo.On("Revoke", mock.Anything, 4, 1).Return(nil)
+
+		w := httptest.NewRecorder()
+		setupRouter(mockRepo).ServeHTTP(w, authorizedRequest("DELETE", "/api-keys/1", nil, 4))
+
+		assert.Equal(t, http.StatusNoContent, w.Code)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		mockRepo.On("Revoke", mock.Anything, 4, 2).Return(ErrNotFound)
+
+		w := httptest.NewRecorder()
+		setupRouter(mockRepo).ServeHTTP(w, authorizedRequest("DELETE", "/api-keys/2", nil, 4))
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
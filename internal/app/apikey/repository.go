@@ -0,0 +1,97 @@
+package apikey
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type Repository interface {
+	Create(ctx context.Context, key APIKey) (APIKey, error)
+	ListByUser(ctx context.Context, userID int) ([]APIKey, error)
+	Revoke(ctx context.Context, userID, id int) error
+	UseActive(ctx context.Context, hash string) (APIKey, error)
+}
+
+const apiKeyColumns = `id, user_id, coalesce(organization_id, 0), name, prefix, scopes, expires_at, last_used_at, revoked_at,
+	created_at, key_hash`
+
+type PostgresRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewRepository(db *pgxpool.Pool) Repository {
+	return &PostgresRepository{db: db}
+}
+
+func (r *PostgresRepository) Create(ctx context.Context, key APIKey) (APIKey, error) {
+	query := `
+		insert into api_keys (user_id, organization_id, name, prefix, key_hash, scopes, expires_at)
+		values ($1, nullif($2, 0), $3, $4, $5, $6, $7)
+		returning ` + apiKeyColumns + `
+	`
+	return scanAPIKey(r.db.QueryRow(ctx, query, key.UserID, key.OrganizationID, key.Name, key.Prefix, key.Hash,
+		key.Scopes, key.ExpiresAt))
+}
+
+func (r *PostgresRepository) ListByUser(ctx context.Context, userID int) ([]APIKey, error) {
+	query := `
+		select ` + apiKeyColumns + `
+		from api_keys
+		where user_id = $1
+		order by created_at desc
+	`
+	rows, err := r.db.Query(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	keys := []APIKey{}
+	for rows.Next() {
+		key, err := scanAPIKey(rows)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+	return keys, rows.Err()
+}
+
+// Revoke disables one of userID's keys. Revoking a revoked key is a no-op.
+func (r *PostgresRepository) Revoke(ctx context.Context, userID, id int) error {
+	tag, err := r.db.Exec(ctx, `
+		update api_keys set revoked_at = coalesce(revoked_at, now())
+		where id = $1 and user_id = $2
+	`, id, userID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// UseActive returns the unrevoked, unexpired key with hash and records that it
+// was used.
+func (r *PostgresRepository) UseActive(ctx context.Context, hash string) (APIKey, error) {
+	query := `
+		update api_keys set last_used_at = now()
+		where key_hash = $1 and revoked_at is null and (expires_at is null or expires_at > now())
+		returning ` + apiKeyColumns + `
+	`
+	key, err := scanAPIKey(r.db.QueryRow(ctx, query, hash))
+	if err == pgx.ErrNoRows {
+		return APIKey{}, ErrNotFound
+	}
+	return key, err
+}
+
+func scanAPIKey(row pgx.Row) (APIKey, error) {
+	var key APIKey
+	err := row.Scan(&key.ID, &key.UserID, &key.OrganizationID, &key.Name, &key.Prefix, &key.Scopes, &key.ExpiresAt,
+		&key.LastUsedAt, &key.RevokedAt, &key.CreatedAt, &key.Hash)
+	return key, err
+}
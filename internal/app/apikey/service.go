@@ -0,0 +1,109 @@
+package apikey
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"health-checker/internal/middleware"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// displayedPrefixLen is how much of a key is kept in clear to identify it.
+const displayedPrefixLen = 11
+
+type APIKeyService struct {
+	repo  Repository
+	roles middleware.RoleResolver
+	log   *zap.Logger
+}
+
+func NewService(repo Repository, roles middleware.RoleResolver, log *zap.Logger) *APIKeyService {
+	return &APIKeyService{repo: repo, roles: roles, log: log}
+}
+
+// Create issues a key acting as userID. Organization keys require userID to be
+// an admin of the organization.
+func (s *APIKeyService) Create(ctx context.Context, userID int, dto CreateAPIKeyDTO) (CreatedAPIKey, error) {
+	for _, scope := range dto.Scopes {
+		if scope != middleware.ScopeServicesRead && scope != middleware.ScopeServicesWrite {
+			return CreatedAPIKey{}, ErrInvalidScopes
+		}
+	}
+	if dto.ExpiresAt != nil && !dto.ExpiresAt.After(time.Now()) {
+		return CreatedAPIKey{}, ErrExpiryInPast
+	}
+	if dto.OrganizationID != 0 {
+		role, err := s.roles.MemberRole(ctx, dto.OrganizationID, userID)
+		if err != nil {
+			return CreatedAPIKey{}, err
+		}
+		if !role.Allows(middleware.RoleAdmin) {
+			return CreatedAPIKey{}, ErrForbidden
+		}
+	}
+
+	key, err := newKey()
+	if err != nil {
+		return CreatedAPIKey{}, err
+	}
+
+	created, err := s.repo.Create(ctx, APIKey{
+		UserID:         userID,
+		OrganizationID: dto.OrganizationID,
+		Name:           dto.Name,
+		Prefix:         key[:displayedPrefixLen],
+		Hash:           hashKey(key),
+		Scopes:         dto.Scopes,
+		ExpiresAt:      dto.ExpiresAt,
+	})
+	if err != nil {
+		return CreatedAPIKey{}, err
+	}
+
+	return CreatedAPIKey{APIKey: created, Key: key}, nil
+}
+
+func (s *APIKeyService) List(ctx context.Context, userID int) ([]APIKey, error) {
+	return s.repo.ListByUser(ctx, userID)
+}
+
+func (s *APIKeyService) Revoke(ctx context.Context, userID, id int) error {
+	return s.repo.Revoke(ctx, userID, id)
+}
+
+// VerifyAPIKey implements middleware.APIKeyVerifier.
+func (s *APIKeyService) VerifyAPIKey(ctx context.Context, key string) (middleware.APIKeyPrincipal, error) {
+	found, err := s.repo.UseActive(ctx, hashKey(key))
+	if errors.Is(err, ErrNotFound) {
+		return middleware.APIKeyPrincipal{}, middleware.ErrInvalidAPIKey
+	}
+	if err != nil {
+		return middleware.APIKeyPrincipal{}, err
+	}
+
+	return middleware.APIKeyPrincipal{
+		KeyID:  found.ID,
+		UserID: found.UserID,
+		OrgID:  found.OrganizationID,
+		Scopes: found.Scopes,
+	}, nil
+}
+
+func newKey() (string, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+	return middleware.APIKeyPrefix + base64.RawURLEncoding.EncodeToString(secret), nil
+}
+
+// hashKey needs no salt or stretching: keys are 256 random bits, not passwords.
+func hashKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,100 @@
+package apikey
+
+import (
+	"context"
+	"health-checker/internal/middleware"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+)
+
+// stubRoles makes user 4 an admin and user 5 an editor of every organization.
+type stubRoles struct{}
+
+func (stubRoles) MemberRole(ctx context.Context, orgID, userID int) (middleware.Role, error) {
+	switch userID {
+	case 4:
+		return middleware.RoleAdmin, nil
+	case 5:
+		return middleware.RoleEditor, nil
+	}
+	return "", nil
+}
+
+func TestService_Create(t *testing.T) {
+	t.Run("StoresHashOnly", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		service := NewService(mockRepo, stubRoles{}, zap.L())
+
+		var stored APIKey
+		mockRepo.On("Create", mock.Anything, mock.AnythingOfType("APIKey")).
+			Run(func(args mock.Arguments) { stored = args.Get(1).(APIKey) }).
+			Return(APIKey{ID: 1}, nil)
+
+		created, err := service.Create(context.Background(), 4, CreateAPIKeyDTO{Name: "ci", Scopes: []string{middleware.ScopeServicesRead}})
+
+		assert.NoError(t, err)
+		assert.True(t, strings.HasPrefix(created.Key, middleware.APIKeyPrefix))
+		assert.Equal(t, created.Key[:displayedPrefixLen], stored.Prefix)
+		assert.Equal(t, hashKey(created.Key), stored.Hash)
+		assert.NotContains(t, stored.Hash, created.Key)
+		assert.Equal(t, 4, stored.UserID)
+	})
+
+	t.Run("InvalidScope", func(t *testing.T) {
+		service := NewService(new(MockRepository), stubRoles{}, zap.L())
+
+		_, err := service.Create(context.Background(), 4, CreateAPIKeyDTO{Name: "ci", Scopes: []string{"services:delete"}})
+
+		assert.ErrorIs(t, err, ErrInvalidScopes)
+	})
+
+	t.Run("ExpiryInPast", func(t *testing.T) {
+		service := NewService(new(MockRepository), stubRoles{}, zap.L())
+		past := time.Now().Add(-time.Hour)
+
+		_, err := service.Create(context.Background(), 4, CreateAPIKeyDTO{Name: "ci", Scopes: []string{middleware.ScopeServicesRead}, ExpiresAt: &past})
+
+		assert.ErrorIs(t, err, ErrExpiryInPast)
+	})
+
+	t.Run("OrganizationKeyRequiresAdmin", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		service := NewService(mockRepo, stubRoles{}, zap.L())
+
+		_, err := service.Create(context.Background(), 5, CreateAPIKeyDTO{Name: "ci", Scopes: []string{middleware.ScopeServicesWrite}, OrganizationID: 7})
+
+		assert.ErrorIs(t, err, ErrForbidden)
+		mockRepo.AssertNotCalled(t, "Create")
+	})
+}
+
+func TestService_VerifyAPIKey(t *testing.T) {
+	t.Run("Valid", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		service := NewService(mockRepo, stubRoles{}, zap.L())
+
+		mockRepo.On("UseActive", mock.Anything, hashKey("hc_secret")).
+			Return(APIKey{ID: 3, UserID: 4, OrganizationID: 7, Scopes: []string{middleware.ScopeServicesRead}}, nil)
+
+		principal, err := service.VerifyAPIKey(context.Background(), "hc_secret")
+
+		assert.NoError(t, err)
+		assert.Equal(t, middleware.APIKeyPrincipal{KeyID: 3, UserID: 4, OrgID: 7, Scopes: []string{middleware.ScopeServicesRead}}, principal)
+	})
+
+	t.Run("UnknownOrRevoked", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		service := NewService(mockRepo, stubRoles{}, zap.L())
+
+		mockRepo.On("UseActive", mock.Anything, hashKey("hc_revoked")).Return(APIKey{}, ErrNotFound)
+
+		_, err := service.VerifyAPIKey(context.Background(), "hc_revoked")
+
+		assert.ErrorIs(t, err, middleware.ErrInvalidAPIKey)
+	})
+}
@@ -1,6 +1,16 @@
 package auth
 
-import "time"
+import (
+	"errors"
+	"time"
+)
+
+var (
+	ErrInvalidRefreshToken = errors.New("invalid refresh token")
+	// ErrRefreshTokenReused means an already rotated refresh token was
+	// presented again; its whole family has been revoked.
+	ErrRefreshTokenReused = errors.New("refresh token reused")
+)
 
 type User struct {
 	ID        int       `json:"id" db:"id"`
@@ -18,3 +28,31 @@ type LoginUserDTO struct {
 	Username string `json:"username" binding:"required" example:"johndoe"`
 	Password string `json:"password" binding:"required" example:"securepassword"`
 }
+
+// RefreshToken is a server-side refresh token record; only its hash is stored.
+type RefreshToken struct {
+	ID        int
+	UserID    int
+	FamilyID  string
+	Hash      string
+	ExpiresAt time.Time
+	RevokedAt *time.Time
+	CreatedAt time.Time
+}
+
+type TokenPair struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+	TokenType    string `json:"token_type" example:"Bearer"`
+	// ExpiresIn is the access token lifetime in seconds.
+	ExpiresIn int `json:"expires_in" example:"900"`
+}
+
+type RefreshTokenDTO struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
+type LogoutDTO struct {
+	// RefreshToken, when given, is revoked together with the tokens rotated from it.
+	RefreshToken string `json:"refresh_token"`
+}
@@ -1,25 +1,33 @@
 package auth
 
 import (
+	"errors"
+	"health-checker/internal/middleware"
+	"io"
+
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
 )
 
 type UserHandler struct {
-	service *UserService
-	logger  *zap.Logger
+	service  *UserService
+	denylist middleware.Denylist
+	logger   *zap.Logger
 }
 
-func NewHandler(service *UserService, logger *zap.Logger) *UserHandler {
+func NewHandler(service *UserService, denylist middleware.Denylist, logger *zap.Logger) *UserHandler {
 	return &UserHandler{
-		service: service,
-		logger:  logger,
+		service:  service,
+		denylist: denylist,
+		logger:   logger,
 	}
 }
 
 func (h *UserHandler) RegisterRoutes(r *gin.RouterGroup) {
 	r.POST("/register", h.RegisterUser)
 	r.POST("/login", h.LoginUser)
+	r.POST("/refresh", h.RefreshToken)
+	r.POST("/logout", middleware.AuthMiddleware(h.denylist, nil), h.LogoutUser)
 }
 
 // RegisterUser godoc
@@ -54,12 +62,12 @@ func (h *UserHandler) RegisterUser(c *gin.Context) {
 // LoginUser godoc
 //
 //	@Summary		Login a user
-//	@Description	Authenticate a user and return a JWT token
+//	@Description	Authenticate a user and return a short-lived JWT access token with a refresh token
 //	@Tags			auth
 //	@Accept			json
 //	@Produce		json
 //	@Param			user	body		LoginUserDTO		true	"User login data"
-//	@Success		200		{object}	map[string]string	"Access token"
+//	@Success		200		{object}	TokenPair			"Access and refresh tokens"
 //	@Failure		400		{object}	map[string]string	"Bad request"
 //	@Failure		500		{object}	map[string]string	"Internal server error"
 //	@Router			/auth/login [post]
@@ -71,12 +79,84 @@ func (h *UserHandler) LoginUser(c *gin.Context) {
 		return
 	}
 
-	token, err := h.service.Login(c.Request.Context(), body)
+	tokens, err := h.service.Login(c.Request.Context(), body)
 	if err != nil {
 		h.logger.Error("failed to login", zap.Error(err))
 		c.JSON(500, gin.H{"error": err.Error()})
 		return
 	}
 
-	c.JSON(200, gin.H{"access_token": token})
+	c.JSON(200, tokens)
+}
+
+// RefreshToken godoc
+//
+//	@Summary		Refresh an access token
+//	@Description	Exchange a refresh token for a new access token and a new refresh token. Each refresh token works once; reusing one revokes every token issued from the same login.
+//	@Tags			auth
+//	@Accept			json
+//	@Produce		json
+//	@Param			token	body		RefreshTokenDTO		true	"Refresh token"
+//	@Success		200		{object}	TokenPair			"Access and refresh tokens"
+//	@Failure		400		{object}	map[string]string	"Bad request"
+//	@Failure		401		{object}	map[string]string	"Invalid refresh token"
+//	@Failure		500		{object}	map[string]string	"Internal server error"
+//	@Router			/auth/refresh [post]
+func (h *UserHandler) RefreshToken(c *gin.Context) {
+	var body RefreshTokenDTO
+	if err := c.ShouldBindJSON(&body); err != nil {
+		h.logger.Error("failed to bind json", zap.Error(err))
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+
+	tokens, err := h.service.Refresh(c.Request.Context(), body.RefreshToken)
+	if errors.Is(err, ErrInvalidRefreshToken) {
+		c.JSON(401, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		h.logger.Error("failed to refresh token", zap.Error(err))
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(200, tokens)
+}
+
+// LogoutUser godoc
+//
+//	@Security		BearerAuth
+//	@Summary		Logout
+//	@Description	Revoke the access token used for this request and, when given, the refresh token
+//	@Tags			auth
+//	@Accept			json
+//	@Param			token	body	LogoutDTO	false	"Refresh token to revoke"
+//	@Success		204		"Logged out"
+//	@Failure		400		{object}	map[string]string	"Bad request"
+//	@Failure		401		{object}	map[string]string	"Unauthorized"
+//	@Failure		500		{object}	map[string]string	"Internal server error"
+//	@Router			/auth/logout [post]
+func (h *UserHandler) LogoutUser(c *gin.Context) {
+	userID, ok := middleware.UserID(c.Request.Context())
+	if !ok {
+		c.JSON(401, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var body LogoutDTO
+	if err := c.ShouldBindJSON(&body); err != nil && !errors.Is(err, io.EOF) {
+		h.logger.Error("failed to bind json", zap.Error(err))
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+
+	accessToken, _ := middleware.AccessTokenFrom(c.Request.Context())
+	if err := h.service.Logout(c.Request.Context(), userID, accessToken, body.RefreshToken); err != nil {
+		h.logger.Error("failed to logout", zap.Error(err))
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Status(204)
 }
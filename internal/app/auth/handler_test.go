@@ -5,6 +5,8 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"health-checker/internal/middleware"
+	"health-checker/internal/middleware/middlewaretest"
 	"net/http"
 	"net/http/httptest"
 	"os"
@@ -31,6 +33,21 @@ func (m *MockRepository) GetUserByUsername(ctx context.Context, username string)
 	return args.Get(0).(User), args.Error(1)
 }
 
+func (m *MockRepository) CreateRefreshToken(ctx context.Context, token RefreshToken) error {
+	args := m.Called(ctx, token)
+	return args.Error(0)
+}
+
+func (m *MockRepository) RotateRefreshToken(ctx context.Context, hash string, next RefreshToken) (User, error) {
+	args := m.Called(ctx, hash, next)
+	return args.Get(0).(User), args.Error(1)
+}
+
+func (m *MockRepository) RevokeRefreshTokenFamily(ctx context.Context, userID int, hash string) error {
+	args := m.Called(ctx, userID, hash)
+	return args.Error(0)
+}
+
 func setupRouter() *gin.Engine {
 	gin.SetMode(gin.TestMode)
 	return gin.Default()
@@ -39,8 +56,8 @@ func setupRouter() *gin.Engine {
 func TestRegisterUser(t *testing.T) {
 	t.Run("Success", func(t *testing.T) {
 		mockRepo := new(MockRepository)
-		service := NewService(mockRepo, zap.L())
-		handler := NewHandler(service, zap.NewNop())
+		service := NewService(mockRepo, nil, zap.L())
+		handler := NewHandler(service, middlewaretest.Denylist{}, zap.NewNop())
 
 		mockRepo.On("Create", mock.Anything, mock.AnythingOfType("auth.User")).Return(nil)
 
@@ -62,8 +79,8 @@ func TestRegisterUser(t *testing.T) {
 
 	t.Run("BadRequest", func(t *testing.T) {
 		mockRepo := new(MockRepository)
-		service := NewService(mockRepo, zap.L())
-		handler := NewHandler(service, zap.NewNop())
+		service := NewService(mockRepo, nil, zap.L())
+		handler := NewHandler(service, middlewaretest.Denylist{}, zap.NewNop())
 
 		r := setupRouter()
 		r.POST("/auth/register", handler.RegisterUser)
@@ -77,8 +94,8 @@ func TestRegisterUser(t *testing.T) {
 
 	t.Run("InternalServerError", func(t *testing.T) {
 		mockRepo := new(MockRepository)
-		service := NewService(mockRepo, zap.L())
-		handler := NewHandler(service, zap.NewNop())
+		service := NewService(mockRepo, nil, zap.L())
+		handler := NewHandler(service, middlewaretest.Denylist{}, zap.NewNop())
 
 		mockRepo.On("Create", mock.Anything, mock.AnythingOfType("auth.User")).Return(errors.New("db error"))
 
@@ -104,8 +121,8 @@ func TestLoginUser(t *testing.T) {
 
 	t.Run("Success", func(t *testing.T) {
 		mockRepo := new(MockRepository)
-		service := NewService(mockRepo, zap.L())
-		handler := NewHandler(service, zap.NewNop())
+		service := NewService(mockRepo, nil, zap.L())
+		handler := NewHandler(service, middlewaretest.Denylist{}, zap.NewNop())
 
 		password := "password123"
 		hashedPassword, _ := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
@@ -116,6 +133,7 @@ func TestLoginUser(t *testing.T) {
 		}
 
 		mockRepo.On("GetUserByUsername", mock.Anything, "testuser").Return(user, nil)
+		mockRepo.On("CreateRefreshToken", mock.Anything, mock.AnythingOfType("auth.RefreshToken")).Return(nil)
 
 		r := setupRouter()
 		r.POST("/auth/login", handler.LoginUser)
@@ -131,17 +149,19 @@ func TestLoginUser(t *testing.T) {
 
 		assert.Equal(t, http.StatusOK, w.Code)
 
-		var response map[string]string
+		var response TokenPair
 		json.Unmarshal(w.Body.Bytes(), &response)
-		assert.NotEmpty(t, response["access_token"])
+		assert.NotEmpty(t, response.AccessToken)
+		assert.NotEmpty(t, response.RefreshToken)
+		assert.Equal(t, 900, response.ExpiresIn)
 
 		mockRepo.AssertExpectations(t)
 	})
 
 	t.Run("InvalidCredentials", func(t *testing.T) {
 		mockRepo := new(MockRepository)
-		service := NewService(mockRepo, zap.L())
-		handler := NewHandler(service, zap.NewNop())
+		service := NewService(mockRepo, nil, zap.L())
+		handler := NewHandler(service, middlewaretest.Denylist{}, zap.NewNop())
 
 		password := "password123"
 		hashedPassword, _ := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
@@ -171,8 +191,8 @@ func TestLoginUser(t *testing.T) {
 
 	t.Run("UserNotFound", func(t *testing.T) {
 		mockRepo := new(MockRepository)
-		service := NewService(mockRepo, zap.L())
-		handler := NewHandler(service, zap.NewNop())
+		service := NewService(mockRepo, nil, zap.L())
+		handler := NewHandler(service, middlewaretest.Denylist{}, zap.NewNop())
 
 		mockRepo.On("GetUserByUsername", mock.Anything, "unknown").Return(User{}, errors.New("user not found"))
 
@@ -195,8 +215,8 @@ func TestLoginUser(t *testing.T) {
 
 func TestRegisterRoutes(t *testing.T) {
 	mockRepo := new(MockRepository)
-	service := NewService(mockRepo, zap.L())
-	handler := NewHandler(service, zap.NewNop())
+	service := NewService(mockRepo, nil, zap.L())
+	handler := NewHandler(service, middlewaretest.Denylist{}, zap.NewNop())
 
 	// Setup mock expectations for register
 	mockRepo.On("Create", mock.Anything, mock.AnythingOfType("auth.User")).Return(nil).Maybe()
@@ -231,3 +251,71 @@ func TestRegisterRoutes(t *testing.T) {
 	r.ServeHTTP(w, req)
 	assert.True(t, w.Code == http.StatusOK || w.Code == http.StatusBadRequest || w.Code == http.StatusInternalServerError || w.Code == http.StatusUnauthorized, "Login route should be registered")
 }
+
+func TestRefreshToken(t *testing.T) {
+	os.Setenv("JWT_SECRET", "testsecret")
+
+	t.Run("Success", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		handler := NewHandler(NewService(mockRepo, nil, zap.L()), middlewaretest.Denylist{}, zap.NewNop())
+
+		mockRepo.On("RotateRefreshToken", mock.Anything, hashToken("old-token"), mock.AnythingOfType("auth.RefreshToken")).
+			Return(User{ID: 1, Username: "testuser"}, nil)
+
+		r := setupRouter()
+		r.POST("/auth/refresh", handler.RefreshToken)
+
+		req, _ := http.NewRequest("POST", "/auth/refresh", bytes.NewBufferString(`{"refresh_token":"old-token"}`))
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), "refresh_token")
+	})
+
+	t.Run("InvalidToken", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		handler := NewHandler(NewService(mockRepo, nil, zap.L()), middlewaretest.Denylist{}, zap.NewNop())
+
+		mockRepo.On("RotateRefreshToken", mock.Anything, hashToken("unknown"), mock.Anything).Return(User{}, ErrInvalidRefreshToken)
+
+		r := setupRouter()
+		r.POST("/auth/refresh", handler.RefreshToken)
+
+		req, _ := http.NewRequest("POST", "/auth/refresh", bytes.NewBufferString(`{"refresh_token":"unknown"}`))
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+}
+
+func TestLogoutUser(t *testing.T) {
+	os.Setenv("JWT_SECRET", "testsecret")
+
+	denylist := middlewaretest.Denylist{}
+
+	mockRepo := new(MockRepository)
+	mockRepo.On("RevokeRefreshTokenFamily", mock.Anything, 1, mock.Anything).Return(nil)
+	service := NewService(mockRepo, denylist, zap.L())
+
+	r := gin.New()
+	NewHandler(service, denylist, zap.NewNop()).RegisterRoutes(r.Group("/auth"))
+	r.GET("/me", middleware.AuthMiddleware(denylist, nil), func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	tokens, err := newTokenPair(User{ID: 1, Username: "testuser"}, "refresh-token")
+	assert.NoError(t, err)
+	request := func(method, path, body string) int {
+		req, _ := http.NewRequest(method, path, bytes.NewBufferString(body))
+		req.Header.Set("Authorization", "Bearer "+tokens.AccessToken)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	assert.Equal(t, http.StatusOK, request("GET", "/me", ""))
+	assert.Equal(t, http.StatusNoContent, request("POST", "/auth/logout", `{"refresh_token":"refresh-token"}`))
+	assert.Equal(t, http.StatusUnauthorized, request("GET", "/me", ""))
+	assert.Len(t, denylist, 1)
+	mockRepo.AssertExpectations(t)
+}
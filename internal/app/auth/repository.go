@@ -2,13 +2,18 @@ package auth
 
 import (
 	"context"
+	"time"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
 type Repository interface {
 	Create(ctx context.Context, user User) error
 	GetUserByUsername(ctx context.Context, username string) (User, error)
+	CreateRefreshToken(ctx context.Context, token RefreshToken) error
+	RotateRefreshToken(ctx context.Context, hash string, next RefreshToken) (User, error)
+	RevokeRefreshTokenFamily(ctx context.Context, userID int, hash string) error
 }
 
 type PostgresRepository struct {
@@ -40,3 +45,84 @@ func (r *PostgresRepository) GetUserByUsername(ctx context.Context, username str
 
 	return user, err
 }
+
+func (r *PostgresRepository) CreateRefreshToken(ctx context.Context, token RefreshToken) error {
+	query := `
+		insert into refresh_tokens (user_id, family_id, token_hash, expires_at)
+		values ($1, $2, $3, $4)
+	`
+	_, err := r.db.Exec(ctx, query, token.UserID, token.FamilyID, token.Hash, token.ExpiresAt)
+	return err
+}
+
+// RotateRefreshToken revokes the active refresh token with hash and stores next
+// in its place, in the same family, returning the token's user. Presenting a
+// token that was already rotated revokes its whole family and returns
+// ErrRefreshTokenReused: either the client or someone else holds a stolen copy.
+func (r *PostgresRepository) RotateRefreshToken(ctx context.Context, hash string, next RefreshToken) (User, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return User{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	var current RefreshToken
+	err = tx.QueryRow(ctx, `
+		select id, user_id, family_id, expires_at, revoked_at
+		from refresh_tokens
+		where token_hash = $1
+		for update
+	`, hash).Scan(&current.ID, &current.UserID, &current.FamilyID, &current.ExpiresAt, &current.RevokedAt)
+	if err == pgx.ErrNoRows {
+		return User{}, ErrInvalidRefreshToken
+	}
+	if err != nil {
+		return User{}, err
+	}
+
+	if current.RevokedAt != nil {
+		if _, err := tx.Exec(ctx, `
+			update refresh_tokens set revoked_at = coalesce(revoked_at, now())
+			where family_id = $1
+		`, current.FamilyID); err != nil {
+			return User{}, err
+		}
+		if err := tx.Commit(ctx); err != nil {
+			return User{}, err
+		}
+		return User{}, ErrRefreshTokenReused
+	}
+	if !current.ExpiresAt.After(time.Now()) {
+		return User{}, ErrInvalidRefreshToken
+	}
+
+	if _, err := tx.Exec(ctx, `update refresh_tokens set revoked_at = now() where id = $1`, current.ID); err != nil {
+		return User{}, err
+	}
+	if _, err := tx.Exec(ctx, `
+		insert into refresh_tokens (user_id, family_id, token_hash, expires_at)
+		values ($1, $2, $3, $4)
+	`, current.UserID, current.FamilyID, next.Hash, next.ExpiresAt); err != nil {
+		return User{}, err
+	}
+
+	var user User
+	err = tx.QueryRow(ctx, `select id, username, password, created_at from users where id = $1`, current.UserID).
+		Scan(&user.ID, &user.Username, &user.Password, &user.CreatedAt)
+	if err != nil {
+		return User{}, err
+	}
+
+	return user, tx.Commit(ctx)
+}
+
+// RevokeRefreshTokenFamily revokes userID's refresh token with hash and every
+// token rotated from the same login. Unknown tokens are ignored.
+func (r *PostgresRepository) RevokeRefreshTokenFamily(ctx context.Context, userID int, hash string) error {
+	query := `
+		update refresh_tokens set revoked_at = coalesce(revoked_at, now())
+		where family_id = (select family_id from refresh_tokens where token_hash = $1 and user_id = $2)
+	`
+	_, err := r.db.Exec(ctx, query, hash, userID)
+	return err
+}
@@ -3,6 +3,7 @@ package auth
 import (
 	"context"
 	"fmt"
+	"health-checker/internal/migrations"
 	"testing"
 	"time"
 
@@ -23,6 +24,7 @@ func TestRepository_Integration(t *testing.T) {
 	pool, err := pgxpool.New(ctx, dbURL)
 	require.NoError(t, err)
 	defer pool.Close()
+	require.NoError(t, migrations.Migrate(pool))
 
 	repo := NewRepository(pool)
 
@@ -52,4 +54,26 @@ func TestRepository_Integration(t *testing.T) {
 		_, err := repo.GetUserByUsername(ctx, "nonexistent")
 		assert.Error(t, err)
 	})
+
+	t.Run("RotateRefreshToken", func(t *testing.T) {
+		user, err := repo.GetUserByUsername(ctx, uniqueUsername)
+		require.NoError(t, err)
+
+		first := RefreshToken{UserID: user.ID, FamilyID: fmt.Sprintf("%032d", time.Now().UnixNano()), Hash: hashToken(uniqueUsername + "-1"), ExpiresAt: time.Now().Add(time.Hour)}
+		require.NoError(t, repo.CreateRefreshToken(ctx, first))
+
+		second := RefreshToken{Hash: hashToken(uniqueUsername + "-2"), ExpiresAt: time.Now().Add(time.Hour)}
+		rotatedFor, err := repo.RotateRefreshToken(ctx, first.Hash, second)
+		assert.NoError(t, err)
+		assert.Equal(t, user.ID, rotatedFor.ID)
+
+		// Reusing the first token revokes the second one too
+		_, err = repo.RotateRefreshToken(ctx, first.Hash, RefreshToken{Hash: hashToken(uniqueUsername + "-3"), ExpiresAt: time.Now().Add(time.Hour)})
+		assert.ErrorIs(t, err, ErrRefreshTokenReused)
+		_, err = repo.RotateRefreshToken(ctx, second.Hash, RefreshToken{Hash: hashToken(uniqueUsername + "-4"), ExpiresAt: time.Now().Add(time.Hour)})
+		assert.ErrorIs(t, err, ErrRefreshTokenReused)
+
+		_, err = repo.RotateRefreshToken(ctx, hashToken("unknown"), second)
+		assert.ErrorIs(t, err, ErrInvalidRefreshToken)
+	})
 }
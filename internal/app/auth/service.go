@@ -2,6 +2,12 @@ package auth
 
 import (
 	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"health-checker/internal/middleware"
 	"os"
 	"time"
 
@@ -10,13 +16,19 @@ import (
 	"golang.org/x/crypto/bcrypt"
 )
 
+const (
+	accessTokenTTL  = 15 * time.Minute
+	refreshTokenTTL = 30 * 24 * time.Hour
+)
+
 type UserService struct {
-	repo Repository
-	log  *zap.Logger
+	repo     Repository
+	denylist middleware.Denylist
+	log      *zap.Logger
 }
 
-func NewService(repo Repository, log *zap.Logger) *UserService {
-	return &UserService{repo: repo, log: log}
+func NewService(repo Repository, denylist middleware.Denylist, log *zap.Logger) *UserService {
+	return &UserService{repo: repo, denylist: denylist, log: log}
 }
 
 func (s *UserService) RegisterUser(ctx context.Context, user RegisterUserDTO) error {
@@ -32,31 +44,132 @@ func (s *UserService) RegisterUser(ctx context.Context, user RegisterUserDTO) er
 	return s.repo.Create(ctx, u)
 }
 
-func (s *UserService) Login(ctx context.Context, body LoginUserDTO) (string, error) {
+func (s *UserService) Login(ctx context.Context, body LoginUserDTO) (TokenPair, error) {
 	user, err := s.repo.GetUserByUsername(ctx, body.Username)
 	if err != nil {
-		return "", err
+		return TokenPair{}, err
 	}
 
 	err = bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(body.Password))
 	if err != nil {
-		return "", err
+		return TokenPair{}, err
+	}
+
+	familyID, err := randomHex(16)
+	if err != nil {
+		return TokenPair{}, err
+	}
+	refreshToken, err := randomToken()
+	if err != nil {
+		return TokenPair{}, err
+	}
+	err = s.repo.CreateRefreshToken(ctx, RefreshToken{
+		UserID:    user.ID,
+		FamilyID:  familyID,
+		Hash:      hashToken(refreshToken),
+		ExpiresAt: time.Now().Add(refreshTokenTTL),
+	})
+	if err != nil {
+		return TokenPair{}, err
 	}
 
-	return generateJwtToken(user)
+	return newTokenPair(user, refreshToken)
 }
 
+// Refresh exchanges a refresh token for a new access token and a new refresh
+// token; the presented one can't be used again.
+func (s *UserService) Refresh(ctx context.Context, refreshToken string) (TokenPair, error) {
+	next, err := randomToken()
+	if err != nil {
+		return TokenPair{}, err
+	}
+
+	user, err := s.repo.RotateRefreshToken(ctx, hashToken(refreshToken), RefreshToken{
+		Hash:      hashToken(next),
+		ExpiresAt: time.Now().Add(refreshTokenTTL),
+	})
+	if errors.Is(err, ErrRefreshTokenReused) {
+		s.log.Warn("refresh token reused, revoked its family")
+		return TokenPair{}, ErrInvalidRefreshToken
+	}
+	if err != nil {
+		return TokenPair{}, err
+	}
+
+	return newTokenPair(user, next)
+}
+
+// Logout revokes the access token until it expires and, when given, the
+// refresh token it was issued with.
+func (s *UserService) Logout(ctx context.Context, userID int, access middleware.AccessToken, refreshToken string) error {
+	if access.ID != "" && s.denylist != nil {
+		if err := s.denylist.Revoke(ctx, access.ID, access.ExpiresAt); err != nil {
+			return err
+		}
+	}
+	if refreshToken == "" {
+		return nil
+	}
+	return s.repo.RevokeRefreshTokenFamily(ctx, userID, hashToken(refreshToken))
+}
+
+func newTokenPair(user User, refreshToken string) (TokenPair, error) {
+	accessToken, err := generateJwtToken(user)
+	if err != nil {
+		return TokenPair{}, err
+	}
+
+	return TokenPair{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		TokenType:    "Bearer",
+		ExpiresIn:    int(accessTokenTTL.Seconds()),
+	}, nil
+}
+
+// generateJwtToken issues a short-lived access token. The jti claim lets
+// Logout revoke it before it expires.
 func generateJwtToken(user User) (string, error) {
+	jti, err := randomHex(16)
+	if err != nil {
+		return "", err
+	}
+
 	key := os.Getenv("JWT_SECRET")
+	now := jwt.TimeFunc()
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
 		"user_id":  user.ID,
 		"username": user.Username,
-		"exp":      jwt.TimeFunc().Add(time.Hour * 24).Unix(),
+		"jti":      jti,
+		"iat":      now.Unix(),
+		"exp":      now.Add(accessTokenTTL).Unix(),
 	})
 
 	return token.SignedString([]byte(key))
 }
 
+func randomToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func randomHex(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// hashToken needs no salt or stretching: refresh tokens are 256 random bits.
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
 func hashPassword(password string) (string, error) {
 	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
 	if err != nil {
@@ -3,8 +3,11 @@ package auth
 import (
 	"context"
 	"errors"
+	"health-checker/internal/middleware"
+	"health-checker/internal/middleware/middlewaretest"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -15,7 +18,7 @@ import (
 func TestService_RegisterUser(t *testing.T) {
 	t.Run("Success", func(t *testing.T) {
 		mockRepo := new(MockRepository)
-		service := NewService(mockRepo, zap.L())
+		service := NewService(mockRepo, nil, zap.L())
 
 		dto := RegisterUserDTO{
 			Username: "testuser",
@@ -34,7 +37,7 @@ func TestService_RegisterUser(t *testing.T) {
 
 	t.Run("RepoError", func(t *testing.T) {
 		mockRepo := new(MockRepository)
-		service := NewService(mockRepo, zap.L())
+		service := NewService(mockRepo, nil, zap.L())
 
 		dto := RegisterUserDTO{
 			Username: "testuser",
@@ -54,7 +57,7 @@ func TestService_Login(t *testing.T) {
 
 	t.Run("Success", func(t *testing.T) {
 		mockRepo := new(MockRepository)
-		service := NewService(mockRepo, zap.L())
+		service := NewService(mockRepo, nil, zap.L())
 
 		password := "password123"
 		hashedPassword, _ := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
@@ -66,21 +69,26 @@ func TestService_Login(t *testing.T) {
 		}
 
 		mockRepo.On("GetUserByUsername", mock.Anything, "testuser").Return(user, nil)
+		mockRepo.On("CreateRefreshToken", mock.Anything, mock.MatchedBy(func(rt RefreshToken) bool {
+			return rt.UserID == 1 && rt.FamilyID != "" && rt.ExpiresAt.After(time.Now())
+		})).Return(nil)
 
 		dto := LoginUserDTO{
 			Username: "testuser",
 			Password: password,
 		}
 
-		token, err := service.Login(context.Background(), dto)
+		tokens, err := service.Login(context.Background(), dto)
 		assert.NoError(t, err)
-		assert.NotEmpty(t, token)
+		assert.NotEmpty(t, tokens.AccessToken)
+		assert.NotEmpty(t, tokens.RefreshToken)
+		assert.Equal(t, "Bearer", tokens.TokenType)
 		mockRepo.AssertExpectations(t)
 	})
 
 	t.Run("UserNotFound", func(t *testing.T) {
 		mockRepo := new(MockRepository)
-		service := NewService(mockRepo, zap.L())
+		service := NewService(mockRepo, nil, zap.L())
 
 		mockRepo.On("GetUserByUsername", mock.Anything, "unknown").Return(User{}, errors.New("user not found"))
 
@@ -97,7 +105,7 @@ func TestService_Login(t *testing.T) {
 
 	t.Run("WrongPassword", func(t *testing.T) {
 		mockRepo := new(MockRepository)
-		service := NewService(mockRepo, zap.L())
+		service := NewService(mockRepo, nil, zap.L())
 
 		password := "password123"
 		hashedPassword, _ := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
@@ -121,3 +129,48 @@ func TestService_Login(t *testing.T) {
 		mockRepo.AssertExpectations(t)
 	})
 }
+
+func TestService_Refresh(t *testing.T) {
+	os.Setenv("JWT_SECRET", "testsecret")
+
+	t.Run("Rotates", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		service := NewService(mockRepo, nil, zap.L())
+
+		var next RefreshToken
+		mockRepo.On("RotateRefreshToken", mock.Anything, hashToken("old-token"), mock.AnythingOfType("auth.RefreshToken")).
+			Run(func(args mock.Arguments) { next = args.Get(2).(RefreshToken) }).
+			Return(User{ID: 1, Username: "testuser"}, nil)
+
+		tokens, err := service.Refresh(context.Background(), "old-token")
+		assert.NoError(t, err)
+		assert.NotEmpty(t, tokens.AccessToken)
+		assert.NotEqual(t, "old-token", tokens.RefreshToken)
+		assert.Equal(t, hashToken(tokens.RefreshToken), next.Hash)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Reused", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		service := NewService(mockRepo, nil, zap.L())
+
+		mockRepo.On("RotateRefreshToken", mock.Anything, hashToken("rotated-token"), mock.Anything).Return(User{}, ErrRefreshTokenReused)
+
+		_, err := service.Refresh(context.Background(), "rotated-token")
+		assert.ErrorIs(t, err, ErrInvalidRefreshToken)
+	})
+}
+
+func TestService_Logout(t *testing.T) {
+	mockRepo := new(MockRepository)
+	denylist := middlewaretest.Denylist{}
+	service := NewService(mockRepo, denylist, zap.L())
+	expiresAt := time.Now().Add(10 * time.Minute)
+
+	mockRepo.On("RevokeRefreshTokenFamily", mock.Anything, 1, hashToken("refresh-token")).Return(nil)
+
+	err := service.Logout(context.Background(), 1, middleware.AccessToken{ID: "jti-1", ExpiresAt: expiresAt}, "refresh-token")
+	assert.NoError(t, err)
+	assert.Equal(t, expiresAt, denylist["jti-1"])
+	mockRepo.AssertExpectations(t)
+}
@@ -0,0 +1,57 @@
+package incident
+
+import (
+	"errors"
+	"time"
+)
+
+const (
+	StatusOpen     = "open"
+	StatusResolved = "resolved"
+)
+
+var ErrIncidentNotFound = errors.New("incident not found")
+
+// Incident is one outage of a service: it opens with the check that took the
+// service DOWN and is resolved when the service recovers, or by hand.
+type Incident struct {
+	ID          int    `json:"id" db:"id"`
+	ServiceID   int    `json:"service_id" db:"service_id"`
+	ServiceName string `json:"service_name" db:"service_name"`
+	// StartedAt is the time of the first failed check.
+	StartedAt  time.Time  `json:"started_at" db:"started_at"`
+	ResolvedAt *time.Time `json:"resolved_at,omitempty" db:"resolved_at"`
+	// DurationSeconds runs until now while the incident is open.
+	DurationSeconds int64 `json:"duration_seconds" db:"duration_seconds" example:"754"`
+	// RootError is what the check that took the service DOWN reported.
+	RootError      string     `json:"root_error" db:"root_error" example:"connection refused"`
+	AcknowledgedAt *time.Time `json:"acknowledged_at,omitempty" db:"acknowledged_at"`
+	AcknowledgedBy int        `json:"acknowledged_by,omitempty" db:"acknowledged_by"`
+	// ResolvedBy is set when a user resolved the incident before the service recovered.
+	ResolvedBy int       `json:"resolved_by,omitempty" db:"resolved_by"`
+	Notes      []Note    `json:"notes,omitempty"`
+	CreatedAt  time.Time `json:"created_at" db:"created_at"`
+}
+
+// Note is a comment a responder left on an incident.
+type Note struct {
+	ID         int       `json:"id" db:"id"`
+	IncidentID int       `json:"incident_id" db:"incident_id"`
+	UserID     int       `json:"user_id" db:"user_id"`
+	Body       string    `json:"body" db:"body" example:"Rolled back the 14:02 deploy"`
+	CreatedAt  time.Time `json:"created_at" db:"created_at"`
+}
+
+type CreateNoteDTO struct {
+	Body string `json:"body" binding:"required,max=10000" example:"Rolled back the 14:02 deploy"`
+}
+
+// ListFilter selects incidents. From and To bound when incidents started.
+type ListFilter struct {
+	ServiceID int        `form:"service_id" binding:"omitempty,min=1"`
+	Status    string     `form:"status" binding:"omitempty,oneof=open resolved"`
+	From      *time.Time `form:"from" time_format:"2006-01-02T15:04:05Z07:00"`
+	To        *time.Time `form:"to" time_format:"2006-01-02T15:04:05Z07:00"`
+	Page      int        `form:"page" binding:"omitempty,min=1"`
+	Limit     int        `form:"limit" binding:"omitempty,min=1,max=100"`
+}
@@ -0,0 +1,260 @@
+package incident
+
+import (
+	"errors"
+	"health-checker/internal/middleware"
+	"health-checker/internal/monitor"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type IncidentHandler struct {
+	service  *IncidentService
+	roles    middleware.RoleResolver
+	denylist middleware.Denylist
+	logger   *zap.Logger
+}
+
+func NewHandler(service *IncidentService, roles middleware.RoleResolver, denylist middleware.Denylist, logger *zap.Logger) *IncidentHandler {
+	return &IncidentHandler{
+		service:  service,
+		roles:    roles,
+		denylist: denylist,
+		logger:   logger,
+	}
+}
+
+// RegisterRoutes lets viewers acknowledge and comment on incidents, since
+// on-call responders often cannot change the configuration. Resolving an
+// incident before its service recovers takes an editor.
+func (h *IncidentHandler) RegisterRoutes(rg *gin.RouterGroup) {
+	authenticated := rg.Group("", middleware.AuthMiddleware(h.denylist, nil))
+	viewer := authenticated.Group("", middleware.Authorize(h.roles, middleware.RoleViewer))
+	viewer.GET("", h.ListIncidents)
+	viewer.GET("/:incidentId", h.GetIncident)
+	viewer.POST("/:incidentId/acknowledge", h.AcknowledgeIncident)
+	viewer.POST("/:incidentId/notes", h.AddNote)
+
+	editor := authenticated.Group("", middleware.Authorize(h.roles, middleware.RoleEditor))
+	editor.POST("/:incidentId/resolve", h.ResolveIncident)
+}
+
+// ListIncidents godoc
+//
+//	@Security		BearerAuth
+//	@Summary		List incidents
+//	@Description	List the incidents of your services, or an organization's, latest first. An incident opens when a service goes DOWN and is resolved when it recovers.
+//	@Tags			incidents
+//	@Produce		json
+//	@Param			service_id			query		int					false	"Only incidents of this service"
+//	@Param			status				query		string				false	"open or resolved"
+//	@Param			from				query		string				false	"Started at or after, RFC 3339"
+//	@Param			to					query		string				false	"Started before, RFC 3339"
+//	@Param			page				query		int					false	"Page number"			default(1)
+//	@Param			limit				query		int					false	"Incidents per page"	default(20)
+//	@Param			X-Organization-ID	header		int					false	"Organization to act in"
+//	@Success		200					{array}		Incident			"Incidents"
+//	@Failure		400					{object}	map[string]string	"Bad request"
+//	@Failure		403					{object}	map[string]string	"Forbidden"
+//	@Failure		500					{object}	map[string]string	"Internal server error"
+//	@Router			/incidents [get]
+func (h *IncidentHandler) ListIncidents(ctx *gin.Context) {
+	owner, ok := h.owner(ctx)
+	if !ok {
+		return
+	}
+
+	var filter ListFilter
+	if err := ctx.ShouldBindQuery(&filter); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	incidents, err := h.service.List(ctx.Request.Context(), owner, filter)
+	if err != nil {
+		h.respondError(ctx, "failed to list incidents", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, incidents)
+}
+
+// GetIncident godoc
+//
+//	@Security		BearerAuth
+//	@Summary		Get an incident
+//	@Description	Get an incident together with its notes
+//	@Tags			incidents
+//	@Produce		json
+//	@Param			incidentId			path		int					true	"Incident ID"
+//	@Param			X-Organization-ID	header		int					false	"Organization to act in"
+//	@Success		200					{object}	Incident			"Incident"
+//	@Failure		400					{object}	map[string]string	"Bad request"
+//	@Failure		403					{object}	map[string]string	"Forbidden"
+//	@Failure		404					{object}	map[string]string	"Incident not found"
+//	@Failure		500					{object}	map[string]string	"Internal server error"
+//	@Router			/incidents/{incidentId} [get]
+func (h *IncidentHandler) GetIncident(ctx *gin.Context) {
+	owner, ok := h.owner(ctx)
+	if !ok {
+		return
+	}
+	incidentID, ok := h.idParam(ctx)
+	if !ok {
+		return
+	}
+
+	incident, err := h.service.Get(ctx.Request.Context(), owner, incidentID)
+	if err != nil {
+		h.respondError(ctx, "failed to get incident", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, incident)
+}
+
+// AcknowledgeIncident godoc
+//
+//	@Security		BearerAuth
+//	@Summary		Acknowledge an incident
+//	@Description	Record that you are handling an incident. The DOWN alerts of its service are acknowledged too, so they are not escalated.
+//	@Tags			incidents
+//	@Produce		json
+//	@Param			incidentId			path		int					true	"Incident ID"
+//	@Param			X-Organization-ID	header		int					false	"Organization to act in"
+//	@Success		200					{object}	Incident			"Acknowledged incident"
+//	@Failure		400					{object}	map[string]string	"Bad request"
+//	@Failure		403					{object}	map[string]string	"Forbidden"
+//	@Failure		404					{object}	map[string]string	"Incident not found"
+//	@Failure		500					{object}	map[string]string	"Internal server error"
+//	@Router			/incidents/{incidentId}/acknowledge [post]
+func (h *IncidentHandler) AcknowledgeIncident(ctx *gin.Context) {
+	owner, ok := h.owner(ctx)
+	if !ok {
+		return
+	}
+	incidentID, ok := h.idParam(ctx)
+	if !ok {
+		return
+	}
+
+	incident, err := h.service.Acknowledge(ctx.Request.Context(), owner, incidentID, owner.UserID)
+	if err != nil {
+		h.respondError(ctx, "failed to acknowledge incident", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, incident)
+}
+
+// ResolveIncident godoc
+//
+//	@Security		BearerAuth
+//	@Summary		Resolve an incident
+//	@Description	Resolve an incident before its service recovers. Requires the editor role. Resolving a resolved incident changes nothing.
+//	@Tags			incidents
+//	@Produce		json
+//	@Param			incidentId			path		int					true	"Incident ID"
+//	@Param			X-Organization-ID	header		int					false	"Organization to act in"
+//	@Success		200					{object}	Incident			"Resolved incident"
+//	@Failure		400					{object}	map[string]string	"Bad request"
+//	@Failure		403					{object}	map[string]string	"Forbidden"
+//	@Failure		404					{object}	map[string]string	"Incident not found"
+//	@Failure		500					{object}	map[string]string	"Internal server error"
+//	@Router			/incidents/{incidentId}/resolve [post]
+func (h *IncidentHandler) ResolveIncident(ctx *gin.Context) {
+	owner, ok := h.owner(ctx)
+	if !ok {
+		return
+	}
+	incidentID, ok := h.idParam(ctx)
+	if !ok {
+		return
+	}
+
+	incident, err := h.service.Resolve(ctx.Request.Context(), owner, incidentID, owner.UserID)
+	if err != nil {
+		h.respondError(ctx, "failed to resolve incident", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, incident)
+}
+
+// AddNote godoc
+//
+//	@Security		BearerAuth
+//	@Summary		Add a note to an incident
+//	@Description	Leave a note on an incident, such as what was found or done
+//	@Tags			incidents
+//	@Accept			json
+//	@Produce		json
+//	@Param			incidentId			path		int					true	"Incident ID"
+//	@Param			note				body		CreateNoteDTO		true	"Note"
+//	@Param			X-Organization-ID	header		int					false	"Organization to act in"
+//	@Success		201					{object}	Note				"Created note"
+//	@Failure		400					{object}	map[string]string	"Bad request"
+//	@Failure		403					{object}	map[string]string	"Forbidden"
+//	@Failure		404					{object}	map[string]string	"Incident not found"
+//	@Failure		500					{object}	map[string]string	"Internal server error"
+//	@Router			/incidents/{incidentId}/notes [post]
+func (h *IncidentHandler) AddNote(ctx *gin.Context) {
+	owner, ok := h.owner(ctx)
+	if !ok {
+		return
+	}
+	incidentID, ok := h.idParam(ctx)
+	if !ok {
+		return
+	}
+
+	var body CreateNoteDTO
+	if err := ctx.ShouldBindJSON(&body); err != nil {
+		h.logger.Error("failed to bind json", zap.Error(err))
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	note, err := h.service.AddNote(ctx.Request.Context(), owner, incidentID, owner.UserID, body)
+	if err != nil {
+		h.respondError(ctx, "failed to add incident note", err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, note)
+}
+
+// owner returns whose incidents the request acts on, responding 401 when it
+// carries no user.
+func (h *IncidentHandler) owner(ctx *gin.Context) (monitor.Owner, bool) {
+	userID, ok := middleware.UserID(ctx.Request.Context())
+	if !ok {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return monitor.Owner{}, false
+	}
+	orgID, _ := middleware.OrgID(ctx.Request.Context())
+	return monitor.Owner{UserID: userID, OrgID: orgID}, true
+}
+
+// idParam parses the incident ID, responding 400 when it is not an integer.
+func (h *IncidentHandler) idParam(ctx *gin.Context) (int, bool) {
+	id, err := strconv.Atoi(ctx.Param("incidentId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "incidentId must be an integer"})
+		return 0, false
+	}
+	return id, true
+}
+
+// respondError maps incident errors onto HTTP status codes.
+func (h *IncidentHandler) respondError(ctx *gin.Context, msg string, err error) {
+	if errors.Is(err, ErrIncidentNotFound) {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	h.logger.Error(msg, zap.Error(err))
+	ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+}
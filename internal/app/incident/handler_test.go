@@ -0,0 +1,224 @@
+package incident
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"health-checker/internal/middleware"
+	"health-checker/internal/middleware/middlewaretest"
+	"health-checker/internal/monitor"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+)
+
+type MockRepository struct {
+	mock.Mock
+}
+
+func (m *MockRepository) Open(ctx context.Context, serviceID int, startedAt time.Time, rootError string) error {
+	args := m.Called(ctx, serviceID, startedAt, rootError)
+	return args.Error(0)
+}
+
+func (m *MockRepository) Close(ctx context.Context, serviceID int, resolvedAt time.Time) error {
+	args := m.Called(ctx, serviceID, resolvedAt)
+	return args.Error(0)
+}
+
+func (m *MockRepository) List(ctx context.Context, owner monitor.Owner, filter ListFilter) ([]Incident, error) {
+	args := m.Called(ctx, owner, filter)
+	return args.Get(0).([]Incident), args.Error(1)
+}
+
+func (m *MockRepository) Get(ctx context.Context, owner monitor.Owner, id int) (Incident, error) {
+	args := m.Called(ctx, owner, id)
+	return args.Get(0).(Incident), args.Error(1)
+}
+
+func (m *MockRepository) Acknowledge(ctx context.Context, owner monitor.Owner, id, userID int) (Incident, error) {
+	args := m.Called(ctx, owner, id, userID)
+	return args.Get(0).(Incident), args.Error(1)
+}
+
+func (m *MockRepository) Resolve(ctx context.Context, owner monitor.Owner, id, userID int) (Incident, error) {
+	args := m.Called(ctx, owner, id, userID)
+	return args.Get(0).(Incident), args.Error(1)
+}
+
+func (m *MockRepository) AddNote(ctx context.Context, owner monitor.Owner, id, userID int, body string) (Note, error) {
+	args := m.Called(ctx, owner, id, userID, body)
+	return args.Get(0).(Note), args.Error(1)
+}
+
+func (m *MockRepository) ListNotes(ctx context.Context, incidentID int) ([]Note, error) {
+	args := m.Called(ctx, incidentID)
+	return args.Get(0).([]Note), args.Error(1)
+}
+
+type stubRoles map[int]middleware.Role
+
+func (s stubRoles) MemberRole(ctx context.Context, orgID, userID int) (middleware.Role, error) {
+	return s[userID], nil
+}
+
+// setupRouter serves the incident routes behind the real auth middleware;
+// user 5 is a viewer and user 6 an editor of every organization.
+func setupRouter(mockRepo *MockRepository) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	os.Setenv("JWT_SECRET", "test-secret")
+
+	r := gin.New()
+	handler := NewHandler(NewService(mockRepo, zap.NewNop()), stubRoles{5: middleware.RoleViewer, 6: middleware.RoleEditor}, middlewaretest.Denylist{}, zap.NewNop())
+	handler.RegisterRoutes(r.Group("/incidents"))
+	return r
+}
+
+func authorizedRequest(method, path string, body interface{}, userID int) *http.Request {
+	token, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"user_id": userID,
+		"exp":     time.Now().Add(time.Hour).Unix(),
+	}).SignedString([]byte("test-secret"))
+
+	var payload []byte
+	if body != nil {
+		payload, _ = json.Marshal(body)
+	}
+	req, _ := http.NewRequest(method, path, bytes.NewBuffer(payload))
+	req.Header.Set("Authorization", "Bearer "+token)
+	return req
+}
+
+func TestListIncidents(t *testing.T) {
+	t.Run("Filters", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		mockRepo.On("List", mock.Anything, monitor.Owner{UserID: 4}, mock.MatchedBy(func(f ListFilter) bool {
+			return f.ServiceID == 3 && f.Status == StatusOpen && f.From.Equal(time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)) &&
+				f.Page == 1 && f.Limit == defaultListLimit
+		})).Return([]Incident{{ID: 1, ServiceID: 3, RootError: "connection refused"}}, nil)
+
+		w := httptest.NewRecorder()
+		setupRouter(mockRepo).ServeHTTP(w, authorizedRequest("GET", "/incidents?service_id=3&status=open&from=2024-05-01T00:00:00Z", nil, 4))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"root_error":"connection refused"`)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("UnknownStatus", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+
+		w := httptest.NewRecorder()
+		setupRouter(mockRepo).ServeHTTP(w, authorizedRequest("GET", "/incidents?status=closed", nil, 4))
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		mockRepo.AssertNotCalled(t, "List")
+	})
+
+	t.Run("Unauthenticated", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		setupRouter(new(MockRepository)).ServeHTTP(w, httptest.NewRequest("GET", "/incidents", nil))
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+}
+
+func TestGetIncident(t *testing.T) {
+	t.Run("WithNotes", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		mockRepo.On("Get", mock.Anything, monitor.Owner{UserID: 4}, 1).Return(Incident{ID: 1}, nil)
+		mockRepo.On("ListNotes", mock.Anything, 1).Return([]Note{{ID: 2, Body: "Rolled back"}}, nil)
+
+		w := httptest.NewRecorder()
+		setupRouter(mockRepo).ServeHTTP(w, authorizedRequest("GET", "/incidents/1", nil, 4))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"body":"Rolled back"`)
+	})
+
+	t.Run("NotOwned", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		mockRepo.On("Get", mock.Anything, monitor.Owner{UserID: 4}, 2).Return(Incident{}, ErrIncidentNotFound)
+
+		w := httptest.NewRecorder()
+		setupRouter(mockRepo).ServeHTTP(w, authorizedRequest("GET", "/incidents/2", nil, 4))
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		mockRepo.AssertNotCalled(t, "ListNotes")
+	})
+}
+
+func TestAcknowledgeIncident(t *testing.T) {
+	mockRepo := new(MockRepository)
+	owner := monitor.Owner{UserID: 5, OrgID: 7}
+	mockRepo.On("Acknowledge", mock.Anything, owner, 1, 5).Return(Incident{ID: 1, AcknowledgedBy: 5}, nil)
+
+	w := httptest.NewRecorder()
+	req := authorizedRequest("POST", "/incidents/1/acknowledge", nil, 5)
+	req.Header.Set(middleware.OrganizationHeader, "7")
+	setupRouter(mockRepo).ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"acknowledged_by":5`)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestResolveIncident(t *testing.T) {
+	t.Run("Editor", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		owner := monitor.Owner{UserID: 6, OrgID: 7}
+		mockRepo.On("Resolve", mock.Anything, owner, 1, 6).Return(Incident{ID: 1, ResolvedBy: 6}, nil)
+
+		w := httptest.NewRecorder()
+		req := authorizedRequest("POST", "/incidents/1/resolve", nil, 6)
+		req.Header.Set(middleware.OrganizationHeader, "7")
+		setupRouter(mockRepo).ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"resolved_by":6`)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("ViewerForbidden", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+
+		w := httptest.NewRecorder()
+		req := authorizedRequest("POST", "/incidents/1/resolve", nil, 5)
+		req.Header.Set(middleware.OrganizationHeader, "7")
+		setupRouter(mockRepo).ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		mockRepo.AssertNotCalled(t, "Resolve")
+	})
+}
+
+func TestAddNote(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		mockRepo.On("AddNote", mock.Anything, monitor.Owner{UserID: 4}, 1, 4, "Rolled back").Return(Note{ID: 2, IncidentID: 1}, nil)
+
+		w := httptest.NewRecorder()
+		setupRouter(mockRepo).ServeHTTP(w, authorizedRequest("POST", "/incidents/1/notes", CreateNoteDTO{Body: "Rolled back"}, 4))
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("EmptyBody", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+
+		w := httptest.NewRecorder()
+		setupRouter(mockRepo).ServeHTTP(w, authorizedRequest("POST", "/incidents/1/notes", CreateNoteDTO{}, 4))
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		mockRepo.AssertNotCalled(t, "AddNote")
+	})
+}
@@ -0,0 +1,239 @@
+package incident
+
+import (
+	"context"
+	"fmt"
+	"health-checker/internal/monitor"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type Repository interface {
+	Open(ctx context.Context, serviceID int, startedAt time.Time, rootError string) error
+	Close(ctx context.Context, serviceID int, resolvedAt time.Time) error
+	List(ctx context.Context, owner monitor.Owner, filter ListFilter) ([]Incident, error)
+	Get(ctx context.Context, owner monitor.Owner, id int) (Incident, error)
+	Acknowledge(ctx context.Context, owner monitor.Owner, id, userID int) (Incident, error)
+	Resolve(ctx context.Context, owner monitor.Owner, id, userID int) (Incident, error)
+	AddNote(ctx context.Context, owner monitor.Owner, id, userID int, body string) (Note, error)
+	ListNotes(ctx context.Context, incidentID int) ([]Note, error)
+}
+
+// incidentColumns are selected from incidents i joined with their service s.
+const incidentColumns = `i.id, i.service_id, s.name, i.started_at, i.resolved_at,
+	extract(epoch from coalesce(i.resolved_at, now()) - i.started_at)::bigint, i.root_error, i.acknowledged_at,
+	coalesce(i.acknowledged_by, 0), coalesce(i.resolved_by, 0), i.created_at`
+
+const noteColumns = `id, incident_id, coalesce(user_id, 0), body, created_at`
+
+// ownedBy restricts a query to incidents of the services of an Owner whose
+// OrgID and UserID are bound at positions arg and arg+1.
+func ownedBy(arg int) string {
+	return fmt.Sprintf("coalesce(s.organization_id, 0) = $%d and ($%d <> 0 or s.user_id = $%d)", arg, arg, arg+1)
+}
+
+type PostgresRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewRepository(db *pgxpool.Pool) Repository {
+	return &PostgresRepository{db: db}
+}
+
+// Open starts an incident for a service unless one is already open, or the
+// service recovered after startedAt: events are delivered concurrently, so
+// the recovery that ended this outage may have been handled first.
+func (r *PostgresRepository) Open(ctx context.Context, serviceID int, startedAt time.Time, rootError string) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	recoveredAt, err := lockRecovery(ctx, tx, serviceID)
+	if err != nil {
+		return err
+	}
+	if recoveredAt != nil && startedAt.Before(*recoveredAt) {
+		return tx.Commit(ctx)
+	}
+
+	query := `
+		insert into incidents (service_id, started_at, root_error)
+		values ($1, $2, $3)
+		on conflict (service_id) where resolved_at is null do nothing
+	`
+	if _, err := tx.Exec(ctx, query, serviceID, startedAt, rootError); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+// Close resolves the open incident of a service that recovered, if any, and
+// records the recovery for Open.
+func (r *PostgresRepository) Close(ctx context.Context, serviceID int, resolvedAt time.Time) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	recoveredAt, err := lockRecovery(ctx, tx, serviceID)
+	if err != nil {
+		return err
+	}
+	if recoveredAt == nil || resolvedAt.After(*recoveredAt) {
+		if _, err := tx.Exec(ctx, `update incident_recoveries set recovered_at = $2 where service_id = $1`, serviceID, resolvedAt); err != nil {
+			return err
+		}
+	}
+
+	query := `update incidents set resolved_at = $2 where service_id = $1 and resolved_at is null and started_at <= $2`
+	if _, err := tx.Exec(ctx, query, serviceID, resolvedAt); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+// lockRecovery locks the recovery row of a service, creating it if needed, so
+// that Open and Close run one at a time per service. It returns when the
+// service last recovered, or nil when it never did.
+func lockRecovery(ctx context.Context, tx pgx.Tx, serviceID int) (*time.Time, error) {
+	query := `
+		insert into incident_recoveries (service_id, recovered_at)
+		values ($1, '-infinity')
+		on conflict (service_id) do update set recovered_at = incident_recoveries.recovered_at
+		returning nullif(recovered_at, '-infinity')
+	`
+	var recoveredAt *time.Time
+	err := tx.QueryRow(ctx, query, serviceID).Scan(&recoveredAt)
+	return recoveredAt, err
+}
+
+// List returns owner's incidents matching filter, latest first.
+func (r *PostgresRepository) List(ctx context.Context, owner monitor.Owner, filter ListFilter) ([]Incident, error) {
+	query := `
+		select ` + incidentColumns + `
+		from incidents i
+		join services s on s.id = i.service_id
+		where ` + ownedBy(1) + `
+			and ($3 = 0 or i.service_id = $3)
+			and ($4 = '' or ($4 = 'open') = (i.resolved_at is null))
+			and ($5::timestamptz is null or i.started_at >= $5)
+			and ($6::timestamptz is null or i.started_at < $6)
+		order by i.started_at desc, i.id desc
+		limit $7 offset $8
+	`
+	rows, err := r.db.Query(ctx, query, owner.OrgID, owner.UserID, filter.ServiceID, filter.Status, filter.From,
+		filter.To, filter.Limit, (filter.Page-1)*filter.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	incidents := []Incident{}
+	for rows.Next() {
+		incident, err := scanIncident(rows)
+		if err != nil {
+			return nil, err
+		}
+		incidents = append(incidents, incident)
+	}
+	return incidents, rows.Err()
+}
+
+func (r *PostgresRepository) Get(ctx context.Context, owner monitor.Owner, id int) (Incident, error) {
+	query := `
+		select ` + incidentColumns + `
+		from incidents i
+		join services s on s.id = i.service_id
+		where i.id = $1 and ` + ownedBy(2)
+	incident, err := scanIncident(r.db.QueryRow(ctx, query, id, owner.OrgID, owner.UserID))
+	if err == pgx.ErrNoRows {
+		return Incident{}, ErrIncidentNotFound
+	}
+	return incident, err
+}
+
+// Acknowledge records the first acknowledgement of an incident; acknowledging
+// it again changes nothing.
+func (r *PostgresRepository) Acknowledge(ctx context.Context, owner monitor.Owner, id, userID int) (Incident, error) {
+	query := `
+		update incidents i
+		set acknowledged_at = coalesce(i.acknowledged_at, now()),
+			acknowledged_by = coalesce(i.acknowledged_by, $2)
+		from services s
+		where i.id = $1 and s.id = i.service_id and ` + ownedBy(3) + `
+		returning ` + incidentColumns
+	incident, err := scanIncident(r.db.QueryRow(ctx, query, id, userID, owner.OrgID, owner.UserID))
+	if err == pgx.ErrNoRows {
+		return Incident{}, ErrIncidentNotFound
+	}
+	return incident, err
+}
+
+// Resolve closes an open incident by hand; resolving a closed one changes nothing.
+func (r *PostgresRepository) Resolve(ctx context.Context, owner monitor.Owner, id, userID int) (Incident, error) {
+	query := `
+		update incidents i
+		set resolved_at = coalesce(i.resolved_at, now()),
+			resolved_by = case when i.resolved_at is null then $2 else i.resolved_by end
+		from services s
+		where i.id = $1 and s.id = i.service_id and ` + ownedBy(3) + `
+		returning ` + incidentColumns
+	incident, err := scanIncident(r.db.QueryRow(ctx, query, id, userID, owner.OrgID, owner.UserID))
+	if err == pgx.ErrNoRows {
+		return Incident{}, ErrIncidentNotFound
+	}
+	return incident, err
+}
+
+func (r *PostgresRepository) AddNote(ctx context.Context, owner monitor.Owner, id, userID int, body string) (Note, error) {
+	query := `
+		insert into incident_notes (incident_id, user_id, body)
+		select i.id, $2, $3
+		from incidents i
+		join services s on s.id = i.service_id
+		where i.id = $1 and ` + ownedBy(4) + `
+		returning ` + noteColumns
+	note, err := scanNote(r.db.QueryRow(ctx, query, id, userID, body, owner.OrgID, owner.UserID))
+	if err == pgx.ErrNoRows {
+		return Note{}, ErrIncidentNotFound
+	}
+	return note, err
+}
+
+// ListNotes returns the notes of an incident, oldest first.
+func (r *PostgresRepository) ListNotes(ctx context.Context, incidentID int) ([]Note, error) {
+	rows, err := r.db.Query(ctx, `select `+noteColumns+` from incident_notes where incident_id = $1 order by id`, incidentID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	notes := []Note{}
+	for rows.Next() {
+		note, err := scanNote(rows)
+		if err != nil {
+			return nil, err
+		}
+		notes = append(notes, note)
+	}
+	return notes, rows.Err()
+}
+
+func scanIncident(row pgx.Row) (Incident, error) {
+	var incident Incident
+	err := row.Scan(&incident.ID, &incident.ServiceID, &incident.ServiceName, &incident.StartedAt, &incident.ResolvedAt,
+		&incident.DurationSeconds, &incident.RootError, &incident.AcknowledgedAt, &incident.AcknowledgedBy,
+		&incident.ResolvedBy, &incident.CreatedAt)
+	return incident, err
+}
+
+func scanNote(row pgx.Row) (Note, error) {
+	var note Note
+	err := row.Scan(&note.ID, &note.IncidentID, &note.UserID, &note.Body, &note.CreatedAt)
+	return note, err
+}
@@ -0,0 +1,108 @@
+package incident
+
+import (
+	"context"
+	"health-checker/internal/monitor"
+
+	"go.uber.org/zap"
+)
+
+const defaultListLimit = 20
+
+// EscalationAcknowledger acknowledges the open alerts of a service, so that
+// acknowledging its incident also stops them from being escalated.
+type EscalationAcknowledger interface {
+	AcknowledgeServiceEscalations(ctx context.Context, serviceID, userID int) error
+}
+
+type IncidentService struct {
+	repo        Repository
+	escalations EscalationAcknowledger
+	log         *zap.Logger
+}
+
+func NewService(repo Repository, log *zap.Logger) *IncidentService {
+	return &IncidentService{repo: repo, log: log}
+}
+
+// SetEscalations sets what acknowledging an incident acknowledges alerts with.
+func (s *IncidentService) SetEscalations(escalations EscalationAcknowledger) {
+	s.escalations = escalations
+}
+
+// HandleStatusChange is subscribed to the EventBus's "StatusChange" events. It
+// opens an incident when a service goes DOWN and closes it when the service
+// leaves DOWN. Events are handled concurrently, so a recovery may be handled
+// before the DOWN it ended; Repository.Open then leaves the incident unopened.
+func (s *IncidentService) HandleStatusChange(ctx context.Context, event monitor.Event) {
+	change, ok := event.(monitor.StatusChangeEvent)
+	if !ok {
+		return
+	}
+	// Heartbeat events are published with the ping request's context.
+	ctx = context.WithoutCancel(ctx)
+
+	switch {
+	case change.NewStatus == monitor.StatusDown:
+		// Since is the first failed check when the failure took several to confirm.
+		startedAt := change.Since
+		if startedAt.IsZero() {
+			startedAt = change.Timestamp
+		}
+		if err := s.repo.Open(ctx, change.ServiceID, startedAt, change.Message); err != nil {
+			s.log.Error("failed to open incident", zap.Int("service_id", change.ServiceID), zap.Error(err))
+		}
+	case change.OldStatus == monitor.StatusDown:
+		if err := s.repo.Close(ctx, change.ServiceID, change.Timestamp); err != nil {
+			s.log.Error("failed to close incident", zap.Int("service_id", change.ServiceID), zap.Error(err))
+		}
+	}
+}
+
+// List returns a page of owner's incidents, 20 by default.
+func (s *IncidentService) List(ctx context.Context, owner monitor.Owner, filter ListFilter) ([]Incident, error) {
+	if filter.Page <= 0 {
+		filter.Page = 1
+	}
+	if filter.Limit <= 0 {
+		filter.Limit = defaultListLimit
+	}
+	return s.repo.List(ctx, owner, filter)
+}
+
+// Get returns an incident together with its notes.
+func (s *IncidentService) Get(ctx context.Context, owner monitor.Owner, id int) (Incident, error) {
+	incident, err := s.repo.Get(ctx, owner, id)
+	if err != nil {
+		return Incident{}, err
+	}
+	if incident.Notes, err = s.repo.ListNotes(ctx, id); err != nil {
+		return Incident{}, err
+	}
+	return incident, nil
+}
+
+// Acknowledge records that userID is handling an incident and acknowledges
+// the service's alerts so they are not escalated.
+func (s *IncidentService) Acknowledge(ctx context.Context, owner monitor.Owner, id, userID int) (Incident, error) {
+	incident, err := s.repo.Acknowledge(ctx, owner, id, userID)
+	if err != nil {
+		return Incident{}, err
+	}
+	if s.escalations != nil && incident.ResolvedAt == nil {
+		if err := s.escalations.AcknowledgeServiceEscalations(ctx, incident.ServiceID, userID); err != nil {
+			s.log.Error("failed to acknowledge escalations", zap.Int("incident_id", id), zap.Error(err))
+		}
+	}
+	return incident, nil
+}
+
+// Resolve closes an incident before its service recovers, such as when the
+// failure is known to be harmless. The next DOWN change opens a new incident.
+func (s *IncidentService) Resolve(ctx context.Context, owner monitor.Owner, id, userID int) (Incident, error) {
+	return s.repo.Resolve(ctx, owner, id, userID)
+}
+
+func (s *IncidentService) AddNote(ctx context.Context, owner monitor.Owner, id, userID int, dto CreateNoteDTO) (Note, error) {
+	return s.repo.AddNote(ctx, owner, id, userID, dto.Body)
+}
@@ -0,0 +1,101 @@
+package incident
+
+import (
+	"context"
+	"errors"
+	"health-checker/internal/monitor"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+)
+
+type stubEscalations struct {
+	serviceID, userID int
+}
+
+func (s *stubEscalations) AcknowledgeServiceEscalations(ctx context.Context, serviceID, userID int) error {
+	s.serviceID, s.userID = serviceID, userID
+	return nil
+}
+
+func TestService_HandleStatusChange(t *testing.T) {
+	at := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("DownOpens", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		mockRepo.On("Open", mock.Anything, 3, at, "connection refused").Return(nil)
+
+		NewService(mockRepo, zap.NewNop()).HandleStatusChange(context.Background(), monitor.StatusChangeEvent{
+			ServiceID: 3, OldStatus: "UP", NewStatus: "DOWN", Message: "connection refused", Timestamp: at,
+		})
+
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("StartsAtFirstFailure", func(t *testing.T) {
+		firstFailure := at.Add(-2 * time.Minute)
+		mockRepo := new(MockRepository)
+		mockRepo.On("Open", mock.Anything, 3, firstFailure, "timeout").Return(nil)
+
+		NewService(mockRepo, zap.NewNop()).HandleStatusChange(context.Background(), monitor.StatusChangeEvent{
+			ServiceID: 3, OldStatus: "UP", NewStatus: "DOWN", Message: "timeout", Timestamp: at, Since: firstFailure,
+		})
+
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("RecoveryCloses", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		mockRepo.On("Close", mock.Anything, 3, at).Return(nil)
+
+		NewService(mockRepo, zap.NewNop()).HandleStatusChange(context.Background(), monitor.StatusChangeEvent{
+			ServiceID: 3, OldStatus: "DOWN", NewStatus: "UP", Timestamp: at,
+		})
+
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("OtherChangesIgnored", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+
+		NewService(mockRepo, zap.NewNop()).HandleStatusChange(context.Background(), monitor.StatusChangeEvent{
+			ServiceID: 3, OldStatus: "UP", NewStatus: "DEGRADED", Timestamp: at,
+		})
+
+		mockRepo.AssertNotCalled(t, "Open")
+		mockRepo.AssertNotCalled(t, "Close")
+	})
+}
+
+func TestService_Acknowledge(t *testing.T) {
+	owner := monitor.Owner{UserID: 4}
+
+	t.Run("AcknowledgesEscalations", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		mockRepo.On("Acknowledge", mock.Anything, owner, 1, 4).Return(Incident{ID: 1, ServiceID: 3}, nil)
+		escalations := &stubEscalations{}
+		service := NewService(mockRepo, zap.NewNop())
+		service.SetEscalations(escalations)
+
+		_, err := service.Acknowledge(context.Background(), owner, 1, 4)
+
+		assert.NoError(t, err)
+		assert.Equal(t, stubEscalations{serviceID: 3, userID: 4}, *escalations)
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		mockRepo.On("Acknowledge", mock.Anything, owner, 2, 4).Return(Incident{}, ErrIncidentNotFound)
+		escalations := &stubEscalations{}
+		service := NewService(mockRepo, zap.NewNop())
+		service.SetEscalations(escalations)
+
+		_, err := service.Acknowledge(context.Background(), owner, 2, 4)
+
+		assert.True(t, errors.Is(err, ErrIncidentNotFound))
+		assert.Zero(t, escalations.serviceID)
+	})
+}
@@ -0,0 +1,238 @@
+package notification
+
+import (
+	"errors"
+	"health-checker/internal/monitor"
+	"net/url"
+	"time"
+)
+
+const (
+	ChannelTypeWebhook  = "webhook"
+	ChannelTypeEmail    = "email"
+	ChannelTypeSlack    = "slack"
+	ChannelTypeDiscord  = "discord"
+	ChannelTypeTeams    = "teams"
+	ChannelTypeTelegram = "telegram"
+)
+
+// Severities of a status change, see SeverityOf.
+const (
+	SeverityCritical = "critical"
+	SeverityWarning  = "warning"
+	SeverityInfo     = "info"
+)
+
+var (
+	ErrChannelNotFound    = errors.New("notification channel not found")
+	ErrInvalidChannel     = errors.New("invalid notification channel")
+	ErrPolicyNotFound     = errors.New("notification policy not found")
+	ErrInvalidPolicy      = errors.New("invalid notification policy")
+	ErrEscalationNotFound = errors.New("escalation not found")
+)
+
+// Channel is a destination for status change notifications. It is notified
+// about its organization's services when OrganizationID is set, otherwise about
+// its user's personal services.
+type Channel struct {
+	ID             int           `json:"id" db:"id"`
+	UserID         int           `json:"user_id" db:"user_id"`
+	OrganizationID int           `json:"organization_id,omitempty" db:"organization_id"`
+	Name           string        `json:"name" db:"name"`
+	Type           string        `json:"type" db:"type" example:"webhook"`
+	Config         ChannelConfig `json:"config" db:"config"`
+	// ServiceIDs limits the channel to these services; empty means all of them.
+	ServiceIDs []int     `json:"service_ids" db:"service_ids"`
+	CreatedAt  time.Time `json:"created_at" db:"created_at"`
+
+	// EncryptedSecret is the sealed webhook signing secret, incoming webhook
+	// URL or Telegram bot token; it never leaves the server.
+	EncryptedSecret string `json:"-" db:"secret_encrypted"`
+}
+
+// Covers reports whether the channel is notified about serviceID.
+func (c Channel) Covers(serviceID int) bool {
+	if len(c.ServiceIDs) == 0 {
+		return true
+	}
+	for _, id := range c.ServiceIDs {
+		if id == serviceID {
+			return true
+		}
+	}
+	return false
+}
+
+// ChannelConfig holds the type-specific settings of a channel. Only the block
+// matching the channel's type is read.
+type ChannelConfig struct {
+	Webhook  *WebhookConfig         `json:"webhook,omitempty"`
+	Email    *EmailConfig           `json:"email,omitempty"`
+	Slack    *IncomingWebhookConfig `json:"slack,omitempty"`
+	Discord  *IncomingWebhookConfig `json:"discord,omitempty"`
+	Teams    *IncomingWebhookConfig `json:"teams,omitempty"`
+	Telegram *TelegramConfig        `json:"telegram,omitempty"`
+}
+
+// incomingWebhook returns the config of a channel type posting through an
+// incoming webhook, or nil for the other types.
+func (c ChannelConfig) incomingWebhook(channelType string) *IncomingWebhookConfig {
+	switch channelType {
+	case ChannelTypeSlack:
+		return c.Slack
+	case ChannelTypeDiscord:
+		return c.Discord
+	case ChannelTypeTeams:
+		return c.Teams
+	}
+	return nil
+}
+
+// withIncomingWebhook returns the config with the incoming webhook block of
+// channelType replaced.
+func (c ChannelConfig) withIncomingWebhook(channelType string, webhook *IncomingWebhookConfig) ChannelConfig {
+	switch channelType {
+	case ChannelTypeSlack:
+		c.Slack = webhook
+	case ChannelTypeDiscord:
+		c.Discord = webhook
+	case ChannelTypeTeams:
+		c.Teams = webhook
+	}
+	return c
+}
+
+type WebhookConfig struct {
+	URL string `json:"url" binding:"required,url" example:"https://example.com/hooks/health"`
+}
+
+type EmailConfig struct {
+	Recipients []string `json:"recipients" binding:"required,min=1,dive,email" example:"oncall@example.com"`
+}
+
+// IncomingWebhookConfig is the incoming webhook URL a chat service gives out
+// for posting into one of its channels. Anyone holding the URL can post, so it
+// is only accepted on creation; it is stored encrypted and only URLHint, its
+// host and last characters, is returned.
+type IncomingWebhookConfig struct {
+	URL     string `json:"url,omitempty" binding:"required,url" example:"https://hooks.slack.com/services/T000/B000/XXXX"`
+	URLHint string `json:"url_hint,omitempty" example:"hooks.slack.com/…XXXX"`
+}
+
+// sealed returns the config to store and return once the URL is encrypted.
+func (c IncomingWebhookConfig) sealed() *IncomingWebhookConfig {
+	hint := "…"
+	if len(c.URL) > 4 {
+		hint += c.URL[len(c.URL)-4:]
+	}
+	if u, err := url.Parse(c.URL); err == nil && u.Host != "" {
+		hint = u.Host + "/" + hint
+	}
+	return &IncomingWebhookConfig{URLHint: hint}
+}
+
+// TelegramConfig is the chat a bot posts to. BotToken is only accepted on
+// creation; it is stored encrypted and never returned.
+type TelegramConfig struct {
+	ChatID   string `json:"chat_id" binding:"required" example:"-1001234567890"`
+	BotToken string `json:"bot_token,omitempty" binding:"required" example:"123456:ABC-DEF1234ghIkl-zyx57W2v1u123ew11"`
+}
+
+type CreateChannelDTO struct {
+	Name       string        `json:"name" binding:"required,max=255" example:"Ops webhook"`
+	Type       string        `json:"type" binding:"required,oneof=webhook email slack discord teams telegram" example:"webhook"`
+	Config     ChannelConfig `json:"config"`
+	ServiceIDs []int         `json:"service_ids,omitempty" binding:"omitempty,dive,min=1"`
+}
+
+// CreatedChannel is returned once, on creation; it is the only time the
+// webhook signing secret is shown.
+type CreatedChannel struct {
+	Channel
+	Secret string `json:"secret,omitempty" example:"whsec_3f1c9a0e5b7d42c8a6e1f0b9d3c7a5e2b4d6f8a0c2e4f6a8"`
+}
+
+// Notification is the payload sent for a status change.
+type Notification struct {
+	Event       string         `json:"event" example:"status_change"`
+	ServiceID   int            `json:"service_id"`
+	ServiceName string         `json:"service_name"`
+	OldStatus   monitor.Status `json:"old_status" example:"UP"`
+	NewStatus   monitor.Status `json:"new_status" example:"DOWN"`
+	Severity    string         `json:"severity" example:"critical"`
+	LatencyMs   int            `json:"latency_ms" example:"1203"`
+	// URL links back to the service; it is empty unless a public URL is set.
+	URL       string    `json:"url,omitempty" example:"https://health.example.com/api/v1/services/1"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// Delivery records one attempt to send a notification over a channel.
+type Delivery struct {
+	ID         int          `json:"id" db:"id"`
+	ChannelID  int          `json:"channel_id" db:"channel_id"`
+	ServiceID  int          `json:"service_id" db:"service_id"`
+	Payload    Notification `json:"payload" db:"payload"`
+	Attempt    int          `json:"attempt" db:"attempt"`
+	Success    bool         `json:"success" db:"success"`
+	StatusCode int          `json:"status_code,omitempty" db:"status_code"`
+	Error      string       `json:"error,omitempty" db:"error"`
+	DurationMs int          `json:"duration_ms" db:"duration_ms"`
+	CreatedAt  time.Time    `json:"created_at" db:"created_at"`
+}
+
+// Policy routes the status changes of an owner's services to some of its
+// channels. Once an owner has a policy, a change is only sent to the channels
+// of the policies it matches. Empty Tags, Severities and Schedule match
+// everything.
+type Policy struct {
+	ID             int    `json:"id" db:"id"`
+	UserID         int    `json:"user_id" db:"user_id"`
+	OrganizationID int    `json:"organization_id,omitempty" db:"organization_id"`
+	Name           string `json:"name" db:"name"`
+	// Tags match services carrying any of them.
+	Tags       []string  `json:"tags" db:"tags" example:"payments"`
+	Severities []string  `json:"severities" db:"severities" example:"critical"`
+	Schedule   *Schedule `json:"schedule,omitempty" db:"schedule"`
+	ChannelIDs []int     `json:"channel_ids" db:"channel_ids"`
+	// EscalationChannelIDs are notified when a DOWN alert routed by the policy
+	// is not acknowledged within EscalateAfterMinutes.
+	EscalationChannelIDs []int     `json:"escalation_channel_ids" db:"escalation_channel_ids"`
+	EscalateAfterMinutes int       `json:"escalate_after_minutes,omitempty" db:"escalate_after_minutes"`
+	CreatedAt            time.Time `json:"created_at" db:"created_at"`
+}
+
+// Schedule limits a policy to some hours of some days. A window whose End is
+// before its Start runs past midnight.
+type Schedule struct {
+	// Timezone is an IANA zone name; it defaults to UTC.
+	Timezone string `json:"timezone,omitempty" example:"Europe/Berlin"`
+	// Days are the weekdays the window opens on, 0 being Sunday; empty means every day.
+	Days  []int  `json:"days,omitempty" binding:"omitempty,dive,min=0,max=6" example:"1,2,3,4,5"`
+	Start string `json:"start,omitempty" example:"09:00"`
+	End   string `json:"end,omitempty" example:"18:00"`
+}
+
+type CreatePolicyDTO struct {
+	Name                 string    `json:"name" binding:"required,max=255" example:"Payments business hours"`
+	Tags                 []string  `json:"tags,omitempty" binding:"omitempty,dive,min=1,max=64" example:"payments"`
+	Severities           []string  `json:"severities,omitempty" binding:"omitempty,dive,oneof=critical warning info" example:"critical"`
+	Schedule             *Schedule `json:"schedule,omitempty"`
+	ChannelIDs           []int     `json:"channel_ids" binding:"required,min=1,dive,min=1" example:"1"`
+	EscalationChannelIDs []int     `json:"escalation_channel_ids,omitempty" binding:"omitempty,dive,min=1" example:"2"`
+	EscalateAfterMinutes int       `json:"escalate_after_minutes,omitempty" binding:"omitempty,min=1,max=1440" example:"15"`
+}
+
+// Escalation tracks a DOWN alert routed by a policy with escalation until it
+// is acknowledged, the service recovers, or it is escalated at DueAt.
+type Escalation struct {
+	ID             int          `json:"id" db:"id"`
+	PolicyID       int          `json:"policy_id" db:"policy_id"`
+	ServiceID      int          `json:"service_id" db:"service_id"`
+	Notification   Notification `json:"notification" db:"notification"`
+	DueAt          time.Time    `json:"due_at" db:"due_at"`
+	AcknowledgedAt *time.Time   `json:"acknowledged_at,omitempty" db:"acknowledged_at"`
+	AcknowledgedBy int          `json:"acknowledged_by,omitempty" db:"acknowledged_by"`
+	EscalatedAt    *time.Time   `json:"escalated_at,omitempty" db:"escalated_at"`
+	ResolvedAt     *time.Time   `json:"resolved_at,omitempty" db:"resolved_at"`
+	CreatedAt      time.Time    `json:"created_at" db:"created_at"`
+}
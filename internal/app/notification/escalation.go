@@ -0,0 +1,57 @@
+package notification
+
+import (
+	"context"
+	"health-checker/internal/monitor"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const defaultEscalationInterval = 30 * time.Second
+
+// RunEscalations escalates due, unacknowledged alerts to the second tier of
+// their policy until ctx is done.
+func (n *Notifier) RunEscalations(ctx context.Context) {
+	ticker := time.NewTicker(n.escalationInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			n.escalate(ctx)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// escalate sends every due escalation to its policy's escalation channels.
+func (n *Notifier) escalate(ctx context.Context) {
+	escalations, err := n.repo.ClaimDueEscalations(ctx)
+	if err != nil {
+		n.log.Error("failed to claim due escalations", zap.Error(err))
+		return
+	}
+
+	for _, escalation := range escalations {
+		policy, err := n.repo.GetPolicyByID(ctx, escalation.PolicyID)
+		if err != nil {
+			n.log.Error("failed to get escalation policy", zap.Int("escalation_id", escalation.ID), zap.Error(err))
+			continue
+		}
+		channels, err := n.repo.ListChannels(ctx, monitor.Owner{UserID: policy.UserID, OrgID: policy.OrganizationID})
+		if err != nil {
+			n.log.Error("failed to list notification channels", zap.Int("escalation_id", escalation.ID), zap.Error(err))
+			continue
+		}
+
+		n.log.Info("escalating unacknowledged alert",
+			zap.Int("escalation_id", escalation.ID),
+			zap.Int("service_id", escalation.ServiceID),
+		)
+		notification := escalation.Notification
+		notification.Event = "escalation"
+		n.send(ctx, filterChannels(channels, policy.EscalationChannelIDs), notification)
+	}
+}
@@ -0,0 +1,124 @@
+package notification
+
+import (
+	"context"
+	"health-checker/internal/monitor"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var (
+	policyOwner    = monitor.Owner{UserID: 4, OrgID: 7}
+	policyChannels = []Channel{{ID: 1, Type: "custom"}, {ID: 2, Type: "custom"}, {ID: 3, Type: "custom"}}
+)
+
+// newPolicyRepo returns a repository with policyChannels, the given policies
+// and a "payments" service 9, that accepts deliveries.
+func newPolicyRepo(policies ...Policy) *MockRepository {
+	mockRepo := new(MockRepository)
+	mockRepo.On("ListChannels", mock.Anything, policyOwner).Return(policyChannels, nil)
+	mockRepo.On("ListPolicies", mock.Anything, policyOwner).Return(policies, nil)
+	mockRepo.On("GetService", mock.Anything, 9).Return(ServiceInfo{Name: "API", Tags: []string{"payments"}}, nil)
+	mockRepo.On("CreateDelivery", mock.Anything, mock.Anything).Return(nil)
+	return mockRepo
+}
+
+func notifyChange(mockRepo *MockRepository, oldStatus, newStatus monitor.Status) {
+	notifier := newTestNotifier(mockRepo)
+	notifier.RegisterSender("custom", stubSender{})
+	notifier.HandleStatusChange(context.Background(), monitor.StatusChangeEvent{
+		ServiceID: 9, UserID: 4, OrgID: 7, OldStatus: oldStatus, NewStatus: newStatus, Timestamp: time.Now(),
+	})
+}
+
+func TestNotifier_Policies(t *testing.T) {
+	tests := []struct {
+		name     string
+		policies []Policy
+		want     []int
+	}{
+		{"no policies notify every channel", nil, []int{1, 2, 3}},
+		{"matching tag", []Policy{{ID: 1, Tags: []string{"payments"}, ChannelIDs: []int{2}}}, []int{2}},
+		{"other tag", []Policy{{ID: 1, Tags: []string{"search"}, ChannelIDs: []int{2}}}, nil},
+		{"matching policies add up", []Policy{
+			{ID: 1, Severities: []string{SeverityCritical}, ChannelIDs: []int{1, 2}},
+			{ID: 2, Tags: []string{"payments"}, ChannelIDs: []int{2, 3}},
+		}, []int{1, 2, 3}},
+		{"other severity", []Policy{{ID: 1, Severities: []string{SeverityWarning}, ChannelIDs: []int{1}}}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := newPolicyRepo(tt.policies...)
+
+			notifyChange(mockRepo, "UP", "DOWN")
+
+			assert.ElementsMatch(t, tt.want, deliveredTo(mockRepo))
+		})
+	}
+}
+
+func TestNotifier_OpensEscalation(t *testing.T) {
+	policy := Policy{ID: 5, ChannelIDs: []int{1}, EscalationChannelIDs: []int{3}, EscalateAfterMinutes: 15}
+	mockRepo := newPolicyRepo(policy)
+	mockRepo.On("CreateEscalation", mock.Anything, mock.MatchedBy(func(e Escalation) bool {
+		return e.PolicyID == 5 && e.ServiceID == 9 && e.Notification.ServiceName == "API" &&
+			time.Until(e.DueAt) > 14*time.Minute && time.Until(e.DueAt) <= 15*time.Minute
+	})).Return(nil).Once()
+
+	notifyChange(mockRepo, "UP", "DOWN")
+
+	assert.Equal(t, []int{1}, deliveredTo(mockRepo))
+	mockRepo.AssertExpectations(t)
+}
+
+func TestNotifier_RecoveryResolvesEscalations(t *testing.T) {
+	policy := Policy{ID: 5, ChannelIDs: []int{1}, EscalationChannelIDs: []int{3}, EscalateAfterMinutes: 15}
+	escalatedAt := time.Now().Add(-time.Minute)
+
+	t.Run("escalated", func(t *testing.T) {
+		mockRepo := newPolicyRepo(policy)
+		mockRepo.On("ResolveEscalations", mock.Anything, 9).Return([]Escalation{{ID: 1, PolicyID: 5, EscalatedAt: &escalatedAt}}, nil)
+
+		notifyChange(mockRepo, "DOWN", "UP")
+
+		assert.ElementsMatch(t, []int{1, 3}, deliveredTo(mockRepo))
+		mockRepo.AssertNotCalled(t, "CreateEscalation", mock.Anything, mock.Anything)
+	})
+
+	t.Run("acknowledged in time", func(t *testing.T) {
+		mockRepo := newPolicyRepo(policy)
+		mockRepo.On("ResolveEscalations", mock.Anything, 9).Return([]Escalation{{ID: 1, PolicyID: 5}}, nil)
+
+		notifyChange(mockRepo, "DOWN", "UP")
+
+		assert.Equal(t, []int{1}, deliveredTo(mockRepo))
+	})
+}
+
+func TestNotifier_Escalate(t *testing.T) {
+	mockRepo := new(MockRepository)
+	mockRepo.On("ClaimDueEscalations", mock.Anything).Return([]Escalation{{
+		ID: 1, PolicyID: 5, ServiceID: 9, Notification: Notification{Event: "status_change", ServiceID: 9, NewStatus: "DOWN"},
+	}}, nil)
+	mockRepo.On("GetPolicyByID", mock.Anything, 5).Return(Policy{
+		ID: 5, UserID: 4, OrganizationID: 7, ChannelIDs: []int{1}, EscalationChannelIDs: []int{2, 3}, EscalateAfterMinutes: 15,
+	}, nil)
+	mockRepo.On("ListChannels", mock.Anything, policyOwner).Return(policyChannels, nil)
+	mockRepo.On("CreateDelivery", mock.Anything, mock.MatchedBy(func(d Delivery) bool {
+		return d.Payload.Event == "escalation"
+	})).Return(nil).Twice()
+
+	notifier := newTestNotifier(mockRepo)
+	notifier.RegisterSender("custom", stubSender{})
+	notifier.escalate(context.Background())
+
+	assert.ElementsMatch(t, []int{2, 3}, deliveredTo(mockRepo))
+	mockRepo.AssertExpectations(t)
+}
+
+func TestTitle_Escalation(t *testing.T) {
+	assert.Equal(t, "🚨 Unacknowledged: API is down", title(Notification{Event: "escalation", ServiceName: "API", NewStatus: "DOWN"}))
+}
@@ -0,0 +1,380 @@
+package notification
+
+import (
+	"errors"
+	"health-checker/internal/middleware"
+	"health-checker/internal/monitor"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type NotificationHandler struct {
+	service  *NotificationService
+	roles    middleware.RoleResolver
+	denylist middleware.Denylist
+	logger   *zap.Logger
+}
+
+func NewHandler(service *NotificationService, roles middleware.RoleResolver, denylist middleware.Denylist, logger *zap.Logger) *NotificationHandler {
+	return &NotificationHandler{
+		service:  service,
+		roles:    roles,
+		denylist: denylist,
+		logger:   logger,
+	}
+}
+
+func (h *NotificationHandler) RegisterRoutes(rg *gin.RouterGroup) {
+	authenticated := rg.Group("", middleware.AuthMiddleware(h.denylist, nil))
+	viewer := authenticated.Group("", middleware.Authorize(h.roles, middleware.RoleViewer))
+	viewer.GET("", h.ListChannels)
+	viewer.GET("/:channelId/deliveries", h.ListDeliveries)
+
+	editor := authenticated.Group("", middleware.Authorize(h.roles, middleware.RoleEditor))
+	editor.POST("", h.CreateChannel)
+	editor.DELETE("/:channelId", h.DeleteChannel)
+}
+
+func (h *NotificationHandler) RegisterPolicyRoutes(rg *gin.RouterGroup) {
+	authenticated := rg.Group("", middleware.AuthMiddleware(h.denylist, nil))
+	authenticated.GET("", middleware.Authorize(h.roles, middleware.RoleViewer), h.ListPolicies)
+
+	editor := authenticated.Group("", middleware.Authorize(h.roles, middleware.RoleEditor))
+	editor.POST("", h.CreatePolicy)
+	editor.DELETE("/:policyId", h.DeletePolicy)
+}
+
+// RegisterEscalationRoutes lets viewers acknowledge alerts, since on-call
+// responders often cannot change the configuration.
+func (h *NotificationHandler) RegisterEscalationRoutes(rg *gin.RouterGroup) {
+	viewer := rg.Group("", middleware.AuthMiddleware(h.denylist, nil), middleware.Authorize(h.roles, middleware.RoleViewer))
+	viewer.GET("", h.ListEscalations)
+	viewer.POST("/:escalationId/acknowledge", h.AcknowledgeEscalation)
+}
+
+// CreateChannel godoc
+//
+//	@Security		BearerAuth
+//	@Summary		Create a notification channel
+//	@Description	Create a channel notified about status changes of your services, or of an organization's services; service_ids limits it to some of them. Webhooks receive a signed JSON POST, and their signing secret is only returned by this call. Email channels are sent to their recipients over SMTP, and slack, discord, teams and telegram channels post a formatted chat message. Incoming webhook URLs and Telegram bot tokens are stored encrypted and never returned; chat channels show a url_hint instead.
+//	@Tags			notifications
+//	@Accept			json
+//	@Produce		json
+//	@Param			channel				body		CreateChannelDTO	true	"Channel settings"
+//	@Param			X-Organization-ID	header		int					false	"Organization to act in"
+//	@Success		201					{object}	CreatedChannel		"Created channel"
+//	@Failure		400					{object}	map[string]string	"Bad request"
+//	@Failure		403					{object}	map[string]string	"Forbidden"
+//	@Failure		500					{object}	map[string]string	"Internal server error"
+//	@Router			/notification-channels [post]
+func (h *NotificationHandler) CreateChannel(ctx *gin.Context) {
+	owner, ok := h.owner(ctx)
+	if !ok {
+		return
+	}
+
+	var body CreateChannelDTO
+	if err := ctx.ShouldBindJSON(&body); err != nil {
+		h.logger.Error("failed to bind json", zap.Error(err))
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	channel, err := h.service.CreateChannel(ctx.Request.Context(), owner, body)
+	if err != nil {
+		h.respondError(ctx, "failed to create notification channel", err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, channel)
+}
+
+// ListChannels godoc
+//
+//	@Security		BearerAuth
+//	@Summary		List notification channels
+//	@Description	List your notification channels, or an organization's
+//	@Tags			notifications
+//	@Produce		json
+//	@Param			X-Organization-ID	header		int					false	"Organization to act in"
+//	@Success		200					{array}		Channel				"Channels"
+//	@Failure		403					{object}	map[string]string	"Forbidden"
+//	@Failure		500					{object}	map[string]string	"Internal server error"
+//	@Router			/notification-channels [get]
+func (h *NotificationHandler) ListChannels(ctx *gin.Context) {
+	owner, ok := h.owner(ctx)
+	if !ok {
+		return
+	}
+
+	channels, err := h.service.ListChannels(ctx.Request.Context(), owner)
+	if err != nil {
+		h.respondError(ctx, "failed to list notification channels", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, channels)
+}
+
+// DeleteChannel godoc
+//
+//	@Security		BearerAuth
+//	@Summary		Delete a notification channel
+//	@Description	Delete a notification channel together with its delivery log
+//	@Tags			notifications
+//	@Param			channelId			path	int	true	"Channel ID"
+//	@Param			X-Organization-ID	header	int	false	"Organization to act in"
+//	@Success		204					"Channel deleted"
+//	@Failure		400					{object}	map[string]string	"Bad request"
+//	@Failure		403					{object}	map[string]string	"Forbidden"
+//	@Failure		404					{object}	map[string]string	"Channel not found"
+//	@Failure		500					{object}	map[string]string	"Internal server error"
+//	@Router			/notification-channels/{channelId} [delete]
+func (h *NotificationHandler) DeleteChannel(ctx *gin.Context) {
+	owner, ok := h.owner(ctx)
+	if !ok {
+		return
+	}
+	channelID, ok := h.idParam(ctx, "channelId")
+	if !ok {
+		return
+	}
+
+	if err := h.service.DeleteChannel(ctx.Request.Context(), owner, channelID); err != nil {
+		h.respondError(ctx, "failed to delete notification channel", err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// ListDeliveries godoc
+//
+//	@Security		BearerAuth
+//	@Summary		List delivery attempts
+//	@Description	List the latest delivery attempts of a channel, newest first, including failed ones and their error
+//	@Tags			notifications
+//	@Produce		json
+//	@Param			channelId			path		int					true	"Channel ID"
+//	@Param			limit				query		int					false	"Number of attempts"	default(50)
+//	@Param			X-Organization-ID	header		int					false	"Organization to act in"
+//	@Success		200					{array}		Delivery			"Delivery attempts"
+//	@Failure		400					{object}	map[string]string	"Bad request"
+//	@Failure		403					{object}	map[string]string	"Forbidden"
+//	@Failure		404					{object}	map[string]string	"Channel not found"
+//	@Failure		500					{object}	map[string]string	"Internal server error"
+//	@Router			/notification-channels/{channelId}/deliveries [get]
+func (h *NotificationHandler) ListDeliveries(ctx *gin.Context) {
+	owner, ok := h.owner(ctx)
+	if !ok {
+		return
+	}
+	channelID, ok := h.idParam(ctx, "channelId")
+	if !ok {
+		return
+	}
+	limit, _ := strconv.Atoi(ctx.DefaultQuery("limit", "50"))
+
+	deliveries, err := h.service.ListDeliveries(ctx.Request.Context(), owner, channelID, limit)
+	if err != nil {
+		h.respondError(ctx, "failed to list deliveries", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, deliveries)
+}
+
+// CreatePolicy godoc
+//
+//	@Security		BearerAuth
+//	@Summary		Create a notification policy
+//	@Description	Route the status changes of services with some tags, of some severities (critical: DOWN and recovering from it, warning: DEGRADED, info: the rest) or inside a schedule to some channels. Once a policy exists, changes only go to the channels of the policies they match. With escalation, a DOWN alert not acknowledged within escalate_after_minutes is sent to the escalation channels too.
+//	@Tags			notifications
+//	@Accept			json
+//	@Produce		json
+//	@Param			policy				body		CreatePolicyDTO		true	"Policy settings"
+//	@Param			X-Organization-ID	header		int					false	"Organization to act in"
+//	@Success		201					{object}	Policy				"Created policy"
+//	@Failure		400					{object}	map[string]string	"Bad request"
+//	@Failure		403					{object}	map[string]string	"Forbidden"
+//	@Failure		500					{object}	map[string]string	"Internal server error"
+//	@Router			/notification-policies [post]
+func (h *NotificationHandler) CreatePolicy(ctx *gin.Context) {
+	owner, ok := h.owner(ctx)
+	if !ok {
+		return
+	}
+
+	var body CreatePolicyDTO
+	if err := ctx.ShouldBindJSON(&body); err != nil {
+		h.logger.Error("failed to bind json", zap.Error(err))
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	policy, err := h.service.CreatePolicy(ctx.Request.Context(), owner, body)
+	if err != nil {
+		h.respondError(ctx, "failed to create notification policy", err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, policy)
+}
+
+// ListPolicies godoc
+//
+//	@Security		BearerAuth
+//	@Summary		List notification policies
+//	@Description	List your notification policies, or an organization's
+//	@Tags			notifications
+//	@Produce		json
+//	@Param			X-Organization-ID	header		int					false	"Organization to act in"
+//	@Success		200					{array}		Policy				"Policies"
+//	@Failure		403					{object}	map[string]string	"Forbidden"
+//	@Failure		500					{object}	map[string]string	"Internal server error"
+//	@Router			/notification-policies [get]
+func (h *NotificationHandler) ListPolicies(ctx *gin.Context) {
+	owner, ok := h.owner(ctx)
+	if !ok {
+		return
+	}
+
+	policies, err := h.service.ListPolicies(ctx.Request.Context(), owner)
+	if err != nil {
+		h.respondError(ctx, "failed to list notification policies", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, policies)
+}
+
+// DeletePolicy godoc
+//
+//	@Security		BearerAuth
+//	@Summary		Delete a notification policy
+//	@Description	Delete a notification policy and its escalations
+//	@Tags			notifications
+//	@Param			policyId			path	int	true	"Policy ID"
+//	@Param			X-Organization-ID	header	int	false	"Organization to act in"
+//	@Success		204					"Policy deleted"
+//	@Failure		400					{object}	map[string]string	"Bad request"
+//	@Failure		403					{object}	map[string]string	"Forbidden"
+//	@Failure		404					{object}	map[string]string	"Policy not found"
+//	@Failure		500					{object}	map[string]string	"Internal server error"
+//	@Router			/notification-policies/{policyId} [delete]
+func (h *NotificationHandler) DeletePolicy(ctx *gin.Context) {
+	owner, ok := h.owner(ctx)
+	if !ok {
+		return
+	}
+	policyID, ok := h.idParam(ctx, "policyId")
+	if !ok {
+		return
+	}
+
+	if err := h.service.DeletePolicy(ctx.Request.Context(), owner, policyID); err != nil {
+		h.respondError(ctx, "failed to delete notification policy", err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// ListEscalations godoc
+//
+//	@Security		BearerAuth
+//	@Summary		List open escalations
+//	@Description	List the DOWN alerts of escalating policies whose service has not recovered yet, oldest first
+//	@Tags			notifications
+//	@Produce		json
+//	@Param			X-Organization-ID	header		int					false	"Organization to act in"
+//	@Success		200					{array}		Escalation			"Escalations"
+//	@Failure		403					{object}	map[string]string	"Forbidden"
+//	@Failure		500					{object}	map[string]string	"Internal server error"
+//	@Router			/escalations [get]
+func (h *NotificationHandler) ListEscalations(ctx *gin.Context) {
+	owner, ok := h.owner(ctx)
+	if !ok {
+		return
+	}
+
+	escalations, err := h.service.ListOpenEscalations(ctx.Request.Context(), owner)
+	if err != nil {
+		h.respondError(ctx, "failed to list escalations", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, escalations)
+}
+
+// AcknowledgeEscalation godoc
+//
+//	@Security		BearerAuth
+//	@Summary		Acknowledge an alert
+//	@Description	Acknowledge a DOWN alert so it is not escalated to the second tier
+//	@Tags			notifications
+//	@Produce		json
+//	@Param			escalationId		path		int					true	"Escalation ID"
+//	@Param			X-Organization-ID	header		int					false	"Organization to act in"
+//	@Success		200					{object}	Escalation			"Acknowledged escalation"
+//	@Failure		400					{object}	map[string]string	"Bad request"
+//	@Failure		403					{object}	map[string]string	"Forbidden"
+//	@Failure		404					{object}	map[string]string	"Escalation not found"
+//	@Failure		500					{object}	map[string]string	"Internal server error"
+//	@Router			/escalations/{escalationId}/acknowledge [post]
+func (h *NotificationHandler) AcknowledgeEscalation(ctx *gin.Context) {
+	owner, ok := h.owner(ctx)
+	if !ok {
+		return
+	}
+	escalationID, ok := h.idParam(ctx, "escalationId")
+	if !ok {
+		return
+	}
+
+	escalation, err := h.service.AcknowledgeEscalation(ctx.Request.Context(), owner, escalationID, owner.UserID)
+	if err != nil {
+		h.respondError(ctx, "failed to acknowledge escalation", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, escalation)
+}
+
+// owner returns whose channels the request acts on, responding 401 when it
+// carries no user.
+func (h *NotificationHandler) owner(ctx *gin.Context) (monitor.Owner, bool) {
+	userID, ok := middleware.UserID(ctx.Request.Context())
+	if !ok {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return monitor.Owner{}, false
+	}
+	orgID, _ := middleware.OrgID(ctx.Request.Context())
+	return monitor.Owner{UserID: userID, OrgID: orgID}, true
+}
+
+// idParam parses an ID path parameter, responding 400 when it is not an integer.
+func (h *NotificationHandler) idParam(ctx *gin.Context, param string) (int, bool) {
+	id, err := strconv.Atoi(ctx.Param(param))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": param + " must be an integer"})
+		return 0, false
+	}
+	return id, true
+}
+
+// respondError maps notification errors onto HTTP status codes.
+func (h *NotificationHandler) respondError(ctx *gin.Context, msg string, err error) {
+	switch {
+	case errors.Is(err, ErrInvalidChannel), errors.Is(err, ErrInvalidPolicy):
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, ErrChannelNotFound), errors.Is(err, ErrPolicyNotFound), errors.Is(err, ErrEscalationNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	default:
+		h.logger.Error(msg, zap.Error(err))
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -0,0 +1,315 @@
+package notification
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"health-checker/internal/middleware"
+	"health-checker/internal/middleware/middlewaretest"
+	"health-checker/internal/monitor"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+)
+
+type MockRepository struct {
+	mock.Mock
+}
+
+func (m *MockRepository) CreateChannel(ctx context.Context, channel Channel) (Channel, error) {
+	args := m.Called(ctx, channel)
+	return args.Get(0).(Channel), args.Error(1)
+}
+
+func (m *MockRepository) ListChannels(ctx context.Context, owner monitor.Owner) ([]Channel, error) {
+	args := m.Called(ctx, owner)
+	return args.Get(0).([]Channel), args.Error(1)
+}
+
+func (m *MockRepository) GetChannel(ctx context.Context, owner monitor.Owner, id int) (Channel, error) {
+	args := m.Called(ctx, owner, id)
+	return args.Get(0).(Channel), args.Error(1)
+}
+
+func (m *MockRepository) DeleteChannel(ctx context.Context, owner monitor.Owner, id int) error {
+	args := m.Called(ctx, owner, id)
+	return args.Error(0)
+}
+
+func (m *MockRepository) GetService(ctx context.Context, serviceID int) (ServiceInfo, error) {
+	args := m.Called(ctx, serviceID)
+	return args.Get(0).(ServiceInfo), args.Error(1)
+}
+
+func (m *MockRepository) CreateDelivery(ctx context.Context, delivery Delivery) error {
+	args := m.Called(ctx, delivery)
+	return args.Error(0)
+}
+
+func (m *MockRepository) ListDeliveries(ctx context.Context, channelID, limit int) ([]Delivery, error) {
+	args := m.Called(ctx, channelID, limit)
+	return args.Get(0).([]Delivery), args.Error(1)
+}
+
+func (m *MockRepository) CreatePolicy(ctx context.Context, policy Policy) (Policy, error) {
+	args := m.Called(ctx, policy)
+	return args.Get(0).(Policy), args.Error(1)
+}
+
+func (m *MockRepository) ListPolicies(ctx context.Context, owner monitor.Owner) ([]Policy, error) {
+	args := m.Called(ctx, owner)
+	return args.Get(0).([]Policy), args.Error(1)
+}
+
+func (m *MockRepository) GetPolicyByID(ctx context.Context, id int) (Policy, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(Policy), args.Error(1)
+}
+
+func (m *MockRepository) DeletePolicy(ctx context.Context, owner monitor.Owner, id int) error {
+	args := m.Called(ctx, owner, id)
+	return args.Error(0)
+}
+
+func (m *MockRepository) CreateEscalation(ctx context.Context, escalation Escalation) error {
+	args := m.Called(ctx, escalation)
+	return args.Error(0)
+}
+
+func (m *MockRepository) ListOpenEscalations(ctx context.Context, owner monitor.Owner) ([]Escalation, error) {
+	args := m.Called(ctx, owner)
+	return args.Get(0).([]Escalation), args.Error(1)
+}
+
+func (m *MockRepository) AcknowledgeEscalation(ctx context.Context, owner monitor.Owner, id, userID int) (Escalation, error) {
+	args := m.Called(ctx, owner, id, userID)
+	return args.Get(0).(Escalation), args.Error(1)
+}
+
+func (m *MockRepository) AcknowledgeServiceEscalations(ctx context.Context, serviceID, userID int) error {
+	args := m.Called(ctx, serviceID, userID)
+	return args.Error(0)
+}
+
+func (m *MockRepository) ResolveEscalations(ctx context.Context, serviceID int) ([]Escalation, error) {
+	args := m.Called(ctx, serviceID)
+	return args.Get(0).([]Escalation), args.Error(1)
+}
+
+func (m *MockRepository) ClaimDueEscalations(ctx context.Context) ([]Escalation, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]Escalation), args.Error(1)
+}
+
+type stubRoles map[int]middleware.Role
+
+func (s stubRoles) MemberRole(ctx context.Context, orgID, userID int) (middleware.Role, error) {
+	return s[userID], nil
+}
+
+// setupRouter serves the channel, policy and escalation routes behind the real auth middleware;
+// user 5 is a viewer of every organization.
+func setupRouter(mockRepo *MockRepository) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	os.Setenv("JWT_SECRET", "test-secret")
+
+	r := gin.New()
+	handler := NewHandler(NewService(mockRepo, zap.L()), stubRoles{5: middleware.RoleViewer}, middlewaretest.Denylist{}, zap.NewNop())
+	handler.RegisterRoutes(r.Group("/notification-channels"))
+	handler.RegisterPolicyRoutes(r.Group("/notification-policies"))
+	handler.RegisterEscalationRoutes(r.Group("/escalations"))
+	return r
+}
+
+func authorizedRequest(method, path string, body interface{}, userID int) *http.Request {
+	token, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"user_id": userID,
+		"exp":     time.Now().Add(time.Hour).Unix(),
+	}).SignedString([]byte("test-secret"))
+
+	var payload []byte
+	if body != nil {
+		payload, _ = json.Marshal(body)
+	}
+	req, _ := http.NewRequest(method, path, bytes.NewBuffer(payload))
+	req.Header.Set("Authorization", "Bearer "+token)
+	return req
+}
+
+func TestCreateChannel(t *testing.T) {
+	os.Setenv("ENCRYPTION_KEY", "test-key")
+	defer os.Unsetenv("ENCRYPTION_KEY")
+
+	t.Run("Success", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		mockRepo.On("CreateChannel", mock.Anything, mock.AnythingOfType("notification.Channel")).
+			Return(Channel{ID: 1, UserID: 4, Name: "ops", Type: ChannelTypeWebhook}, nil)
+
+		w := httptest.NewRecorder()
+		body := CreateChannelDTO{Name: "ops", Type: ChannelTypeWebhook, Config: ChannelConfig{Webhook: &WebhookConfig{URL: "https://example.com/hook"}}}
+		setupRouter(mockRepo).ServeHTTP(w, authorizedRequest("POST", "/notification-channels", body, 4))
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		assert.Contains(t, w.Body.String(), `"secret":"whsec_`)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("MissingConfig", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+
+		w := httptest.NewRecorder()
+		body := CreateChannelDTO{Name: "ops", Type: ChannelTypeWebhook}
+		setupRouter(mockRepo).ServeHTTP(w, authorizedRequest("POST", "/notification-channels", body, 4))
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		mockRepo.AssertNotCalled(t, "CreateChannel")
+	})
+
+	t.Run("ViewerForbidden", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+
+		w := httptest.NewRecorder()
+		req := authorizedRequest("POST", "/notification-channels", CreateChannelDTO{Name: "ops", Type: ChannelTypeWebhook}, 5)
+		req.Header.Set(middleware.OrganizationHeader, "7")
+		setupRouter(mockRepo).ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		mockRepo.AssertNotCalled(t, "CreateChannel")
+	})
+}
+
+func TestListChannels(t *testing.T) {
+	t.Run("ViewerDoesNotSeeWebhookURLs", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		owner := monitor.Owner{UserID: 5, OrgID: 7}
+		mockRepo.On("ListChannels", mock.Anything, owner).Return([]Channel{
+			{ID: 1, Type: ChannelTypeSlack, Config: ChannelConfig{Slack: &IncomingWebhookConfig{URLHint: "hooks.slack.com/…XXXX"}}, EncryptedSecret: "sealed"},
+			// Created before incoming webhook URLs were sealed.
+			{ID: 2, Type: ChannelTypeDiscord, Config: ChannelConfig{Discord: &IncomingWebhookConfig{URL: "https://discord.com/api/webhooks/1/token-YYYY"}}},
+		}, nil)
+
+		w := httptest.NewRecorder()
+		req := authorizedRequest("GET", "/notification-channels", nil, 5)
+		req.Header.Set(middleware.OrganizationHeader, "7")
+		setupRouter(mockRepo).ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.NotContains(t, w.Body.String(), `"url":`)
+		assert.NotContains(t, w.Body.String(), "token-YYYY")
+		assert.NotContains(t, w.Body.String(), "sealed")
+		assert.Contains(t, w.Body.String(), `"url_hint":"hooks.slack.com/…XXXX"`)
+		assert.Contains(t, w.Body.String(), `"url_hint":"discord.com/…YYYY"`)
+		mockRepo.AssertExpectations(t)
+	})
+}
+
+func TestListDeliveries(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		owner := monitor.Owner{UserID: 5, OrgID: 7}
+		mockRepo.On("GetChannel", mock.Anything, owner, 1).Return(Channel{ID: 1}, nil)
+		mockRepo.On("ListDeliveries", mock.Anything, 1, 10).Return([]Delivery{{ID: 3, ChannelID: 1, Attempt: 2, Success: true}}, nil)
+
+		w := httptest.NewRecorder()
+		req := authorizedRequest("GET", "/notification-channels/1/deliveries?limit=10", nil, 5)
+		req.Header.Set(middleware.OrganizationHeader, "7")
+		setupRouter(mockRepo).ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"attempt":2`)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("NotOwned", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		mockRepo.On("GetChannel", mock.Anything, monitor.Owner{UserID: 4}, 2).Return(Channel{}, ErrChannelNotFound)
+
+		w := httptest.NewRecorder()
+		setupRouter(mockRepo).ServeHTTP(w, authorizedRequest("GET", "/notification-channels/2/deliveries", nil, 4))
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		mockRepo.AssertNotCalled(t, "ListDeliveries")
+	})
+}
+
+func TestDeleteChannel(t *testing.T) {
+	mockRepo := new(MockRepository)
+	mockRepo.On("DeleteChannel", mock.Anything, monitor.Owner{UserID: 4}, 1).Return(nil)
+
+	w := httptest.NewRecorder()
+	setupRouter(mockRepo).ServeHTTP(w, authorizedRequest("DELETE", "/notification-channels/1", nil, 4))
+
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestCreatePolicy(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		mockRepo.On("ListChannels", mock.Anything, monitor.Owner{UserID: 4}).Return([]Channel{{ID: 1}, {ID: 2}}, nil)
+		mockRepo.On("CreatePolicy", mock.Anything, mock.MatchedBy(func(p Policy) bool {
+			return p.UserID == 4 && p.Schedule.Timezone == "Europe/Berlin" && p.EscalateAfterMinutes == 15
+		})).Return(Policy{ID: 1, Name: "payments"}, nil)
+
+		w := httptest.NewRecorder()
+		body := CreatePolicyDTO{
+			Name:                 "payments",
+			Tags:                 []string{"payments"},
+			Schedule:             &Schedule{Timezone: "Europe/Berlin", Start: "09:00", End: "18:00"},
+			ChannelIDs:           []int{1},
+			EscalationChannelIDs: []int{2},
+			EscalateAfterMinutes: 15,
+		}
+		setupRouter(mockRepo).ServeHTTP(w, authorizedRequest("POST", "/notification-policies", body, 4))
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("UnknownSeverity", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+
+		w := httptest.NewRecorder()
+		body := CreatePolicyDTO{Name: "payments", Severities: []string{"urgent"}, ChannelIDs: []int{1}}
+		setupRouter(mockRepo).ServeHTTP(w, authorizedRequest("POST", "/notification-policies", body, 4))
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		mockRepo.AssertNotCalled(t, "CreatePolicy")
+	})
+}
+
+func TestAcknowledgeEscalation(t *testing.T) {
+	t.Run("Viewer", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		acknowledgedAt := time.Now()
+		mockRepo.On("AcknowledgeEscalation", mock.Anything, monitor.Owner{UserID: 5, OrgID: 7}, 3, 5).
+			Return(Escalation{ID: 3, AcknowledgedAt: &acknowledgedAt, AcknowledgedBy: 5}, nil)
+
+		w := httptest.NewRecorder()
+		req := authorizedRequest("POST", "/escalations/3/acknowledge", nil, 5)
+		req.Header.Set(middleware.OrganizationHeader, "7")
+		setupRouter(mockRepo).ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"acknowledged_by":5`)
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		mockRepo.On("AcknowledgeEscalation", mock.Anything, monitor.Owner{UserID: 4}, 3, 4).
+			Return(Escalation{}, ErrEscalationNotFound)
+
+		w := httptest.NewRecorder()
+		setupRouter(mockRepo).ServeHTTP(w, authorizedRequest("POST", "/escalations/3/acknowledge", nil, 4))
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
@@ -0,0 +1,252 @@
+package notification
+
+import (
+	"context"
+	"errors"
+	"health-checker/internal/monitor"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const (
+	defaultMaxAttempts    = 5
+	defaultInitialBackoff = 2 * time.Second
+	senderTimeout         = 10 * time.Second
+)
+
+// Notifier sends status changes to the channels of the service's owner. Each
+// channel is tried up to maxAttempts times with exponential backoff, and every
+// attempt is recorded as a Delivery.
+type Notifier struct {
+	repo           Repository
+	log            *zap.Logger
+	maxAttempts    int
+	initialBackoff time.Duration
+	// escalationInterval is how often RunEscalations looks for due escalations.
+	escalationInterval time.Duration
+	publicURL          string
+
+	mu      sync.RWMutex
+	senders map[string]Sender
+}
+
+func NewNotifier(repo Repository, log *zap.Logger) *Notifier {
+	n := &Notifier{
+		repo:               repo,
+		log:                log,
+		maxAttempts:        defaultMaxAttempts,
+		initialBackoff:     defaultInitialBackoff,
+		senders:            make(map[string]Sender),
+		escalationInterval: defaultEscalationInterval,
+	}
+	client := &http.Client{Timeout: senderTimeout}
+	n.RegisterSender(ChannelTypeWebhook, NewWebhookSender(client))
+	n.RegisterSender(ChannelTypeSlack, NewSlackSender(client))
+	n.RegisterSender(ChannelTypeDiscord, NewDiscordSender(client))
+	n.RegisterSender(ChannelTypeTeams, NewTeamsSender(client))
+	n.RegisterSender(ChannelTypeTelegram, NewTelegramSender(client, TelegramAPIURL))
+	return n
+}
+
+// SetPublicURL sets the address the API is reachable at, such as
+// https://health.example.com. Notifications link back to their service when
+// it is set.
+func (n *Notifier) SetPublicURL(publicURL string) {
+	n.publicURL = strings.TrimRight(publicURL, "/")
+}
+
+// RegisterSender adds or replaces the Sender used for a channel type.
+func (n *Notifier) RegisterSender(channelType string, sender Sender) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	n.senders[channelType] = sender
+}
+
+// HandleStatusChange is subscribed to the EventBus's "StatusChange" events.
+// Without policies every channel covering the service is notified; otherwise
+// only the channels of the policies the change matches.
+func (n *Notifier) HandleStatusChange(ctx context.Context, event monitor.Event) {
+	change, ok := event.(monitor.StatusChangeEvent)
+	if !ok {
+		return
+	}
+	// Heartbeat events are published with the ping request's context, which is
+	// canceled long before retries are done.
+	ctx = context.WithoutCancel(ctx)
+	owner := monitor.Owner{UserID: change.UserID, OrgID: change.OrgID}
+
+	var resolved []Escalation
+	if change.OldStatus == monitor.StatusDown {
+		var err error
+		if resolved, err = n.repo.ResolveEscalations(ctx, change.ServiceID); err != nil {
+			n.log.Error("failed to resolve escalations", zap.Int("service_id", change.ServiceID), zap.Error(err))
+		}
+	}
+
+	channels, err := n.repo.ListChannels(ctx, owner)
+	if err != nil {
+		n.log.Error("failed to list notification channels", zap.Int("service_id", change.ServiceID), zap.Error(err))
+		return
+	}
+	covered := channels[:0]
+	for _, channel := range channels {
+		if channel.Covers(change.ServiceID) {
+			covered = append(covered, channel)
+		}
+	}
+	if len(covered) == 0 {
+		return
+	}
+	policies, err := n.repo.ListPolicies(ctx, owner)
+	if err != nil {
+		n.log.Error("failed to list notification policies", zap.Int("service_id", change.ServiceID), zap.Error(err))
+		return
+	}
+
+	service, err := n.repo.GetService(ctx, change.ServiceID)
+	if err != nil {
+		n.log.Warn("failed to get service", zap.Int("service_id", change.ServiceID), zap.Error(err))
+	}
+	notification := Notification{
+		Event:       "status_change",
+		ServiceID:   change.ServiceID,
+		ServiceName: service.Name,
+		OldStatus:   change.OldStatus,
+		NewStatus:   change.NewStatus,
+		Severity:    SeverityOf(change.OldStatus, change.NewStatus),
+		LatencyMs:   change.Latency,
+		Timestamp:   change.Timestamp,
+	}
+	if n.publicURL != "" {
+		notification.URL = n.publicURL + "/api/v1/services/" + strconv.Itoa(change.ServiceID)
+	}
+
+	if len(policies) > 0 {
+		covered = filterChannels(covered, n.route(ctx, policies, service.Tags, resolved, notification))
+	}
+	n.send(ctx, covered, notification)
+}
+
+// route returns the IDs of the channels that policies pick for notification,
+// and opens an escalation for each escalating policy a DOWN change matches.
+// The second tier of escalations that were resolved by the change hears about
+// the recovery too.
+func (n *Notifier) route(ctx context.Context, policies []Policy, tags []string, resolved []Escalation, notification Notification) []int {
+	var channelIDs []int
+	for _, policy := range policies {
+		if !policy.Matches(notification.Severity, tags, notification.Timestamp) {
+			continue
+		}
+		channelIDs = append(channelIDs, policy.ChannelIDs...)
+
+		if notification.NewStatus == monitor.StatusDown && policy.Escalates() {
+			escalation := Escalation{
+				PolicyID:     policy.ID,
+				ServiceID:    notification.ServiceID,
+				Notification: notification,
+				DueAt:        time.Now().Add(time.Duration(policy.EscalateAfterMinutes) * time.Minute),
+			}
+			if err := n.repo.CreateEscalation(ctx, escalation); err != nil {
+				n.log.Error("failed to open escalation", zap.Int("policy_id", policy.ID), zap.Error(err))
+			}
+		}
+	}
+
+	for _, escalation := range resolved {
+		if escalation.EscalatedAt == nil {
+			continue
+		}
+		for _, policy := range policies {
+			if policy.ID == escalation.PolicyID {
+				channelIDs = append(channelIDs, policy.EscalationChannelIDs...)
+			}
+		}
+	}
+	return channelIDs
+}
+
+// send delivers notification over channels concurrently and waits for all of them.
+func (n *Notifier) send(ctx context.Context, channels []Channel, notification Notification) {
+	var wg sync.WaitGroup
+	for _, channel := range channels {
+		wg.Add(1)
+		go func(channel Channel) {
+			defer wg.Done()
+			n.deliver(ctx, channel, notification)
+		}(channel)
+	}
+	wg.Wait()
+}
+
+// filterChannels keeps the channels whose ID is in ids, each once.
+func filterChannels(channels []Channel, ids []int) []Channel {
+	var filtered []Channel
+	for _, channel := range channels {
+		if slices.Contains(ids, channel.ID) {
+			filtered = append(filtered, channel)
+		}
+	}
+	return filtered
+}
+
+// deliver sends notification over channel, retrying failed attempts until one
+// succeeds, the failure is permanent, attempts run out or ctx is done.
+func (n *Notifier) deliver(ctx context.Context, channel Channel, notification Notification) {
+	n.mu.RLock()
+	sender, ok := n.senders[channel.Type]
+	n.mu.RUnlock()
+	if !ok {
+		n.log.Error("no sender for channel type", zap.Int("channel_id", channel.ID), zap.String("type", channel.Type))
+		return
+	}
+
+	backoff := n.initialBackoff
+	for attempt := 1; attempt <= n.maxAttempts; attempt++ {
+		start := time.Now()
+		statusCode, err := sender.Send(ctx, channel, notification)
+
+		delivery := Delivery{
+			ChannelID:  channel.ID,
+			ServiceID:  notification.ServiceID,
+			Payload:    notification,
+			Attempt:    attempt,
+			Success:    err == nil,
+			StatusCode: statusCode,
+			DurationMs: int(time.Since(start).Milliseconds()),
+		}
+		if err != nil {
+			delivery.Error = err.Error()
+		}
+		if logErr := n.repo.CreateDelivery(ctx, delivery); logErr != nil {
+			n.log.Error("failed to record delivery", zap.Int("channel_id", channel.ID), zap.Error(logErr))
+		}
+
+		if err == nil {
+			return
+		}
+		n.log.Warn("notification delivery failed",
+			zap.Int("channel_id", channel.ID),
+			zap.Int("attempt", attempt),
+			zap.Error(err),
+		)
+		if errors.Is(err, ErrPermanent) || attempt == n.maxAttempts {
+			return
+		}
+
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+		backoff *= 2
+	}
+}
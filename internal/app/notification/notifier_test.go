@@ -0,0 +1,174 @@
+package notification
+
+import (
+	"context"
+	"encoding/json"
+	"health-checker/internal/monitor"
+	"health-checker/internal/secrets"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func newTestNotifier(repo Repository) *Notifier {
+	n := NewNotifier(repo, zap.NewNop())
+	n.initialBackoff = time.Millisecond
+	return n
+}
+
+func webhookChannel(t *testing.T, url, secret string) Channel {
+	encrypted, err := secrets.Encrypt([]byte(secret))
+	require.NoError(t, err)
+	return Channel{ID: 1, Type: ChannelTypeWebhook, Config: ChannelConfig{Webhook: &WebhookConfig{URL: url}}, EncryptedSecret: encrypted}
+}
+
+func TestNotifier_SignsWebhook(t *testing.T) {
+	os.Setenv("ENCRYPTION_KEY", "test-key")
+	defer os.Unsetenv("ENCRYPTION_KEY")
+
+	var received Notification
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		signature := strings.TrimPrefix(r.Header.Get(SignatureHeader), "sha256=")
+		assert.Equal(t, Sign([]byte("whsec_test"), r.Header.Get(TimestampHeader), body), signature)
+		json.Unmarshal(body, &received)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	owner := monitor.Owner{UserID: 4}
+	mockRepo := new(MockRepository)
+	mockRepo.On("ListChannels", mock.Anything, owner).Return([]Channel{webhookChannel(t, server.URL, "whsec_test")}, nil)
+	mockRepo.On("ListPolicies", mock.Anything, monitor.Owner{UserID: 4}).Return([]Policy{}, nil)
+	mockRepo.On("GetService", mock.Anything, 9).Return(ServiceInfo{Name: "API"}, nil)
+	mockRepo.On("CreateDelivery", mock.Anything, mock.MatchedBy(func(d Delivery) bool {
+		return d.Success && d.Attempt == 1 && d.StatusCode == http.StatusNoContent
+	})).Return(nil).Once()
+
+	notifier := newTestNotifier(mockRepo)
+	notifier.SetPublicURL("https://health.example.com/")
+	notifier.HandleStatusChange(context.Background(), monitor.StatusChangeEvent{
+		ServiceID: 9, UserID: 4, OldStatus: "UP", NewStatus: "DOWN", Latency: 1203, Timestamp: time.Now(),
+	})
+
+	assert.Equal(t, "API", received.ServiceName)
+	assert.Equal(t, monitor.StatusDown, received.NewStatus)
+	assert.Equal(t, 1203, received.LatencyMs)
+	assert.Equal(t, "https://health.example.com/api/v1/services/9", received.URL)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestNotifier_Retries(t *testing.T) {
+	os.Setenv("ENCRYPTION_KEY", "test-key")
+	defer os.Unsetenv("ENCRYPTION_KEY")
+
+	tests := []struct {
+		name     string
+		statuses []int
+		attempts int
+		success  bool
+	}{
+		{"succeeds after server errors", []int{500, 503, 200}, 3, true},
+		{"gives up after max attempts", []int{500, 500, 500, 500, 500}, 5, false},
+		{"does not retry client errors", []int{404}, 1, false},
+		{"retries rate limiting", []int{429, 200}, 2, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				i := atomic.AddInt32(&calls, 1) - 1
+				w.WriteHeader(tt.statuses[i])
+			}))
+			defer server.Close()
+
+			var mu sync.Mutex
+			var deliveries []Delivery
+			mockRepo := new(MockRepository)
+			mockRepo.On("CreateDelivery", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+				mu.Lock()
+				defer mu.Unlock()
+				deliveries = append(deliveries, args.Get(1).(Delivery))
+			}).Return(nil)
+
+			newTestNotifier(mockRepo).deliver(context.Background(), webhookChannel(t, server.URL, "whsec_test"), Notification{ServiceID: 9})
+
+			require.Len(t, deliveries, tt.attempts)
+			last := deliveries[len(deliveries)-1]
+			assert.Equal(t, tt.attempts, last.Attempt)
+			assert.Equal(t, tt.success, last.Success)
+			if !tt.success {
+				assert.NotEmpty(t, last.Error)
+			}
+		})
+	}
+}
+
+func TestNotifier_NoChannels(t *testing.T) {
+	mockRepo := new(MockRepository)
+	mockRepo.On("ListChannels", mock.Anything, monitor.Owner{UserID: 4, OrgID: 7}).Return([]Channel{}, nil)
+
+	newTestNotifier(mockRepo).HandleStatusChange(context.Background(), monitor.StatusChangeEvent{ServiceID: 9, UserID: 4, OrgID: 7})
+
+	mockRepo.AssertNotCalled(t, "GetService", mock.Anything, mock.Anything)
+	mockRepo.AssertNotCalled(t, "CreateDelivery", mock.Anything, mock.Anything)
+}
+
+func TestNotifier_ServiceIDs(t *testing.T) {
+	mockRepo := new(MockRepository)
+	mockRepo.On("ListChannels", mock.Anything, monitor.Owner{UserID: 4}).Return([]Channel{
+		{ID: 1, Type: "custom", ServiceIDs: []int{3}},
+		{ID: 2, Type: "custom", ServiceIDs: []int{3, 9}},
+		{ID: 3, Type: "custom"},
+	}, nil)
+	mockRepo.On("ListPolicies", mock.Anything, monitor.Owner{UserID: 4}).Return([]Policy{}, nil)
+	mockRepo.On("GetService", mock.Anything, 9).Return(ServiceInfo{Name: "API"}, nil)
+	mockRepo.On("CreateDelivery", mock.Anything, mock.Anything).Return(nil)
+
+	notifier := newTestNotifier(mockRepo)
+	notifier.RegisterSender("custom", stubSender{})
+	notifier.HandleStatusChange(context.Background(), monitor.StatusChangeEvent{ServiceID: 9, UserID: 4})
+
+	assert.ElementsMatch(t, []int{2, 3}, deliveredTo(mockRepo))
+}
+
+// deliveredTo returns the IDs of the channels a delivery was recorded for.
+func deliveredTo(mockRepo *MockRepository) []int {
+	var channelIDs []int
+	for _, call := range mockRepo.Calls {
+		if call.Method == "CreateDelivery" {
+			channelIDs = append(channelIDs, call.Arguments.Get(1).(Delivery).ChannelID)
+		}
+	}
+	return channelIDs
+}
+
+type stubSender struct{ err error }
+
+func (s stubSender) Send(ctx context.Context, channel Channel, notification Notification) (int, error) {
+	return 0, s.err
+}
+
+func TestNotifier_RegisterSender(t *testing.T) {
+	mockRepo := new(MockRepository)
+	mockRepo.On("CreateDelivery", mock.Anything, mock.MatchedBy(func(d Delivery) bool {
+		return !d.Success && d.Error == "permanent delivery failure"
+	})).Return(nil).Once()
+
+	notifier := newTestNotifier(mockRepo)
+	notifier.RegisterSender("custom", stubSender{err: ErrPermanent})
+	notifier.deliver(context.Background(), Channel{ID: 2, Type: "custom"}, Notification{})
+
+	mockRepo.AssertExpectations(t)
+}
@@ -0,0 +1,88 @@
+package notification
+
+import (
+	"errors"
+	"fmt"
+	"health-checker/internal/monitor"
+	"slices"
+	"time"
+)
+
+// SeverityOf rates a status change by the worst status involved: going DOWN
+// and recovering from DOWN are critical, DEGRADED is a warning and anything
+// else is informational.
+func SeverityOf(oldStatus, newStatus monitor.Status) string {
+	status := newStatus
+	if newStatus == monitor.StatusUp {
+		status = oldStatus
+	}
+	switch status {
+	case monitor.StatusDown:
+		return SeverityCritical
+	case monitor.StatusDegraded:
+		return SeverityWarning
+	default:
+		return SeverityInfo
+	}
+}
+
+// Matches reports whether the policy routes a change of the given severity,
+// on a service with tags, that happened at.
+func (p Policy) Matches(severity string, tags []string, at time.Time) bool {
+	if len(p.Severities) > 0 && !slices.Contains(p.Severities, severity) {
+		return false
+	}
+	if len(p.Tags) > 0 && !slices.ContainsFunc(tags, func(tag string) bool { return slices.Contains(p.Tags, tag) }) {
+		return false
+	}
+	return p.Schedule == nil || p.Schedule.Active(at)
+}
+
+// Escalates reports whether DOWN alerts routed by the policy must be acknowledged.
+func (p Policy) Escalates() bool {
+	return p.EscalateAfterMinutes > 0 && len(p.EscalationChannelIDs) > 0
+}
+
+// Active reports whether at falls into the schedule's window.
+func (s Schedule) Active(at time.Time) bool {
+	location, err := time.LoadLocation(s.Timezone)
+	if err != nil {
+		// Validate rejects unknown zones; fail open rather than lose alerts.
+		return true
+	}
+	local := at.In(location)
+	day := local.Weekday()
+
+	if s.Start != "" && s.End != "" {
+		start, _ := time.Parse("15:04", s.Start)
+		end, _ := time.Parse("15:04", s.End)
+		minute := local.Hour()*60 + local.Minute()
+		from, to := start.Hour()*60+start.Minute(), end.Hour()*60+end.Minute()
+		switch {
+		case from <= to && (minute < from || minute >= to):
+			return false
+		case from > to && minute < from && minute >= to:
+			return false
+		case from > to && minute < to:
+			// The early hours belong to the window opened the day before.
+			day = (day + 6) % 7
+		}
+	}
+
+	return len(s.Days) == 0 || slices.Contains(s.Days, int(day))
+}
+
+func (s Schedule) Validate() error {
+	if _, err := time.LoadLocation(s.Timezone); err != nil {
+		return fmt.Errorf("unknown timezone %q", s.Timezone)
+	}
+	if (s.Start == "") != (s.End == "") {
+		return errors.New("start and end must be set together")
+	}
+	for _, value := range []string{s.Start, s.End} {
+		if _, err := time.Parse("15:04", value); value != "" && err != nil {
+			return fmt.Errorf("invalid time %q, expected HH:MM", value)
+		}
+	}
+	return nil
+}
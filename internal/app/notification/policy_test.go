@@ -0,0 +1,87 @@
+package notification
+
+import (
+	"health-checker/internal/monitor"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSeverityOf(t *testing.T) {
+	tests := []struct {
+		oldStatus, newStatus monitor.Status
+		severity             string
+	}{
+		{"UP", "DOWN", SeverityCritical},
+		{"DOWN", "UP", SeverityCritical},
+		{"UP", "DEGRADED", SeverityWarning},
+		{"DEGRADED", "UP", SeverityWarning},
+		{"DOWN", "DEGRADED", SeverityWarning},
+		{"PAUSED", "UP", SeverityInfo},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.severity, SeverityOf(tt.oldStatus, tt.newStatus), "%s -> %s", tt.oldStatus, tt.newStatus)
+	}
+}
+
+func TestPolicy_Matches(t *testing.T) {
+	at := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		policy   Policy
+		severity string
+		tags     []string
+		want     bool
+	}{
+		{"empty policy matches everything", Policy{}, SeverityInfo, nil, true},
+		{"severity matches", Policy{Severities: []string{SeverityCritical}}, SeverityCritical, nil, true},
+		{"severity does not match", Policy{Severities: []string{SeverityCritical}}, SeverityWarning, nil, false},
+		{"any tag matches", Policy{Tags: []string{"payments", "search"}}, SeverityInfo, []string{"prod", "search"}, true},
+		{"no tag matches", Policy{Tags: []string{"payments"}}, SeverityInfo, []string{"prod"}, false},
+		{"untagged service", Policy{Tags: []string{"payments"}}, SeverityInfo, nil, false},
+		{"outside schedule", Policy{Schedule: &Schedule{Start: "18:00", End: "20:00"}}, SeverityInfo, nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.policy.Matches(tt.severity, tt.tags, at))
+		})
+	}
+}
+
+func TestSchedule_Active(t *testing.T) {
+	businessHours := Schedule{Timezone: "Europe/Berlin", Days: []int{1, 2, 3, 4, 5}, Start: "09:00", End: "18:00"}
+	overnight := Schedule{Days: []int{5}, Start: "22:00", End: "06:00"}
+
+	tests := []struct {
+		name     string
+		schedule Schedule
+		at       time.Time
+		want     bool
+	}{
+		// 2024-01-01 is a Monday; Berlin is UTC+1 in winter.
+		{"inside business hours", businessHours, time.Date(2024, 1, 1, 8, 0, 0, 0, time.UTC), true},
+		{"before business hours in local time", businessHours, time.Date(2024, 1, 1, 7, 59, 0, 0, time.UTC), false},
+		{"end is exclusive", businessHours, time.Date(2024, 1, 1, 17, 0, 0, 0, time.UTC), false},
+		{"weekend", businessHours, time.Date(2024, 1, 6, 12, 0, 0, 0, time.UTC), false},
+		{"overnight window on its day", overnight, time.Date(2024, 1, 5, 23, 0, 0, 0, time.UTC), true},
+		{"overnight window after midnight", overnight, time.Date(2024, 1, 6, 5, 0, 0, 0, time.UTC), true},
+		{"overnight window a day late", overnight, time.Date(2024, 1, 6, 23, 0, 0, 0, time.UTC), false},
+		{"outside overnight window", overnight, time.Date(2024, 1, 5, 12, 0, 0, 0, time.UTC), false},
+		{"days only", Schedule{Days: []int{0, 6}}, time.Date(2024, 1, 7, 3, 0, 0, 0, time.UTC), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.schedule.Active(tt.at))
+		})
+	}
+}
+
+func TestSchedule_Validate(t *testing.T) {
+	assert.NoError(t, Schedule{Timezone: "America/New_York", Start: "09:00", End: "17:30"}.Validate())
+	assert.NoError(t, Schedule{Days: []int{1}}.Validate())
+	assert.Error(t, Schedule{Timezone: "Mars/Olympus"}.Validate())
+	assert.Error(t, Schedule{Start: "09:00"}.Validate())
+	assert.Error(t, Schedule{Start: "9am", End: "5pm"}.Validate())
+}
@@ -0,0 +1,355 @@
+package notification
+
+import (
+	"context"
+	"fmt"
+	"health-checker/internal/monitor"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type Repository interface {
+	CreateChannel(ctx context.Context, channel Channel) (Channel, error)
+	ListChannels(ctx context.Context, owner monitor.Owner) ([]Channel, error)
+	GetChannel(ctx context.Context, owner monitor.Owner, id int) (Channel, error)
+	DeleteChannel(ctx context.Context, owner monitor.Owner, id int) error
+	GetService(ctx context.Context, serviceID int) (ServiceInfo, error)
+	CreateDelivery(ctx context.Context, delivery Delivery) error
+	ListDeliveries(ctx context.Context, channelID, limit int) ([]Delivery, error)
+	CreatePolicy(ctx context.Context, policy Policy) (Policy, error)
+	ListPolicies(ctx context.Context, owner monitor.Owner) ([]Policy, error)
+	GetPolicyByID(ctx context.Context, id int) (Policy, error)
+	DeletePolicy(ctx context.Context, owner monitor.Owner, id int) error
+	CreateEscalation(ctx context.Context, escalation Escalation) error
+	ListOpenEscalations(ctx context.Context, owner monitor.Owner) ([]Escalation, error)
+	AcknowledgeEscalation(ctx context.Context, owner monitor.Owner, id, userID int) (Escalation, error)
+	AcknowledgeServiceEscalations(ctx context.Context, serviceID, userID int) error
+	ResolveEscalations(ctx context.Context, serviceID int) ([]Escalation, error)
+	ClaimDueEscalations(ctx context.Context) ([]Escalation, error)
+}
+
+// ServiceInfo is what notifications need to know about a service.
+type ServiceInfo struct {
+	Name string
+	Tags []string
+}
+
+const channelColumns = `id, user_id, coalesce(organization_id, 0), name, type, config, service_ids, secret_encrypted,
+	created_at`
+
+const policyColumns = `id, user_id, coalesce(organization_id, 0), name, tags, severities, schedule, channel_ids,
+	escalation_channel_ids, escalate_after_minutes, created_at`
+
+const escalationColumns = `e.id, e.policy_id, e.service_id, e.notification, e.due_at, e.acknowledged_at,
+	coalesce(e.acknowledged_by, 0), e.escalated_at, e.resolved_at, e.created_at`
+
+// ownedBy matches channels of an organization when the owner's org ID
+// (argument arg) is set, otherwise the personal channels of the user ID in
+// argument arg+1.
+func ownedBy(arg int) string {
+	return fmt.Sprintf("coalesce(organization_id, 0) = $%d and ($%d <> 0 or user_id = $%d)", arg, arg, arg+1)
+}
+
+type PostgresRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewRepository(db *pgxpool.Pool) Repository {
+	return &PostgresRepository{db: db}
+}
+
+func (r *PostgresRepository) CreateChannel(ctx context.Context, channel Channel) (Channel, error) {
+	query := `
+		insert into notification_channels (user_id, organization_id, name, type, config, service_ids, secret_encrypted)
+		values ($1, nullif($2, 0), $3, $4, $5, $6, $7)
+		returning ` + channelColumns + `
+	`
+	return scanChannel(r.db.QueryRow(ctx, query, channel.UserID, channel.OrganizationID, channel.Name, channel.Type,
+		channel.Config, notNil(channel.ServiceIDs), channel.EncryptedSecret))
+}
+
+func (r *PostgresRepository) ListChannels(ctx context.Context, owner monitor.Owner) ([]Channel, error) {
+	query := `
+		select ` + channelColumns + `
+		from notification_channels
+		where ` + ownedBy(1) + `
+		order by id
+	`
+	rows, err := r.db.Query(ctx, query, owner.OrgID, owner.UserID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	channels := []Channel{}
+	for rows.Next() {
+		channel, err := scanChannel(rows)
+		if err != nil {
+			return nil, err
+		}
+		channels = append(channels, channel)
+	}
+	return channels, rows.Err()
+}
+
+func (r *PostgresRepository) GetChannel(ctx context.Context, owner monitor.Owner, id int) (Channel, error) {
+	query := `
+		select ` + channelColumns + `
+		from notification_channels
+		where id = $1 and ` + ownedBy(2)
+	channel, err := scanChannel(r.db.QueryRow(ctx, query, id, owner.OrgID, owner.UserID))
+	if err == pgx.ErrNoRows {
+		return Channel{}, ErrChannelNotFound
+	}
+	return channel, err
+}
+
+func (r *PostgresRepository) DeleteChannel(ctx context.Context, owner monitor.Owner, id int) error {
+	tag, err := r.db.Exec(ctx, `delete from notification_channels where id = $1 and `+ownedBy(2), id, owner.OrgID, owner.UserID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrChannelNotFound
+	}
+	return nil
+}
+
+func (r *PostgresRepository) GetService(ctx context.Context, serviceID int) (ServiceInfo, error) {
+	var service ServiceInfo
+	err := r.db.QueryRow(ctx, `select name, tags from services where id = $1`, serviceID).Scan(&service.Name, &service.Tags)
+	return service, err
+}
+
+func (r *PostgresRepository) CreateDelivery(ctx context.Context, delivery Delivery) error {
+	query := `
+		insert into notification_deliveries (channel_id, service_id, payload, attempt, success, status_code, error, duration_ms)
+		values ($1, $2, $3, $4, $5, $6, $7, $8)
+	`
+	_, err := r.db.Exec(ctx, query, delivery.ChannelID, delivery.ServiceID, delivery.Payload, delivery.Attempt,
+		delivery.Success, delivery.StatusCode, delivery.Error, delivery.DurationMs)
+	return err
+}
+
+// ListDeliveries returns the latest delivery attempts of a channel, newest first.
+func (r *PostgresRepository) ListDeliveries(ctx context.Context, channelID, limit int) ([]Delivery, error) {
+	query := `
+		select id, channel_id, service_id, payload, attempt, success, status_code, error, duration_ms, created_at
+		from notification_deliveries
+		where channel_id = $1
+		order by created_at desc, id desc
+		limit $2
+	`
+	rows, err := r.db.Query(ctx, query, channelID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	deliveries := []Delivery{}
+	for rows.Next() {
+		var d Delivery
+		if err := rows.Scan(&d.ID, &d.ChannelID, &d.ServiceID, &d.Payload, &d.Attempt, &d.Success, &d.StatusCode,
+			&d.Error, &d.DurationMs, &d.CreatedAt); err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, d)
+	}
+	return deliveries, rows.Err()
+}
+
+func (r *PostgresRepository) CreatePolicy(ctx context.Context, policy Policy) (Policy, error) {
+	query := `
+		insert into notification_policies (user_id, organization_id, name, tags, severities, schedule, channel_ids,
+			escalation_channel_ids, escalate_after_minutes)
+		values ($1, nullif($2, 0), $3, $4, $5, $6, $7, $8, $9)
+		returning ` + policyColumns + `
+	`
+	return scanPolicy(r.db.QueryRow(ctx, query, policy.UserID, policy.OrganizationID, policy.Name, notNil(policy.Tags),
+		notNil(policy.Severities), policy.Schedule, notNil(policy.ChannelIDs), notNil(policy.EscalationChannelIDs),
+		policy.EscalateAfterMinutes))
+}
+
+func (r *PostgresRepository) ListPolicies(ctx context.Context, owner monitor.Owner) ([]Policy, error) {
+	query := `
+		select ` + policyColumns + `
+		from notification_policies
+		where ` + ownedBy(1) + `
+		order by id
+	`
+	rows, err := r.db.Query(ctx, query, owner.OrgID, owner.UserID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	policies := []Policy{}
+	for rows.Next() {
+		policy, err := scanPolicy(rows)
+		if err != nil {
+			return nil, err
+		}
+		policies = append(policies, policy)
+	}
+	return policies, rows.Err()
+}
+
+// GetPolicyByID looks a policy up regardless of its owner, for escalating
+// alerts in the background.
+func (r *PostgresRepository) GetPolicyByID(ctx context.Context, id int) (Policy, error) {
+	policy, err := scanPolicy(r.db.QueryRow(ctx, `select `+policyColumns+` from notification_policies where id = $1`, id))
+	if err == pgx.ErrNoRows {
+		return Policy{}, ErrPolicyNotFound
+	}
+	return policy, err
+}
+
+// DeletePolicy removes a policy together with its escalations.
+func (r *PostgresRepository) DeletePolicy(ctx context.Context, owner monitor.Owner, id int) error {
+	tag, err := r.db.Exec(ctx, `delete from notification_policies where id = $1 and `+ownedBy(2), id, owner.OrgID, owner.UserID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrPolicyNotFound
+	}
+	return nil
+}
+
+func (r *PostgresRepository) CreateEscalation(ctx context.Context, escalation Escalation) error {
+	query := `
+		insert into escalations (policy_id, service_id, notification, due_at)
+		values ($1, $2, $3, $4)
+	`
+	_, err := r.db.Exec(ctx, query, escalation.PolicyID, escalation.ServiceID, escalation.Notification, escalation.DueAt)
+	return err
+}
+
+// ListOpenEscalations returns the escalations of owner's policies whose
+// service has not recovered yet, oldest first.
+func (r *PostgresRepository) ListOpenEscalations(ctx context.Context, owner monitor.Owner) ([]Escalation, error) {
+	query := `
+		select ` + escalationColumns + `
+		from escalations e
+		join notification_policies p on p.id = e.policy_id
+		where e.resolved_at is null and ` + ownedBy(1) + `
+		order by e.created_at, e.id
+	`
+	rows, err := r.db.Query(ctx, query, owner.OrgID, owner.UserID)
+	if err != nil {
+		return nil, err
+	}
+	return collectEscalations(rows)
+}
+
+// AcknowledgeEscalation records the first acknowledgement of an escalation;
+// acknowledging it again changes nothing.
+func (r *PostgresRepository) AcknowledgeEscalation(ctx context.Context, owner monitor.Owner, id, userID int) (Escalation, error) {
+	query := `
+		update escalations e
+		set acknowledged_at = coalesce(e.acknowledged_at, now()),
+			acknowledged_by = coalesce(e.acknowledged_by, $2)
+		from notification_policies p
+		where e.id = $1 and p.id = e.policy_id and ` + ownedBy(3) + `
+		returning ` + escalationColumns + `
+	`
+	escalation, err := scanEscalation(r.db.QueryRow(ctx, query, id, userID, owner.OrgID, owner.UserID))
+	if err == pgx.ErrNoRows {
+		return Escalation{}, ErrEscalationNotFound
+	}
+	return escalation, err
+}
+
+// AcknowledgeServiceEscalations acknowledges every open escalation of a
+// service that is not acknowledged yet.
+func (r *PostgresRepository) AcknowledgeServiceEscalations(ctx context.Context, serviceID, userID int) error {
+	query := `
+		update escalations
+		set acknowledged_at = now(), acknowledged_by = $2
+		where service_id = $1 and resolved_at is null and acknowledged_at is null
+	`
+	_, err := r.db.Exec(ctx, query, serviceID, userID)
+	return err
+}
+
+// ResolveEscalations closes the open escalations of a service that recovered
+// and returns them.
+func (r *PostgresRepository) ResolveEscalations(ctx context.Context, serviceID int) ([]Escalation, error) {
+	query := `
+		update escalations e
+		set resolved_at = now()
+		where e.service_id = $1 and e.resolved_at is null
+		returning ` + escalationColumns + `
+	`
+	rows, err := r.db.Query(ctx, query, serviceID)
+	if err != nil {
+		return nil, err
+	}
+	return collectEscalations(rows)
+}
+
+// ClaimDueEscalations marks the unacknowledged escalations that are due as
+// escalated and returns them, so that each is escalated once even with
+// several instances running.
+func (r *PostgresRepository) ClaimDueEscalations(ctx context.Context) ([]Escalation, error) {
+	query := `
+		update escalations e
+		set escalated_at = now()
+		where e.id in (
+			select id from escalations
+			where due_at <= now() and acknowledged_at is null and escalated_at is null and resolved_at is null
+			for update skip locked
+		)
+		returning ` + escalationColumns + `
+	`
+	rows, err := r.db.Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	return collectEscalations(rows)
+}
+
+func collectEscalations(rows pgx.Rows) ([]Escalation, error) {
+	defer rows.Close()
+
+	escalations := []Escalation{}
+	for rows.Next() {
+		escalation, err := scanEscalation(rows)
+		if err != nil {
+			return nil, err
+		}
+		escalations = append(escalations, escalation)
+	}
+	return escalations, rows.Err()
+}
+
+// notNil turns a nil slice into an empty one for the NOT NULL array columns.
+func notNil[T any](values []T) []T {
+	if values == nil {
+		return []T{}
+	}
+	return values
+}
+
+func scanPolicy(row pgx.Row) (Policy, error) {
+	var policy Policy
+	err := row.Scan(&policy.ID, &policy.UserID, &policy.OrganizationID, &policy.Name, &policy.Tags, &policy.Severities,
+		&policy.Schedule, &policy.ChannelIDs, &policy.EscalationChannelIDs, &policy.EscalateAfterMinutes, &policy.CreatedAt)
+	return policy, err
+}
+
+func scanEscalation(row pgx.Row) (Escalation, error) {
+	var escalation Escalation
+	err := row.Scan(&escalation.ID, &escalation.PolicyID, &escalation.ServiceID, &escalation.Notification,
+		&escalation.DueAt, &escalation.AcknowledgedAt, &escalation.AcknowledgedBy, &escalation.EscalatedAt,
+		&escalation.ResolvedAt, &escalation.CreatedAt)
+	return escalation, err
+}
+
+func scanChannel(row pgx.Row) (Channel, error) {
+	var channel Channel
+	err := row.Scan(&channel.ID, &channel.UserID, &channel.OrganizationID, &channel.Name, &channel.Type,
+		&channel.Config, &channel.ServiceIDs, &channel.EncryptedSecret, &channel.CreatedAt)
+	return channel, err
+}
@@ -0,0 +1,37 @@
+package notification
+
+import (
+	"context"
+	"errors"
+	"health-checker/internal/monitor"
+)
+
+// ErrPermanent marks a failed delivery that retrying cannot fix, such as a
+// webhook answering 404.
+var ErrPermanent = errors.New("permanent delivery failure")
+
+// Sender delivers notifications over channels of a single type. It returns
+// the response status code when the transport has one, and an error wrapping
+// ErrPermanent when the delivery must not be retried.
+type Sender interface {
+	Send(ctx context.Context, channel Channel, notification Notification) (int, error)
+}
+
+const (
+	kindDown      = "DOWN"
+	kindRecovered = "RECOVERED"
+	kindChanged   = "CHANGED"
+)
+
+// kindOf tells going DOWN and coming back UP after an outage apart from other
+// status changes, so messages can lead with them.
+func kindOf(notification Notification) string {
+	switch {
+	case notification.NewStatus == monitor.StatusDown:
+		return kindDown
+	case notification.NewStatus == monitor.StatusUp && notification.OldStatus == monitor.StatusDown:
+		return kindRecovered
+	default:
+		return kindChanged
+	}
+}
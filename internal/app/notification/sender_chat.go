@@ -0,0 +1,121 @@
+package notification
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"health-checker/internal/monitor"
+	"health-checker/internal/secrets"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// ChatFormatter renders a notification as the JSON body of a chat service's
+// API and returns the endpoint to POST it to.
+type ChatFormatter func(channel Channel, notification Notification) (endpoint string, payload any, err error)
+
+// ChatSender posts notifications to chat services that take a plain JSON POST,
+// such as Slack incoming webhooks. The formatter decides where to and how the
+// message looks.
+type ChatSender struct {
+	client *http.Client
+	format ChatFormatter
+}
+
+func NewChatSender(client *http.Client, format ChatFormatter) *ChatSender {
+	return &ChatSender{client: client, format: format}
+}
+
+func (s *ChatSender) Send(ctx context.Context, channel Channel, notification Notification) (int, error) {
+	endpoint, payload, err := s.format(channel, notification)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %v", ErrPermanent, err)
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return 0, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return 0, fmt.Errorf("%w: %v", ErrPermanent, withoutURL(err))
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "health-checker-webhook/1.0")
+
+	statusCode, err := doPost(s.client, req)
+	return statusCode, withoutURL(err)
+}
+
+// withoutURL drops the request URL from err. Incoming webhook URLs and
+// Telegram bot tokens are credentials, and errors end up in the delivery log.
+func withoutURL(err error) error {
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		return fmt.Errorf("%s: %w", urlErr.Op, urlErr.Err)
+	}
+	return err
+}
+
+// incomingWebhookURL unseals the incoming webhook URL of a chat channel.
+// Channels created before URLs were sealed still carry it in config.
+func incomingWebhookURL(channel Channel, config *IncomingWebhookConfig) (string, error) {
+	if channel.EncryptedSecret == "" {
+		return config.URL, nil
+	}
+	endpoint, err := secrets.Decrypt(channel.EncryptedSecret)
+	if err != nil {
+		return "", fmt.Errorf("decrypt incoming webhook URL: %w", err)
+	}
+	return string(endpoint), nil
+}
+
+// title is the headline of a chat message, such as "🔴 API is down".
+func title(notification Notification) string {
+	name := notification.ServiceName
+	if name == "" {
+		name = "Service #" + strconv.Itoa(notification.ServiceID)
+	}
+	if notification.Event == "escalation" {
+		return "🚨 Unacknowledged: " + name + " is down"
+	}
+	switch kindOf(notification) {
+	case kindDown:
+		return "🔴 " + name + " is down"
+	case kindRecovered:
+		return "✅ " + name + " is back up"
+	default:
+		if notification.NewStatus == monitor.StatusUp {
+			return "✅ " + name + " is UP"
+		}
+		return "🟡 " + name + " is " + string(notification.NewStatus)
+	}
+}
+
+// statusLine reads like "UP → DOWN".
+func statusLine(notification Notification) string {
+	return string(notification.OldStatus + " → " + notification.NewStatus)
+}
+
+func latencyLine(notification Notification) string {
+	return strconv.Itoa(notification.LatencyMs) + " ms"
+}
+
+func changedAt(notification Notification) string {
+	return notification.Timestamp.Format("2006-01-02 15:04:05 MST")
+}
+
+// statusColor is the RGB accent of a message: red when down, green when up
+// and amber otherwise.
+func statusColor(status monitor.Status) int {
+	switch status {
+	case monitor.StatusDown:
+		return 0xE01E5A
+	case monitor.StatusUp:
+		return 0x2EB67D
+	default:
+		return 0xECB22E
+	}
+}
@@ -0,0 +1,194 @@
+package notification
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"health-checker/internal/secrets"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var downNotification = Notification{
+	ServiceID:   9,
+	ServiceName: "API",
+	OldStatus:   "UP",
+	NewStatus:   "DOWN",
+	LatencyMs:   1203,
+	URL:         "https://health.example.com/api/v1/services/9",
+	Timestamp:   time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC),
+}
+
+// chatServer records the path and decoded JSON body of the last request and
+// answers with status.
+type chatServer struct {
+	*httptest.Server
+	path string
+	body map[string]any
+}
+
+func newChatServer(t *testing.T, status int) *chatServer {
+	s := &chatServer{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		s.path = r.URL.Path
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&s.body))
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+// get walks the decoded body along keys (map keys) and indexes (array positions).
+func (s *chatServer) get(path ...any) any {
+	var v any = s.body
+	for _, p := range path {
+		switch p := p.(type) {
+		case string:
+			v = v.(map[string]any)[p]
+		case int:
+			v = v.([]any)[p]
+		}
+	}
+	return v
+}
+
+func TestSlackSender(t *testing.T) {
+	os.Setenv("ENCRYPTION_KEY", "test-key")
+	defer os.Unsetenv("ENCRYPTION_KEY")
+
+	server := newChatServer(t, http.StatusOK)
+	endpoint, err := secrets.Encrypt([]byte(server.URL + "/services/T0/B0/x"))
+	require.NoError(t, err)
+	channel := Channel{Type: ChannelTypeSlack, Config: ChannelConfig{Slack: &IncomingWebhookConfig{URLHint: "…0/x"}}, EncryptedSecret: endpoint}
+
+	code, err := NewSlackSender(server.Client()).Send(context.Background(), channel, downNotification)
+
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "/services/T0/B0/x", server.path)
+	assert.Equal(t, "🔴 API is down", server.get("text"))
+	assert.Equal(t, "🔴 API is down", server.get("blocks", 0, "text", "text"))
+	assert.Equal(t, "*Status*\nUP → DOWN", server.get("blocks", 1, "fields", 0, "text"))
+	assert.Equal(t, "*Latency*\n1203 ms", server.get("blocks", 1, "fields", 1, "text"))
+	assert.Equal(t, downNotification.URL, server.get("blocks", 3, "elements", 0, "url"))
+}
+
+func TestSlackSender_WithoutURL(t *testing.T) {
+	// Channels created before URLs were sealed keep posting to the stored one.
+	server := newChatServer(t, http.StatusOK)
+	channel := Channel{Type: ChannelTypeSlack, Config: ChannelConfig{Slack: &IncomingWebhookConfig{URL: server.URL}}}
+	notification := downNotification
+	notification.URL = ""
+
+	_, err := NewSlackSender(server.Client()).Send(context.Background(), channel, notification)
+
+	require.NoError(t, err)
+	assert.Len(t, server.get("blocks"), 3)
+}
+
+func TestDiscordSender(t *testing.T) {
+	server := newChatServer(t, http.StatusNoContent)
+	channel := Channel{Type: ChannelTypeDiscord, Config: ChannelConfig{Discord: &IncomingWebhookConfig{URL: server.URL}}}
+	notification := downNotification
+	notification.OldStatus, notification.NewStatus = "DOWN", "UP"
+
+	code, err := NewDiscordSender(server.Client()).Send(context.Background(), channel, notification)
+
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusNoContent, code)
+	assert.Equal(t, "✅ API is back up", server.get("embeds", 0, "title"))
+	assert.Equal(t, downNotification.URL, server.get("embeds", 0, "url"))
+	assert.EqualValues(t, 0x2EB67D, server.get("embeds", 0, "color"))
+	assert.Equal(t, "DOWN → UP", server.get("embeds", 0, "fields", 0, "value"))
+	assert.Equal(t, "1203 ms", server.get("embeds", 0, "fields", 1, "value"))
+	assert.Equal(t, "2024-01-01T12:00:00Z", server.get("embeds", 0, "timestamp"))
+	assert.Empty(t, server.get("allowed_mentions", "parse"))
+}
+
+func TestTeamsSender(t *testing.T) {
+	server := newChatServer(t, http.StatusAccepted)
+	channel := Channel{Type: ChannelTypeTeams, Config: ChannelConfig{Teams: &IncomingWebhookConfig{URL: server.URL}}}
+
+	_, err := NewTeamsSender(server.Client()).Send(context.Background(), channel, downNotification)
+
+	require.NoError(t, err)
+	assert.Equal(t, "message", server.get("type"))
+	assert.Equal(t, "application/vnd.microsoft.card.adaptive", server.get("attachments", 0, "contentType"))
+	card := []any{"attachments", 0, "content"}
+	assert.Equal(t, "AdaptiveCard", server.get(append(card, "type")...))
+	assert.Equal(t, "🔴 API is down", server.get(append(card, "body", 0, "text")...))
+	assert.Equal(t, "Attention", server.get(append(card, "body", 0, "color")...))
+	assert.Equal(t, "UP → DOWN", server.get(append(card, "body", 1, "facts", 0, "value")...))
+	assert.Equal(t, "1203 ms", server.get(append(card, "body", 1, "facts", 1, "value")...))
+	assert.Equal(t, downNotification.URL, server.get(append(card, "actions", 0, "url")...))
+}
+
+func TestTelegramSender(t *testing.T) {
+	os.Setenv("ENCRYPTION_KEY", "test-key")
+	defer os.Unsetenv("ENCRYPTION_KEY")
+
+	server := newChatServer(t, http.StatusOK)
+	token, err := secrets.Encrypt([]byte("123:secret"))
+	require.NoError(t, err)
+	channel := Channel{Type: ChannelTypeTelegram, Config: ChannelConfig{Telegram: &TelegramConfig{ChatID: "-100"}}, EncryptedSecret: token}
+	notification := downNotification
+	notification.ServiceName = "<API>"
+
+	_, err = NewTelegramSender(server.Client(), server.URL).Send(context.Background(), channel, notification)
+
+	require.NoError(t, err)
+	assert.Equal(t, "/bot123:secret/sendMessage", server.path)
+	assert.Equal(t, "-100", server.get("chat_id"))
+	assert.Equal(t, "HTML", server.get("parse_mode"))
+	assert.Equal(t, "<b>🔴 &lt;API&gt; is down</b>\n"+
+		"Status: UP → DOWN\n"+
+		"Latency: 1203 ms\n"+
+		"Changed at: 2024-01-01 12:00:00 UTC\n"+
+		"<a href=\"https://health.example.com/api/v1/services/9\">View service</a>", server.get("text"))
+}
+
+func TestTelegramSender_ErrorsHideToken(t *testing.T) {
+	os.Setenv("ENCRYPTION_KEY", "test-key")
+	defer os.Unsetenv("ENCRYPTION_KEY")
+
+	server := newChatServer(t, http.StatusOK)
+	server.Close()
+	token, err := secrets.Encrypt([]byte("123:secret"))
+	require.NoError(t, err)
+	channel := Channel{Type: ChannelTypeTelegram, Config: ChannelConfig{Telegram: &TelegramConfig{ChatID: "-100"}}, EncryptedSecret: token}
+
+	_, err = NewTelegramSender(http.DefaultClient, server.URL).Send(context.Background(), channel, downNotification)
+
+	require.Error(t, err)
+	assert.NotContains(t, err.Error(), "123:secret")
+	assert.False(t, errors.Is(err, ErrPermanent))
+}
+
+func TestChatSender_ClientErrorIsPermanent(t *testing.T) {
+	server := newChatServer(t, http.StatusNotFound)
+	channel := Channel{Type: ChannelTypeSlack, Config: ChannelConfig{Slack: &IncomingWebhookConfig{URL: server.URL}}}
+
+	code, err := NewSlackSender(server.Client()).Send(context.Background(), channel, downNotification)
+
+	assert.Equal(t, http.StatusNotFound, code)
+	assert.True(t, errors.Is(err, ErrPermanent))
+}
+
+func TestChatSender_MissingConfigIsPermanent(t *testing.T) {
+	for channelType, sender := range map[string]Sender{
+		ChannelTypeSlack:    NewSlackSender(http.DefaultClient),
+		ChannelTypeDiscord:  NewDiscordSender(http.DefaultClient),
+		ChannelTypeTeams:    NewTeamsSender(http.DefaultClient),
+		ChannelTypeTelegram: NewTelegramSender(http.DefaultClient, TelegramAPIURL),
+	} {
+		_, err := sender.Send(context.Background(), Channel{Type: channelType}, downNotification)
+		assert.True(t, errors.Is(err, ErrPermanent), channelType)
+	}
+}
@@ -0,0 +1,42 @@
+package notification
+
+import (
+	"errors"
+	"net/http"
+	"time"
+)
+
+func NewDiscordSender(client *http.Client) *ChatSender {
+	return NewChatSender(client, formatDiscord)
+}
+
+// formatDiscord renders an embed for a Discord webhook, colored by the new
+// status and titled with a link back to the service.
+func formatDiscord(channel Channel, notification Notification) (string, any, error) {
+	if channel.Config.Discord == nil {
+		return "", nil, errors.New("discord config is missing")
+	}
+	endpoint, err := incomingWebhookURL(channel, channel.Config.Discord)
+	if err != nil {
+		return "", nil, err
+	}
+
+	embed := map[string]any{
+		"title": title(notification),
+		"color": statusColor(notification.NewStatus),
+		"fields": []map[string]any{
+			{"name": "Status", "value": statusLine(notification), "inline": true},
+			{"name": "Latency", "value": latencyLine(notification), "inline": true},
+		},
+		"timestamp": notification.Timestamp.UTC().Format(time.RFC3339),
+	}
+	if notification.URL != "" {
+		embed["url"] = notification.URL
+	}
+
+	return endpoint, map[string]any{
+		"embeds": []map[string]any{embed},
+		// A service named "@everyone" must not ping the whole server.
+		"allowed_mentions": map[string]any{"parse": []string{}},
+	}, nil
+}
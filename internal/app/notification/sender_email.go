@@ -0,0 +1,165 @@
+package notification
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"net/textproto"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// SMTPConfig is the server email channels send through. Username may be empty
+// for relays that do not require authentication.
+type SMTPConfig struct {
+	Host     string
+	Port     string
+	Username string
+	Password string
+	From     string
+}
+
+// EmailSender sends a templated plain text email to the channel's recipients.
+// The connection is upgraded with STARTTLS whenever the server offers it.
+type EmailSender struct {
+	config  SMTPConfig
+	timeout time.Duration
+}
+
+func NewEmailSender(config SMTPConfig) *EmailSender {
+	return &EmailSender{config: config, timeout: senderTimeout}
+}
+
+var (
+	emailSubjects = template.Must(template.New("subject").Parse(
+		`{{if eq .Event "escalation"}}[ESCALATED] {{end}}` +
+			`{{if eq .Kind "DOWN"}}[DOWN] {{.ServiceName}} is down` +
+			`{{else if eq .Kind "RECOVERED"}}[RECOVERED] {{.ServiceName}} is back up` +
+			`{{else}}[{{.NewStatus}}] {{.ServiceName}} changed status{{end}}`))
+	emailBodies = template.Must(template.New("body").Parse(
+		`{{if eq .Event "escalation"}}Nobody acknowledged this alert in time, so it is escalated to you.
+
+{{end}}{{if eq .Kind "DOWN"}}{{.ServiceName}} is DOWN.{{else if eq .Kind "RECOVERED"}}{{.ServiceName}} has RECOVERED.{{else}}{{.ServiceName}} is {{.NewStatus}}.{{end}}
+
+Service:    {{.ServiceName}} (#{{.ServiceID}})
+Status:     {{.OldStatus}} -> {{.NewStatus}}
+Latency:    {{.LatencyMs}} ms
+Changed at: {{.Timestamp.Format "2006-01-02 15:04:05 MST"}}
+{{- if .URL}}
+Details:    {{.URL}}{{end}}
+
+You receive this email because you are a recipient of a Health Checker notification channel.
+`))
+)
+
+var lineBreaks = strings.NewReplacer("\r", " ", "\n", " ")
+
+// emailData is what the email templates are rendered with.
+type emailData struct {
+	Notification
+	// Kind is one of kindDown, kindRecovered and kindChanged.
+	Kind string
+}
+
+func (s *EmailSender) Send(ctx context.Context, channel Channel, notification Notification) (int, error) {
+	if channel.Config.Email == nil || len(channel.Config.Email.Recipients) == 0 {
+		return 0, fmt.Errorf("%w: email recipients are missing", ErrPermanent)
+	}
+
+	msg, err := s.message(channel.Config.Email.Recipients, notification)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %v", ErrPermanent, err)
+	}
+
+	err = s.sendMail(ctx, channel.Config.Email.Recipients, msg)
+	var smtpErr *textproto.Error
+	if errors.As(err, &smtpErr) {
+		if smtpErr.Code >= 500 {
+			return smtpErr.Code, fmt.Errorf("%w: %v", ErrPermanent, err)
+		}
+		return smtpErr.Code, err
+	}
+	return 0, err
+}
+
+// message renders the notification into an RFC 5322 message.
+func (s *EmailSender) message(recipients []string, notification Notification) ([]byte, error) {
+	// Service names are user input; keep them on one line.
+	notification.ServiceName = lineBreaks.Replace(notification.ServiceName)
+	data := emailData{Notification: notification, Kind: kindOf(notification)}
+
+	var subject, body bytes.Buffer
+	if err := emailSubjects.Execute(&subject, data); err != nil {
+		return nil, err
+	}
+	if err := emailBodies.Execute(&body, data); err != nil {
+		return nil, err
+	}
+
+	var msg bytes.Buffer
+	fmt.Fprintf(&msg, "From: %s\r\n", s.config.From)
+	fmt.Fprintf(&msg, "To: %s\r\n", strings.Join(recipients, ", "))
+	fmt.Fprintf(&msg, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", subject.String()))
+	fmt.Fprintf(&msg, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	msg.WriteString("MIME-Version: 1.0\r\n")
+	msg.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	msg.WriteString("\r\n")
+	msg.WriteString(strings.ReplaceAll(body.String(), "\n", "\r\n"))
+	return msg.Bytes(), nil
+}
+
+// sendMail is smtp.SendMail with a deadline on the whole conversation.
+func (s *EmailSender) sendMail(ctx context.Context, recipients []string, msg []byte) error {
+	dialer := net.Dialer{Timeout: s.timeout}
+	conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(s.config.Host, s.config.Port))
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	if err := conn.SetDeadline(time.Now().Add(s.timeout)); err != nil {
+		return err
+	}
+
+	client, err := smtp.NewClient(conn, s.config.Host)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: s.config.Host}); err != nil {
+			return err
+		}
+	}
+	if s.config.Username != "" {
+		if err := client.Auth(smtp.PlainAuth("", s.config.Username, s.config.Password, s.config.Host)); err != nil {
+			return err
+		}
+	}
+	if err := client.Mail(s.config.From); err != nil {
+		return err
+	}
+	for _, recipient := range recipients {
+		if err := client.Rcpt(recipient); err != nil {
+			return err
+		}
+	}
+
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(msg); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return client.Quit()
+}
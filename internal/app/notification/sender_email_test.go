@@ -0,0 +1,159 @@
+package notification
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeSMTP is a minimal SMTP listener that records one message. rcptReply
+// overrides the reply to RCPT TO, to simulate rejected recipients.
+type fakeSMTP struct {
+	listener   net.Listener
+	rcptReply  string
+	from       string
+	recipients []string
+	data       string
+	done       chan struct{}
+}
+
+func newFakeSMTP(t *testing.T, rcptReply string) *fakeSMTP {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	f := &fakeSMTP{listener: listener, rcptReply: rcptReply, done: make(chan struct{})}
+	go f.serve()
+	t.Cleanup(func() { listener.Close() })
+	return f
+}
+
+func (f *fakeSMTP) serve() {
+	defer close(f.done)
+	conn, err := f.listener.Accept()
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	r := bufio.NewReader(conn)
+	reply := func(line string) { conn.Write([]byte(line + "\r\n")) }
+	reply("220 fake.smtp ESMTP")
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		line = strings.TrimRight(line, "\r\n")
+		command := strings.ToUpper(line)
+		switch {
+		case strings.HasPrefix(command, "EHLO"), strings.HasPrefix(command, "HELO"):
+			reply("250 fake.smtp")
+		case strings.HasPrefix(command, "MAIL FROM:"):
+			f.from = strings.Trim(line[len("MAIL FROM:"):], "<>")
+			reply("250 OK")
+		case strings.HasPrefix(command, "RCPT TO:"):
+			if f.rcptReply != "" {
+				reply(f.rcptReply)
+				continue
+			}
+			f.recipients = append(f.recipients, strings.Trim(line[len("RCPT TO:"):], "<>"))
+			reply("250 OK")
+		case command == "DATA":
+			reply("354 End data with <CR><LF>.<CR><LF>")
+			var data strings.Builder
+			for {
+				l, err := r.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if l == ".\r\n" {
+					break
+				}
+				data.WriteString(l)
+			}
+			f.data = data.String()
+			reply("250 OK")
+		case command == "QUIT":
+			reply("221 Bye")
+			return
+		default:
+			reply("250 OK")
+		}
+	}
+}
+
+func (f *fakeSMTP) sender() *EmailSender {
+	host, port, _ := net.SplitHostPort(f.listener.Addr().String())
+	return NewEmailSender(SMTPConfig{Host: host, Port: port, From: "alerts@example.com"})
+}
+
+func emailChannel(recipients ...string) Channel {
+	return Channel{ID: 3, Type: ChannelTypeEmail, Config: ChannelConfig{Email: &EmailConfig{Recipients: recipients}}}
+}
+
+func TestEmailSender_Down(t *testing.T) {
+	server := newFakeSMTP(t, "")
+
+	_, err := server.sender().Send(context.Background(), emailChannel("oncall@example.com", "lead@example.com"), Notification{
+		ServiceID: 9, ServiceName: "API", OldStatus: "UP", NewStatus: "DOWN", Timestamp: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC),
+	})
+	require.NoError(t, err)
+	<-server.done
+
+	assert.Equal(t, "alerts@example.com", server.from)
+	assert.Equal(t, []string{"oncall@example.com", "lead@example.com"}, server.recipients)
+	assert.Contains(t, server.data, "Subject: [DOWN] API is down\r\n")
+	assert.Contains(t, server.data, "To: oncall@example.com, lead@example.com\r\n")
+	assert.Contains(t, server.data, "Status:     UP -> DOWN\r\n")
+	assert.Contains(t, server.data, "Changed at: 2024-01-01 12:00:00 UTC\r\n")
+}
+
+func TestEmailSender_Recovered(t *testing.T) {
+	server := newFakeSMTP(t, "")
+
+	_, err := server.sender().Send(context.Background(), emailChannel("oncall@example.com"), Notification{
+		ServiceName: "API", OldStatus: "DOWN", NewStatus: "UP",
+	})
+	require.NoError(t, err)
+	<-server.done
+
+	assert.Contains(t, server.data, "Subject: [RECOVERED] API is back up\r\n")
+	assert.Contains(t, server.data, "API has RECOVERED.")
+}
+
+func TestEmailSender_HeaderInjection(t *testing.T) {
+	server := newFakeSMTP(t, "")
+
+	_, err := server.sender().Send(context.Background(), emailChannel("oncall@example.com"), Notification{
+		ServiceName: "API\r\nBcc: attacker@example.com", OldStatus: "UP", NewStatus: "DOWN",
+	})
+	require.NoError(t, err)
+	<-server.done
+
+	assert.NotContains(t, server.data, "\r\nBcc:")
+}
+
+func TestEmailSender_RejectedRecipientIsPermanent(t *testing.T) {
+	server := newFakeSMTP(t, "550 No such user")
+
+	code, err := server.sender().Send(context.Background(), emailChannel("nobody@example.com"), Notification{ServiceName: "API", NewStatus: "DOWN"})
+
+	assert.Equal(t, 550, code)
+	assert.True(t, errors.Is(err, ErrPermanent))
+}
+
+func TestEmailSender_TemporaryFailureIsRetried(t *testing.T) {
+	server := newFakeSMTP(t, "451 Try again later")
+
+	code, err := server.sender().Send(context.Background(), emailChannel("oncall@example.com"), Notification{ServiceName: "API", NewStatus: "DOWN"})
+
+	assert.Equal(t, 451, code)
+	assert.Error(t, err)
+	assert.False(t, errors.Is(err, ErrPermanent))
+}
@@ -0,0 +1,43 @@
+package notification
+
+import (
+	"errors"
+	"net/http"
+)
+
+func NewSlackSender(client *http.Client) *ChatSender {
+	return NewChatSender(client, formatSlack)
+}
+
+// formatSlack renders a Block Kit message for a Slack incoming webhook: a
+// header, status and latency side by side, and a button back to the service.
+func formatSlack(channel Channel, notification Notification) (string, any, error) {
+	if channel.Config.Slack == nil {
+		return "", nil, errors.New("slack config is missing")
+	}
+	endpoint, err := incomingWebhookURL(channel, channel.Config.Slack)
+	if err != nil {
+		return "", nil, err
+	}
+
+	blocks := []map[string]any{
+		{"type": "header", "text": map[string]any{"type": "plain_text", "text": title(notification)}},
+		{"type": "section", "fields": []map[string]any{
+			{"type": "mrkdwn", "text": "*Status*\n" + statusLine(notification)},
+			{"type": "mrkdwn", "text": "*Latency*\n" + latencyLine(notification)},
+		}},
+		{"type": "context", "elements": []map[string]any{
+			{"type": "plain_text", "text": "Changed at " + changedAt(notification)},
+		}},
+	}
+	if notification.URL != "" {
+		blocks = append(blocks, map[string]any{"type": "actions", "elements": []map[string]any{{
+			"type": "button",
+			"text": map[string]any{"type": "plain_text", "text": "View service"},
+			"url":  notification.URL,
+		}}})
+	}
+
+	// text is the fallback shown in notifications and by clients without blocks.
+	return endpoint, map[string]any{"text": title(notification), "blocks": blocks}, nil
+}
@@ -0,0 +1,67 @@
+package notification
+
+import (
+	"errors"
+	"health-checker/internal/monitor"
+	"net/http"
+)
+
+func NewTeamsSender(client *http.Client) *ChatSender {
+	return NewChatSender(client, formatTeams)
+}
+
+// formatTeams renders an adaptive card for a Microsoft Teams incoming webhook
+// or Workflows trigger.
+func formatTeams(channel Channel, notification Notification) (string, any, error) {
+	if channel.Config.Teams == nil {
+		return "", nil, errors.New("teams config is missing")
+	}
+	endpoint, err := incomingWebhookURL(channel, channel.Config.Teams)
+	if err != nil {
+		return "", nil, err
+	}
+
+	card := map[string]any{
+		"$schema": "http://adaptivecards.io/schemas/adaptive-card.json",
+		"type":    "AdaptiveCard",
+		"version": "1.4",
+		"body": []map[string]any{
+			{
+				"type":   "TextBlock",
+				"text":   title(notification),
+				"size":   "Medium",
+				"weight": "Bolder",
+				"color":  teamsColor(notification.NewStatus),
+				"wrap":   true,
+			},
+			{"type": "FactSet", "facts": []map[string]any{
+				{"title": "Status", "value": statusLine(notification)},
+				{"title": "Latency", "value": latencyLine(notification)},
+				{"title": "Changed at", "value": changedAt(notification)},
+			}},
+		},
+	}
+	if notification.URL != "" {
+		card["actions"] = []map[string]any{{"type": "Action.OpenUrl", "title": "View service", "url": notification.URL}}
+	}
+
+	return endpoint, map[string]any{
+		"type": "message",
+		"attachments": []map[string]any{{
+			"contentType": "application/vnd.microsoft.card.adaptive",
+			"content":     card,
+		}},
+	}, nil
+}
+
+// teamsColor maps a status onto the adaptive card color palette.
+func teamsColor(status monitor.Status) string {
+	switch status {
+	case monitor.StatusDown:
+		return "Attention"
+	case monitor.StatusUp:
+		return "Good"
+	default:
+		return "Warning"
+	}
+}
@@ -0,0 +1,48 @@
+package notification
+
+import (
+	"errors"
+	"fmt"
+	"health-checker/internal/secrets"
+	"html"
+	"net/http"
+	"strings"
+)
+
+// TelegramAPIURL is the Bot API server Telegram channels post through.
+const TelegramAPIURL = "https://api.telegram.org"
+
+// NewTelegramSender sends messages through the Bot API at apiURL, using the
+// channel's sealed bot token.
+func NewTelegramSender(client *http.Client, apiURL string) *ChatSender {
+	return NewChatSender(client, func(channel Channel, notification Notification) (string, any, error) {
+		return formatTelegram(apiURL, channel, notification)
+	})
+}
+
+// formatTelegram renders an HTML sendMessage call.
+func formatTelegram(apiURL string, channel Channel, notification Notification) (string, any, error) {
+	if channel.Config.Telegram == nil {
+		return "", nil, errors.New("telegram config is missing")
+	}
+	token, err := secrets.Decrypt(channel.EncryptedSecret)
+	if err != nil {
+		return "", nil, fmt.Errorf("decrypt bot token: %w", err)
+	}
+
+	var text strings.Builder
+	fmt.Fprintf(&text, "<b>%s</b>\n", html.EscapeString(title(notification)))
+	fmt.Fprintf(&text, "Status: %s\n", html.EscapeString(statusLine(notification)))
+	fmt.Fprintf(&text, "Latency: %s\n", latencyLine(notification))
+	fmt.Fprintf(&text, "Changed at: %s", changedAt(notification))
+	if notification.URL != "" {
+		fmt.Fprintf(&text, "\n<a href=\"%s\">View service</a>", html.EscapeString(notification.URL))
+	}
+
+	return apiURL + "/bot" + string(token) + "/sendMessage", map[string]any{
+		"chat_id":                  channel.Config.Telegram.ChatID,
+		"text":                     text.String(),
+		"parse_mode":               "HTML",
+		"disable_web_page_preview": true,
+	}, nil
+}
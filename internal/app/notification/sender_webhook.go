@@ -0,0 +1,87 @@
+package notification
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"health-checker/internal/secrets"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	SignatureHeader = "X-Webhook-Signature"
+	TimestampHeader = "X-Webhook-Timestamp"
+)
+
+// WebhookSender POSTs the notification as JSON. Each request is signed with
+// the channel's secret so receivers can check it came from us; see Sign.
+type WebhookSender struct {
+	client *http.Client
+}
+
+func NewWebhookSender(client *http.Client) *WebhookSender {
+	return &WebhookSender{client: client}
+}
+
+func (s *WebhookSender) Send(ctx context.Context, channel Channel, notification Notification) (int, error) {
+	if channel.Config.Webhook == nil {
+		return 0, fmt.Errorf("%w: webhook config is missing", ErrPermanent)
+	}
+	secret, err := secrets.Decrypt(channel.EncryptedSecret)
+	if err != nil {
+		return 0, fmt.Errorf("%w: decrypt secret: %v", ErrPermanent, err)
+	}
+
+	body, err := json.Marshal(notification)
+	if err != nil {
+		return 0, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, channel.Config.Webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, fmt.Errorf("%w: %v", ErrPermanent, err)
+	}
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "health-checker-webhook/1.0")
+	req.Header.Set(TimestampHeader, timestamp)
+	req.Header.Set(SignatureHeader, "sha256="+Sign(secret, timestamp, body))
+
+	return doPost(s.client, req)
+}
+
+// Sign returns the hex HMAC-SHA256 of "<timestamp>.<body>" under secret.
+// Receivers recompute it and should reject stale timestamps to stop replays.
+func Sign(secret []byte, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// doPost sends req and classifies the response: 2xx succeeds, 408, 429 and
+// 5xx are worth retrying and any other status is permanent.
+func doPost(client *http.Client, req *http.Request) (int, error) {
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		return resp.StatusCode, nil
+	case resp.StatusCode == http.StatusRequestTimeout, resp.StatusCode == http.StatusTooManyRequests, resp.StatusCode >= 500:
+		return resp.StatusCode, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	default:
+		return resp.StatusCode, fmt.Errorf("%w: unexpected status %d", ErrPermanent, resp.StatusCode)
+	}
+}
@@ -0,0 +1,209 @@
+package notification
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"health-checker/internal/monitor"
+	"health-checker/internal/secrets"
+	"slices"
+
+	"go.uber.org/zap"
+)
+
+// defaultDeliveriesLimit caps how many delivery attempts are listed per request.
+const defaultDeliveriesLimit = 50
+
+type NotificationService struct {
+	repo Repository
+	log  *zap.Logger
+}
+
+func NewService(repo Repository, log *zap.Logger) *NotificationService {
+	return &NotificationService{repo: repo, log: log}
+}
+
+// CreateChannel adds a channel for owner's services, generating a signing
+// secret for webhooks and sealing the incoming webhook URL of chat channels
+// and the bot token of Telegram channels.
+// Validation failures wrap ErrInvalidChannel.
+func (s *NotificationService) CreateChannel(ctx context.Context, owner monitor.Owner, dto CreateChannelDTO) (CreatedChannel, error) {
+	if err := validateConfig(dto.Type, dto.Config); err != nil {
+		return CreatedChannel{}, err
+	}
+
+	channel := Channel{
+		UserID:         owner.UserID,
+		OrganizationID: owner.OrgID,
+		Name:           dto.Name,
+		Type:           dto.Type,
+		Config:         dto.Config,
+		ServiceIDs:     dto.ServiceIDs,
+	}
+
+	var secret string
+	switch dto.Type {
+	case ChannelTypeWebhook:
+		var err error
+		if secret, err = newSecret(); err != nil {
+			return CreatedChannel{}, err
+		}
+		if channel.EncryptedSecret, err = secrets.Encrypt([]byte(secret)); err != nil {
+			return CreatedChannel{}, err
+		}
+	case ChannelTypeTelegram:
+		var err error
+		if channel.EncryptedSecret, err = secrets.Encrypt([]byte(dto.Config.Telegram.BotToken)); err != nil {
+			return CreatedChannel{}, err
+		}
+		telegram := *dto.Config.Telegram
+		telegram.BotToken = ""
+		channel.Config.Telegram = &telegram
+	case ChannelTypeSlack, ChannelTypeDiscord, ChannelTypeTeams:
+		webhook := dto.Config.incomingWebhook(dto.Type)
+		var err error
+		if channel.EncryptedSecret, err = secrets.Encrypt([]byte(webhook.URL)); err != nil {
+			return CreatedChannel{}, err
+		}
+		channel.Config = channel.Config.withIncomingWebhook(dto.Type, webhook.sealed())
+	}
+
+	created, err := s.repo.CreateChannel(ctx, channel)
+	if err != nil {
+		return CreatedChannel{}, err
+	}
+
+	return CreatedChannel{Channel: created, Secret: secret}, nil
+}
+
+// ListChannels returns owner's channels. Chat channels created before their
+// incoming webhook URL was sealed still store it in their config; it is
+// replaced by its hint.
+func (s *NotificationService) ListChannels(ctx context.Context, owner monitor.Owner) ([]Channel, error) {
+	channels, err := s.repo.ListChannels(ctx, owner)
+	if err != nil {
+		return nil, err
+	}
+	for i, channel := range channels {
+		if webhook := channel.Config.incomingWebhook(channel.Type); webhook != nil && webhook.URL != "" {
+			channels[i].Config = channel.Config.withIncomingWebhook(channel.Type, webhook.sealed())
+		}
+	}
+	return channels, nil
+}
+
+func (s *NotificationService) DeleteChannel(ctx context.Context, owner monitor.Owner, id int) error {
+	return s.repo.DeleteChannel(ctx, owner, id)
+}
+
+// ListDeliveries returns ErrChannelNotFound unless owner owns the channel.
+func (s *NotificationService) ListDeliveries(ctx context.Context, owner monitor.Owner, channelID, limit int) ([]Delivery, error) {
+	if _, err := s.repo.GetChannel(ctx, owner, channelID); err != nil {
+		return nil, err
+	}
+	if limit <= 0 || limit > defaultDeliveriesLimit {
+		limit = defaultDeliveriesLimit
+	}
+	return s.repo.ListDeliveries(ctx, channelID, limit)
+}
+
+// CreatePolicy adds a routing policy for owner's services. Its channels must
+// be owner's. Validation failures wrap ErrInvalidPolicy.
+func (s *NotificationService) CreatePolicy(ctx context.Context, owner monitor.Owner, dto CreatePolicyDTO) (Policy, error) {
+	if dto.Schedule != nil {
+		if err := dto.Schedule.Validate(); err != nil {
+			return Policy{}, fmt.Errorf("%w: schedule: %v", ErrInvalidPolicy, err)
+		}
+	}
+	if (dto.EscalateAfterMinutes > 0) != (len(dto.EscalationChannelIDs) > 0) {
+		return Policy{}, fmt.Errorf("%w: escalate_after_minutes and escalation_channel_ids must be set together", ErrInvalidPolicy)
+	}
+
+	channels, err := s.repo.ListChannels(ctx, owner)
+	if err != nil {
+		return Policy{}, err
+	}
+	for _, id := range append(slices.Clone(dto.ChannelIDs), dto.EscalationChannelIDs...) {
+		if !slices.ContainsFunc(channels, func(channel Channel) bool { return channel.ID == id }) {
+			return Policy{}, fmt.Errorf("%w: unknown channel %d", ErrInvalidPolicy, id)
+		}
+	}
+
+	return s.repo.CreatePolicy(ctx, Policy{
+		UserID:               owner.UserID,
+		OrganizationID:       owner.OrgID,
+		Name:                 dto.Name,
+		Tags:                 dto.Tags,
+		Severities:           dto.Severities,
+		Schedule:             dto.Schedule,
+		ChannelIDs:           dto.ChannelIDs,
+		EscalationChannelIDs: dto.EscalationChannelIDs,
+		EscalateAfterMinutes: dto.EscalateAfterMinutes,
+	})
+}
+
+func (s *NotificationService) ListPolicies(ctx context.Context, owner monitor.Owner) ([]Policy, error) {
+	return s.repo.ListPolicies(ctx, owner)
+}
+
+func (s *NotificationService) DeletePolicy(ctx context.Context, owner monitor.Owner, id int) error {
+	return s.repo.DeletePolicy(ctx, owner, id)
+}
+
+func (s *NotificationService) ListOpenEscalations(ctx context.Context, owner monitor.Owner) ([]Escalation, error) {
+	return s.repo.ListOpenEscalations(ctx, owner)
+}
+
+// AcknowledgeEscalation stops an alert from being escalated; userID is
+// recorded as the responder.
+func (s *NotificationService) AcknowledgeEscalation(ctx context.Context, owner monitor.Owner, id, userID int) (Escalation, error) {
+	return s.repo.AcknowledgeEscalation(ctx, owner, id, userID)
+}
+
+// AcknowledgeServiceEscalations acknowledges the open alerts of a service on
+// behalf of userID, such as when its incident is acknowledged. The caller
+// must have checked that the service belongs to the user.
+func (s *NotificationService) AcknowledgeServiceEscalations(ctx context.Context, serviceID, userID int) error {
+	return s.repo.AcknowledgeServiceEscalations(ctx, serviceID, userID)
+}
+
+func validateConfig(channelType string, config ChannelConfig) error {
+	switch channelType {
+	case ChannelTypeWebhook:
+		if config.Webhook == nil {
+			return fmt.Errorf("%w: config.webhook is required", ErrInvalidChannel)
+		}
+	case ChannelTypeEmail:
+		if config.Email == nil {
+			return fmt.Errorf("%w: config.email is required", ErrInvalidChannel)
+		}
+	case ChannelTypeSlack:
+		if config.Slack == nil {
+			return fmt.Errorf("%w: config.slack is required", ErrInvalidChannel)
+		}
+	case ChannelTypeDiscord:
+		if config.Discord == nil {
+			return fmt.Errorf("%w: config.discord is required", ErrInvalidChannel)
+		}
+	case ChannelTypeTeams:
+		if config.Teams == nil {
+			return fmt.Errorf("%w: config.teams is required", ErrInvalidChannel)
+		}
+	case ChannelTypeTelegram:
+		if config.Telegram == nil || config.Telegram.BotToken == "" {
+			return fmt.Errorf("%w: config.telegram with a bot_token is required", ErrInvalidChannel)
+		}
+	default:
+		return fmt.Errorf("%w: unknown type %q", ErrInvalidChannel, channelType)
+	}
+	return nil
+}
+
+func newSecret() (string, error) {
+	secret := make([]byte, 24)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+	return "whsec_" + hex.EncodeToString(secret), nil
+}
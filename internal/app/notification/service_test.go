@@ -0,0 +1,167 @@
+package notification
+
+import (
+	"context"
+	"health-checker/internal/monitor"
+	"health-checker/internal/secrets"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+)
+
+func TestService_CreateChannel(t *testing.T) {
+	os.Setenv("ENCRYPTION_KEY", "test-key")
+	defer os.Unsetenv("ENCRYPTION_KEY")
+
+	t.Run("EncryptsSecret", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		service := NewService(mockRepo, zap.L())
+
+		var stored Channel
+		mockRepo.On("CreateChannel", mock.Anything, mock.AnythingOfType("notification.Channel")).
+			Run(func(args mock.Arguments) { stored = args.Get(1).(Channel) }).
+			Return(Channel{ID: 1}, nil)
+
+		owner := monitor.Owner{UserID: 4, OrgID: 7}
+		dto := CreateChannelDTO{Name: "ops", Type: ChannelTypeWebhook, Config: ChannelConfig{Webhook: &WebhookConfig{URL: "https://example.com/hook"}}}
+		created, err := service.CreateChannel(context.Background(), owner, dto)
+
+		assert.NoError(t, err)
+		assert.True(t, strings.HasPrefix(created.Secret, "whsec_"))
+		assert.Equal(t, 7, stored.OrganizationID)
+		assert.NotContains(t, stored.EncryptedSecret, created.Secret)
+		secret, err := secrets.Decrypt(stored.EncryptedSecret)
+		assert.NoError(t, err)
+		assert.Equal(t, created.Secret, string(secret))
+	})
+
+	t.Run("EmailHasNoSecret", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		service := NewService(mockRepo, zap.L())
+
+		var stored Channel
+		mockRepo.On("CreateChannel", mock.Anything, mock.AnythingOfType("notification.Channel")).
+			Run(func(args mock.Arguments) { stored = args.Get(1).(Channel) }).
+			Return(Channel{ID: 2}, nil)
+
+		dto := CreateChannelDTO{
+			Name:       "on-call",
+			Type:       ChannelTypeEmail,
+			Config:     ChannelConfig{Email: &EmailConfig{Recipients: []string{"oncall@example.com"}}},
+			ServiceIDs: []int{9},
+		}
+		created, err := service.CreateChannel(context.Background(), monitor.Owner{UserID: 4}, dto)
+
+		assert.NoError(t, err)
+		assert.Empty(t, created.Secret)
+		assert.Empty(t, stored.EncryptedSecret)
+		assert.Equal(t, []int{9}, stored.ServiceIDs)
+	})
+
+	t.Run("SealsTelegramBotToken", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		service := NewService(mockRepo, zap.L())
+
+		var stored Channel
+		mockRepo.On("CreateChannel", mock.Anything, mock.AnythingOfType("notification.Channel")).
+			Run(func(args mock.Arguments) { stored = args.Get(1).(Channel) }).
+			Return(Channel{ID: 3}, nil)
+
+		dto := CreateChannelDTO{
+			Name:   "on-call",
+			Type:   ChannelTypeTelegram,
+			Config: ChannelConfig{Telegram: &TelegramConfig{ChatID: "-100", BotToken: "123:secret"}},
+		}
+		created, err := service.CreateChannel(context.Background(), monitor.Owner{UserID: 4}, dto)
+
+		assert.NoError(t, err)
+		assert.Empty(t, created.Secret)
+		assert.Equal(t, "-100", stored.Config.Telegram.ChatID)
+		assert.Empty(t, stored.Config.Telegram.BotToken)
+		token, err := secrets.Decrypt(stored.EncryptedSecret)
+		assert.NoError(t, err)
+		assert.Equal(t, "123:secret", string(token))
+	})
+
+	t.Run("SealsIncomingWebhookURL", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		service := NewService(mockRepo, zap.L())
+
+		var stored Channel
+		mockRepo.On("CreateChannel", mock.Anything, mock.AnythingOfType("notification.Channel")).
+			Run(func(args mock.Arguments) { stored = args.Get(1).(Channel) }).
+			Return(Channel{ID: 4}, nil)
+
+		url := "https://hooks.slack.com/services/T000/B000/XXXX"
+		dto := CreateChannelDTO{Name: "#ops", Type: ChannelTypeSlack, Config: ChannelConfig{Slack: &IncomingWebhookConfig{URL: url}}}
+		_, err := service.CreateChannel(context.Background(), monitor.Owner{UserID: 4}, dto)
+
+		assert.NoError(t, err)
+		assert.Empty(t, stored.Config.Slack.URL)
+		assert.Equal(t, "hooks.slack.com/…XXXX", stored.Config.Slack.URLHint)
+		assert.Equal(t, url, dto.Config.Slack.URL, "the request is left untouched")
+		sealed, err := secrets.Decrypt(stored.EncryptedSecret)
+		assert.NoError(t, err)
+		assert.Equal(t, url, string(sealed))
+	})
+
+	t.Run("EmailWithoutConfig", func(t *testing.T) {
+		service := NewService(new(MockRepository), zap.L())
+
+		_, err := service.CreateChannel(context.Background(), monitor.Owner{UserID: 4}, CreateChannelDTO{Name: "on-call", Type: ChannelTypeEmail})
+
+		assert.ErrorIs(t, err, ErrInvalidChannel)
+	})
+
+	t.Run("UnknownType", func(t *testing.T) {
+		service := NewService(new(MockRepository), zap.L())
+
+		_, err := service.CreateChannel(context.Background(), monitor.Owner{UserID: 4}, CreateChannelDTO{Name: "ops", Type: "pager"})
+
+		assert.ErrorIs(t, err, ErrInvalidChannel)
+	})
+}
+
+func TestService_ListDeliveries_CapsLimit(t *testing.T) {
+	mockRepo := new(MockRepository)
+	service := NewService(mockRepo, zap.L())
+	owner := monitor.Owner{UserID: 4}
+
+	mockRepo.On("GetChannel", mock.Anything, owner, 1).Return(Channel{ID: 1}, nil)
+	mockRepo.On("ListDeliveries", mock.Anything, 1, defaultDeliveriesLimit).Return([]Delivery{}, nil)
+
+	_, err := service.ListDeliveries(context.Background(), owner, 1, 1000)
+
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestService_CreatePolicy(t *testing.T) {
+	owner := monitor.Owner{UserID: 4}
+
+	tests := []struct {
+		name string
+		dto  CreatePolicyDTO
+	}{
+		{"channel of someone else", CreatePolicyDTO{Name: "p", ChannelIDs: []int{1, 9}}},
+		{"escalation channel of someone else", CreatePolicyDTO{Name: "p", ChannelIDs: []int{1}, EscalationChannelIDs: []int{9}, EscalateAfterMinutes: 5}},
+		{"escalation without delay", CreatePolicyDTO{Name: "p", ChannelIDs: []int{1}, EscalationChannelIDs: []int{2}}},
+		{"delay without escalation channels", CreatePolicyDTO{Name: "p", ChannelIDs: []int{1}, EscalateAfterMinutes: 5}},
+		{"invalid schedule", CreatePolicyDTO{Name: "p", ChannelIDs: []int{1}, Schedule: &Schedule{Timezone: "Nowhere"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockRepository)
+			mockRepo.On("ListChannels", mock.Anything, owner).Return([]Channel{{ID: 1}, {ID: 2}}, nil)
+
+			_, err := NewService(mockRepo, zap.L()).CreatePolicy(context.Background(), owner, tt.dto)
+
+			assert.ErrorIs(t, err, ErrInvalidPolicy)
+			mockRepo.AssertNotCalled(t, "CreatePolicy")
+		})
+	}
+}
@@ -0,0 +1,45 @@
+package org
+
+import (
+	"errors"
+	"health-checker/internal/middleware"
+	"time"
+)
+
+var (
+	ErrMemberNotFound = errors.New("member not found")
+	ErrUserNotFound   = errors.New("user not found")
+	ErrMemberExists   = errors.New("user is already a member")
+	ErrInvalidRole    = errors.New("role must be one of owner, admin, editor, viewer")
+	ErrForbidden      = errors.New("only owners can grant or change the owner role")
+	ErrLastOwner      = errors.New("an organization must keep at least one owner")
+)
+
+type Organization struct {
+	ID        int       `json:"id" db:"id"`
+	Name      string    `json:"name" db:"name"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+
+	// Role is the caller's role, filled in when listing their organizations.
+	Role middleware.Role `json:"role,omitempty" db:"role" swaggertype:"string"`
+}
+
+type Member struct {
+	UserID    int             `json:"user_id" db:"user_id"`
+	Username  string          `json:"username" db:"username"`
+	Role      middleware.Role `json:"role" db:"role" swaggertype:"string"`
+	CreatedAt time.Time       `json:"created_at" db:"created_at"`
+}
+
+type CreateOrganizationDTO struct {
+	Name string `json:"name" binding:"required,max=255" example:"Platform team"`
+}
+
+type AddMemberDTO struct {
+	Username string          `json:"username" binding:"required" example:"johndoe"`
+	Role     middleware.Role `json:"role" binding:"required" example:"viewer" swaggertype:"string" enums:"owner,admin,editor,viewer"`
+}
+
+type UpdateMemberDTO struct {
+	Role middleware.Role `json:"role" binding:"required" example:"editor" swaggertype:"string" enums:"owner,admin,editor,viewer"`
+}
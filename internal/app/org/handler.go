@@ -0,0 +1,289 @@
+package org
+
+import (
+	"errors"
+	"health-checker/internal/middleware"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type OrganizationHandler struct {
+	service  *OrganizationService
+	denylist middleware.Denylist
+	logger   *zap.Logger
+}
+
+func NewHandler(service *OrganizationService, denylist middleware.Denylist, logger *zap.Logger) *OrganizationHandler {
+	return &OrganizationHandler{
+		service:  service,
+		denylist: denylist,
+		logger:   logger,
+	}
+}
+
+func (h *OrganizationHandler) RegisterRoutes(rg *gin.RouterGroup) {
+	authorized := rg.Group("", middleware.AuthMiddleware(h.denylist, nil))
+	authorized.POST("", h.CreateOrganization)
+	authorized.GET("", h.ListOrganizations)
+	authorized.DELETE("/:orgId", middleware.Authorize(h.service, middleware.RoleOwner), h.DeleteOrganization)
+	authorized.GET("/:orgId/members", middleware.Authorize(h.service, middleware.RoleViewer), h.ListMembers)
+	authorized.POST("/:orgId/members", middleware.Authorize(h.service, middleware.RoleAdmin), h.AddMember)
+	authorized.PATCH("/:orgId/members/:userId", middleware.Authorize(h.service, middleware.RoleAdmin), h.UpdateMember)
+	authorized.DELETE("/:orgId/members/:userId", middleware.Authorize(h.service, middleware.RoleAdmin), h.RemoveMember)
+}
+
+// CreateOrganization godoc
+//
+//	@Security		BearerAuth
+//	@Summary		Create an organization
+//	@Description	Create an organization owned by the caller
+//	@Tags			organizations
+//	@Accept			json
+//	@Produce		json
+//	@Param			organization	body		CreateOrganizationDTO	true	"Organization data"
+//	@Success		201				{object}	Organization			"Created organization"
+//	@Failure		400				{object}	map[string]string		"Bad request"
+//	@Failure		500				{object}	map[string]string		"Internal server error"
+//	@Router			/organizations [post]
+func (h *OrganizationHandler) CreateOrganization(ctx *gin.Context) {
+	userID, ok := h.userID(ctx)
+	if !ok {
+		return
+	}
+
+	var body CreateOrganizationDTO
+	if err := ctx.ShouldBindJSON(&body); err != nil {
+		h.logger.Error("failed to bind json", zap.Error(err))
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	organization, err := h.service.Create(ctx.Request.Context(), userID, body)
+	if err != nil {
+		h.respondError(ctx, "failed to create organization", err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, organization)
+}
+
+// ListOrganizations godoc
+//
+//	@Security		BearerAuth
+//	@Summary		List organizations
+//	@Description	List the organizations the caller belongs to, with the caller's role in each
+//	@Tags			organizations
+//	@Produce		json
+//	@Success		200	{array}		Organization		"Organizations"
+//	@Failure		500	{object}	map[string]string	"Internal server error"
+//	@Router			/organizations [get]
+func (h *OrganizationHandler) ListOrganizations(ctx *gin.Context) {
+	userID, ok := h.userID(ctx)
+	if !ok {
+		return
+	}
+
+	organizations, err := h.service.ListOrganizations(ctx.Request.Context(), userID)
+	if err != nil {
+		h.respondError(ctx, "failed to list organizations", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, organizations)
+}
+
+// DeleteOrganization godoc
+//
+//	@Security		BearerAuth
+//	@Summary		Delete an organization
+//	@Description	Delete an organization together with its services. Requires the owner role.
+//	@Tags			organizations
+//	@Param			orgId	path	int	true	"Organization ID"
+//	@Success		204		"Organization deleted"
+//	@Failure		403		{object}	map[string]string	"Forbidden"
+//	@Failure		500		{object}	map[string]string	"Internal server error"
+//	@Router			/organizations/{orgId} [delete]
+func (h *OrganizationHandler) DeleteOrganization(ctx *gin.Context) {
+	orgID, _ := middleware.OrgID(ctx.Request.Context())
+
+	if err := h.service.Delete(ctx.Request.Context(), orgID); err != nil {
+		h.respondError(ctx, "failed to delete organization", err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// ListMembers godoc
+//
+//	@Security		BearerAuth
+//	@Summary		List members
+//	@Description	List the members of an organization and their roles. Requires the viewer role.
+//	@Tags			organizations
+//	@Produce		json
+//	@Param			orgId	path		int					true	"Organization ID"
+//	@Success		200		{array}		Member				"Members"
+//	@Failure		403		{object}	map[string]string	"Forbidden"
+//	@Failure		500		{object}	map[string]string	"Internal server error"
+//	@Router			/organizations/{orgId}/members [get]
+func (h *OrganizationHandler) ListMembers(ctx *gin.Context) {
+	orgID, _ := middleware.OrgID(ctx.Request.Context())
+
+	members, err := h.service.ListMembers(ctx.Request.Context(), orgID)
+	if err != nil {
+		h.respondError(ctx, "failed to list members", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, members)
+}
+
+// AddMember godoc
+//
+//	@Security		BearerAuth
+//	@Summary		Add a member
+//	@Description	Add an existing user to an organization. Requires the admin role; only owners can add owners.
+//	@Tags			organizations
+//	@Accept			json
+//	@Produce		json
+//	@Param			orgId	path		int					true	"Organization ID"
+//	@Param			member	body		AddMemberDTO		true	"Member data"
+//	@Success		201		{object}	Member				"Added member"
+//	@Failure		400		{object}	map[string]string	"Bad request"
+//	@Failure		403		{object}	map[string]string	"Forbidden"
+//	@Failure		404		{object}	map[string]string	"User not found"
+//	@Failure		409		{object}	map[string]string	"Already a member"
+//	@Failure		500		{object}	map[string]string	"Internal server error"
+//	@Router			/organizations/{orgId}/members [post]
+func (h *OrganizationHandler) AddMember(ctx *gin.Context) {
+	orgID, _ := middleware.OrgID(ctx.Request.Context())
+	actor, _ := middleware.RoleFrom(ctx.Request.Context())
+
+	var body AddMemberDTO
+	if err := ctx.ShouldBindJSON(&body); err != nil {
+		h.logger.Error("failed to bind json", zap.Error(err))
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	member, err := h.service.AddMember(ctx.Request.Context(), actor, orgID, body)
+	if err != nil {
+		h.respondError(ctx, "failed to add member", err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, member)
+}
+
+// UpdateMember godoc
+//
+//	@Security		BearerAuth
+//	@Summary		Change a member's role
+//	@Description	Change a member's role. Requires the admin role; only owners can grant or revoke the owner role.
+//	@Tags			organizations
+//	@Accept			json
+//	@Produce		json
+//	@Param			orgId	path		int					true	"Organization ID"
+//	@Param			userId	path		int					true	"User ID"
+//	@Param			member	body		UpdateMemberDTO		true	"New role"
+//	@Success		200		{object}	Member				"Updated member"
+//	@Failure		400		{object}	map[string]string	"Bad request"
+//	@Failure		403		{object}	map[string]string	"Forbidden"
+//	@Failure		404		{object}	map[string]string	"Member not found"
+//	@Failure		409		{object}	map[string]string	"Last owner"
+//	@Failure		500		{object}	map[string]string	"Internal server error"
+//	@Router			/organizations/{orgId}/members/{userId} [patch]
+func (h *OrganizationHandler) UpdateMember(ctx *gin.Context) {
+	orgID, _ := middleware.OrgID(ctx.Request.Context())
+	actor, _ := middleware.RoleFrom(ctx.Request.Context())
+	memberID, ok := h.memberID(ctx)
+	if !ok {
+		return
+	}
+
+	var body UpdateMemberDTO
+	if err := ctx.ShouldBindJSON(&body); err != nil {
+		h.logger.Error("failed to bind json", zap.Error(err))
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	member, err := h.service.UpdateMemberRole(ctx.Request.Context(), actor, orgID, memberID, body)
+	if err != nil {
+		h.respondError(ctx, "failed to update member", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, member)
+}
+
+// RemoveMember godoc
+//
+//	@Security		BearerAuth
+//	@Summary		Remove a member
+//	@Description	Remove a member from an organization. Requires the admin role; only owners can remove owners.
+//	@Tags			organizations
+//	@Param			orgId	path	int	true	"Organization ID"
+//	@Param			userId	path	int	true	"User ID"
+//	@Success		204		"Member removed"
+//	@Failure		400		{object}	map[string]string	"Bad request"
+//	@Failure		403		{object}	map[string]string	"Forbidden"
+//	@Failure		404		{object}	map[string]string	"Member not found"
+//	@Failure		409		{object}	map[string]string	"Last owner"
+//	@Failure		500		{object}	map[string]string	"Internal server error"
+//	@Router			/organizations/{orgId}/members/{userId} [delete]
+func (h *OrganizationHandler) RemoveMember(ctx *gin.Context) {
+	orgID, _ := middleware.OrgID(ctx.Request.Context())
+	actor, _ := middleware.RoleFrom(ctx.Request.Context())
+	memberID, ok := h.memberID(ctx)
+	if !ok {
+		return
+	}
+
+	if err := h.service.RemoveMember(ctx.Request.Context(), actor, orgID, memberID); err != nil {
+		h.respondError(ctx, "failed to remove member", err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// userID returns the authenticated user, responding 401 when the request carries none.
+func (h *OrganizationHandler) userID(ctx *gin.Context) (int, bool) {
+	userID, ok := middleware.UserID(ctx.Request.Context())
+	if !ok {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return 0, false
+	}
+	return userID, true
+}
+
+// memberID parses the userId path parameter, responding 400 when it is not an integer.
+func (h *OrganizationHandler) memberID(ctx *gin.Context) (int, bool) {
+	memberID, err := strconv.Atoi(ctx.Param("userId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "userId must be an integer"})
+		return 0, false
+	}
+	return memberID, true
+}
+
+// respondError maps organization errors onto HTTP status codes.
+func (h *OrganizationHandler) respondError(ctx *gin.Context, msg string, err error) {
+	switch {
+	case errors.Is(err, ErrInvalidRole):
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, ErrForbidden):
+		ctx.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, ErrMemberNotFound), errors.Is(err, ErrUserNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, ErrMemberExists), errors.Is(err, ErrLastOwner):
+		ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		h.logger.Error(msg, zap.Error(err))
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -0,0 +1,210 @@
+package org
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"health-checker/internal/middleware"
+	"health-checker/internal/middleware/middlewaretest"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+)
+
+type MockRepository struct {
+	mock.Mock
+}
+
+func (m *MockRepository) Create(ctx context.Context, name string, ownerID int) (Organization, error) {
+	args := m.Called(ctx, name, ownerID)
+	return args.Get(0).(Organization), args.Error(1)
+}
+
+func (m *MockRepository) ListOrganizations(ctx context.Context, userID int) ([]Organization, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).([]Organization), args.Error(1)
+}
+
+func (m *MockRepository) Delete(ctx context.Context, orgID int) error {
+	args := m.Called(ctx, orgID)
+	return args.Error(0)
+}
+
+func (m *MockRepository) MemberRole(ctx context.Context, orgID, userID int) (middleware.Role, error) {
+	args := m.Called(ctx, orgID, userID)
+	return args.Get(0).(middleware.Role), args.Error(1)
+}
+
+func (m *MockRepository) ListMembers(ctx context.Context, orgID int) ([]Member, error) {
+	args := m.Called(ctx, orgID)
+	return args.Get(0).([]Member), args.Error(1)
+}
+
+func (m *MockRepository) AddMember(ctx context.Context, orgID int, username string, role middleware.Role) (Member, error) {
+	args := m.Called(ctx, orgID, username, role)
+	return args.Get(0).(Member), args.Error(1)
+}
+
+func (m *MockRepository) UpdateMemberRole(ctx context.Context, orgID, userID int, role middleware.Role) (Member, error) {
+	args := m.Called(ctx, orgID, userID, role)
+	return args.Get(0).(Member), args.Error(1)
+}
+
+func (m *MockRepository) RemoveMember(ctx context.Context, orgID, userID int) error {
+	args := m.Called(ctx, orgID, userID)
+	return args.Error(0)
+}
+
+func (m *MockRepository) CountOwners(ctx context.Context, orgID int) (int, error) {
+	args := m.Called(ctx, orgID)
+	return args.Get(0).(int), args.Error(1)
+}
+
+// setupRouter serves the organization routes behind the real auth middleware.
+func setupRouter(mockRepo *MockRepository) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	os.Setenv("JWT_SECRET", "test-secret")
+
+	r := gin.New()
+	NewHandler(NewService(mockRepo, zap.L()), middlewaretest.Denylist{}, zap.NewNop()).RegisterRoutes(r.Group("/organizations"))
+	return r
+}
+
+func authorizedRequest(method, path string, body interface{}, userID int) *http.Request {
+	token, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"user_id": userID,
+		"exp":     time.Now().Add(time.Hour).Unix(),
+	}).SignedString([]byte("test-secret"))
+
+	var payload []byte
+	if body != nil {
+		payload, _ = json.Marshal(body)
+	}
+	req, _ := http.NewRequest(method, path, bytes.NewBuffer(payload))
+	req.Header.Set("Authorization", "Bearer "+token)
+	return req
+}
+
+func TestCreateOrganization(t *testing.T) {
+	mockRepo := new(MockRepository)
+	mockRepo.On("Create", mock.Anything, "Platform", 4).Return(Organization{ID: 1, Name: "Platform", Role: middleware.RoleOwner}, nil)
+
+	w := httptest.NewRecorder()
+	setupRouter(mockRepo).ServeHTTP(w, authorizedRequest("POST", "/organizations", CreateOrganizationDTO{Name: "Platform"}, 4))
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Contains(t, w.Body.String(), `"role":"owner"`)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestOrganizationRoutes_Roles(t *testing.T) {
+	t.Run("viewer lists members", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		mockRepo.On("MemberRole", mock.Anything, 1, 4).Return(middleware.RoleViewer, nil)
+		mockRepo.On("ListMembers", mock.Anything, 1).Return([]Member{{UserID: 4, Role: middleware.RoleViewer}}, nil)
+
+		w := httptest.NewRecorder()
+		setupRouter(mockRepo).ServeHTTP(w, authorizedRequest("GET", "/organizations/1/members", nil, 4))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("non-member is forbidden", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		mockRepo.On("MemberRole", mock.Anything, 1, 5).Return(middleware.Role(""), nil)
+
+		w := httptest.NewRecorder()
+		setupRouter(mockRepo).ServeHTTP(w, authorizedRequest("GET", "/organizations/1/members", nil, 5))
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		mockRepo.AssertNotCalled(t, "ListMembers")
+	})
+
+	t.Run("editor cannot add members", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		mockRepo.On("MemberRole", mock.Anything, 1, 4).Return(middleware.RoleEditor, nil)
+
+		w := httptest.NewRecorder()
+		body := AddMemberDTO{Username: "oncall", Role: middleware.RoleViewer}
+		setupRouter(mockRepo).ServeHTTP(w, authorizedRequest("POST", "/organizations/1/members", body, 4))
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		mockRepo.AssertNotCalled(t, "AddMember")
+	})
+
+	t.Run("admin adds a member", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		mockRepo.On("MemberRole", mock.Anything, 1, 4).Return(middleware.RoleAdmin, nil)
+		mockRepo.On("AddMember", mock.Anything, 1, "oncall", middleware.RoleViewer).Return(Member{UserID: 6, Username: "oncall", Role: middleware.RoleViewer}, nil)
+
+		w := httptest.NewRecorder()
+		body := AddMemberDTO{Username: "oncall", Role: middleware.RoleViewer}
+		setupRouter(mockRepo).ServeHTTP(w, authorizedRequest("POST", "/organizations/1/members", body, 4))
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("admin cannot add owners", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		mockRepo.On("MemberRole", mock.Anything, 1, 4).Return(middleware.RoleAdmin, nil)
+
+		w := httptest.NewRecorder()
+		body := AddMemberDTO{Username: "oncall", Role: middleware.RoleOwner}
+		setupRouter(mockRepo).ServeHTTP(w, authorizedRequest("POST", "/organizations/1/members", body, 4))
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+
+	t.Run("unknown user", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		mockRepo.On("MemberRole", mock.Anything, 1, 4).Return(middleware.RoleAdmin, nil)
+		mockRepo.On("AddMember", mock.Anything, 1, "ghost", middleware.RoleViewer).Return(Member{}, ErrUserNotFound)
+
+		w := httptest.NewRecorder()
+		body := AddMemberDTO{Username: "ghost", Role: middleware.RoleViewer}
+		setupRouter(mockRepo).ServeHTTP(w, authorizedRequest("POST", "/organizations/1/members", body, 4))
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("removing the last owner conflicts", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		mockRepo.On("MemberRole", mock.Anything, 1, 4).Return(middleware.RoleOwner, nil)
+		mockRepo.On("CountOwners", mock.Anything, 1).Return(1, nil)
+
+		w := httptest.NewRecorder()
+		setupRouter(mockRepo).ServeHTTP(w, authorizedRequest("DELETE", "/organizations/1/members/4", nil, 4))
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+		mockRepo.AssertNotCalled(t, "RemoveMember")
+	})
+
+	t.Run("only owners delete the organization", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		mockRepo.On("MemberRole", mock.Anything, 1, 4).Return(middleware.RoleAdmin, nil)
+
+		w := httptest.NewRecorder()
+		setupRouter(mockRepo).ServeHTTP(w, authorizedRequest("DELETE", "/organizations/1", nil, 4))
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		mockRepo.AssertNotCalled(t, "Delete")
+	})
+
+	t.Run("unauthenticated", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/organizations", nil)
+		setupRouter(new(MockRepository)).ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+}
@@ -0,0 +1,176 @@
+package org
+
+import (
+	"context"
+	"errors"
+	"health-checker/internal/middleware"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type Repository interface {
+	Create(ctx context.Context, name string, ownerID int) (Organization, error)
+	ListOrganizations(ctx context.Context, userID int) ([]Organization, error)
+	Delete(ctx context.Context, orgID int) error
+	MemberRole(ctx context.Context, orgID, userID int) (middleware.Role, error)
+	ListMembers(ctx context.Context, orgID int) ([]Member, error)
+	AddMember(ctx context.Context, orgID int, username string, role middleware.Role) (Member, error)
+	UpdateMemberRole(ctx context.Context, orgID, userID int, role middleware.Role) (Member, error)
+	RemoveMember(ctx context.Context, orgID, userID int) error
+	CountOwners(ctx context.Context, orgID int) (int, error)
+}
+
+type PostgresRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewRepository(db *pgxpool.Pool) Repository {
+	return &PostgresRepository{db: db}
+}
+
+// Create inserts the organization and makes ownerID its first owner.
+func (r *PostgresRepository) Create(ctx context.Context, name string, ownerID int) (Organization, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return Organization{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	organization := Organization{Name: name, Role: middleware.RoleOwner}
+	err = tx.QueryRow(ctx, `
+		insert into organizations (name) values ($1)
+		returning id, created_at
+	`, name).Scan(&organization.ID, &organization.CreatedAt)
+	if err != nil {
+		return Organization{}, err
+	}
+
+	_, err = tx.Exec(ctx, `
+		insert into organization_members (organization_id, user_id, role) values ($1, $2, $3)
+	`, organization.ID, ownerID, middleware.RoleOwner)
+	if err != nil {
+		return Organization{}, err
+	}
+
+	return organization, tx.Commit(ctx)
+}
+
+func (r *PostgresRepository) ListOrganizations(ctx context.Context, userID int) ([]Organization, error) {
+	rows, err := r.db.Query(ctx, `
+		select o.id, o.name, o.created_at, m.role
+		from organizations o
+		join organization_members m on m.organization_id = o.id
+		where m.user_id = $1
+		order by o.id
+	`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	organizations := []Organization{}
+	for rows.Next() {
+		var organization Organization
+		if err := rows.Scan(&organization.ID, &organization.Name, &organization.CreatedAt, &organization.Role); err != nil {
+			return nil, err
+		}
+		organizations = append(organizations, organization)
+	}
+	return organizations, rows.Err()
+}
+
+func (r *PostgresRepository) Delete(ctx context.Context, orgID int) error {
+	_, err := r.db.Exec(ctx, `delete from organizations where id = $1`, orgID)
+	return err
+}
+
+// MemberRole returns an empty role when userID is not a member of orgID.
+func (r *PostgresRepository) MemberRole(ctx context.Context, orgID, userID int) (middleware.Role, error) {
+	var role middleware.Role
+	err := r.db.QueryRow(ctx, `
+		select role from organization_members where organization_id = $1 and user_id = $2
+	`, orgID, userID).Scan(&role)
+	if err == pgx.ErrNoRows {
+		return "", nil
+	}
+	return role, err
+}
+
+func (r *PostgresRepository) ListMembers(ctx context.Context, orgID int) ([]Member, error) {
+	rows, err := r.db.Query(ctx, `
+		select m.user_id, u.username, m.role, m.created_at
+		from organization_members m
+		join users u on u.id = m.user_id
+		where m.organization_id = $1
+		order by m.created_at, m.user_id
+	`, orgID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	members := []Member{}
+	for rows.Next() {
+		var member Member
+		if err := rows.Scan(&member.UserID, &member.Username, &member.Role, &member.CreatedAt); err != nil {
+			return nil, err
+		}
+		members = append(members, member)
+	}
+	return members, rows.Err()
+}
+
+func (r *PostgresRepository) AddMember(ctx context.Context, orgID int, username string, role middleware.Role) (Member, error) {
+	member := Member{Username: username, Role: role}
+	err := r.db.QueryRow(ctx, `
+		insert into organization_members (organization_id, user_id, role)
+		select $1, id, $3 from users where username = $2
+		returning user_id, created_at
+	`, orgID, username, role).Scan(&member.UserID, &member.CreatedAt)
+
+	var pgErr *pgconn.PgError
+	switch {
+	case err == pgx.ErrNoRows:
+		return Member{}, ErrUserNotFound
+	case errors.As(err, &pgErr) && pgErr.Code == "23505":
+		return Member{}, ErrMemberExists
+	}
+	return member, err
+}
+
+func (r *PostgresRepository) UpdateMemberRole(ctx context.Context, orgID, userID int, role middleware.Role) (Member, error) {
+	var member Member
+	err := r.db.QueryRow(ctx, `
+		update organization_members m set role = $3
+		from users u
+		where m.organization_id = $1 and m.user_id = $2 and u.id = m.user_id
+		returning m.user_id, u.username, m.role, m.created_at
+	`, orgID, userID, role).Scan(&member.UserID, &member.Username, &member.Role, &member.CreatedAt)
+	if err == pgx.ErrNoRows {
+		return Member{}, ErrMemberNotFound
+	}
+	return member, err
+}
+
+func (r *PostgresRepository) RemoveMember(ctx context.Context, orgID, userID int) error {
+	tag, err := r.db.Exec(ctx, `
+		delete from organization_members where organization_id = $1 and user_id = $2
+	`, orgID, userID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrMemberNotFound
+	}
+	return nil
+}
+
+func (r *PostgresRepository) CountOwners(ctx context.Context, orgID int) (int, error) {
+	var owners int
+	err := r.db.QueryRow(ctx, `
+		select count(*) from organization_members where organization_id = $1 and role = $2
+	`, orgID, middleware.RoleOwner).Scan(&owners)
+	return owners, err
+}
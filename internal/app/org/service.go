@@ -0,0 +1,111 @@
+package org
+
+import (
+	"context"
+	"health-checker/internal/middleware"
+
+	"go.uber.org/zap"
+)
+
+type OrganizationService struct {
+	repo Repository
+	log  *zap.Logger
+}
+
+func NewService(repo Repository, log *zap.Logger) *OrganizationService {
+	return &OrganizationService{repo: repo, log: log}
+}
+
+// Create makes a new organization owned by userID.
+func (s *OrganizationService) Create(ctx context.Context, userID int, dto CreateOrganizationDTO) (Organization, error) {
+	return s.repo.Create(ctx, dto.Name, userID)
+}
+
+func (s *OrganizationService) ListOrganizations(ctx context.Context, userID int) ([]Organization, error) {
+	return s.repo.ListOrganizations(ctx, userID)
+}
+
+func (s *OrganizationService) Delete(ctx context.Context, orgID int) error {
+	return s.repo.Delete(ctx, orgID)
+}
+
+// MemberRole implements middleware.RoleResolver.
+func (s *OrganizationService) MemberRole(ctx context.Context, orgID, userID int) (middleware.Role, error) {
+	return s.repo.MemberRole(ctx, orgID, userID)
+}
+
+func (s *OrganizationService) ListMembers(ctx context.Context, orgID int) ([]Member, error) {
+	return s.repo.ListMembers(ctx, orgID)
+}
+
+// AddMember adds an existing user to the organization. actor is the caller's
+// own role; only owners may add other owners.
+func (s *OrganizationService) AddMember(ctx context.Context, actor middleware.Role, orgID int, dto AddMemberDTO) (Member, error) {
+	if !dto.Role.Valid() {
+		return Member{}, ErrInvalidRole
+	}
+	if dto.Role == middleware.RoleOwner && actor != middleware.RoleOwner {
+		return Member{}, ErrForbidden
+	}
+
+	return s.repo.AddMember(ctx, orgID, dto.Username, dto.Role)
+}
+
+// UpdateMemberRole changes a member's role. Only owners may promote to or
+// demote from owner, and the last owner cannot be demoted.
+func (s *OrganizationService) UpdateMemberRole(ctx context.Context, actor middleware.Role, orgID, userID int, dto UpdateMemberDTO) (Member, error) {
+	if !dto.Role.Valid() {
+		return Member{}, ErrInvalidRole
+	}
+
+	current, err := s.repo.MemberRole(ctx, orgID, userID)
+	if err != nil {
+		return Member{}, err
+	}
+	if current == "" {
+		return Member{}, ErrMemberNotFound
+	}
+	if (current == middleware.RoleOwner || dto.Role == middleware.RoleOwner) && actor != middleware.RoleOwner {
+		return Member{}, ErrForbidden
+	}
+	if current == middleware.RoleOwner && dto.Role != middleware.RoleOwner {
+		if err := s.ensureAnotherOwner(ctx, orgID); err != nil {
+			return Member{}, err
+		}
+	}
+
+	return s.repo.UpdateMemberRole(ctx, orgID, userID, dto.Role)
+}
+
+// RemoveMember removes a member. Only owners may remove owners, and the last
+// owner cannot be removed.
+func (s *OrganizationService) RemoveMember(ctx context.Context, actor middleware.Role, orgID, userID int) error {
+	current, err := s.repo.MemberRole(ctx, orgID, userID)
+	if err != nil {
+		return err
+	}
+	if current == "" {
+		return ErrMemberNotFound
+	}
+	if current == middleware.RoleOwner {
+		if actor != middleware.RoleOwner {
+			return ErrForbidden
+		}
+		if err := s.ensureAnotherOwner(ctx, orgID); err != nil {
+			return err
+		}
+	}
+
+	return s.repo.RemoveMember(ctx, orgID, userID)
+}
+
+func (s *OrganizationService) ensureAnotherOwner(ctx context.Context, orgID int) error {
+	owners, err := s.repo.CountOwners(ctx, orgID)
+	if err != nil {
+		return err
+	}
+	if owners <= 1 {
+		return ErrLastOwner
+	}
+	return nil
+}
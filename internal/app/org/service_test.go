@@ -0,0 +1,165 @@
+package org
+
+import (
+	"context"
+	"errors"
+	"health-checker/internal/middleware"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+)
+
+func TestService_Create(t *testing.T) {
+	mockRepo := new(MockRepository)
+	service := NewService(mockRepo, zap.L())
+
+	mockRepo.On("Create", mock.Anything, "Platform", 4).Return(Organization{ID: 1, Name: "Platform", Role: middleware.RoleOwner}, nil)
+
+	organization, err := service.Create(context.Background(), 4, CreateOrganizationDTO{Name: "Platform"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, middleware.RoleOwner, organization.Role)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestService_AddMember(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		service := NewService(mockRepo, zap.L())
+
+		mockRepo.On("AddMember", mock.Anything, 1, "oncall", middleware.RoleViewer).Return(Member{UserID: 2, Username: "oncall", Role: middleware.RoleViewer}, nil)
+
+		member, err := service.AddMember(context.Background(), middleware.RoleAdmin, 1, AddMemberDTO{Username: "oncall", Role: middleware.RoleViewer})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 2, member.UserID)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("InvalidRole", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		service := NewService(mockRepo, zap.L())
+
+		_, err := service.AddMember(context.Background(), middleware.RoleOwner, 1, AddMemberDTO{Username: "oncall", Role: "root"})
+
+		assert.ErrorIs(t, err, ErrInvalidRole)
+		mockRepo.AssertNotCalled(t, "AddMember")
+	})
+
+	t.Run("OnlyOwnersAddOwners", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		service := NewService(mockRepo, zap.L())
+
+		_, err := service.AddMember(context.Background(), middleware.RoleAdmin, 1, AddMemberDTO{Username: "oncall", Role: middleware.RoleOwner})
+
+		assert.ErrorIs(t, err, ErrForbidden)
+		mockRepo.AssertNotCalled(t, "AddMember")
+	})
+}
+
+func TestService_UpdateMemberRole(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		service := NewService(mockRepo, zap.L())
+
+		mockRepo.On("MemberRole", mock.Anything, 1, 2).Return(middleware.RoleViewer, nil)
+		mockRepo.On("UpdateMemberRole", mock.Anything, 1, 2, middleware.RoleEditor).Return(Member{UserID: 2, Role: middleware.RoleEditor}, nil)
+
+		member, err := service.UpdateMemberRole(context.Background(), middleware.RoleAdmin, 1, 2, UpdateMemberDTO{Role: middleware.RoleEditor})
+
+		assert.NoError(t, err)
+		assert.Equal(t, middleware.RoleEditor, member.Role)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("NotAMember", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		service := NewService(mockRepo, zap.L())
+
+		mockRepo.On("MemberRole", mock.Anything, 1, 9).Return(middleware.Role(""), nil)
+
+		_, err := service.UpdateMemberRole(context.Background(), middleware.RoleAdmin, 1, 9, UpdateMemberDTO{Role: middleware.RoleEditor})
+
+		assert.ErrorIs(t, err, ErrMemberNotFound)
+	})
+
+	t.Run("AdminCannotDemoteOwner", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		service := NewService(mockRepo, zap.L())
+
+		mockRepo.On("MemberRole", mock.Anything, 1, 2).Return(middleware.RoleOwner, nil)
+
+		_, err := service.UpdateMemberRole(context.Background(), middleware.RoleAdmin, 1, 2, UpdateMemberDTO{Role: middleware.RoleViewer})
+
+		assert.ErrorIs(t, err, ErrForbidden)
+		mockRepo.AssertNotCalled(t, "UpdateMemberRole")
+	})
+
+	t.Run("LastOwner", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		service := NewService(mockRepo, zap.L())
+
+		mockRepo.On("MemberRole", mock.Anything, 1, 2).Return(middleware.RoleOwner, nil)
+		mockRepo.On("CountOwners", mock.Anything, 1).Return(1, nil)
+
+		_, err := service.UpdateMemberRole(context.Background(), middleware.RoleOwner, 1, 2, UpdateMemberDTO{Role: middleware.RoleAdmin})
+
+		assert.ErrorIs(t, err, ErrLastOwner)
+		mockRepo.AssertNotCalled(t, "UpdateMemberRole")
+	})
+}
+
+func TestService_RemoveMember(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		service := NewService(mockRepo, zap.L())
+
+		mockRepo.On("MemberRole", mock.Anything, 1, 2).Return(middleware.RoleEditor, nil)
+		mockRepo.On("RemoveMember", mock.Anything, 1, 2).Return(nil)
+
+		err := service.RemoveMember(context.Background(), middleware.RoleAdmin, 1, 2)
+
+		assert.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("AnotherOwnerRemains", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		service := NewService(mockRepo, zap.L())
+
+		mockRepo.On("MemberRole", mock.Anything, 1, 2).Return(middleware.RoleOwner, nil)
+		mockRepo.On("CountOwners", mock.Anything, 1).Return(2, nil)
+		mockRepo.On("RemoveMember", mock.Anything, 1, 2).Return(nil)
+
+		err := service.RemoveMember(context.Background(), middleware.RoleOwner, 1, 2)
+
+		assert.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("LastOwner", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		service := NewService(mockRepo, zap.L())
+
+		mockRepo.On("MemberRole", mock.Anything, 1, 2).Return(middleware.RoleOwner, nil)
+		mockRepo.On("CountOwners", mock.Anything, 1).Return(1, nil)
+
+		err := service.RemoveMember(context.Background(), middleware.RoleOwner, 1, 2)
+
+		assert.ErrorIs(t, err, ErrLastOwner)
+		mockRepo.AssertNotCalled(t, "RemoveMember")
+	})
+
+	t.Run("RepoError", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		service := NewService(mockRepo, zap.L())
+
+		mockRepo.On("MemberRole", mock.Anything, 1, 2).Return(middleware.Role(""), errors.New("db error"))
+
+		err := service.RemoveMember(context.Background(), middleware.RoleOwner, 1, 2)
+
+		assert.Error(t, err)
+	})
+}
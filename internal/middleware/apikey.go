@@ -0,0 +1,66 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"strings"
+)
+
+// APIKeyPrefix starts every API key, which tells AuthMiddleware to verify the
+// bearer credential as a key instead of a JWT.
+const APIKeyPrefix = "hc_"
+
+// API key scopes. A key acts as the user that created it, with at most the
+// role its scopes allow.
+const (
+	ScopeServicesRead  = "services:read"
+	ScopeServicesWrite = "services:write"
+)
+
+// ErrInvalidAPIKey is returned for unknown, revoked and expired keys.
+var ErrInvalidAPIKey = errors.New("invalid API key")
+
+// APIKeyPrincipal is the identity an API key authenticates as.
+type APIKeyPrincipal struct {
+	KeyID  int
+	UserID int
+	// OrgID pins an organization key to its organization; it is 0 for user keys.
+	OrgID  int
+	Scopes []string
+}
+
+// MaxRole is the highest role the key's scopes allow.
+func (p APIKeyPrincipal) MaxRole() Role {
+	var role Role
+	for _, scope := range p.Scopes {
+		switch {
+		case scope == ScopeServicesWrite:
+			return RoleEditor
+		case scope == ScopeServicesRead:
+			role = RoleViewer
+		}
+	}
+	return role
+}
+
+// APIKeyVerifier resolves a presented API key, returning ErrInvalidAPIKey when
+// it cannot be used.
+type APIKeyVerifier interface {
+	VerifyAPIKey(ctx context.Context, key string) (APIKeyPrincipal, error)
+}
+
+// IsAPIKey reports whether a bearer credential is an API key.
+func IsAPIKey(credential string) bool {
+	return strings.HasPrefix(credential, APIKeyPrefix)
+}
+
+// ContextWithAPIKey stores the API key a request authenticated with.
+func ContextWithAPIKey(ctx context.Context, principal APIKeyPrincipal) context.Context {
+	return context.WithValue(ctx, "api_key", principal)
+}
+
+// APIKeyFrom returns the API key a request authenticated with, if any.
+func APIKeyFrom(ctx context.Context) (APIKeyPrincipal, bool) {
+	principal, ok := ctx.Value("api_key").(APIKeyPrincipal)
+	return principal, ok
+}
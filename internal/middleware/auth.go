@@ -3,14 +3,18 @@ package middleware
 import (
 	"context"
 	"errors"
-	"os"
 	"strings"
 
 	"github.com/gin-gonic/gin"
-	"github.com/golang-jwt/jwt/v5"
 )
 
-func AuthMiddleware() gin.HandlerFunc {
+// AuthMiddleware authenticates the bearer credential, which is either a JWT
+// checked against denylist or, when keys is non-nil, an API key. It panics
+// without a denylist, so that routes cannot be mounted with logout unenforced.
+func AuthMiddleware(denylist Denylist, keys APIKeyVerifier) gin.HandlerFunc {
+	if denylist == nil {
+		panic("middleware: AuthMiddleware requires a Denylist")
+	}
 	return func(ctx *gin.Context) {
 		token := strings.TrimPrefix(ctx.GetHeader("Authorization"), "Bearer ")
 		if token == "" {
@@ -18,28 +22,68 @@ func AuthMiddleware() gin.HandlerFunc {
 			return
 		}
 
-		parsedToken, err := jwt.Parse(token, func(t *jwt.Token) (interface{}, error) {
-			return []byte(os.Getenv("JWT_SECRET")), nil
-		})
-
-		if err != nil || !parsedToken.Valid {
-			if errors.Is(err, jwt.ErrTokenExpired) {
-				ctx.AbortWithStatusJSON(401, gin.H{"error": "Token expired"})
+		if keys != nil && IsAPIKey(token) {
+			principal, err := keys.VerifyAPIKey(ctx.Request.Context(), token)
+			if errors.Is(err, ErrInvalidAPIKey) {
+				ctx.AbortWithStatusJSON(401, gin.H{"error": "Invalid API key"})
 				return
 			}
-			ctx.AbortWithStatusJSON(401, gin.H{"error": "Invalid token"})
+			if err != nil {
+				ctx.AbortWithStatusJSON(500, gin.H{"error": "Internal server error"})
+				return
+			}
+
+			reqCtx := ContextWithUserID(ctx.Request.Context(), principal.UserID)
+			ctx.Request = ctx.Request.WithContext(ContextWithAPIKey(reqCtx, principal))
+			ctx.Next()
 			return
 		}
 
-		claims, ok := parsedToken.Claims.(jwt.MapClaims)
-		if !ok {
-			ctx.AbortWithStatusJSON(401, gin.H{"error": "Invalid token claims"})
+		claims, err := ParseAccessToken(ctx.Request.Context(), denylist, token)
+		switch {
+		case errors.Is(err, ErrTokenExpired):
+			ctx.AbortWithStatusJSON(401, gin.H{"error": "Token expired"})
+			return
+		case errors.Is(err, ErrTokenRevoked):
+			ctx.AbortWithStatusJSON(401, gin.H{"error": "Token revoked"})
+			return
+		case errors.Is(err, ErrInvalidToken):
+			ctx.AbortWithStatusJSON(401, gin.H{"error": "Invalid token"})
+			return
+		case err != nil:
+			ctx.AbortWithStatusJSON(500, gin.H{"error": "Internal server error"})
 			return
 		}
 
-		newContext := context.WithValue(ctx.Request.Context(), "user_id", claims["user_id"])
-
-		ctx.Request = ctx.Request.WithContext(newContext)
+		reqCtx := ContextWithUserID(ctx.Request.Context(), claims["user_id"])
+		if jti, _ := claims["jti"].(string); jti != "" {
+			accessToken := AccessToken{ID: jti}
+			if exp, _ := claims.GetExpirationTime(); exp != nil {
+				accessToken.ExpiresAt = exp.Time
+			}
+			reqCtx = ContextWithAccessToken(reqCtx, accessToken)
+		}
+		ctx.Request = ctx.Request.WithContext(reqCtx)
 		ctx.Next()
 	}
 }
+
+// ContextWithUserID stores the user_id claim of an authenticated request.
+func ContextWithUserID(ctx context.Context, userID interface{}) context.Context {
+	return context.WithValue(ctx, "user_id", userID)
+}
+
+// UserID returns the authenticated user's ID stored by AuthMiddleware. JWT
+// claims decode numbers as float64, so every numeric form is accepted.
+func UserID(ctx context.Context) (int, bool) {
+	switch id := ctx.Value("user_id").(type) {
+	case int:
+		return id, true
+	case int64:
+		return int(id), true
+	case float64:
+		return int(id), true
+	default:
+		return 0, false
+	}
+}
@@ -1,6 +1,9 @@
 package middleware
 
 import (
+	"context"
+	"errors"
+	"health-checker/internal/middleware/middlewaretest"
 	"net/http"
 	"net/http/httptest"
 	"os"
@@ -28,7 +31,7 @@ func TestAuthMiddleware_ValidToken(t *testing.T) {
 	tokenString, _ := token.SignedString([]byte("test-secret"))
 
 	router := gin.New()
-	router.Use(AuthMiddleware())
+	router.Use(AuthMiddleware(middlewaretest.Denylist{}, nil))
 	router.GET("/test", func(c *gin.Context) {
 		c.JSON(200, gin.H{"message": "success"})
 	})
@@ -46,7 +49,7 @@ func TestAuthMiddleware_NoToken(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
 	router := gin.New()
-	router.Use(AuthMiddleware())
+	router.Use(AuthMiddleware(middlewaretest.Denylist{}, nil))
 	router.GET("/test", func(c *gin.Context) {
 		c.JSON(200, gin.H{"message": "success"})
 	})
@@ -66,7 +69,7 @@ func TestAuthMiddleware_InvalidToken(t *testing.T) {
 	defer os.Unsetenv("JWT_SECRET")
 
 	router := gin.New()
-	router.Use(AuthMiddleware())
+	router.Use(AuthMiddleware(middlewaretest.Denylist{}, nil))
 	router.GET("/test", func(c *gin.Context) {
 		c.JSON(200, gin.H{"message": "success"})
 	})
@@ -95,7 +98,7 @@ func TestAuthMiddleware_ExpiredToken(t *testing.T) {
 	tokenString, _ := token.SignedString([]byte("test-secret"))
 
 	router := gin.New()
-	router.Use(AuthMiddleware())
+	router.Use(AuthMiddleware(middlewaretest.Denylist{}, nil))
 	router.GET("/test", func(c *gin.Context) {
 		c.JSON(200, gin.H{"message": "success"})
 	})
@@ -108,3 +111,102 @@ func TestAuthMiddleware_ExpiredToken(t *testing.T) {
 
 	assert.Equal(t, http.StatusUnauthorized, w.Code)
 }
+
+func TestUserID(t *testing.T) {
+	id, ok := UserID(ContextWithUserID(context.Background(), float64(7)))
+	assert.True(t, ok)
+	assert.Equal(t, 7, id)
+
+	id, ok = UserID(ContextWithUserID(context.Background(), 3))
+	assert.True(t, ok)
+	assert.Equal(t, 3, id)
+
+	_, ok = UserID(context.Background())
+	assert.False(t, ok)
+
+	_, ok = UserID(ContextWithUserID(context.Background(), "7"))
+	assert.False(t, ok)
+}
+
+func TestAuthMiddleware_SetsUserID(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	os.Setenv("JWT_SECRET", "test-secret")
+	defer os.Unsetenv("JWT_SECRET")
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"user_id": 42,
+		"exp":     time.Now().Add(time.Hour).Unix(),
+	})
+	tokenString, _ := token.SignedString([]byte("test-secret"))
+
+	var userID int
+	router := gin.New()
+	router.Use(AuthMiddleware(middlewaretest.Denylist{}, nil))
+	router.GET("/test", func(c *gin.Context) {
+		userID, _ = UserID(c.Request.Context())
+		c.Status(http.StatusOK)
+	})
+
+	req := httptest.NewRequest("GET", "/test", nil)
+	req.Header.Set("Authorization", "Bearer "+tokenString)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, 42, userID)
+}
+
+type stubKeys map[string]APIKeyPrincipal
+
+func (s stubKeys) VerifyAPIKey(ctx context.Context, key string) (APIKeyPrincipal, error) {
+	if key == "hc_broken" {
+		return APIKeyPrincipal{}, errors.New("db error")
+	}
+	principal, ok := s[key]
+	if !ok {
+		return APIKeyPrincipal{}, ErrInvalidAPIKey
+	}
+	return principal, nil
+}
+
+func TestAuthMiddleware_APIKey(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	keys := stubKeys{"hc_valid": {KeyID: 1, UserID: 9, Scopes: []string{ScopeServicesRead}}}
+	newRouter := func(keys APIKeyVerifier) *gin.Engine {
+		router := gin.New()
+		router.Use(AuthMiddleware(middlewaretest.Denylist{}, keys))
+		router.GET("/test", func(c *gin.Context) {
+			userID, _ := UserID(c.Request.Context())
+			principal, _ := APIKeyFrom(c.Request.Context())
+			c.JSON(http.StatusOK, gin.H{"user_id": userID, "key_id": principal.KeyID})
+		})
+		return router
+	}
+
+	tests := []struct {
+		name   string
+		keys   APIKeyVerifier
+		key    string
+		status int
+	}{
+		{"valid key", keys, "hc_valid", http.StatusOK},
+		{"unknown key", keys, "hc_unknown", http.StatusUnauthorized},
+		{"verifier error", keys, "hc_broken", http.StatusInternalServerError},
+		{"keys not accepted", nil, "hc_valid", http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/test", nil)
+			req.Header.Set("Authorization", "Bearer "+tt.key)
+			w := httptest.NewRecorder()
+			newRouter(tt.keys).ServeHTTP(w, req)
+
+			assert.Equal(t, tt.status, w.Code)
+			if tt.status == http.StatusOK {
+				assert.JSONEq(t, `{"user_id":9,"key_id":1}`, w.Body.String())
+			}
+		})
+	}
+}
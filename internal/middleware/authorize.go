@@ -0,0 +1,134 @@
+package middleware
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Role is a member's role inside an organization.
+type Role string
+
+const (
+	RoleViewer Role = "viewer"
+	RoleEditor Role = "editor"
+	RoleAdmin  Role = "admin"
+	RoleOwner  Role = "owner"
+)
+
+var roleRank = map[Role]int{
+	RoleViewer: 1,
+	RoleEditor: 2,
+	RoleAdmin:  3,
+	RoleOwner:  4,
+}
+
+// Valid reports whether r is one of the known roles.
+func (r Role) Valid() bool {
+	_, ok := roleRank[r]
+	return ok
+}
+
+// Allows reports whether r grants at least the permissions of required.
+func (r Role) Allows(required Role) bool {
+	return r.Valid() && roleRank[r] >= roleRank[required]
+}
+
+// OrganizationHeader selects the organization a request acts in. Routes
+// nested under an organization take it from their :orgId path parameter, and
+// WebSocket clients, which cannot set headers from a browser, pass the org_id
+// query parameter instead.
+const OrganizationHeader = "X-Organization-ID"
+
+// RoleResolver looks up a user's role in an organization. It returns an empty
+// role when the user is not a member.
+type RoleResolver interface {
+	MemberRole(ctx context.Context, orgID, userID int) (Role, error)
+}
+
+// Authorize rejects requests whose caller lacks the required role. Requests
+// without an organization act on the caller's personal resources, which they
+// own. Requests authenticated with an API key get at most the role its scopes
+// allow, and organization keys only act in their organization. It must run
+// after AuthMiddleware.
+func Authorize(resolver RoleResolver, required Role) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		userID, ok := UserID(ctx.Request.Context())
+		if !ok {
+			ctx.AbortWithStatusJSON(401, gin.H{"error": "Unauthorized"})
+			return
+		}
+
+		orgParam := ctx.Param("orgId")
+		if orgParam == "" {
+			orgParam = ctx.GetHeader(OrganizationHeader)
+		}
+		if orgParam == "" {
+			orgParam = ctx.Query("org_id")
+		}
+
+		key, isKey := APIKeyFrom(ctx.Request.Context())
+		if isKey && key.OrgID != 0 {
+			if orgParam != "" && orgParam != strconv.Itoa(key.OrgID) {
+				ctx.AbortWithStatusJSON(403, gin.H{"error": "Forbidden"})
+				return
+			}
+			orgParam = strconv.Itoa(key.OrgID)
+		}
+
+		role, orgID := RoleOwner, 0
+		if orgParam != "" {
+			var err error
+			orgID, err = strconv.Atoi(orgParam)
+			if err != nil || orgID <= 0 {
+				ctx.AbortWithStatusJSON(400, gin.H{"error": "Invalid organization ID"})
+				return
+			}
+
+			role = ""
+			if resolver != nil {
+				if role, err = resolver.MemberRole(ctx.Request.Context(), orgID, userID); err != nil {
+					ctx.AbortWithStatusJSON(500, gin.H{"error": "Internal server error"})
+					return
+				}
+			}
+		}
+		if isKey && !key.MaxRole().Allows(role) {
+			role = key.MaxRole()
+		}
+		if !role.Allows(required) {
+			ctx.AbortWithStatusJSON(403, gin.H{"error": "Forbidden"})
+			return
+		}
+
+		reqCtx := ctx.Request.Context()
+		if orgID != 0 {
+			reqCtx = ContextWithOrgID(reqCtx, orgID)
+		}
+		ctx.Request = ctx.Request.WithContext(ContextWithRole(reqCtx, role))
+		ctx.Next()
+	}
+}
+
+// ContextWithOrgID stores the organization a request acts in.
+func ContextWithOrgID(ctx context.Context, orgID int) context.Context {
+	return context.WithValue(ctx, "org_id", orgID)
+}
+
+// OrgID returns the organization selected by Authorize, if any.
+func OrgID(ctx context.Context) (int, bool) {
+	orgID, ok := ctx.Value("org_id").(int)
+	return orgID, ok
+}
+
+// ContextWithRole stores the caller's role for the current request.
+func ContextWithRole(ctx context.Context, role Role) context.Context {
+	return context.WithValue(ctx, "role", role)
+}
+
+// RoleFrom returns the role granted by Authorize.
+func RoleFrom(ctx context.Context) (Role, bool) {
+	role, ok := ctx.Value("role").(Role)
+	return role, ok
+}
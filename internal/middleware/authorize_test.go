@@ -0,0 +1,146 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+type stubResolver map[int]Role
+
+func (s stubResolver) MemberRole(ctx context.Context, orgID, userID int) (Role, error) {
+	if orgID == 99 {
+		return "", errors.New("db error")
+	}
+	return s[userID], nil
+}
+
+func TestRole_Allows(t *testing.T) {
+	assert.True(t, RoleOwner.Allows(RoleAdmin))
+	assert.True(t, RoleEditor.Allows(RoleEditor))
+	assert.True(t, RoleEditor.Allows(RoleViewer))
+	assert.False(t, RoleViewer.Allows(RoleEditor))
+	assert.False(t, RoleAdmin.Allows(RoleOwner))
+	assert.False(t, Role("").Allows(RoleViewer))
+	assert.False(t, Role("root").Valid())
+}
+
+func TestAuthorize(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	resolver := stubResolver{1: RoleViewer, 2: RoleEditor}
+	newRouter := func(userID int) *gin.Engine {
+		router := gin.New()
+		router.Use(func(c *gin.Context) {
+			if userID != 0 {
+				c.Request = c.Request.WithContext(ContextWithUserID(c.Request.Context(), userID))
+			}
+		})
+		router.DELETE("/test", Authorize(resolver, RoleEditor), func(c *gin.Context) {
+			orgID, _ := OrgID(c.Request.Context())
+			role, _ := RoleFrom(c.Request.Context())
+			c.JSON(200, gin.H{"org_id": orgID, "role": role})
+		})
+		return router
+	}
+
+	tests := []struct {
+		name   string
+		userID int
+		org    string
+		query  string
+		status int
+	}{
+		{"personal scope", 1, "", "", http.StatusOK},
+		{"editor in org", 2, "7", "", http.StatusOK},
+		{"org from query", 2, "", "?org_id=7", http.StatusOK},
+		{"viewer in org", 1, "7", "", http.StatusForbidden},
+		{"not a member", 3, "7", "", http.StatusForbidden},
+		{"invalid org", 2, "seven", "", http.StatusBadRequest},
+		{"resolver error", 2, "99", "", http.StatusInternalServerError},
+		{"unauthenticated", 0, "", "", http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("DELETE", "/test"+tt.query, nil)
+			if tt.org != "" {
+				req.Header.Set(OrganizationHeader, tt.org)
+			}
+			w := httptest.NewRecorder()
+			newRouter(tt.userID).ServeHTTP(w, req)
+
+			assert.Equal(t, tt.status, w.Code)
+		})
+	}
+
+	t.Run("stores org and role", func(t *testing.T) {
+		req := httptest.NewRequest("DELETE", "/test", nil)
+		req.Header.Set(OrganizationHeader, "7")
+		w := httptest.NewRecorder()
+		newRouter(2).ServeHTTP(w, req)
+
+		assert.JSONEq(t, `{"org_id":7,"role":"editor"}`, w.Body.String())
+	})
+}
+
+func TestAPIKeyPrincipal_MaxRole(t *testing.T) {
+	assert.Equal(t, RoleViewer, APIKeyPrincipal{Scopes: []string{ScopeServicesRead}}.MaxRole())
+	assert.Equal(t, RoleEditor, APIKeyPrincipal{Scopes: []string{ScopeServicesRead, ScopeServicesWrite}}.MaxRole())
+	assert.Equal(t, Role(""), APIKeyPrincipal{}.MaxRole())
+}
+
+func TestAuthorize_APIKey(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	resolver := stubResolver{1: RoleAdmin}
+	newRouter := func(principal APIKeyPrincipal, required Role) *gin.Engine {
+		router := gin.New()
+		router.Use(func(c *gin.Context) {
+			ctx := ContextWithUserID(c.Request.Context(), principal.UserID)
+			c.Request = c.Request.WithContext(ContextWithAPIKey(ctx, principal))
+		})
+		router.GET("/test", Authorize(resolver, required), func(c *gin.Context) {
+			orgID, _ := OrgID(c.Request.Context())
+			role, _ := RoleFrom(c.Request.Context())
+			c.JSON(200, gin.H{"org_id": orgID, "role": role})
+		})
+		return router
+	}
+	readKey := APIKeyPrincipal{UserID: 1, Scopes: []string{ScopeServicesRead}}
+	orgKey := APIKeyPrincipal{UserID: 1, OrgID: 7, Scopes: []string{ScopeServicesWrite}}
+
+	tests := []struct {
+		name      string
+		principal APIKeyPrincipal
+		required  Role
+		org       string
+		status    int
+		body      string
+	}{
+		{"read key reads", readKey, RoleViewer, "", http.StatusOK, `{"org_id":0,"role":"viewer"}`},
+		{"read key cannot write", readKey, RoleEditor, "", http.StatusForbidden, ""},
+		{"org key is pinned to its org", orgKey, RoleEditor, "", http.StatusOK, `{"org_id":7,"role":"editor"}`},
+		{"org key in another org", orgKey, RoleViewer, "8", http.StatusForbidden, ""},
+		{"write key cannot administer", orgKey, RoleAdmin, "", http.StatusForbidden, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/test", nil)
+			if tt.org != "" {
+				req.Header.Set(OrganizationHeader, tt.org)
+			}
+			w := httptest.NewRecorder()
+			newRouter(tt.principal, tt.required).ServeHTTP(w, req)
+
+			assert.Equal(t, tt.status, w.Code)
+			if tt.body != "" {
+				assert.JSONEq(t, tt.body, w.Body.String())
+			}
+		})
+	}
+}
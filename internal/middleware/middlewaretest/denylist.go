@@ -0,0 +1,31 @@
+// Package middlewaretest provides test doubles for the middleware package.
+package middlewaretest
+
+import (
+	"context"
+	"time"
+)
+
+// Denylist is an in-memory middleware.Denylist mapping the ID of each revoked
+// access token to when it expires.
+type Denylist map[string]time.Time
+
+// Revoked returns a Denylist in which the tokens with the given IDs are
+// revoked.
+func Revoked(ids ...string) Denylist {
+	d := Denylist{}
+	for _, id := range ids {
+		d[id] = time.Now().Add(time.Hour)
+	}
+	return d
+}
+
+func (d Denylist) Revoke(ctx context.Context, jti string, expiresAt time.Time) error {
+	d[jti] = expiresAt
+	return nil
+}
+
+func (d Denylist) IsRevoked(ctx context.Context, jti string) (bool, error) {
+	_, ok := d[jti]
+	return ok, nil
+}
@@ -0,0 +1,107 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"os"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+var (
+	ErrInvalidToken = errors.New("invalid token")
+	ErrTokenExpired = errors.New("token expired")
+	ErrTokenRevoked = errors.New("token revoked")
+
+	errNoDenylist = errors.New("no access token denylist configured")
+)
+
+// Denylist records access tokens revoked before they expire, by their jti claim.
+type Denylist interface {
+	Revoke(ctx context.Context, jti string, expiresAt time.Time) error
+	IsRevoked(ctx context.Context, jti string) (bool, error)
+}
+
+// RedisDenylist keeps one key per revoked token, expiring together with the
+// token so the set only holds tokens that would otherwise still be accepted.
+type RedisDenylist struct {
+	rdb *redis.Client
+}
+
+func NewRedisDenylist(rdb *redis.Client) *RedisDenylist {
+	return &RedisDenylist{rdb: rdb}
+}
+
+func (d *RedisDenylist) Revoke(ctx context.Context, jti string, expiresAt time.Time) error {
+	ttl := time.Until(expiresAt)
+	if ttl <= 0 {
+		return nil
+	}
+	return d.rdb.Set(ctx, denylistKey(jti), 1, ttl).Err()
+}
+
+func (d *RedisDenylist) IsRevoked(ctx context.Context, jti string) (bool, error) {
+	n, err := d.rdb.Exists(ctx, denylistKey(jti)).Result()
+	return n > 0, err
+}
+
+func denylistKey(jti string) string {
+	return "auth:denylist:" + jti
+}
+
+// AccessToken identifies the JWT a request authenticated with, so it can be
+// revoked on logout.
+type AccessToken struct {
+	ID        string
+	ExpiresAt time.Time
+}
+
+// ParseAccessToken validates a JWT access token and rejects it with
+// ErrTokenRevoked when its jti is on denylist. Errors other than
+// ErrInvalidToken, ErrTokenExpired and ErrTokenRevoked come from the denylist;
+// without one every token is rejected, since revocation could not be checked.
+func ParseAccessToken(ctx context.Context, denylist Denylist, token string) (jwt.MapClaims, error) {
+	if denylist == nil {
+		return nil, errNoDenylist
+	}
+
+	parsedToken, err := jwt.Parse(token, func(t *jwt.Token) (interface{}, error) {
+		return []byte(os.Getenv("JWT_SECRET")), nil
+	})
+	if err != nil || !parsedToken.Valid {
+		if errors.Is(err, jwt.ErrTokenExpired) {
+			return nil, ErrTokenExpired
+		}
+		return nil, ErrInvalidToken
+	}
+
+	claims, ok := parsedToken.Claims.(jwt.MapClaims)
+	if !ok {
+		return nil, ErrInvalidToken
+	}
+
+	if jti, _ := claims["jti"].(string); jti != "" {
+		revoked, err := denylist.IsRevoked(ctx, jti)
+		if err != nil {
+			return nil, err
+		}
+		if revoked {
+			return nil, ErrTokenRevoked
+		}
+	}
+
+	return claims, nil
+}
+
+// ContextWithAccessToken stores the JWT a request authenticated with.
+func ContextWithAccessToken(ctx context.Context, token AccessToken) context.Context {
+	return context.WithValue(ctx, "access_token", token)
+}
+
+// AccessTokenFrom returns the JWT a request authenticated with, if any.
+func AccessTokenFrom(ctx context.Context) (AccessToken, bool) {
+	token, ok := ctx.Value("access_token").(AccessToken)
+	return token, ok
+}
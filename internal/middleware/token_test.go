@@ -0,0 +1,100 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"health-checker/internal/middleware/middlewaretest"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+// failingDenylist cannot tell whether a token is revoked.
+type failingDenylist struct {
+	middlewaretest.Denylist
+}
+
+func (failingDenylist) IsRevoked(ctx context.Context, jti string) (bool, error) {
+	return false, errors.New("redis unavailable")
+}
+
+func TestAuthMiddleware_Denylist(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	os.Setenv("JWT_SECRET", "test-secret")
+	defer os.Unsetenv("JWT_SECRET")
+
+	expiresAt := time.Now().Add(time.Hour).Truncate(time.Second)
+	revoked := middlewaretest.Revoked("revoked")
+
+	tests := []struct {
+		name     string
+		denylist Denylist
+		jti      string
+		status   int
+	}{
+		{"active token", revoked, "active", http.StatusOK},
+		{"revoked token", revoked, "revoked", http.StatusUnauthorized},
+		{"denylist error", failingDenylist{}, "active", http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			router.Use(AuthMiddleware(tt.denylist, nil))
+			router.GET("/test", func(c *gin.Context) {
+				token, _ := AccessTokenFrom(c.Request.Context())
+				assert.Equal(t, AccessToken{ID: "active", ExpiresAt: expiresAt}, token)
+				c.Status(http.StatusOK)
+			})
+
+			token, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+				"user_id": 1,
+				"jti":     tt.jti,
+				"exp":     expiresAt.Unix(),
+			}).SignedString([]byte("test-secret"))
+
+			req := httptest.NewRequest("GET", "/test", nil)
+			req.Header.Set("Authorization", "Bearer "+token)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.status, w.Code)
+		})
+	}
+}
+
+func TestParseAccessToken(t *testing.T) {
+	os.Setenv("JWT_SECRET", "test-secret")
+	defer os.Unsetenv("JWT_SECRET")
+
+	expired, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"user_id": 1,
+		"exp":     time.Now().Add(-time.Hour).Unix(),
+	}).SignedString([]byte("test-secret"))
+	_, err := ParseAccessToken(context.Background(), middlewaretest.Denylist{}, expired)
+	assert.ErrorIs(t, err, ErrTokenExpired)
+
+	_, err = ParseAccessToken(context.Background(), middlewaretest.Denylist{}, "not-a-jwt")
+	assert.ErrorIs(t, err, ErrInvalidToken)
+}
+
+func TestAuthMiddleware_RequiresDenylist(t *testing.T) {
+	os.Setenv("JWT_SECRET", "test-secret")
+	defer os.Unsetenv("JWT_SECRET")
+
+	assert.Panics(t, func() { AuthMiddleware(nil, nil) })
+
+	// Without a denylist, revocation cannot be checked and tokens are refused.
+	token, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"user_id": 1,
+		"jti":     "active",
+		"exp":     time.Now().Add(time.Hour).Unix(),
+	}).SignedString([]byte("test-secret"))
+	_, err := ParseAccessToken(context.Background(), nil, token)
+	assert.ErrorIs(t, err, errNoDenylist)
+}
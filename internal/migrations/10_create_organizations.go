@@ -0,0 +1,45 @@
+package migrations
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// CreateOrganizations adds organizations, their members' roles, and an
+// optional owning organization on services. Services without one remain
+// personal to the user that registered them.
+func CreateOrganizations(db *pgxpool.Pool) error {
+	query := `
+	CREATE TABLE IF NOT EXISTS organizations (
+		id SERIAL PRIMARY KEY,
+		name VARCHAR(255) NOT NULL,
+		created_at TIMESTAMP WITH TIME ZONE DEFAULT clock_timestamp()
+	);
+	CREATE TABLE IF NOT EXISTS organization_members (
+		organization_id INT NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+		user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+		role VARCHAR(16) NOT NULL CHECK (role IN ('owner', 'admin', 'editor', 'viewer')),
+		created_at TIMESTAMP WITH TIME ZONE DEFAULT clock_timestamp(),
+		PRIMARY KEY (organization_id, user_id)
+	);
+	CREATE INDEX IF NOT EXISTS idx_organization_members_user_id ON organization_members(user_id);
+	ALTER TABLE services
+		ADD COLUMN IF NOT EXISTS organization_id INT REFERENCES organizations(id) ON DELETE CASCADE;
+	CREATE INDEX IF NOT EXISTS idx_services_organization_id ON services(organization_id);
+	`
+
+	_, err := db.Exec(context.Background(), query)
+	return err
+}
+
+func RollbackCreateOrganizations(db *pgxpool.Pool) error {
+	query := `
+	DROP INDEX IF EXISTS idx_services_organization_id;
+	ALTER TABLE IF EXISTS services DROP COLUMN IF EXISTS organization_id;
+	DROP TABLE IF EXISTS organization_members;
+	DROP TABLE IF EXISTS organizations;
+	`
+	_, err := db.Exec(context.Background(), query)
+	return err
+}
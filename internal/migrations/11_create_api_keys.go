@@ -0,0 +1,38 @@
+package migrations
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// CreateAPIKeys stores API keys for machine clients. Only a SHA-256 hash of
+// each key is kept; prefix is the key's first characters, shown so users can
+// tell their keys apart.
+func CreateAPIKeys(db *pgxpool.Pool) error {
+	query := `
+	CREATE TABLE IF NOT EXISTS api_keys (
+		id SERIAL PRIMARY KEY,
+		user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+		organization_id INT REFERENCES organizations(id) ON DELETE CASCADE,
+		name VARCHAR(255) NOT NULL,
+		prefix VARCHAR(16) NOT NULL,
+		key_hash CHAR(64) NOT NULL UNIQUE,
+		scopes TEXT[] NOT NULL,
+		expires_at TIMESTAMP WITH TIME ZONE,
+		last_used_at TIMESTAMP WITH TIME ZONE,
+		revoked_at TIMESTAMP WITH TIME ZONE,
+		created_at TIMESTAMP WITH TIME ZONE DEFAULT clock_timestamp()
+	);
+	CREATE INDEX IF NOT EXISTS idx_api_keys_user_id ON api_keys(user_id);
+	`
+
+	_, err := db.Exec(context.Background(), query)
+	return err
+}
+
+func RollbackCreateAPIKeys(db *pgxpool.Pool) error {
+	query := `DROP TABLE IF EXISTS api_keys;`
+	_, err := db.Exec(context.Background(), query)
+	return err
+}
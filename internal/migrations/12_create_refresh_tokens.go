@@ -0,0 +1,34 @@
+package migrations
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// CreateRefreshTokens stores refresh tokens by SHA-256 hash. Every refresh
+// rotates the token; tokens descending from the same login share a family_id,
+// so presenting a rotated token again can revoke the whole family.
+func CreateRefreshTokens(db *pgxpool.Pool) error {
+	query := `
+	CREATE TABLE IF NOT EXISTS refresh_tokens (
+		id SERIAL PRIMARY KEY,
+		user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+		family_id CHAR(32) NOT NULL,
+		token_hash CHAR(64) NOT NULL UNIQUE,
+		expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+		revoked_at TIMESTAMP WITH TIME ZONE,
+		created_at TIMESTAMP WITH TIME ZONE DEFAULT clock_timestamp()
+	);
+	CREATE INDEX IF NOT EXISTS idx_refresh_tokens_family_id ON refresh_tokens(family_id);
+	`
+
+	_, err := db.Exec(context.Background(), query)
+	return err
+}
+
+func RollbackCreateRefreshTokens(db *pgxpool.Pool) error {
+	query := `DROP TABLE IF EXISTS refresh_tokens;`
+	_, err := db.Exec(context.Background(), query)
+	return err
+}
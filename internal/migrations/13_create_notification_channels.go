@@ -0,0 +1,52 @@
+package migrations
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// CreateNotificationChannels stores where status changes are sent and a log
+// of every delivery attempt. A channel with an organization_id is notified
+// about that organization's services, otherwise about its user's personal ones.
+func CreateNotificationChannels(db *pgxpool.Pool) error {
+	query := `
+	CREATE TABLE IF NOT EXISTS notification_channels (
+		id SERIAL PRIMARY KEY,
+		user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+		organization_id INT REFERENCES organizations(id) ON DELETE CASCADE,
+		name VARCHAR(255) NOT NULL,
+		type VARCHAR(32) NOT NULL,
+		config JSONB NOT NULL DEFAULT '{}',
+		secret_encrypted TEXT NOT NULL DEFAULT '',
+		created_at TIMESTAMP WITH TIME ZONE DEFAULT clock_timestamp()
+	);
+	CREATE INDEX IF NOT EXISTS idx_notification_channels_owner ON notification_channels(organization_id, user_id);
+
+	CREATE TABLE IF NOT EXISTS notification_deliveries (
+		id SERIAL PRIMARY KEY,
+		channel_id INT NOT NULL REFERENCES notification_channels(id) ON DELETE CASCADE,
+		service_id INT NOT NULL REFERENCES services(id) ON DELETE CASCADE,
+		payload JSONB NOT NULL,
+		attempt INT NOT NULL,
+		success BOOLEAN NOT NULL,
+		status_code INT NOT NULL DEFAULT 0,
+		error TEXT NOT NULL DEFAULT '',
+		duration_ms INT NOT NULL DEFAULT 0,
+		created_at TIMESTAMP WITH TIME ZONE DEFAULT clock_timestamp()
+	);
+	CREATE INDEX IF NOT EXISTS idx_notification_deliveries_channel_id ON notification_deliveries(channel_id, created_at DESC);
+	`
+
+	_, err := db.Exec(context.Background(), query)
+	return err
+}
+
+func RollbackCreateNotificationChannels(db *pgxpool.Pool) error {
+	query := `
+	DROP TABLE IF EXISTS notification_deliveries;
+	DROP TABLE IF EXISTS notification_channels;
+	`
+	_, err := db.Exec(context.Background(), query)
+	return err
+}
@@ -0,0 +1,25 @@
+package migrations
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// AddChannelServiceIDs limits a notification channel to some of its owner's
+// services; an empty list keeps it notified about all of them.
+func AddChannelServiceIDs(db *pgxpool.Pool) error {
+	query := `
+	ALTER TABLE notification_channels
+		ADD COLUMN IF NOT EXISTS service_ids INT[] NOT NULL DEFAULT '{}';
+	`
+
+	_, err := db.Exec(context.Background(), query)
+	return err
+}
+
+func RollbackAddChannelServiceIDs(db *pgxpool.Pool) error {
+	query := `ALTER TABLE IF EXISTS notification_channels DROP COLUMN IF EXISTS service_ids;`
+	_, err := db.Exec(context.Background(), query)
+	return err
+}
@@ -0,0 +1,25 @@
+package migrations
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// AddServiceTags labels services, e.g. "payments" or "prod", so notification
+// policies can route their alerts.
+func AddServiceTags(db *pgxpool.Pool) error {
+	query := `
+	ALTER TABLE services
+		ADD COLUMN IF NOT EXISTS tags TEXT[] NOT NULL DEFAULT '{}';
+	`
+
+	_, err := db.Exec(context.Background(), query)
+	return err
+}
+
+func RollbackAddServiceTags(db *pgxpool.Pool) error {
+	query := `ALTER TABLE IF EXISTS services DROP COLUMN IF EXISTS tags;`
+	_, err := db.Exec(context.Background(), query)
+	return err
+}
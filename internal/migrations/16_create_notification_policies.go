@@ -0,0 +1,57 @@
+package migrations
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// CreateNotificationPolicies stores the rules routing status changes to
+// channels, and the escalations opened when a policy's DOWN alert has to be
+// acknowledged before it is passed on to a second tier of channels.
+func CreateNotificationPolicies(db *pgxpool.Pool) error {
+	query := `
+	CREATE TABLE IF NOT EXISTS notification_policies (
+		id SERIAL PRIMARY KEY,
+		user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+		organization_id INT REFERENCES organizations(id) ON DELETE CASCADE,
+		name VARCHAR(255) NOT NULL,
+		tags TEXT[] NOT NULL DEFAULT '{}',
+		severities TEXT[] NOT NULL DEFAULT '{}',
+		schedule JSONB,
+		channel_ids INT[] NOT NULL DEFAULT '{}',
+		escalation_channel_ids INT[] NOT NULL DEFAULT '{}',
+		escalate_after_minutes INT NOT NULL DEFAULT 0,
+		created_at TIMESTAMP WITH TIME ZONE DEFAULT clock_timestamp()
+	);
+	CREATE INDEX IF NOT EXISTS idx_notification_policies_owner ON notification_policies(organization_id, user_id);
+
+	CREATE TABLE IF NOT EXISTS escalations (
+		id SERIAL PRIMARY KEY,
+		policy_id INT NOT NULL REFERENCES notification_policies(id) ON DELETE CASCADE,
+		service_id INT NOT NULL REFERENCES services(id) ON DELETE CASCADE,
+		notification JSONB NOT NULL,
+		due_at TIMESTAMP WITH TIME ZONE NOT NULL,
+		acknowledged_at TIMESTAMP WITH TIME ZONE,
+		acknowledged_by INT REFERENCES users(id) ON DELETE SET NULL,
+		escalated_at TIMESTAMP WITH TIME ZONE,
+		resolved_at TIMESTAMP WITH TIME ZONE,
+		created_at TIMESTAMP WITH TIME ZONE DEFAULT clock_timestamp()
+	);
+	CREATE INDEX IF NOT EXISTS idx_escalations_pending ON escalations(due_at)
+		WHERE acknowledged_at IS NULL AND escalated_at IS NULL AND resolved_at IS NULL;
+	CREATE INDEX IF NOT EXISTS idx_escalations_service_id ON escalations(service_id) WHERE resolved_at IS NULL;
+	`
+
+	_, err := db.Exec(context.Background(), query)
+	return err
+}
+
+func RollbackCreateNotificationPolicies(db *pgxpool.Pool) error {
+	query := `
+	DROP TABLE IF EXISTS escalations;
+	DROP TABLE IF EXISTS notification_policies;
+	`
+	_, err := db.Exec(context.Background(), query)
+	return err
+}
@@ -0,0 +1,49 @@
+package migrations
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// CreateIncidents records every outage of a service, from the check that took
+// it DOWN until it recovered, together with the notes responders left on it.
+// A service has at most one open incident.
+func CreateIncidents(db *pgxpool.Pool) error {
+	query := `
+	CREATE TABLE IF NOT EXISTS incidents (
+		id SERIAL PRIMARY KEY,
+		service_id INT NOT NULL REFERENCES services(id) ON DELETE CASCADE,
+		started_at TIMESTAMP WITH TIME ZONE NOT NULL,
+		resolved_at TIMESTAMP WITH TIME ZONE,
+		root_error TEXT NOT NULL DEFAULT '',
+		acknowledged_at TIMESTAMP WITH TIME ZONE,
+		acknowledged_by INT REFERENCES users(id) ON DELETE SET NULL,
+		resolved_by INT REFERENCES users(id) ON DELETE SET NULL,
+		created_at TIMESTAMP WITH TIME ZONE DEFAULT clock_timestamp()
+	);
+	CREATE UNIQUE INDEX IF NOT EXISTS idx_incidents_open_service_id ON incidents(service_id) WHERE resolved_at IS NULL;
+	CREATE INDEX IF NOT EXISTS idx_incidents_service_id_started_at ON incidents(service_id, started_at DESC);
+
+	CREATE TABLE IF NOT EXISTS incident_notes (
+		id SERIAL PRIMARY KEY,
+		incident_id INT NOT NULL REFERENCES incidents(id) ON DELETE CASCADE,
+		user_id INT REFERENCES users(id) ON DELETE SET NULL,
+		body TEXT NOT NULL,
+		created_at TIMESTAMP WITH TIME ZONE DEFAULT clock_timestamp()
+	);
+	CREATE INDEX IF NOT EXISTS idx_incident_notes_incident_id ON incident_notes(incident_id);
+	`
+
+	_, err := db.Exec(context.Background(), query)
+	return err
+}
+
+func RollbackCreateIncidents(db *pgxpool.Pool) error {
+	query := `
+	DROP TABLE IF EXISTS incident_notes;
+	DROP TABLE IF EXISTS incidents;
+	`
+	_, err := db.Exec(context.Background(), query)
+	return err
+}
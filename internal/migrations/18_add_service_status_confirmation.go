@@ -0,0 +1,51 @@
+package migrations
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// AddServiceStatusConfirmation stores each service's confirmed status and the
+// streak of checks disagreeing with it, so that a status only changes after
+// failure_threshold or success_threshold consecutive checks agree. Existing
+// services start from the status of their latest check.
+func AddServiceStatusConfirmation(db *pgxpool.Pool) error {
+	query := `
+	ALTER TABLE services
+		ADD COLUMN IF NOT EXISTS failure_threshold INT NOT NULL DEFAULT 1,
+		ADD COLUMN IF NOT EXISTS success_threshold INT NOT NULL DEFAULT 1,
+		ADD COLUMN IF NOT EXISTS recheck BOOLEAN NOT NULL DEFAULT FALSE,
+		ADD COLUMN IF NOT EXISTS status VARCHAR(50),
+		ADD COLUMN IF NOT EXISTS pending_status VARCHAR(50),
+		ADD COLUMN IF NOT EXISTS pending_count INT NOT NULL DEFAULT 0,
+		ADD COLUMN IF NOT EXISTS pending_since TIMESTAMP WITH TIME ZONE;
+
+	UPDATE services s
+	SET status = (
+		SELECT h.status FROM health_checks h
+		WHERE h.service_id = s.id
+		ORDER BY h.created_at DESC
+		LIMIT 1
+	)
+	WHERE s.status IS NULL;
+	`
+
+	_, err := db.Exec(context.Background(), query)
+	return err
+}
+
+func RollbackAddServiceStatusConfirmation(db *pgxpool.Pool) error {
+	query := `
+	ALTER TABLE IF EXISTS services
+		DROP COLUMN IF EXISTS failure_threshold,
+		DROP COLUMN IF EXISTS success_threshold,
+		DROP COLUMN IF EXISTS recheck,
+		DROP COLUMN IF EXISTS status,
+		DROP COLUMN IF EXISTS pending_status,
+		DROP COLUMN IF EXISTS pending_count,
+		DROP COLUMN IF EXISTS pending_since;
+	`
+	_, err := db.Exec(context.Background(), query)
+	return err
+}
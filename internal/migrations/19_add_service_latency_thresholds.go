@@ -0,0 +1,30 @@
+package migrations
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// AddServiceLatencyThresholds adds the response times, in milliseconds, over
+// which a responding service is DEGRADED or DOWN. 0 disables a threshold.
+func AddServiceLatencyThresholds(db *pgxpool.Pool) error {
+	query := `
+	ALTER TABLE services
+		ADD COLUMN IF NOT EXISTS latency_warn_ms INT NOT NULL DEFAULT 0,
+		ADD COLUMN IF NOT EXISTS latency_critical_ms INT NOT NULL DEFAULT 0;
+	`
+
+	_, err := db.Exec(context.Background(), query)
+	return err
+}
+
+func RollbackAddServiceLatencyThresholds(db *pgxpool.Pool) error {
+	query := `
+	ALTER TABLE IF EXISTS services
+		DROP COLUMN IF EXISTS latency_warn_ms,
+		DROP COLUMN IF EXISTS latency_critical_ms;
+	`
+	_, err := db.Exec(context.Background(), query)
+	return err
+}
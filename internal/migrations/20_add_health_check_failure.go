@@ -0,0 +1,34 @@
+package migrations
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// AddHealthCheckFailure stores why a check failed: the HTTP status code, the
+// kind of failure, its message and the start of the response body.
+func AddHealthCheckFailure(db *pgxpool.Pool) error {
+	query := `
+	ALTER TABLE health_checks
+		ADD COLUMN IF NOT EXISTS status_code INT,
+		ADD COLUMN IF NOT EXISTS error_class VARCHAR(50),
+		ADD COLUMN IF NOT EXISTS message TEXT,
+		ADD COLUMN IF NOT EXISTS body_snippet TEXT;
+	`
+
+	_, err := db.Exec(context.Background(), query)
+	return err
+}
+
+func RollbackAddHealthCheckFailure(db *pgxpool.Pool) error {
+	query := `
+	ALTER TABLE IF EXISTS health_checks
+		DROP COLUMN IF EXISTS status_code,
+		DROP COLUMN IF EXISTS error_class,
+		DROP COLUMN IF EXISTS message,
+		DROP COLUMN IF EXISTS body_snippet;
+	`
+	_, err := db.Exec(context.Background(), query)
+	return err
+}
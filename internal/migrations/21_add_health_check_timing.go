@@ -0,0 +1,25 @@
+package migrations
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// AddHealthCheckTiming stores how long each phase of an HTTP check took, so
+// that a latency spike can be traced to DNS, the network or the application.
+func AddHealthCheckTiming(db *pgxpool.Pool) error {
+	query := `
+	ALTER TABLE health_checks
+		ADD COLUMN IF NOT EXISTS timing JSONB;
+	`
+
+	_, err := db.Exec(context.Background(), query)
+	return err
+}
+
+func RollbackAddHealthCheckTiming(db *pgxpool.Pool) error {
+	query := `ALTER TABLE IF EXISTS health_checks DROP COLUMN IF EXISTS timing;`
+	_, err := db.Exec(context.Background(), query)
+	return err
+}
@@ -0,0 +1,24 @@
+package migrations
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// AddHealthChecksServiceIndex indexes health checks by service and time, which
+// every per-service history and report query filters on.
+func AddHealthChecksServiceIndex(db *pgxpool.Pool) error {
+	query := `
+	CREATE INDEX IF NOT EXISTS idx_health_checks_service_id_created_at ON health_checks(service_id, created_at);
+	`
+
+	_, err := db.Exec(context.Background(), query)
+	return err
+}
+
+func RollbackAddHealthChecksServiceIndex(db *pgxpool.Pool) error {
+	query := `DROP INDEX IF EXISTS idx_health_checks_service_id_created_at;`
+	_, err := db.Exec(context.Background(), query)
+	return err
+}
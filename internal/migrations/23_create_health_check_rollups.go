@@ -0,0 +1,61 @@
+package migrations
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// CreateHealthCheckRollups keeps hourly and daily aggregates of health checks,
+// which outlive the raw rows deleted by the retention job. Latency columns
+// are NULL for buckets without an answered check. The BRIN index lets that
+// job find old rows without a per-row index on the largest table.
+func CreateHealthCheckRollups(db *pgxpool.Pool) error {
+	query := `
+	CREATE TABLE IF NOT EXISTS health_check_rollups_hourly (
+		service_id INT NOT NULL REFERENCES services(id) ON DELETE CASCADE,
+		bucket_start TIMESTAMP WITH TIME ZONE NOT NULL,
+		check_count INT NOT NULL,
+		failure_count INT NOT NULL,
+		degraded_count INT NOT NULL,
+		latency_min INT,
+		latency_max INT,
+		latency_mean DOUBLE PRECISION,
+		latency_p50 INT,
+		latency_p90 INT,
+		latency_p95 INT,
+		latency_p99 INT,
+		PRIMARY KEY (service_id, bucket_start)
+	);
+	CREATE TABLE IF NOT EXISTS health_check_rollups_daily (
+		service_id INT NOT NULL REFERENCES services(id) ON DELETE CASCADE,
+		bucket_start TIMESTAMP WITH TIME ZONE NOT NULL,
+		check_count INT NOT NULL,
+		failure_count INT NOT NULL,
+		degraded_count INT NOT NULL,
+		latency_min INT,
+		latency_max INT,
+		latency_mean DOUBLE PRECISION,
+		latency_p50 INT,
+		latency_p90 INT,
+		latency_p95 INT,
+		latency_p99 INT,
+		PRIMARY KEY (service_id, bucket_start)
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_health_checks_created_at_brin ON health_checks USING BRIN (created_at);
+	`
+
+	_, err := db.Exec(context.Background(), query)
+	return err
+}
+
+func RollbackCreateHealthCheckRollups(db *pgxpool.Pool) error {
+	query := `
+	DROP INDEX IF EXISTS idx_health_checks_created_at_brin;
+	DROP TABLE IF EXISTS health_check_rollups_daily;
+	DROP TABLE IF EXISTS health_check_rollups_hourly;
+	`
+	_, err := db.Exec(context.Background(), query)
+	return err
+}
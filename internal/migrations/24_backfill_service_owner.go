@@ -0,0 +1,39 @@
+package migrations
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// BackfillServiceOwner gives the services registered before ownership existed,
+// which have neither a user nor an organization, to the user whose ID is in
+// SERVICE_OWNER_BACKFILL_USER_ID. Without it they stay hidden from the API and
+// are not checked.
+func BackfillServiceOwner(db *pgxpool.Pool) error {
+	value := os.Getenv("SERVICE_OWNER_BACKFILL_USER_ID")
+	if value == "" {
+		return nil
+	}
+	userID, err := strconv.Atoi(value)
+	if err != nil || userID <= 0 {
+		return fmt.Errorf("invalid SERVICE_OWNER_BACKFILL_USER_ID %q", value)
+	}
+
+	query := `
+	UPDATE services SET user_id = $1
+	WHERE user_id IS NULL AND organization_id IS NULL;
+	`
+
+	_, err = db.Exec(context.Background(), query, userID)
+	return err
+}
+
+// RollbackBackfillServiceOwner leaves the backfilled owners in place, since
+// they can no longer be told apart from the services the user registered.
+func RollbackBackfillServiceOwner(db *pgxpool.Pool) error {
+	return nil
+}
@@ -0,0 +1,27 @@
+package migrations
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// CreateIncidentRecoveries records when each service last recovered, so that a
+// DOWN event delivered after the recovery that followed it does not open an
+// incident that nothing would resolve.
+func CreateIncidentRecoveries(db *pgxpool.Pool) error {
+	query := `
+	CREATE TABLE IF NOT EXISTS incident_recoveries (
+		service_id INT PRIMARY KEY REFERENCES services(id) ON DELETE CASCADE,
+		recovered_at TIMESTAMP WITH TIME ZONE NOT NULL
+	);
+	`
+
+	_, err := db.Exec(context.Background(), query)
+	return err
+}
+
+func RollbackCreateIncidentRecoveries(db *pgxpool.Pool) error {
+	_, err := db.Exec(context.Background(), `DROP TABLE IF EXISTS incident_recoveries;`)
+	return err
+}
@@ -0,0 +1,35 @@
+package migrations
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// AddRollupLatencyCount counts the answered checks of each rollup bucket, the
+// ones its latency columns summarize, so that buckets can be merged into
+// longer ones. Buckets rolled up before it are given their checks that did
+// not fail, which leaves out the slow responses among the failures.
+func AddRollupLatencyCount(db *pgxpool.Pool) error {
+	query := `
+	ALTER TABLE health_check_rollups_hourly ADD COLUMN IF NOT EXISTS latency_count INT NOT NULL DEFAULT 0;
+	ALTER TABLE health_check_rollups_daily ADD COLUMN IF NOT EXISTS latency_count INT NOT NULL DEFAULT 0;
+
+	UPDATE health_check_rollups_hourly SET latency_count = greatest(check_count - failure_count, 1)
+	WHERE latency_count = 0 AND latency_min IS NOT NULL;
+	UPDATE health_check_rollups_daily SET latency_count = greatest(check_count - failure_count, 1)
+	WHERE latency_count = 0 AND latency_min IS NOT NULL;
+	`
+
+	_, err := db.Exec(context.Background(), query)
+	return err
+}
+
+func RollbackAddRollupLatencyCount(db *pgxpool.Pool) error {
+	query := `
+	ALTER TABLE health_check_rollups_daily DROP COLUMN IF EXISTS latency_count;
+	ALTER TABLE health_check_rollups_hourly DROP COLUMN IF EXISTS latency_count;
+	`
+	_, err := db.Exec(context.Background(), query)
+	return err
+}
@@ -0,0 +1,28 @@
+package migrations
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+func AddServiceCheckType(db *pgxpool.Pool) error {
+	query := `
+	ALTER TABLE services
+		ADD COLUMN IF NOT EXISTS type VARCHAR(50) NOT NULL DEFAULT 'http';
+	ALTER TABLE health_checks
+		ADD COLUMN IF NOT EXISTS details JSONB;
+	`
+
+	_, err := db.Exec(context.Background(), query)
+	return err
+}
+
+func RollbackAddServiceCheckType(db *pgxpool.Pool) error {
+	query := `
+	ALTER TABLE IF EXISTS health_checks DROP COLUMN IF EXISTS details;
+	ALTER TABLE IF EXISTS services DROP COLUMN IF EXISTS type;
+	`
+	_, err := db.Exec(context.Background(), query)
+	return err
+}
@@ -0,0 +1,32 @@
+package migrations
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+func AddServiceTargetFields(db *pgxpool.Pool) error {
+	query := `
+	ALTER TABLE services
+		ALTER COLUMN url SET DEFAULT '',
+		ADD COLUMN IF NOT EXISTS host VARCHAR(255) NOT NULL DEFAULT '',
+		ADD COLUMN IF NOT EXISTS port INT NOT NULL DEFAULT 0,
+		ADD COLUMN IF NOT EXISTS config JSONB NOT NULL DEFAULT '{}';
+	`
+
+	_, err := db.Exec(context.Background(), query)
+	return err
+}
+
+func RollbackAddServiceTargetFields(db *pgxpool.Pool) error {
+	query := `
+	ALTER TABLE IF EXISTS services
+		DROP COLUMN IF EXISTS config,
+		DROP COLUMN IF EXISTS port,
+		DROP COLUMN IF EXISTS host,
+		ALTER COLUMN url DROP DEFAULT;
+	`
+	_, err := db.Exec(context.Background(), query)
+	return err
+}
@@ -0,0 +1,28 @@
+package migrations
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+func AddServiceRequestOptions(db *pgxpool.Pool) error {
+	query := `
+	ALTER TABLE services
+		ADD COLUMN IF NOT EXISTS timeout INT NOT NULL DEFAULT 5,
+		ADD COLUMN IF NOT EXISTS auth_encrypted TEXT NOT NULL DEFAULT '';
+	`
+
+	_, err := db.Exec(context.Background(), query)
+	return err
+}
+
+func RollbackAddServiceRequestOptions(db *pgxpool.Pool) error {
+	query := `
+	ALTER TABLE IF EXISTS services
+		DROP COLUMN IF EXISTS auth_encrypted,
+		DROP COLUMN IF EXISTS timeout;
+	`
+	_, err := db.Exec(context.Background(), query)
+	return err
+}
@@ -0,0 +1,30 @@
+package migrations
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+func AddServiceHeartbeatFields(db *pgxpool.Pool) error {
+	query := `
+	ALTER TABLE services
+		ADD COLUMN IF NOT EXISTS heartbeat_token VARCHAR(64) UNIQUE,
+		ADD COLUMN IF NOT EXISTS grace_period INT NOT NULL DEFAULT 0,
+		ADD COLUMN IF NOT EXISTS last_ping_at TIMESTAMP WITH TIME ZONE;
+	`
+
+	_, err := db.Exec(context.Background(), query)
+	return err
+}
+
+func RollbackAddServiceHeartbeatFields(db *pgxpool.Pool) error {
+	query := `
+	ALTER TABLE IF EXISTS services
+		DROP COLUMN IF EXISTS last_ping_at,
+		DROP COLUMN IF EXISTS grace_period,
+		DROP COLUMN IF EXISTS heartbeat_token;
+	`
+	_, err := db.Exec(context.Background(), query)
+	return err
+}
@@ -0,0 +1,23 @@
+package migrations
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+func AddServicePaused(db *pgxpool.Pool) error {
+	query := `
+	ALTER TABLE services
+		ADD COLUMN IF NOT EXISTS paused BOOLEAN NOT NULL DEFAULT false;
+	`
+
+	_, err := db.Exec(context.Background(), query)
+	return err
+}
+
+func RollbackAddServicePaused(db *pgxpool.Pool) error {
+	query := `ALTER TABLE IF EXISTS services DROP COLUMN IF EXISTS paused;`
+	_, err := db.Exec(context.Background(), query)
+	return err
+}
@@ -0,0 +1,30 @@
+package migrations
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// AddServiceOwner scopes services to the user that registered them. Services
+// created before ownership existed have no owner: they are hidden from the API
+// and not checked until BackfillServiceOwner assigns one.
+func AddServiceOwner(db *pgxpool.Pool) error {
+	query := `
+	ALTER TABLE services
+		ADD COLUMN IF NOT EXISTS user_id INT REFERENCES users(id) ON DELETE CASCADE;
+	CREATE INDEX IF NOT EXISTS idx_services_user_id ON services(user_id);
+	`
+
+	_, err := db.Exec(context.Background(), query)
+	return err
+}
+
+func RollbackAddServiceOwner(db *pgxpool.Pool) error {
+	query := `
+	DROP INDEX IF EXISTS idx_services_user_id;
+	ALTER TABLE IF EXISTS services DROP COLUMN IF EXISTS user_id;
+	`
+	_, err := db.Exec(context.Background(), query)
+	return err
+}
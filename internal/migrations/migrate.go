@@ -6,12 +6,58 @@ var migrations = []func(*pgxpool.Pool) error{
 	CreateServicesTable,
 	CreateUsersTable,
 	CreateHealthChecksTable,
+	AddServiceCheckType,
+	AddServiceTargetFields,
+	AddServiceRequestOptions,
+	AddServiceHeartbeatFields,
+	AddServicePaused,
+	AddServiceOwner,
+	CreateOrganizations,
+	CreateAPIKeys,
+	CreateRefreshTokens,
+	CreateNotificationChannels,
+	AddChannelServiceIDs,
+	AddServiceTags,
+	CreateNotificationPolicies,
+	CreateIncidents,
+	AddServiceStatusConfirmation,
+	AddServiceLatencyThresholds,
+	AddHealthCheckFailure,
+	AddHealthCheckTiming,
+	AddHealthChecksServiceIndex,
+	CreateHealthCheckRollups,
+	BackfillServiceOwner,
+	CreateIncidentRecoveries,
+	AddRollupLatencyCount,
 }
 
 var rollbacks = []func(*pgxpool.Pool) error{
 	RollbackCreateServicesTable,
 	RollbackCreateUsersTable,
 	RollbackCreateHealthChecksTable,
+	RollbackAddServiceCheckType,
+	RollbackAddServiceTargetFields,
+	RollbackAddServiceRequestOptions,
+	RollbackAddServiceHeartbeatFields,
+	RollbackAddServicePaused,
+	RollbackAddServiceOwner,
+	RollbackCreateOrganizations,
+	RollbackCreateAPIKeys,
+	RollbackCreateRefreshTokens,
+	RollbackCreateNotificationChannels,
+	RollbackAddChannelServiceIDs,
+	RollbackAddServiceTags,
+	RollbackCreateNotificationPolicies,
+	RollbackCreateIncidents,
+	RollbackAddServiceStatusConfirmation,
+	RollbackAddServiceLatencyThresholds,
+	RollbackAddHealthCheckFailure,
+	RollbackAddHealthCheckTiming,
+	RollbackAddHealthChecksServiceIndex,
+	RollbackCreateHealthCheckRollups,
+	RollbackBackfillServiceOwner,
+	RollbackCreateIncidentRecoveries,
+	RollbackAddRollupLatencyCount,
 }
 
 func Migrate(db *pgxpool.Pool) error {
@@ -0,0 +1,153 @@
+package monitor
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	CheckTypeHTTP = "http"
+	CheckTypeTCP  = "tcp"
+	CheckTypeDNS  = "dns"
+	CheckTypeTLS  = "tls"
+	CheckTypeGRPC = "grpc"
+	// CheckTypeHeartbeat services are never probed; they are pinged by the
+	// monitored job and only reach a worker once their deadline has lapsed.
+	CheckTypeHeartbeat = "heartbeat"
+)
+
+// Status is the health of a service as reported by a check.
+type Status string
+
+const (
+	StatusUp Status = "UP"
+	// StatusDegraded is a service that answers but not well, such as one
+	// slower than its warning latency.
+	StatusDegraded Status = "DEGRADED"
+	StatusDown     Status = "DOWN"
+)
+
+// CheckJob is the probe target decoded from a health check stream message.
+type CheckJob struct {
+	ServiceID int
+	// UserID and OrgID own the service; they scope the status change events
+	// the job produces.
+	UserID  int
+	OrgID   int
+	Type    string
+	URL     string
+	Host    string
+	Port    int
+	Config  CheckConfig
+	Timeout time.Duration
+	// EncryptedAuth is decrypted by the checker that needs it, so credentials
+	// never sit in the stream in clear text.
+	EncryptedAuth string
+
+	FailureThreshold int
+	SuccessThreshold int
+	Recheck          bool
+	// LatencyWarnMs and LatencyCriticalMs are 0 when disabled.
+	LatencyWarnMs     int
+	LatencyCriticalMs int
+	// Attempt counts the immediate re-checks that led to this job; it is 0
+	// for scheduled checks.
+	Attempt int
+}
+
+// threshold is how many consecutive checks must report status before the
+// job's service changes to it. A re-checked service needs at least two.
+func (j CheckJob) threshold(status Status) int {
+	threshold := j.FailureThreshold
+	if status == StatusUp {
+		threshold = j.SuccessThreshold
+	}
+	if j.Recheck {
+		threshold = max(threshold, 2)
+	}
+	return max(threshold, 1)
+}
+
+// applyLatency downgrades a result that took longer than the job's latency
+// thresholds: a service slower than its critical latency is as good as DOWN.
+func (j CheckJob) applyLatency(result CheckResult) CheckResult {
+	if result.Status == StatusDown {
+		return result
+	}
+	ms := result.Latency.Milliseconds()
+	switch {
+	case j.LatencyCriticalMs > 0 && ms >= int64(j.LatencyCriticalMs):
+		result.Status = StatusDown
+		result.ErrorClass = ErrorClassSlowResponse
+		result.Message = fmt.Sprintf("responded in %dms, over the critical latency of %dms", ms, j.LatencyCriticalMs)
+	case j.LatencyWarnMs > 0 && ms >= int64(j.LatencyWarnMs) && result.Status == StatusUp:
+		result.Status = StatusDegraded
+		result.ErrorClass = ErrorClassSlowResponse
+		result.Message = fmt.Sprintf("responded in %dms, over the warning latency of %dms", ms, j.LatencyWarnMs)
+	}
+	return result
+}
+
+// CheckResult is what a Checker reports for a single probe.
+type CheckResult struct {
+	Status  Status
+	Latency time.Duration
+	Message string
+	Details map[string]interface{}
+
+	// ErrorClass is what kind of failure Message describes; it is empty for UP results.
+	ErrorClass ErrorClass
+	// StatusCode and BodySnippet are set by HTTP checks that got a response.
+	StatusCode  int
+	BodySnippet string
+	// Timing breaks the latency of HTTP checks down by phase.
+	Timing *Timing
+}
+
+// Checker probes a target of a single check type.
+type Checker interface {
+	Check(ctx context.Context, job CheckJob) CheckResult
+}
+
+type CheckerRegistry struct {
+	mu       sync.RWMutex
+	checkers map[string]Checker
+}
+
+func NewCheckerRegistry() *CheckerRegistry {
+	return &CheckerRegistry{
+		checkers: make(map[string]Checker),
+	}
+}
+
+func (r *CheckerRegistry) Register(checkType string, checker Checker) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.checkers[checkType] = checker
+}
+
+func (r *CheckerRegistry) Get(checkType string) (Checker, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	checker, ok := r.checkers[checkType]
+	return checker, ok
+}
+
+// DefaultCheckers returns a registry with every built-in check type registered.
+// Heartbeat deadlines are read from heartbeats.
+func DefaultCheckers(httpClient *http.Client, heartbeats HeartbeatDeadlines) *CheckerRegistry {
+	registry := NewCheckerRegistry()
+	registry.Register(CheckTypeHTTP, NewHTTPChecker(httpClient))
+	registry.Register(CheckTypeTCP, NewTCPChecker(&net.Dialer{}))
+	registry.Register(CheckTypeDNS, NewDNSChecker(&net.Dialer{}))
+	registry.Register(CheckTypeTLS, NewTLSChecker(&net.Dialer{}, nil))
+	registry.Register(CheckTypeGRPC, NewGRPCChecker(&net.Dialer{}, nil))
+	registry.Register(CheckTypeHeartbeat, NewHeartbeatChecker(heartbeats))
+	return registry
+}
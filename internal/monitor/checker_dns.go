@@ -0,0 +1,264 @@
+package monitor
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+const (
+	DNSMatchContains = "contains"
+	DNSMatchExact    = "exact"
+
+	defaultResolver = "127.0.0.1:53"
+	resolvConfPath  = "/etc/resolv.conf"
+)
+
+var dnsRecordTypes = map[string]dnsmessage.Type{
+	"A":     dnsmessage.TypeA,
+	"AAAA":  dnsmessage.TypeAAAA,
+	"CNAME": dnsmessage.TypeCNAME,
+	"MX":    dnsmessage.TypeMX,
+	"TXT":   dnsmessage.TypeTXT,
+}
+
+type DNSChecker struct {
+	dialer *net.Dialer
+}
+
+func NewDNSChecker(dialer *net.Dialer) *DNSChecker {
+	return &DNSChecker{dialer: dialer}
+}
+
+func (c *DNSChecker) Check(ctx context.Context, job CheckJob) CheckResult {
+	config := DNSCheckConfig{}
+	if job.Config.DNS != nil {
+		config = *job.Config.DNS
+	}
+
+	recordType := strings.ToUpper(config.RecordType)
+	if recordType == "" {
+		recordType = "A"
+	}
+	qtype, ok := dnsRecordTypes[recordType]
+	if !ok {
+		return CheckResult{Status: StatusDown, Message: fmt.Sprintf("unsupported record type %q", config.RecordType), ErrorClass: ErrorClassOther}
+	}
+
+	resolver := config.Resolver
+	if resolver == "" {
+		resolver = systemResolver()
+	}
+	if _, _, err := net.SplitHostPort(resolver); err != nil {
+		resolver = net.JoinHostPort(resolver, "53")
+	}
+
+	details := map[string]interface{}{
+		"resolver":    resolver,
+		"record_type": recordType,
+	}
+
+	start := time.Now()
+	resp, err := c.exchange(ctx, resolver, job.Host, qtype)
+	latency := time.Since(start)
+	details["response_ms"] = latency.Milliseconds()
+	if err != nil {
+		return CheckResult{Status: StatusDown, Latency: latency, Message: err.Error(), Details: details, ErrorClass: classifyError(err)}
+	}
+
+	details["rcode"] = strings.TrimPrefix(resp.RCode.String(), "RCode")
+	if resp.RCode != dnsmessage.RCodeSuccess {
+		message := fmt.Sprintf("resolver answered %s", details["rcode"])
+		return CheckResult{Status: StatusDown, Latency: latency, Message: message, Details: details, ErrorClass: ErrorClassDNS}
+	}
+
+	answers := dnsAnswers(resp, qtype)
+	details["answers"] = answers
+
+	if err := assertDNSAnswers(config, answers, latency); err != nil {
+		return CheckResult{Status: StatusDown, Latency: latency, Message: err.Error(), Details: details, ErrorClass: ErrorClassAssertion}
+	}
+
+	return CheckResult{Status: StatusUp, Latency: latency, Details: details}
+}
+
+// exchange sends a single recursive query over UDP, retrying over TCP when the answer is truncated.
+func (c *DNSChecker) exchange(ctx context.Context, resolver, host string, qtype dnsmessage.Type) (*dnsmessage.Message, error) {
+	fqdn := host
+	if !strings.HasSuffix(fqdn, ".") {
+		fqdn += "."
+	}
+	name, err := dnsmessage.NewName(fqdn)
+	if err != nil {
+		return nil, fmt.Errorf("invalid dns name %q: %w", host, err)
+	}
+
+	query := dnsmessage.Message{
+		Header: dnsmessage.Header{ID: uint16(rand.Intn(1 << 16)), RecursionDesired: true},
+		Questions: []dnsmessage.Question{
+			{Name: name, Type: qtype, Class: dnsmessage.ClassINET},
+		},
+	}
+	packed, err := query.Pack()
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.roundTrip(ctx, "udp", resolver, packed)
+	if err == nil && resp.Truncated {
+		resp, err = c.roundTrip(ctx, "tcp", resolver, packed)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if resp.ID != query.ID {
+		return nil, errors.New("dns response id does not match query")
+	}
+
+	return resp, nil
+}
+
+func (c *DNSChecker) roundTrip(ctx context.Context, network, resolver string, query []byte) (*dnsmessage.Message, error) {
+	conn, err := c.dialer.DialContext(ctx, network, resolver)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	var buf []byte
+	if network == "tcp" {
+		framed := make([]byte, 2+len(query))
+		binary.BigEndian.PutUint16(framed, uint16(len(query)))
+		copy(framed[2:], query)
+		if _, err := conn.Write(framed); err != nil {
+			return nil, err
+		}
+
+		var length [2]byte
+		if _, err := io.ReadFull(conn, length[:]); err != nil {
+			return nil, err
+		}
+		buf = make([]byte, binary.BigEndian.Uint16(length[:]))
+		if _, err := io.ReadFull(conn, buf); err != nil {
+			return nil, err
+		}
+	} else {
+		if _, err := conn.Write(query); err != nil {
+			return nil, err
+		}
+
+		buf = make([]byte, 4096)
+		n, err := conn.Read(buf)
+		if err != nil {
+			return nil, err
+		}
+		buf = buf[:n]
+	}
+
+	var resp dnsmessage.Message
+	if err := resp.Unpack(buf); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// dnsAnswers renders the answers of the queried type as comparable strings.
+func dnsAnswers(resp *dnsmessage.Message, qtype dnsmessage.Type) []string {
+	answers := []string{}
+	for _, answer := range resp.Answers {
+		if answer.Header.Type != qtype {
+			continue
+		}
+
+		switch body := answer.Body.(type) {
+		case *dnsmessage.AResource:
+			answers = append(answers, net.IP(body.A[:]).String())
+		case *dnsmessage.AAAAResource:
+			answers = append(answers, net.IP(body.AAAA[:]).String())
+		case *dnsmessage.CNAMEResource:
+			answers = append(answers, normalizeDNSName(body.CNAME.String()))
+		case *dnsmessage.MXResource:
+			answers = append(answers, normalizeDNSName(body.MX.String()))
+		case *dnsmessage.TXTResource:
+			answers = append(answers, strings.Join(body.TXT, ""))
+		}
+	}
+	return answers
+}
+
+func assertDNSAnswers(config DNSCheckConfig, answers []string, latency time.Duration) error {
+	minCount := config.MinCount
+	if minCount == 0 && len(config.Expected) == 0 {
+		minCount = 1
+	}
+	if len(answers) < minCount {
+		return fmt.Errorf("expected at least %d answers, got %d", minCount, len(answers))
+	}
+
+	got := make(map[string]bool, len(answers))
+	for _, answer := range answers {
+		got[normalizeDNSName(answer)] = true
+	}
+
+	for _, expected := range config.Expected {
+		if !got[normalizeDNSName(expected)] {
+			return fmt.Errorf("expected answer %q not found in %v", expected, answers)
+		}
+	}
+
+	if config.Match == DNSMatchExact {
+		want := make(map[string]bool, len(config.Expected))
+		for _, expected := range config.Expected {
+			want[normalizeDNSName(expected)] = true
+		}
+		if len(want) != len(got) {
+			sorted := append([]string(nil), answers...)
+			sort.Strings(sorted)
+			return fmt.Errorf("expected exactly %v, got %v", config.Expected, sorted)
+		}
+	}
+
+	if config.MaxResponseMs > 0 && latency > time.Duration(config.MaxResponseMs)*time.Millisecond {
+		return fmt.Errorf("response took %dms, limit is %dms", latency.Milliseconds(), config.MaxResponseMs)
+	}
+
+	return nil
+}
+
+// normalizeDNSName makes answers comparable regardless of case and trailing root dot.
+func normalizeDNSName(name string) string {
+	return strings.TrimSuffix(strings.ToLower(name), ".")
+}
+
+// systemResolver returns the first nameserver listed in /etc/resolv.conf.
+func systemResolver() string {
+	f, err := os.Open(resolvConfPath)
+	if err != nil {
+		return defaultResolver
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) >= 2 && fields[0] == "nameserver" {
+			return net.JoinHostPort(fields[1], "53")
+		}
+	}
+	return defaultResolver
+}
@@ -0,0 +1,189 @@
+package monitor
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+// startDNSServer answers UDP queries from records, keyed by lowercase FQDN and
+// record type. Unknown names get NXDOMAIN.
+func startDNSServer(t *testing.T, records map[string]map[dnsmessage.Type][]dnsmessage.ResourceBody) string {
+	t.Helper()
+
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	go func() {
+		buf := make([]byte, 512)
+		for {
+			n, addr, err := conn.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+
+			var query dnsmessage.Message
+			if err := query.Unpack(buf[:n]); err != nil || len(query.Questions) != 1 {
+				continue
+			}
+			question := query.Questions[0]
+
+			resp := dnsmessage.Message{
+				Header:    dnsmessage.Header{ID: query.ID, Response: true, RecursionAvailable: true},
+				Questions: query.Questions,
+			}
+			byType, ok := records[question.Name.String()]
+			if !ok {
+				resp.RCode = dnsmessage.RCodeNameError
+			}
+			for _, body := range byType[question.Type] {
+				resp.Answers = append(resp.Answers, dnsmessage.Resource{
+					Header: dnsmessage.ResourceHeader{Name: question.Name, Class: dnsmessage.ClassINET, TTL: 60},
+					Body:   body,
+				})
+			}
+
+			packed, err := resp.Pack()
+			if err != nil {
+				continue
+			}
+			conn.WriteTo(packed, addr)
+		}
+	}()
+
+	return conn.LocalAddr().String()
+}
+
+func TestDNSChecker_Check(t *testing.T) {
+	resolver := startDNSServer(t, map[string]map[dnsmessage.Type][]dnsmessage.ResourceBody{
+		"app.example.test.": {
+			dnsmessage.TypeA: {
+				&dnsmessage.AResource{A: [4]byte{10, 0, 0, 1}},
+				&dnsmessage.AResource{A: [4]byte{10, 0, 0, 2}},
+			},
+			dnsmessage.TypeTXT: {
+				&dnsmessage.TXTResource{TXT: []string{"v=spf1 -all"}},
+			},
+		},
+		"www.example.test.": {
+			dnsmessage.TypeCNAME: {
+				&dnsmessage.CNAMEResource{CNAME: dnsmessage.MustNewName("app.example.test.")},
+			},
+		},
+		"example.test.": {
+			dnsmessage.TypeMX: {
+				&dnsmessage.MXResource{Pref: 10, MX: dnsmessage.MustNewName("mail.example.test.")},
+			},
+		},
+	})
+
+	checker := NewDNSChecker(&net.Dialer{})
+	check := func(host string, config DNSCheckConfig) CheckResult {
+		config.Resolver = resolver
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+		return checker.Check(ctx, CheckJob{Type: CheckTypeDNS, Host: host, Config: CheckConfig{DNS: &config}})
+	}
+
+	t.Run("A record with answers is UP", func(t *testing.T) {
+		result := check("app.example.test", DNSCheckConfig{})
+
+		assert.Equal(t, StatusUp, result.Status)
+		assert.ElementsMatch(t, []string{"10.0.0.1", "10.0.0.2"}, result.Details["answers"])
+	})
+
+	t.Run("contains assertion", func(t *testing.T) {
+		result := check("app.example.test", DNSCheckConfig{Expected: []string{"10.0.0.2"}})
+		assert.Equal(t, StatusUp, result.Status)
+
+		result = check("app.example.test", DNSCheckConfig{Expected: []string{"10.0.0.9"}})
+		assert.Equal(t, StatusDown, result.Status)
+		assert.Contains(t, result.Message, "10.0.0.9")
+	})
+
+	t.Run("exact assertion", func(t *testing.T) {
+		result := check("app.example.test", DNSCheckConfig{
+			Expected: []string{"10.0.0.2", "10.0.0.1"},
+			Match:    DNSMatchExact,
+		})
+		assert.Equal(t, StatusUp, result.Status)
+
+		result = check("app.example.test", DNSCheckConfig{
+			Expected: []string{"10.0.0.1"},
+			Match:    DNSMatchExact,
+		})
+		assert.Equal(t, StatusDown, result.Status)
+	})
+
+	t.Run("min count assertion", func(t *testing.T) {
+		result := check("app.example.test", DNSCheckConfig{MinCount: 3})
+
+		assert.Equal(t, StatusDown, result.Status)
+	})
+
+	t.Run("CNAME record", func(t *testing.T) {
+		result := check("www.example.test", DNSCheckConfig{
+			RecordType: "CNAME",
+			Expected:   []string{"APP.example.test."},
+		})
+
+		assert.Equal(t, StatusUp, result.Status)
+		assert.Equal(t, []string{"app.example.test"}, result.Details["answers"])
+	})
+
+	t.Run("MX record", func(t *testing.T) {
+		result := check("example.test", DNSCheckConfig{RecordType: "MX", Expected: []string{"mail.example.test"}})
+
+		assert.Equal(t, StatusUp, result.Status)
+	})
+
+	t.Run("TXT record", func(t *testing.T) {
+		result := check("app.example.test", DNSCheckConfig{RecordType: "TXT", Expected: []string{"v=spf1 -all"}})
+
+		assert.Equal(t, StatusUp, result.Status)
+	})
+
+	t.Run("no records of type is DOWN", func(t *testing.T) {
+		result := check("app.example.test", DNSCheckConfig{RecordType: "AAAA"})
+
+		assert.Equal(t, StatusDown, result.Status)
+	})
+
+	t.Run("NXDOMAIN is DOWN", func(t *testing.T) {
+		result := check("missing.example.test", DNSCheckConfig{})
+
+		assert.Equal(t, StatusDown, result.Status)
+		assert.Equal(t, "NameError", result.Details["rcode"])
+		assert.Equal(t, ErrorClassDNS, result.ErrorClass)
+	})
+
+	t.Run("unreachable resolver is DOWN", func(t *testing.T) {
+		conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+		require.NoError(t, err)
+		silent := conn.LocalAddr().String()
+		defer conn.Close()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+		result := checker.Check(ctx, CheckJob{
+			Type:   CheckTypeDNS,
+			Host:   "app.example.test",
+			Config: CheckConfig{DNS: &DNSCheckConfig{Resolver: silent}},
+		})
+
+		assert.Equal(t, StatusDown, result.Status)
+	})
+}
+
+func TestAssertDNSAnswers_MaxResponseTime(t *testing.T) {
+	config := DNSCheckConfig{MaxResponseMs: 50}
+
+	assert.NoError(t, assertDNSAnswers(config, []string{"10.0.0.1"}, 10*time.Millisecond))
+	assert.Error(t, assertDNSAnswers(config, []string{"10.0.0.1"}, 80*time.Millisecond))
+}
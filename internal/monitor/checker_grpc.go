@@ -0,0 +1,118 @@
+package monitor
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+)
+
+// GRPCChecker probes servers implementing the gRPC Health Checking Protocol.
+type GRPCChecker struct {
+	dialer *net.Dialer
+	// roots verifies the server certificate when TLS is enabled; nil means the system pool.
+	roots *x509.CertPool
+}
+
+func NewGRPCChecker(dialer *net.Dialer, roots *x509.CertPool) *GRPCChecker {
+	return &GRPCChecker{dialer: dialer, roots: roots}
+}
+
+func (c *GRPCChecker) Check(ctx context.Context, job CheckJob) CheckResult {
+	config := GRPCCheckConfig{}
+	if job.Config.GRPC != nil {
+		config = *job.Config.GRPC
+	}
+
+	address := net.JoinHostPort(job.Host, strconv.Itoa(job.Port))
+	details := map[string]interface{}{
+		"address": address,
+		"service": config.Service,
+		"tls":     config.TLS,
+	}
+
+	creds := insecure.NewCredentials()
+	if config.TLS {
+		serverName := config.ServerName
+		if serverName == "" {
+			serverName = job.Host
+		}
+		creds = credentials.NewTLS(&tls.Config{
+			ServerName:         serverName,
+			RootCAs:            c.roots,
+			InsecureSkipVerify: config.SkipVerify,
+		})
+	}
+
+	conn, err := grpc.NewClient(address,
+		grpc.WithTransportCredentials(creds),
+		grpc.WithContextDialer(func(ctx context.Context, addr string) (net.Conn, error) {
+			return c.dialer.DialContext(ctx, "tcp", addr)
+		}),
+	)
+	if err != nil {
+		return CheckResult{Status: StatusDown, Message: err.Error(), Details: details, ErrorClass: ErrorClassOther}
+	}
+	defer conn.Close()
+
+	start := time.Now()
+	resp, err := healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{Service: config.Service})
+	latency := time.Since(start)
+	if err != nil {
+		details["code"] = status.Code(err).String()
+		return CheckResult{Status: StatusDown, Latency: latency, Message: status.Convert(err).Message(), Details: details,
+			ErrorClass: grpcErrorClass(err)}
+	}
+
+	servingStatus := resp.GetStatus()
+	details["serving_status"] = servingStatus.String()
+
+	result := CheckResult{Status: StatusUp, Latency: latency, Details: details}
+	switch servingStatus {
+	case healthpb.HealthCheckResponse_SERVING:
+	case healthpb.HealthCheckResponse_UNKNOWN:
+		// The server answered but cannot vouch for the service yet, e.g. while starting up.
+		result.Status = StatusDegraded
+		result.Message = "serving status is UNKNOWN"
+		result.ErrorClass = ErrorClassUnhealthy
+	default:
+		result.Status = StatusDown
+		result.Message = fmt.Sprintf("serving status is %s", servingStatus)
+		result.ErrorClass = ErrorClassUnhealthy
+	}
+	return result
+}
+
+// grpcErrorClass classifies a failed health RPC by its status, since gRPC
+// does not wrap the network error behind it.
+func grpcErrorClass(err error) ErrorClass {
+	switch status.Code(err) {
+	case codes.DeadlineExceeded:
+		return ErrorClassTimeout
+	case codes.Unavailable:
+		message := status.Convert(err).Message()
+		switch {
+		case strings.Contains(message, "connection refused"):
+			return ErrorClassConnectionRefused
+		case strings.Contains(message, "tls:"), strings.Contains(message, "x509:"):
+			return ErrorClassTLS
+		}
+		return ErrorClassConnection
+	case codes.NotFound, codes.Unimplemented:
+		// The server has no health service, or does not know the service.
+		return ErrorClassUnhealthy
+	default:
+		return ErrorClassOther
+	}
+}
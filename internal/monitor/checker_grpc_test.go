@@ -0,0 +1,154 @@
+package monitor
+
+import (
+	"context"
+	"crypto/x509"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// startGRPCServer serves the standard health service on a random local port
+// and returns it along with the port.
+func startGRPCServer(t *testing.T, opts ...grpc.ServerOption) (*health.Server, int) {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	server := grpc.NewServer(opts...)
+	healthServer := health.NewServer()
+	healthpb.RegisterHealthServer(server, healthServer)
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	return healthServer, listener.Addr().(*net.TCPAddr).Port
+}
+
+func TestGRPCChecker_Check(t *testing.T) {
+	healthServer, port := startGRPCServer(t)
+	healthServer.SetServingStatus("orders.v1.OrderService", healthpb.HealthCheckResponse_SERVING)
+	healthServer.SetServingStatus("billing.v1.BillingService", healthpb.HealthCheckResponse_NOT_SERVING)
+	healthServer.SetServingStatus("search.v1.SearchService", healthpb.HealthCheckResponse_UNKNOWN)
+
+	checker := NewGRPCChecker(&net.Dialer{}, nil)
+	check := func(port int, config GRPCCheckConfig) CheckResult {
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+		return checker.Check(ctx, CheckJob{
+			Type:   CheckTypeGRPC,
+			Host:   "127.0.0.1",
+			Port:   port,
+			Config: CheckConfig{GRPC: &config},
+		})
+	}
+
+	t.Run("server without service name is UP", func(t *testing.T) {
+		result := check(port, GRPCCheckConfig{})
+
+		assert.Equal(t, StatusUp, result.Status)
+		assert.Equal(t, "SERVING", result.Details["serving_status"])
+	})
+
+	t.Run("SERVING is UP", func(t *testing.T) {
+		result := check(port, GRPCCheckConfig{Service: "orders.v1.OrderService"})
+
+		assert.Equal(t, StatusUp, result.Status)
+	})
+
+	t.Run("NOT_SERVING is DOWN", func(t *testing.T) {
+		result := check(port, GRPCCheckConfig{Service: "billing.v1.BillingService"})
+
+		assert.Equal(t, StatusDown, result.Status)
+		assert.Equal(t, "NOT_SERVING", result.Details["serving_status"])
+		assert.Equal(t, ErrorClassUnhealthy, result.ErrorClass)
+	})
+
+	t.Run("UNKNOWN is DEGRADED", func(t *testing.T) {
+		result := check(port, GRPCCheckConfig{Service: "search.v1.SearchService"})
+
+		assert.Equal(t, StatusDegraded, result.Status)
+	})
+
+	t.Run("unregistered service is DOWN", func(t *testing.T) {
+		result := check(port, GRPCCheckConfig{Service: "missing.v1.Service"})
+
+		assert.Equal(t, StatusDown, result.Status)
+		assert.Equal(t, "NotFound", result.Details["code"])
+	})
+
+	t.Run("server without health service is DOWN", func(t *testing.T) {
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		server := grpc.NewServer()
+		go server.Serve(listener)
+		defer server.Stop()
+
+		result := check(listener.Addr().(*net.TCPAddr).Port, GRPCCheckConfig{})
+
+		assert.Equal(t, StatusDown, result.Status)
+		assert.Equal(t, "Unimplemented", result.Details["code"])
+	})
+
+	t.Run("connection refused is DOWN", func(t *testing.T) {
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		closedPort := listener.Addr().(*net.TCPAddr).Port
+		listener.Close()
+
+		result := check(closedPort, GRPCCheckConfig{})
+
+		assert.Equal(t, StatusDown, result.Status)
+		assert.Equal(t, "Unavailable", result.Details["code"])
+		assert.Equal(t, ErrorClassConnectionRefused, result.ErrorClass)
+	})
+}
+
+func TestGRPCChecker_TLS(t *testing.T) {
+	cert := newTestCertificate(t, time.Now().Add(90*24*time.Hour))
+	_, port := startGRPCServer(t, grpc.Creds(credentials.NewServerTLSFromCert(&cert)))
+	roots := x509.NewCertPool()
+	roots.AddCert(cert.Leaf)
+
+	check := func(checker *GRPCChecker, config GRPCCheckConfig) CheckResult {
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+		return checker.Check(ctx, CheckJob{
+			Type:   CheckTypeGRPC,
+			Host:   "127.0.0.1",
+			Port:   port,
+			Config: CheckConfig{GRPC: &config},
+		})
+	}
+
+	t.Run("trusted certificate is UP", func(t *testing.T) {
+		result := check(NewGRPCChecker(&net.Dialer{}, roots), GRPCCheckConfig{TLS: true})
+
+		assert.Equal(t, StatusUp, result.Status)
+	})
+
+	t.Run("untrusted certificate is DOWN", func(t *testing.T) {
+		result := check(NewGRPCChecker(&net.Dialer{}, x509.NewCertPool()), GRPCCheckConfig{TLS: true})
+
+		assert.Equal(t, StatusDown, result.Status)
+	})
+
+	t.Run("skip verify accepts untrusted certificate", func(t *testing.T) {
+		result := check(NewGRPCChecker(&net.Dialer{}, x509.NewCertPool()), GRPCCheckConfig{TLS: true, SkipVerify: true})
+
+		assert.Equal(t, StatusUp, result.Status)
+	})
+
+	t.Run("plaintext against tls server is DOWN", func(t *testing.T) {
+		result := check(NewGRPCChecker(&net.Dialer{}, roots), GRPCCheckConfig{})
+
+		assert.Equal(t, StatusDown, result.Status)
+	})
+}
@@ -0,0 +1,37 @@
+package monitor
+
+import "context"
+
+// HeartbeatDeadlines tells whether a heartbeat service's last ping, or its
+// creation when it never pinged, is older than its interval plus grace period.
+type HeartbeatDeadlines interface {
+	HeartbeatOverdue(ctx context.Context, serviceID int) (bool, error)
+}
+
+// HeartbeatChecker reports heartbeat services whose deadline lapsed without a
+// ping. The scheduler only enqueues a heartbeat service once next_run_at has
+// passed, which a ping pushes forward, but a ping may land between the claim
+// and the check, so the deadline is checked again before reporting DOWN.
+type HeartbeatChecker struct {
+	deadlines HeartbeatDeadlines
+}
+
+func NewHeartbeatChecker(deadlines HeartbeatDeadlines) *HeartbeatChecker {
+	return &HeartbeatChecker{deadlines: deadlines}
+}
+
+func (c *HeartbeatChecker) Check(ctx context.Context, job CheckJob) CheckResult {
+	// When the deadline cannot be read, the scheduler's word is taken for it.
+	if overdue, err := c.deadlines.HeartbeatOverdue(ctx, job.ServiceID); err == nil && !overdue {
+		return CheckResult{
+			Status:  StatusUp,
+			Message: "heartbeat received within the expected interval",
+		}
+	}
+	return CheckResult{
+		Status:     StatusDown,
+		Message:    "no heartbeat received within the expected interval",
+		Details:    map[string]interface{}{"missed_heartbeat": true},
+		ErrorClass: ErrorClassMissedHeartbeat,
+	}
+}
@@ -0,0 +1,152 @@
+package monitor
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"health-checker/internal/secrets"
+	"io"
+	"net/http"
+	"net/http/httptrace"
+	"strings"
+	"time"
+)
+
+// defaultMaxBodyBytes bounds how much of a response body is read for body assertions.
+const defaultMaxBodyBytes = 1 << 20
+
+type HTTPChecker struct {
+	client *http.Client
+}
+
+func NewHTTPChecker(client *http.Client) *HTTPChecker {
+	return &HTTPChecker{client: client}
+}
+
+func (c *HTTPChecker) Check(ctx context.Context, job CheckJob) CheckResult {
+	config := HTTPCheckConfig{}
+	if job.Config.HTTP != nil {
+		config = *job.Config.HTTP
+	}
+	var assertions HTTPAssertions
+	if config.Assertions != nil {
+		assertions = *config.Assertions
+	}
+
+	req, err := newCheckRequest(ctx, job, config)
+	if err != nil {
+		return CheckResult{Status: StatusDown, Message: err.Error(), ErrorClass: ErrorClassOther}
+	}
+
+	trace := &httpTrace{}
+	req = req.WithContext(httptrace.WithClientTrace(req.Context(), trace.clientTrace()))
+
+	start := time.Now()
+	resp, err := c.client.Do(req)
+	latency := time.Since(start)
+	if err != nil {
+		// The phases that did complete tell where the time went, e.g. a slow DNS lookup.
+		return CheckResult{Status: StatusDown, Latency: latency, Message: err.Error(), ErrorClass: classifyError(err),
+			Timing: trace.timing(time.Now())}
+	}
+	defer resp.Body.Close()
+
+	result := CheckResult{
+		Status:     StatusDown,
+		Latency:    latency,
+		Message:    resp.Status,
+		StatusCode: resp.StatusCode,
+		Details: map[string]interface{}{
+			"status_code": resp.StatusCode,
+		},
+	}
+	if resp.TLS != nil && len(resp.TLS.PeerCertificates) > 0 {
+		result.Details["tls"] = certificateDetails(resp.TLS.PeerCertificates[0])
+	}
+
+	var body, snippet []byte
+	truncated := false
+	if assertions.readsBody() {
+		limit := assertions.MaxBodyBytes
+		if limit <= 0 {
+			limit = defaultMaxBodyBytes
+		}
+		body, err = io.ReadAll(io.LimitReader(resp.Body, limit+1))
+		result.Timing = trace.timing(time.Now())
+		if err != nil {
+			result.Message = "failed to read response body: " + err.Error()
+			result.ErrorClass = classifyError(err)
+			return result
+		}
+		if int64(len(body)) > limit {
+			truncated = true
+			body = body[:limit]
+		}
+		snippet = body
+	} else {
+		// Keep the start of the body for diagnosis and drain the rest, up to
+		// the usual bound, so that the transfer is timed too.
+		snippet, _ = io.ReadAll(io.LimitReader(resp.Body, maxBodySnippetBytes))
+		io.Copy(io.Discard, io.LimitReader(resp.Body, defaultMaxBodyBytes))
+		result.Timing = trace.timing(time.Now())
+	}
+
+	failures := assertions.Evaluate(resp, body, truncated)
+	if len(failures) > 0 {
+		result.Message = strings.Join(failures, "; ")
+		result.Details["assertion_failures"] = failures
+		result.ErrorClass = ErrorClassAssertion
+		if !statusCodeAllowed(assertions.StatusCodes, resp.StatusCode) {
+			result.ErrorClass = ErrorClassHTTPStatus
+		}
+		result.BodySnippet = bodySnippet(snippet)
+		return result
+	}
+
+	result.Status = StatusUp
+	return result
+}
+
+// newCheckRequest builds the probe request from the service's method, headers,
+// body and decrypted credentials.
+func newCheckRequest(ctx context.Context, job CheckJob, config HTTPCheckConfig) (*http.Request, error) {
+	method := config.Method
+	if method == "" {
+		method = http.MethodGet
+	}
+	var body io.Reader
+	if config.Body != "" {
+		body = strings.NewReader(config.Body)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, job.URL, body)
+	if err != nil {
+		return nil, err
+	}
+	for name, value := range config.Headers {
+		if strings.EqualFold(name, "Host") {
+			req.Host = value
+			continue
+		}
+		req.Header.Set(name, value)
+	}
+
+	if job.EncryptedAuth == "" {
+		return req, nil
+	}
+	plaintext, err := secrets.Decrypt(job.EncryptedAuth)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt credentials: %w", err)
+	}
+	var auth HTTPAuth
+	if err := json.Unmarshal(plaintext, &auth); err != nil {
+		return nil, fmt.Errorf("failed to decode credentials: %w", err)
+	}
+	switch auth.Type {
+	case "basic":
+		req.SetBasicAuth(auth.Username, auth.Password)
+	case "bearer":
+		req.Header.Set("Authorization", "Bearer "+auth.Token)
+	}
+	return req, nil
+}
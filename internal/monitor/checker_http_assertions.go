@@ -0,0 +1,203 @@
+package monitor
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Validate reports assertions that can never be evaluated, such as malformed
+// regular expressions or status code patterns.
+func (a HTTPAssertions) Validate() error {
+	for _, pattern := range a.StatusCodes {
+		if _, err := matchStatusCode(pattern, 0); err != nil {
+			return err
+		}
+	}
+	if a.BodyRegex != "" {
+		if _, err := regexp.Compile(a.BodyRegex); err != nil {
+			return fmt.Errorf("invalid body_regex: %w", err)
+		}
+	}
+	for _, header := range a.Headers {
+		if header.Matches == "" {
+			continue
+		}
+		if _, err := regexp.Compile(header.Matches); err != nil {
+			return fmt.Errorf("invalid header regex for %s: %w", header.Name, err)
+		}
+	}
+	return nil
+}
+
+// readsBody reports whether evaluating the assertions needs the response body.
+func (a HTTPAssertions) readsBody() bool {
+	return len(a.BodyContains) > 0 || a.BodyRegex != "" || len(a.JSONPath) > 0 || a.MaxBodyBytes > 0
+}
+
+// Evaluate returns a description of every assertion the response fails.
+// bodyTruncated is set when more than MaxBodyBytes were available.
+func (a HTTPAssertions) Evaluate(resp *http.Response, body []byte, bodyTruncated bool) []string {
+	var failures []string
+
+	if !statusCodeAllowed(a.StatusCodes, resp.StatusCode) {
+		expected := a.StatusCodes
+		if len(expected) == 0 {
+			expected = []string{"2xx"}
+		}
+		failures = append(failures, fmt.Sprintf("unexpected status %s, expected %s", resp.Status, strings.Join(expected, ", ")))
+	}
+
+	for _, header := range a.Headers {
+		value := resp.Header.Get(header.Name)
+		if _, present := resp.Header[http.CanonicalHeaderKey(header.Name)]; !present {
+			failures = append(failures, fmt.Sprintf("header %s is missing", header.Name))
+			continue
+		}
+		if header.Matches != "" {
+			re, err := regexp.Compile(header.Matches)
+			if err != nil || !re.MatchString(value) {
+				failures = append(failures, fmt.Sprintf("header %s value %q does not match %q", header.Name, value, header.Matches))
+			}
+		}
+	}
+
+	if a.MaxBodyBytes > 0 && bodyTruncated {
+		failures = append(failures, fmt.Sprintf("body exceeds %d bytes", a.MaxBodyBytes))
+	}
+
+	for _, substring := range a.BodyContains {
+		if !strings.Contains(string(body), substring) {
+			failures = append(failures, fmt.Sprintf("body does not contain %q", substring))
+		}
+	}
+
+	if a.BodyRegex != "" {
+		re, err := regexp.Compile(a.BodyRegex)
+		if err != nil || !re.Match(body) {
+			failures = append(failures, fmt.Sprintf("body does not match %q", a.BodyRegex))
+		}
+	}
+
+	if len(a.JSONPath) > 0 {
+		var document interface{}
+		if err := json.Unmarshal(body, &document); err != nil {
+			failures = append(failures, fmt.Sprintf("body is not valid JSON: %v", err))
+		} else {
+			for _, assertion := range a.JSONPath {
+				if failure := assertion.evaluate(document); failure != "" {
+					failures = append(failures, failure)
+				}
+			}
+		}
+	}
+
+	return failures
+}
+
+func (a JSONPathAssertion) evaluate(document interface{}) string {
+	value, found := lookupJSONPath(document, a.Path)
+
+	wantExists := a.Exists == nil || *a.Exists
+	if !wantExists {
+		if found {
+			return fmt.Sprintf("json path %s should not exist", a.Path)
+		}
+		return ""
+	}
+	if !found {
+		return fmt.Sprintf("json path %s does not exist", a.Path)
+	}
+
+	if a.Equals != nil && !reflect.DeepEqual(value, a.Equals) {
+		return fmt.Sprintf("json path %s is %v, expected %v", a.Path, value, a.Equals)
+	}
+	return ""
+}
+
+// lookupJSONPath resolves a dotted path with optional array indexes, such as
+// "$.data.items[0].status", against a decoded JSON document.
+func lookupJSONPath(document interface{}, path string) (interface{}, bool) {
+	path = strings.TrimPrefix(strings.TrimPrefix(path, "$"), ".")
+	if path == "" {
+		return document, true
+	}
+
+	current := document
+	for _, segment := range strings.Split(path, ".") {
+		name := segment
+		var indexes []int
+		if open := strings.Index(segment, "["); open >= 0 {
+			name = segment[:open]
+			for _, part := range strings.Split(segment[open+1:], "[") {
+				index, err := strconv.Atoi(strings.TrimSuffix(part, "]"))
+				if err != nil || !strings.HasSuffix(part, "]") {
+					return nil, false
+				}
+				indexes = append(indexes, index)
+			}
+		}
+
+		if name != "" {
+			object, ok := current.(map[string]interface{})
+			if !ok {
+				return nil, false
+			}
+			if current, ok = object[name]; !ok {
+				return nil, false
+			}
+		}
+
+		for _, index := range indexes {
+			array, ok := current.([]interface{})
+			if !ok || index < 0 || index >= len(array) {
+				return nil, false
+			}
+			current = array[index]
+		}
+	}
+
+	return current, true
+}
+
+// statusCodeAllowed matches code against patterns like "200", "2xx" or
+// "200-299". Without patterns any 2xx code is allowed.
+func statusCodeAllowed(patterns []string, code int) bool {
+	if len(patterns) == 0 {
+		return code >= 200 && code < 300
+	}
+	for _, pattern := range patterns {
+		if ok, _ := matchStatusCode(pattern, code); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func matchStatusCode(pattern string, code int) (bool, error) {
+	pattern = strings.ToLower(strings.TrimSpace(pattern))
+
+	if len(pattern) == 3 && strings.HasSuffix(pattern, "xx") && pattern[0] >= '1' && pattern[0] <= '5' {
+		class := int(pattern[0] - '0')
+		return code/100 == class, nil
+	}
+
+	if low, high, ok := strings.Cut(pattern, "-"); ok {
+		from, err1 := strconv.Atoi(low)
+		to, err2 := strconv.Atoi(high)
+		if err1 != nil || err2 != nil || from > to {
+			return false, fmt.Errorf("invalid status code range %q", pattern)
+		}
+		return code >= from && code <= to, nil
+	}
+
+	exact, err := strconv.Atoi(pattern)
+	if err != nil {
+		return false, fmt.Errorf("invalid status code %q", pattern)
+	}
+	return code == exact, nil
+}
@@ -0,0 +1,215 @@
+package monitor
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatchStatusCode(t *testing.T) {
+	tests := []struct {
+		pattern string
+		code    int
+		want    bool
+		wantErr bool
+	}{
+		{pattern: "200", code: 200, want: true},
+		{pattern: "200", code: 201, want: false},
+		{pattern: "2xx", code: 204, want: true},
+		{pattern: "3XX", code: 301, want: true},
+		{pattern: "4xx", code: 503, want: false},
+		{pattern: "200-299", code: 250, want: true},
+		{pattern: "200-299", code: 301, want: false},
+		{pattern: "299-200", wantErr: true},
+		{pattern: "abc", wantErr: true},
+		{pattern: "9xx", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern, func(t *testing.T) {
+			got, err := matchStatusCode(tt.pattern, tt.code)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestLookupJSONPath(t *testing.T) {
+	document := map[string]interface{}{
+		"status": "ok",
+		"data": map[string]interface{}{
+			"items": []interface{}{
+				map[string]interface{}{"name": "db", "healthy": true},
+				[]interface{}{float64(1), float64(2)},
+			},
+		},
+	}
+
+	tests := []struct {
+		path  string
+		want  interface{}
+		found bool
+	}{
+		{path: "status", want: "ok", found: true},
+		{path: "$.status", want: "ok", found: true},
+		{path: "data.items[0].name", want: "db", found: true},
+		{path: "$.data.items[0].healthy", want: true, found: true},
+		{path: "data.items[1][1]", want: float64(2), found: true},
+		{path: "data.items[5]", found: false},
+		{path: "data.missing", found: false},
+		{path: "status.nested", found: false},
+		{path: "data.items[x]", found: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			got, found := lookupJSONPath(document, tt.path)
+			assert.Equal(t, tt.found, found)
+			if tt.found {
+				assert.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
+
+func TestHTTPAssertions_Validate(t *testing.T) {
+	assert.NoError(t, HTTPAssertions{StatusCodes: []string{"2xx", "301"}, BodyRegex: "ok|healthy"}.Validate())
+	assert.Error(t, HTTPAssertions{StatusCodes: []string{"two hundred"}}.Validate())
+	assert.Error(t, HTTPAssertions{BodyRegex: "("}.Validate())
+	assert.Error(t, HTTPAssertions{Headers: []HeaderAssertion{{Name: "X-Version", Matches: "["}}}.Validate())
+}
+
+func TestHTTPChecker_Assertions(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/maintenance":
+			w.Write([]byte("<h1>Down for maintenance</h1>"))
+		case "/json":
+			w.Header().Set("Content-Type", "application/json")
+			w.Header().Set("X-Version", "1.4.2")
+			w.Write([]byte(`{"status":"fail","checks":[{"name":"db","ok":false}],"uptime":42}`))
+		case "/redirect":
+			w.WriteHeader(http.StatusFound)
+		case "/large":
+			w.Write([]byte(strings.Repeat("a", 2048)))
+		}
+	}))
+	defer server.Close()
+
+	checker := NewHTTPChecker(&http.Client{
+		Timeout: time.Second,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	})
+	check := func(path string, assertions HTTPAssertions) CheckResult {
+		return checker.Check(context.Background(), CheckJob{
+			Type:   CheckTypeHTTP,
+			URL:    server.URL + path,
+			Config: CheckConfig{HTTP: &HTTPCheckConfig{Assertions: &assertions}},
+		})
+	}
+	exists := func(v bool) *bool { return &v }
+
+	t.Run("body contains", func(t *testing.T) {
+		result := check("/maintenance", HTTPAssertions{BodyContains: []string{"maintenance"}})
+		assert.Equal(t, StatusUp, result.Status)
+
+		result = check("/maintenance", HTTPAssertions{BodyContains: []string{"Welcome"}})
+		assert.Equal(t, StatusDown, result.Status)
+		assert.Contains(t, result.Message, "Welcome")
+		assert.Equal(t, ErrorClassAssertion, result.ErrorClass)
+		assert.Equal(t, "<h1>Down for maintenance</h1>", result.BodySnippet)
+	})
+
+	t.Run("body regex", func(t *testing.T) {
+		result := check("/maintenance", HTTPAssertions{BodyRegex: `^\{"status":"ok"`})
+		assert.Equal(t, StatusDown, result.Status)
+
+		result = check("/json", HTTPAssertions{BodyRegex: `"uptime":\d+`})
+		assert.Equal(t, StatusUp, result.Status)
+	})
+
+	t.Run("json path equals", func(t *testing.T) {
+		result := check("/json", HTTPAssertions{JSONPath: []JSONPathAssertion{{Path: "$.status", Equals: "ok"}}})
+		assert.Equal(t, StatusDown, result.Status)
+		assert.Equal(t, []string{"json path $.status is fail, expected ok"}, result.Details["assertion_failures"])
+
+		result = check("/json", HTTPAssertions{JSONPath: []JSONPathAssertion{
+			{Path: "uptime", Equals: float64(42)},
+			{Path: "checks[0].ok", Equals: false},
+		}})
+		assert.Equal(t, StatusUp, result.Status)
+	})
+
+	t.Run("json path exists", func(t *testing.T) {
+		result := check("/json", HTTPAssertions{JSONPath: []JSONPathAssertion{{Path: "checks[0].name"}}})
+		assert.Equal(t, StatusUp, result.Status)
+
+		result = check("/json", HTTPAssertions{JSONPath: []JSONPathAssertion{{Path: "error", Exists: exists(false)}}})
+		assert.Equal(t, StatusUp, result.Status)
+
+		result = check("/json", HTTPAssertions{JSONPath: []JSONPathAssertion{{Path: "status", Exists: exists(false)}}})
+		assert.Equal(t, StatusDown, result.Status)
+	})
+
+	t.Run("json path on non-json body", func(t *testing.T) {
+		result := check("/maintenance", HTTPAssertions{JSONPath: []JSONPathAssertion{{Path: "status"}}})
+		assert.Equal(t, StatusDown, result.Status)
+		assert.Contains(t, result.Message, "not valid JSON")
+	})
+
+	t.Run("headers", func(t *testing.T) {
+		result := check("/json", HTTPAssertions{Headers: []HeaderAssertion{
+			{Name: "content-type", Matches: "^application/json"},
+			{Name: "X-Version"},
+		}})
+		assert.Equal(t, StatusUp, result.Status)
+
+		result = check("/json", HTTPAssertions{Headers: []HeaderAssertion{{Name: "X-Version", Matches: `^2\.`}}})
+		assert.Equal(t, StatusDown, result.Status)
+
+		result = check("/json", HTTPAssertions{Headers: []HeaderAssertion{{Name: "X-Request-Id"}}})
+		assert.Equal(t, StatusDown, result.Status)
+		assert.Contains(t, result.Message, "missing")
+	})
+
+	t.Run("status codes", func(t *testing.T) {
+		result := check("/redirect", HTTPAssertions{})
+		assert.Equal(t, StatusDown, result.Status)
+
+		result = check("/redirect", HTTPAssertions{StatusCodes: []string{"2xx", "302"}})
+		assert.Equal(t, StatusUp, result.Status)
+
+		result = check("/json", HTTPAssertions{StatusCodes: []string{"300-399"}})
+		assert.Equal(t, StatusDown, result.Status)
+	})
+
+	t.Run("max body size", func(t *testing.T) {
+		result := check("/large", HTTPAssertions{MaxBodyBytes: 4096})
+		assert.Equal(t, StatusUp, result.Status)
+
+		result = check("/large", HTTPAssertions{MaxBodyBytes: 1024})
+		assert.Equal(t, StatusDown, result.Status)
+		assert.Contains(t, result.Message, "exceeds 1024 bytes")
+		assert.Len(t, result.BodySnippet, maxBodySnippetBytes)
+	})
+
+	t.Run("multiple failures are all reported", func(t *testing.T) {
+		result := check("/json", HTTPAssertions{
+			BodyContains: []string{"healthy"},
+			JSONPath:     []JSONPathAssertion{{Path: "status", Equals: "ok"}},
+		})
+		assert.Equal(t, StatusDown, result.Status)
+		assert.Len(t, result.Details["assertion_failures"], 2)
+	})
+}
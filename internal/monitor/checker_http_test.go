@@ -0,0 +1,191 @@
+package monitor
+
+import (
+	"context"
+	"encoding/json"
+	"health-checker/internal/secrets"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTTPChecker_Check(t *testing.T) {
+	t.Run("2xx is UP", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, http.MethodGet, r.Method)
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		defer server.Close()
+
+		checker := NewHTTPChecker(&http.Client{Timeout: time.Second})
+		result := checker.Check(context.Background(), CheckJob{Type: CheckTypeHTTP, URL: server.URL})
+
+		assert.Equal(t, StatusUp, result.Status)
+		assert.Equal(t, http.StatusNoContent, result.Details["status_code"])
+		assert.GreaterOrEqual(t, result.Latency, time.Duration(0))
+	})
+
+	t.Run("non-2xx is DOWN", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			w.Write([]byte("upstream connect error"))
+		}))
+		defer server.Close()
+
+		checker := NewHTTPChecker(&http.Client{Timeout: time.Second})
+		result := checker.Check(context.Background(), CheckJob{Type: CheckTypeHTTP, URL: server.URL})
+
+		assert.Equal(t, StatusDown, result.Status)
+		assert.Equal(t, "unexpected status 503 Service Unavailable, expected 2xx", result.Message)
+		assert.Equal(t, http.StatusServiceUnavailable, result.StatusCode)
+		assert.Equal(t, ErrorClassHTTPStatus, result.ErrorClass)
+		assert.Equal(t, "upstream connect error", result.BodySnippet)
+	})
+
+	t.Run("connection error is DOWN", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+		url := server.URL
+		server.Close()
+
+		checker := NewHTTPChecker(&http.Client{Timeout: time.Second})
+		result := checker.Check(context.Background(), CheckJob{Type: CheckTypeHTTP, URL: url})
+
+		assert.Equal(t, StatusDown, result.Status)
+		assert.NotEmpty(t, result.Message)
+		assert.Equal(t, ErrorClassConnectionRefused, result.ErrorClass)
+		assert.Zero(t, result.StatusCode)
+	})
+
+	t.Run("timeout is DOWN", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			time.Sleep(200 * time.Millisecond)
+		}))
+		defer server.Close()
+
+		checker := NewHTTPChecker(&http.Client{Timeout: 50 * time.Millisecond})
+		result := checker.Check(context.Background(), CheckJob{Type: CheckTypeHTTP, URL: server.URL})
+
+		assert.Equal(t, StatusDown, result.Status)
+		assert.Equal(t, ErrorClassTimeout, result.ErrorClass)
+	})
+
+	t.Run("invalid url is DOWN", func(t *testing.T) {
+		checker := NewHTTPChecker(&http.Client{})
+		result := checker.Check(context.Background(), CheckJob{Type: CheckTypeHTTP, URL: "://bad"})
+
+		assert.Equal(t, StatusDown, result.Status)
+	})
+}
+
+func TestHTTPChecker_RequestOptions(t *testing.T) {
+	os.Setenv("ENCRYPTION_KEY", "test-key")
+	defer os.Unsetenv("ENCRYPTION_KEY")
+
+	var received *http.Request
+	var receivedBody string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		received, receivedBody = r, string(body)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	encryptAuth := func(auth HTTPAuth) string {
+		plaintext, err := json.Marshal(auth)
+		require.NoError(t, err)
+		encrypted, err := secrets.Encrypt(plaintext)
+		require.NoError(t, err)
+		return encrypted
+	}
+	checker := NewHTTPChecker(&http.Client{Timeout: time.Second})
+
+	t.Run("method, headers and body", func(t *testing.T) {
+		result := checker.Check(context.Background(), CheckJob{
+			Type: CheckTypeHTTP,
+			URL:  server.URL,
+			Config: CheckConfig{HTTP: &HTTPCheckConfig{
+				Method:  http.MethodPost,
+				Headers: map[string]string{"Content-Type": "application/json", "X-Probe": "health-checker"},
+				Body:    `{"ping":true}`,
+			}},
+		})
+
+		assert.Equal(t, StatusUp, result.Status)
+		assert.Equal(t, http.MethodPost, received.Method)
+		assert.Equal(t, "application/json", received.Header.Get("Content-Type"))
+		assert.Equal(t, "health-checker", received.Header.Get("X-Probe"))
+		assert.Equal(t, `{"ping":true}`, receivedBody)
+	})
+
+	t.Run("basic auth", func(t *testing.T) {
+		result := checker.Check(context.Background(), CheckJob{
+			Type:          CheckTypeHTTP,
+			URL:           server.URL,
+			EncryptedAuth: encryptAuth(HTTPAuth{Type: "basic", Username: "monitor", Password: "pw"}),
+		})
+
+		assert.Equal(t, StatusUp, result.Status)
+		username, password, ok := received.BasicAuth()
+		assert.True(t, ok)
+		assert.Equal(t, "monitor", username)
+		assert.Equal(t, "pw", password)
+	})
+
+	t.Run("bearer auth", func(t *testing.T) {
+		result := checker.Check(context.Background(), CheckJob{
+			Type:          CheckTypeHTTP,
+			URL:           server.URL,
+			EncryptedAuth: encryptAuth(HTTPAuth{Type: "bearer", Token: "t0ken"}),
+		})
+
+		assert.Equal(t, StatusUp, result.Status)
+		assert.Equal(t, "Bearer t0ken", received.Header.Get("Authorization"))
+	})
+
+	t.Run("undecryptable auth is DOWN", func(t *testing.T) {
+		received = nil
+		result := checker.Check(context.Background(), CheckJob{
+			Type:          CheckTypeHTTP,
+			URL:           server.URL,
+			EncryptedAuth: "not-ciphertext",
+		})
+
+		assert.Equal(t, StatusDown, result.Status)
+		assert.Contains(t, result.Message, "decrypt")
+		assert.Nil(t, received)
+	})
+}
+
+func TestHTTPChecker_Timing(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(100 * time.Millisecond)
+		w.Write([]byte("first half,"))
+		w.(http.Flusher).Flush()
+		time.Sleep(50 * time.Millisecond)
+		w.Write([]byte("second half"))
+	}))
+	defer server.Close()
+
+	checker := NewHTTPChecker(&http.Client{Timeout: time.Second})
+	result := checker.Check(context.Background(), CheckJob{Type: CheckTypeHTTP, URL: server.URL})
+
+	assert.Equal(t, StatusUp, result.Status)
+	require.NotNil(t, result.Timing)
+	assert.GreaterOrEqual(t, result.Timing.TTFBMs, 100)
+	assert.GreaterOrEqual(t, result.Timing.TransferMs, 50)
+	assert.Zero(t, result.Timing.DNSMs, "an IP address needs no lookup")
+	assert.Zero(t, result.Timing.TLSMs, "plain HTTP has no handshake")
+}
+
+func TestMillisBetween(t *testing.T) {
+	start := time.Now()
+	assert.Equal(t, 250, millisBetween(start, start.Add(250*time.Millisecond)))
+	assert.Zero(t, millisBetween(time.Time{}, start), "a phase that never started")
+	assert.Zero(t, millisBetween(start, time.Time{}), "a phase that never finished")
+}
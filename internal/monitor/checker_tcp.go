@@ -0,0 +1,87 @@
+package monitor
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxBannerBytes caps how much of a TCP response is read while looking for the expected banner.
+const maxBannerBytes = 4096
+
+type TCPChecker struct {
+	dialer *net.Dialer
+}
+
+func NewTCPChecker(dialer *net.Dialer) *TCPChecker {
+	return &TCPChecker{dialer: dialer}
+}
+
+func (c *TCPChecker) Check(ctx context.Context, job CheckJob) CheckResult {
+	address := net.JoinHostPort(job.Host, strconv.Itoa(job.Port))
+	details := map[string]interface{}{
+		"address": address,
+	}
+
+	start := time.Now()
+	conn, err := c.dialer.DialContext(ctx, "tcp", address)
+	latency := time.Since(start)
+	if err != nil {
+		return CheckResult{Status: StatusDown, Latency: latency, Message: err.Error(), Details: details, ErrorClass: classifyError(err)}
+	}
+	defer conn.Close()
+
+	details["connect_ms"] = latency.Milliseconds()
+
+	config := job.Config.TCP
+	if config == nil || (config.Send == "" && config.Expect == "") {
+		return CheckResult{Status: StatusUp, Latency: latency, Details: details}
+	}
+
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	if config.Send != "" {
+		if _, err := conn.Write([]byte(config.Send)); err != nil {
+			return CheckResult{Status: StatusDown, Latency: latency, Message: err.Error(), Details: details, ErrorClass: classifyError(err)}
+		}
+	}
+
+	if config.Expect == "" {
+		return CheckResult{Status: StatusUp, Latency: latency, Details: details}
+	}
+
+	banner, err := readUntil(conn, config.Expect)
+	details["banner"] = banner
+	if !strings.Contains(banner, config.Expect) {
+		message := fmt.Sprintf("expected %q in response", config.Expect)
+		if err != nil {
+			message = fmt.Sprintf("%s: %v", message, err)
+		}
+		return CheckResult{Status: StatusDown, Latency: latency, Message: message, Details: details, ErrorClass: ErrorClassAssertion}
+	}
+
+	return CheckResult{Status: StatusUp, Latency: latency, Details: details}
+}
+
+// readUntil reads from conn until expect has been seen, the peer stops sending
+// or maxBannerBytes have been read.
+func readUntil(conn net.Conn, expect string) (string, error) {
+	buf := make([]byte, 0, 512)
+	chunk := make([]byte, 512)
+	for len(buf) < maxBannerBytes {
+		n, err := conn.Read(chunk)
+		buf = append(buf, chunk[:n]...)
+		if strings.Contains(string(buf), expect) {
+			return string(buf), nil
+		}
+		if err != nil {
+			return string(buf), err
+		}
+	}
+	return string(buf), nil
+}
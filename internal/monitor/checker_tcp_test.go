@@ -0,0 +1,132 @@
+package monitor
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// startTCPServer listens on a random local port and runs handle for every accepted connection.
+func startTCPServer(t *testing.T, handle func(conn net.Conn)) (string, int) {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				handle(conn)
+			}()
+		}
+	}()
+
+	addr := listener.Addr().(*net.TCPAddr)
+	return addr.IP.String(), addr.Port
+}
+
+func TestTCPChecker_Check(t *testing.T) {
+	checker := NewTCPChecker(&net.Dialer{})
+
+	t.Run("connect only is UP", func(t *testing.T) {
+		host, port := startTCPServer(t, func(conn net.Conn) {})
+
+		result := checker.Check(context.Background(), CheckJob{Type: CheckTypeTCP, Host: host, Port: port})
+
+		assert.Equal(t, StatusUp, result.Status)
+		assert.Contains(t, result.Details, "connect_ms")
+	})
+
+	t.Run("connection refused is DOWN", func(t *testing.T) {
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		port := listener.Addr().(*net.TCPAddr).Port
+		listener.Close()
+
+		result := checker.Check(context.Background(), CheckJob{Type: CheckTypeTCP, Host: "127.0.0.1", Port: port})
+
+		assert.Equal(t, StatusDown, result.Status)
+		assert.NotEmpty(t, result.Message)
+		assert.Equal(t, ErrorClassConnectionRefused, result.ErrorClass)
+	})
+
+	t.Run("expected banner is UP", func(t *testing.T) {
+		host, port := startTCPServer(t, func(conn net.Conn) {
+			conn.Write([]byte("SSH-2.0-OpenSSH_9.6\r\n"))
+		})
+
+		result := checker.Check(context.Background(), CheckJob{
+			Type:   CheckTypeTCP,
+			Host:   host,
+			Port:   port,
+			Config: CheckConfig{TCP: &TCPCheckConfig{Expect: "SSH-2.0"}},
+		})
+
+		assert.Equal(t, StatusUp, result.Status)
+		assert.Equal(t, "SSH-2.0-OpenSSH_9.6\r\n", result.Details["banner"])
+	})
+
+	t.Run("send and expect reply is UP", func(t *testing.T) {
+		host, port := startTCPServer(t, func(conn net.Conn) {
+			line, err := bufio.NewReader(conn).ReadString('\n')
+			if err == nil && line == "PING\r\n" {
+				conn.Write([]byte("+PONG\r\n"))
+			}
+		})
+
+		result := checker.Check(context.Background(), CheckJob{
+			Type:   CheckTypeTCP,
+			Host:   host,
+			Port:   port,
+			Config: CheckConfig{TCP: &TCPCheckConfig{Send: "PING\r\n", Expect: "+PONG"}},
+		})
+
+		assert.Equal(t, StatusUp, result.Status)
+	})
+
+	t.Run("unexpected banner is DOWN", func(t *testing.T) {
+		host, port := startTCPServer(t, func(conn net.Conn) {
+			conn.Write([]byte("-ERR unknown command\r\n"))
+		})
+
+		result := checker.Check(context.Background(), CheckJob{
+			Type:   CheckTypeTCP,
+			Host:   host,
+			Port:   port,
+			Config: CheckConfig{TCP: &TCPCheckConfig{Expect: "+PONG"}},
+		})
+
+		assert.Equal(t, StatusDown, result.Status)
+		assert.Contains(t, result.Message, "+PONG")
+		assert.Equal(t, ErrorClassAssertion, result.ErrorClass)
+	})
+
+	t.Run("silent peer times out as DOWN", func(t *testing.T) {
+		host, port := startTCPServer(t, func(conn net.Conn) {
+			time.Sleep(time.Second)
+		})
+
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+
+		result := checker.Check(ctx, CheckJob{
+			Type:   CheckTypeTCP,
+			Host:   host,
+			Port:   port,
+			Config: CheckConfig{TCP: &TCPCheckConfig{Expect: "220"}},
+		})
+
+		assert.Equal(t, StatusDown, result.Status)
+	})
+}
@@ -0,0 +1,100 @@
+package monitor
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type stubChecker struct {
+	result CheckResult
+	jobs   []CheckJob
+}
+
+func (c *stubChecker) Check(ctx context.Context, job CheckJob) CheckResult {
+	c.jobs = append(c.jobs, job)
+	return c.result
+}
+
+func TestCheckerRegistry(t *testing.T) {
+	t.Run("Get returns registered checker", func(t *testing.T) {
+		registry := NewCheckerRegistry()
+		checker := &stubChecker{}
+		registry.Register("stub", checker)
+
+		got, ok := registry.Get("stub")
+		assert.True(t, ok)
+		assert.Same(t, checker, got)
+	})
+
+	t.Run("Get unknown type", func(t *testing.T) {
+		registry := NewCheckerRegistry()
+
+		got, ok := registry.Get("unknown")
+		assert.False(t, ok)
+		assert.Nil(t, got)
+	})
+
+	t.Run("Register replaces existing checker", func(t *testing.T) {
+		registry := NewCheckerRegistry()
+		first := &stubChecker{}
+		second := &stubChecker{}
+		registry.Register("stub", first)
+		registry.Register("stub", second)
+
+		got, _ := registry.Get("stub")
+		assert.Same(t, second, got)
+	})
+}
+
+func TestDefaultCheckers(t *testing.T) {
+	registry := DefaultCheckers(&http.Client{}, stubDeadlines{overdue: true})
+
+	checker, ok := registry.Get(CheckTypeHTTP)
+	assert.True(t, ok)
+	assert.IsType(t, &HTTPChecker{}, checker)
+
+	checker, ok = registry.Get(CheckTypeGRPC)
+	assert.True(t, ok)
+	assert.IsType(t, &GRPCChecker{}, checker)
+
+	checker, ok = registry.Get(CheckTypeHeartbeat)
+	assert.True(t, ok)
+	assert.Equal(t, StatusDown, checker.Check(context.Background(), CheckJob{Type: CheckTypeHeartbeat}).Status)
+}
+
+// stubDeadlines reports every heartbeat deadline as overdue or not, or fails.
+type stubDeadlines struct {
+	overdue bool
+	err     error
+}
+
+func (s stubDeadlines) HeartbeatOverdue(ctx context.Context, serviceID int) (bool, error) {
+	return s.overdue, s.err
+}
+
+func TestHeartbeatChecker_Check(t *testing.T) {
+	tests := []struct {
+		name       string
+		deadlines  stubDeadlines
+		wantStatus Status
+	}{
+		{name: "missed beat", deadlines: stubDeadlines{overdue: true}, wantStatus: StatusDown},
+		{name: "pinged since the claim", deadlines: stubDeadlines{overdue: false}, wantStatus: StatusUp},
+		{name: "deadline unreadable", deadlines: stubDeadlines{err: errors.New("db error")}, wantStatus: StatusDown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := NewHeartbeatChecker(tt.deadlines).Check(context.Background(), CheckJob{ServiceID: 3, Type: CheckTypeHeartbeat})
+
+			assert.Equal(t, tt.wantStatus, result.Status)
+			if tt.wantStatus == StatusDown {
+				assert.Equal(t, ErrorClassMissedHeartbeat, result.ErrorClass)
+			}
+		})
+	}
+}
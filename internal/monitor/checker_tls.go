@@ -0,0 +1,169 @@
+package monitor
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"math"
+	"net"
+	"strconv"
+	"time"
+)
+
+const (
+	defaultTLSPort         = 443
+	defaultTLSWarnDays     = 14
+	defaultTLSCriticalDays = 7
+)
+
+// days returns the warn and critical thresholds, defaulting those left unset.
+func (c TLSCheckConfig) days() (int, int) {
+	warnDays := c.WarnDays
+	if warnDays == 0 {
+		warnDays = defaultTLSWarnDays
+	}
+	criticalDays := c.CriticalDays
+	if criticalDays == 0 {
+		criticalDays = defaultTLSCriticalDays
+	}
+	return warnDays, criticalDays
+}
+
+// Validate reports thresholds under which the certificate would go DOWN
+// without first being DEGRADED.
+func (c TLSCheckConfig) Validate() error {
+	if warnDays, criticalDays := c.days(); warnDays <= criticalDays {
+		return fmt.Errorf("warn_days (%d) must be above critical_days (%d)", warnDays, criticalDays)
+	}
+	return nil
+}
+
+type TLSChecker struct {
+	dialer *net.Dialer
+	// roots verifies the chain; nil means the system pool.
+	roots *x509.CertPool
+}
+
+func NewTLSChecker(dialer *net.Dialer, roots *x509.CertPool) *TLSChecker {
+	return &TLSChecker{dialer: dialer, roots: roots}
+}
+
+func (c *TLSChecker) Check(ctx context.Context, job CheckJob) CheckResult {
+	config := TLSCheckConfig{}
+	if job.Config.TLS != nil {
+		config = *job.Config.TLS
+	}
+	warnDays, criticalDays := config.days()
+	serverName := config.ServerName
+	if serverName == "" {
+		serverName = job.Host
+	}
+	port := job.Port
+	if port == 0 {
+		port = defaultTLSPort
+	}
+
+	address := net.JoinHostPort(job.Host, strconv.Itoa(port))
+	details := map[string]interface{}{
+		"address":     address,
+		"server_name": serverName,
+	}
+
+	// Verification is done below so that a bad chain is reported instead of failing the handshake.
+	dialer := &tls.Dialer{
+		NetDialer: c.dialer,
+		Config:    &tls.Config{ServerName: serverName, InsecureSkipVerify: true},
+	}
+
+	start := time.Now()
+	conn, err := dialer.DialContext(ctx, "tcp", address)
+	latency := time.Since(start)
+	if err != nil {
+		return CheckResult{Status: StatusDown, Latency: latency, Message: err.Error(), Details: details, ErrorClass: classifyError(err)}
+	}
+	defer conn.Close()
+
+	state := conn.(*tls.Conn).ConnectionState()
+	if len(state.PeerCertificates) == 0 {
+		return CheckResult{Status: StatusDown, Latency: latency, Message: "peer presented no certificates", Details: details,
+			ErrorClass: ErrorClassTLS}
+	}
+
+	leaf := state.PeerCertificates[0]
+	for key, value := range certificateDetails(leaf) {
+		details[key] = value
+	}
+	details["tls_version"] = tls.VersionName(state.Version)
+
+	chain := make([]map[string]interface{}, 0, len(state.PeerCertificates))
+	intermediates := x509.NewCertPool()
+	for i, cert := range state.PeerCertificates {
+		chain = append(chain, map[string]interface{}{
+			"subject":   cert.Subject.String(),
+			"issuer":    cert.Issuer.String(),
+			"not_after": cert.NotAfter,
+		})
+		if i > 0 {
+			intermediates.AddCert(cert)
+		}
+	}
+	details["chain"] = chain
+
+	hostnameErr := leaf.VerifyHostname(serverName)
+	details["hostname_mismatch"] = hostnameErr != nil
+
+	_, chainErr := leaf.Verify(x509.VerifyOptions{
+		Roots:         c.roots,
+		Intermediates: intermediates,
+	})
+	details["chain_valid"] = chainErr == nil
+
+	daysLeft := daysUntil(leaf.NotAfter)
+	result := CheckResult{Status: StatusUp, Latency: latency, Details: details}
+	switch {
+	case daysLeft < 0:
+		result.Status = StatusDown
+		result.Message = fmt.Sprintf("certificate expired on %s", leaf.NotAfter.Format(time.RFC3339))
+	case chainErr != nil:
+		result.Status = StatusDown
+		result.Message = fmt.Sprintf("certificate chain is not trusted: %v", chainErr)
+	case hostnameErr != nil:
+		result.Status = StatusDown
+		result.Message = hostnameErr.Error()
+	case daysLeft <= criticalDays:
+		result.Status = StatusDown
+		result.Message = fmt.Sprintf("certificate expires in %d days (critical threshold %d)", daysLeft, criticalDays)
+	case daysLeft <= warnDays:
+		result.Status = StatusDegraded
+		result.Message = fmt.Sprintf("certificate expires in %d days (warning threshold %d)", daysLeft, warnDays)
+	}
+	if result.Status != StatusUp {
+		result.ErrorClass = ErrorClassTLS
+	}
+
+	return result
+}
+
+// certificateDetails describes a leaf certificate for health check details.
+func certificateDetails(cert *x509.Certificate) map[string]interface{} {
+	return map[string]interface{}{
+		"days_until_expiry": daysUntil(cert.NotAfter),
+		"not_after":         cert.NotAfter,
+		"issuer":            cert.Issuer.String(),
+		"subject":           cert.Subject.String(),
+		"sans":              certificateSANs(cert),
+	}
+}
+
+func daysUntil(t time.Time) int {
+	return int(math.Floor(time.Until(t).Hours() / 24))
+}
+
+func certificateSANs(cert *x509.Certificate) []string {
+	sans := append([]string{}, cert.DNSNames...)
+	for _, ip := range cert.IPAddresses {
+		sans = append(sans, ip.String())
+	}
+	return sans
+}
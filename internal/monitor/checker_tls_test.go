@@ -0,0 +1,170 @@
+package monitor
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestCertificate creates a self-signed certificate for localhost and 127.0.0.1.
+func newTestCertificate(t *testing.T, notAfter time.Time) tls.Certificate {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "localhost"},
+		Issuer:                pkix.Name{CommonName: "localhost"},
+		NotBefore:             notAfter.Add(-365 * 24 * time.Hour),
+		NotAfter:              notAfter,
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+		DNSNames:              []string{"localhost"},
+		IPAddresses:           []net.IP{net.ParseIP("127.0.0.1")},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	leaf, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key, Leaf: leaf}
+}
+
+// startTLSServer serves cert on a random local port and returns its port.
+func startTLSServer(t *testing.T, cert tls.Certificate) int {
+	t.Helper()
+
+	listener, err := tls.Listen("tcp", "127.0.0.1:0", &tls.Config{Certificates: []tls.Certificate{cert}})
+	require.NoError(t, err)
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				conn.(*tls.Conn).Handshake()
+			}()
+		}
+	}()
+
+	return listener.Addr().(*net.TCPAddr).Port
+}
+
+func TestTLSChecker_Check(t *testing.T) {
+	validCert := newTestCertificate(t, time.Now().Add(90*24*time.Hour))
+	validPort := startTLSServer(t, validCert)
+	roots := x509.NewCertPool()
+	roots.AddCert(validCert.Leaf)
+
+	check := func(checker *TLSChecker, port int, config TLSCheckConfig) CheckResult {
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+		return checker.Check(ctx, CheckJob{
+			Type:   CheckTypeTLS,
+			Host:   "127.0.0.1",
+			Port:   port,
+			Config: CheckConfig{TLS: &config},
+		})
+	}
+
+	t.Run("valid certificate is UP", func(t *testing.T) {
+		result := check(NewTLSChecker(&net.Dialer{}, roots), validPort, TLSCheckConfig{})
+
+		assert.Equal(t, StatusUp, result.Status)
+		assert.Equal(t, true, result.Details["chain_valid"])
+		assert.Equal(t, false, result.Details["hostname_mismatch"])
+		assert.InDelta(t, 89, result.Details["days_until_expiry"], 1)
+		assert.Equal(t, "CN=localhost", result.Details["issuer"])
+		assert.Equal(t, []string{"localhost", "127.0.0.1"}, result.Details["sans"])
+	})
+
+	t.Run("within warning threshold is DEGRADED", func(t *testing.T) {
+		result := check(NewTLSChecker(&net.Dialer{}, roots), validPort, TLSCheckConfig{WarnDays: 120, CriticalDays: 30})
+
+		assert.Equal(t, StatusDegraded, result.Status)
+	})
+
+	t.Run("within critical threshold is DOWN", func(t *testing.T) {
+		result := check(NewTLSChecker(&net.Dialer{}, roots), validPort, TLSCheckConfig{WarnDays: 180, CriticalDays: 120})
+
+		assert.Equal(t, StatusDown, result.Status)
+		assert.Contains(t, result.Message, "critical")
+	})
+
+	t.Run("hostname mismatch is DOWN", func(t *testing.T) {
+		result := check(NewTLSChecker(&net.Dialer{}, roots), validPort, TLSCheckConfig{ServerName: "api.example.com"})
+
+		assert.Equal(t, StatusDown, result.Status)
+		assert.Equal(t, true, result.Details["hostname_mismatch"])
+	})
+
+	t.Run("untrusted chain is DOWN", func(t *testing.T) {
+		result := check(NewTLSChecker(&net.Dialer{}, x509.NewCertPool()), validPort, TLSCheckConfig{})
+
+		assert.Equal(t, StatusDown, result.Status)
+		assert.Equal(t, false, result.Details["chain_valid"])
+	})
+
+	t.Run("expired certificate is DOWN", func(t *testing.T) {
+		expired := newTestCertificate(t, time.Now().Add(-48*time.Hour))
+		expiredRoots := x509.NewCertPool()
+		expiredRoots.AddCert(expired.Leaf)
+		port := startTLSServer(t, expired)
+
+		result := check(NewTLSChecker(&net.Dialer{}, expiredRoots), port, TLSCheckConfig{})
+
+		assert.Equal(t, StatusDown, result.Status)
+		assert.Contains(t, result.Message, "expired")
+		assert.Equal(t, ErrorClassTLS, result.ErrorClass)
+	})
+
+	t.Run("connection refused is DOWN", func(t *testing.T) {
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		port := listener.Addr().(*net.TCPAddr).Port
+		listener.Close()
+
+		result := check(NewTLSChecker(&net.Dialer{}, roots), port, TLSCheckConfig{})
+
+		assert.Equal(t, StatusDown, result.Status)
+		assert.Equal(t, ErrorClassConnectionRefused, result.ErrorClass)
+	})
+}
+
+func TestHTTPChecker_ReportsCertificate(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	checker := NewHTTPChecker(server.Client())
+	result := checker.Check(context.Background(), CheckJob{Type: CheckTypeHTTP, URL: server.URL})
+
+	assert.Equal(t, StatusUp, result.Status)
+	certificate, ok := result.Details["tls"].(map[string]interface{})
+	if assert.True(t, ok) {
+		assert.Contains(t, certificate, "days_until_expiry")
+	}
+}
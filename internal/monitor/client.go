@@ -13,6 +13,8 @@ type WsClient struct {
 	send   chan []byte
 	hub    *WsHub
 	logger *zap.Logger
+	userID int
+	orgID  int
 }
 
 func NewWsClient(conn *websocket.Conn, hub *WsHub, logger *zap.Logger) *WsClient {
@@ -0,0 +1,43 @@
+package monitor
+
+import "time"
+
+// StatusState is a service's confirmed status together with the streak of
+// latest checks that disagree with it, if any.
+type StatusState struct {
+	Status        Status
+	PendingStatus Status
+	PendingCount  int
+	// PendingSince is when the first check of the streak ran.
+	PendingSince *time.Time
+}
+
+// Observe returns the state after a check that ran at and reported observed,
+// and whether that check confirmed a change of status. threshold is how many
+// consecutive checks must report a status before the service changes to it.
+// A service's first check is adopted as its status without a change.
+func (s StatusState) Observe(observed Status, at time.Time, threshold int) (StatusState, bool) {
+	if s.Status == "" {
+		return StatusState{Status: observed}, false
+	}
+	if observed == s.Status {
+		return StatusState{Status: s.Status}, false
+	}
+
+	next := StatusState{Status: s.Status, PendingStatus: observed, PendingCount: 1, PendingSince: &at}
+	if observed == s.PendingStatus {
+		next.PendingCount = s.PendingCount + 1
+		if s.PendingSince != nil {
+			next.PendingSince = s.PendingSince
+		}
+	}
+	if next.PendingCount >= threshold {
+		return StatusState{Status: observed}, true
+	}
+	return next, false
+}
+
+// Confirming reports whether a change of status awaits more agreeing checks.
+func (s StatusState) Confirming() bool {
+	return s.PendingStatus != ""
+}
@@ -0,0 +1,82 @@
+package monitor
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStatusState_Observe(t *testing.T) {
+	earlier := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	now := earlier.Add(time.Minute)
+
+	tests := []struct {
+		name        string
+		state       StatusState
+		observed    Status
+		threshold   int
+		want        StatusState
+		wantChanged bool
+	}{
+		{
+			name:      "first check is adopted",
+			observed:  "DOWN",
+			threshold: 3,
+			want:      StatusState{Status: "DOWN"},
+		},
+		{
+			name:      "agreeing check clears a streak",
+			state:     StatusState{Status: "UP", PendingStatus: "DOWN", PendingCount: 2, PendingSince: &earlier},
+			observed:  "UP",
+			threshold: 3,
+			want:      StatusState{Status: "UP"},
+		},
+		{
+			name:      "first disagreeing check starts a streak",
+			state:     StatusState{Status: "UP"},
+			observed:  "DOWN",
+			threshold: 3,
+			want:      StatusState{Status: "UP", PendingStatus: "DOWN", PendingCount: 1, PendingSince: &now},
+		},
+		{
+			name:      "streak grows",
+			state:     StatusState{Status: "UP", PendingStatus: "DOWN", PendingCount: 1, PendingSince: &earlier},
+			observed:  "DOWN",
+			threshold: 3,
+			want:      StatusState{Status: "UP", PendingStatus: "DOWN", PendingCount: 2, PendingSince: &earlier},
+		},
+		{
+			name:        "streak reaching the threshold confirms",
+			state:       StatusState{Status: "UP", PendingStatus: "DOWN", PendingCount: 2, PendingSince: &earlier},
+			observed:    "DOWN",
+			threshold:   3,
+			want:        StatusState{Status: "DOWN"},
+			wantChanged: true,
+		},
+		{
+			name:      "another status restarts the streak",
+			state:     StatusState{Status: "UP", PendingStatus: "DOWN", PendingCount: 2, PendingSince: &earlier},
+			observed:  "DEGRADED",
+			threshold: 3,
+			want:      StatusState{Status: "UP", PendingStatus: "DEGRADED", PendingCount: 1, PendingSince: &now},
+		},
+		{
+			name:        "threshold of one confirms right away",
+			state:       StatusState{Status: "DOWN"},
+			observed:    "UP",
+			threshold:   1,
+			want:        StatusState{Status: "UP"},
+			wantChanged: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, changed := tt.state.Observe(tt.observed, now, tt.threshold)
+
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantChanged, changed)
+		})
+	}
+}
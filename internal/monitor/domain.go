@@ -1,26 +1,317 @@
 package monitor
 
-import "time"
+import (
+	"errors"
+	"time"
+)
+
+var (
+	ErrServiceNotFound = errors.New("service not found")
+	ErrInvalidService  = errors.New("invalid service")
+)
+
+// Owner selects whose services a request acts on: an organization's when OrgID
+// is set, otherwise UserID's personal services.
+type Owner struct {
+	UserID int
+	OrgID  int
+}
 
 type Service struct {
-	ID            int       `json:"id" db:"id"`
-	Name          string    `json:"name" db:"name"`
-	URL           string    `json:"url" db:"url"`
-	CheckInterval int       `json:"check_interval" db:"check_interval"`
-	NextRunAt     time.Time `json:"next_run_at" db:"next_run_at"`
-	CreatedAt     time.Time `json:"created_at" db:"created_at"`
+	ID             int         `json:"id" db:"id"`
+	UserID         int         `json:"user_id" db:"user_id"`
+	OrganizationID int         `json:"organization_id,omitempty" db:"organization_id"`
+	Name           string      `json:"name" db:"name"`
+	Type           string      `json:"type" db:"type"`
+	URL            string      `json:"url,omitempty" db:"url"`
+	Host           string      `json:"host,omitempty" db:"host"`
+	Port           int         `json:"port,omitempty" db:"port"`
+	Config         CheckConfig `json:"config" db:"config"`
+	Timeout        int         `json:"timeout" db:"timeout"`
+	CheckInterval  int         `json:"check_interval" db:"check_interval"`
+	Tags           []string    `json:"tags" db:"tags"`
+	Paused         bool        `json:"paused" db:"paused"`
+	NextRunAt      time.Time   `json:"next_run_at" db:"next_run_at"`
+	CreatedAt      time.Time   `json:"created_at" db:"created_at"`
+
+	// Status is the confirmed status of the service; it is empty until the
+	// first check.
+	Status Status `json:"status,omitempty" db:"status"`
+	// FailureThreshold and SuccessThreshold are how many consecutive checks must
+	// fail before the service goes DOWN, and succeed before it is UP again.
+	FailureThreshold int `json:"failure_threshold" db:"failure_threshold"`
+	SuccessThreshold int `json:"success_threshold" db:"success_threshold"`
+	// Recheck runs a check again right away while a change of status awaits
+	// confirmation, instead of waiting for the next interval.
+	Recheck bool `json:"recheck" db:"recheck"`
+	// LatencyWarnMs and LatencyCriticalMs are the response times over which a
+	// responding service is DEGRADED and DOWN; 0 disables them.
+	LatencyWarnMs     int `json:"latency_warn_ms" db:"latency_warn_ms"`
+	LatencyCriticalMs int `json:"latency_critical_ms" db:"latency_critical_ms"`
+
+	// HeartbeatToken identifies a heartbeat service in its ping URL.
+	HeartbeatToken string     `json:"heartbeat_token,omitempty" db:"heartbeat_token"`
+	GracePeriod    int        `json:"grace_period,omitempty" db:"grace_period"`
+	LastPingAt     *time.Time `json:"last_ping_at,omitempty" db:"last_ping_at"`
+
+	// EncryptedAuth is the sealed JSON of the service's HTTPAuth; it never leaves the server.
+	EncryptedAuth string `json:"-" db:"auth_encrypted"`
+}
+
+// CheckConfig holds the type-specific settings of a service. Only the block
+// matching the service's check type is read by the worker.
+type CheckConfig struct {
+	HTTP *HTTPCheckConfig `json:"http,omitempty"`
+	TCP  *TCPCheckConfig  `json:"tcp,omitempty"`
+	DNS  *DNSCheckConfig  `json:"dns,omitempty"`
+	TLS  *TLSCheckConfig  `json:"tls,omitempty"`
+	GRPC *GRPCCheckConfig `json:"grpc,omitempty"`
+}
+
+type HTTPCheckConfig struct {
+	Method     string            `json:"method,omitempty" binding:"omitempty,oneof=GET HEAD POST PUT PATCH DELETE OPTIONS" example:"GET"`
+	Headers    map[string]string `json:"headers,omitempty"`
+	Body       string            `json:"body,omitempty" example:"{\"ping\":true}"`
+	Assertions *HTTPAssertions   `json:"assertions,omitempty"`
+}
+
+// HTTPAuth holds credentials sent with HTTP checks. It is only accepted on
+// registration and is stored encrypted.
+type HTTPAuth struct {
+	Type     string `json:"type" binding:"required,oneof=basic bearer" example:"bearer"`
+	Username string `json:"username,omitempty" example:"monitor"`
+	Password string `json:"password,omitempty"`
+	Token    string `json:"token,omitempty"`
+}
+
+// HTTPAssertions are evaluated against every response; a service is UP only
+// when all of them pass.
+type HTTPAssertions struct {
+	// StatusCodes accepts exact codes, classes and ranges such as "200", "2xx" or "200-299". Defaults to 2xx.
+	StatusCodes  []string            `json:"status_codes,omitempty" example:"2xx"`
+	BodyContains []string            `json:"body_contains,omitempty" example:"healthy"`
+	BodyRegex    string              `json:"body_regex,omitempty" example:"version: [0-9]+"`
+	JSONPath     []JSONPathAssertion `json:"json_path,omitempty" binding:"omitempty,dive"`
+	Headers      []HeaderAssertion   `json:"headers,omitempty" binding:"omitempty,dive"`
+	MaxBodyBytes int64               `json:"max_body_bytes,omitempty" binding:"omitempty,min=1" example:"65536"`
+}
+
+type JSONPathAssertion struct {
+	Path string `json:"path" binding:"required" example:"$.status"`
+	// Equals compares the value at Path with any JSON value.
+	Equals interface{} `json:"equals,omitempty"`
+	// Exists defaults to true; set it to false to assert the path is absent.
+	Exists *bool `json:"exists,omitempty"`
+}
+
+type HeaderAssertion struct {
+	Name    string `json:"name" binding:"required" example:"Content-Type"`
+	Matches string `json:"matches,omitempty" example:"^application/json"`
+}
+
+type TCPCheckConfig struct {
+	Send   string `json:"send,omitempty" example:"PING\r\n"`
+	Expect string `json:"expect,omitempty" example:"+PONG"`
+}
+
+type DNSCheckConfig struct {
+	RecordType    string   `json:"record_type,omitempty" binding:"omitempty,oneof=A AAAA CNAME MX TXT" example:"A"`
+	Resolver      string   `json:"resolver,omitempty" example:"1.1.1.1:53"`
+	Expected      []string `json:"expected,omitempty" example:"93.184.216.34"`
+	Match         string   `json:"match,omitempty" binding:"omitempty,oneof=contains exact" example:"contains"`
+	MinCount      int      `json:"min_count,omitempty" binding:"omitempty,min=0" example:"1"`
+	MaxResponseMs int      `json:"max_response_ms,omitempty" binding:"omitempty,min=0" example:"500"`
+}
+
+type TLSCheckConfig struct {
+	ServerName   string `json:"server_name,omitempty" example:"example.com"`
+	WarnDays     int    `json:"warn_days,omitempty" binding:"omitempty,min=1" example:"14"`
+	CriticalDays int    `json:"critical_days,omitempty" binding:"omitempty,min=1" example:"7"`
+}
+
+type GRPCCheckConfig struct {
+	// Service is the name passed to grpc.health.v1.Health/Check; empty asks about the server as a whole.
+	Service    string `json:"service,omitempty" example:"orders.v1.OrderService"`
+	TLS        bool   `json:"tls,omitempty" example:"true"`
+	ServerName string `json:"server_name,omitempty" example:"orders.internal"`
+	SkipVerify bool   `json:"skip_verify,omitempty" example:"false"`
 }
 
 type RegisterServiceDTO struct {
-	Name          string `json:"name" binding:"required" example:"My Service"`
-	URL           string `json:"url" binding:"required,url" example:"https://example.com"`
-	CheckInterval int    `json:"check_interval" binding:"required,min=1" example:"60"`
+	Name          string      `json:"name" binding:"required" example:"My Service"`
+	Type          string      `json:"type" binding:"omitempty,oneof=http tcp dns tls grpc heartbeat" example:"http"`
+	URL           string      `json:"url" binding:"omitempty,url" example:"https://example.com"`
+	Host          string      `json:"host" example:"db.internal"`
+	Port          int         `json:"port" binding:"omitempty,min=1,max=65535" example:"5432"`
+	Config        CheckConfig `json:"config"`
+	Auth          *HTTPAuth   `json:"auth,omitempty"`
+	Timeout       int         `json:"timeout" binding:"omitempty,min=1,max=60" example:"5"`
+	CheckInterval int         `json:"check_interval" binding:"required,min=1" example:"60"`
+	// GracePeriod is how many seconds a heartbeat may be late before the service goes DOWN.
+	GracePeriod int `json:"grace_period" binding:"omitempty,min=0" example:"300"`
+	// Tags label the service for notification policies.
+	Tags []string `json:"tags,omitempty" binding:"omitempty,max=20,dive,min=1,max=64" example:"payments,prod"`
+	// FailureThreshold is how many consecutive checks must fail before the service goes DOWN. Defaults to 1.
+	FailureThreshold int `json:"failure_threshold" binding:"omitempty,min=1,max=10" example:"3"`
+	// SuccessThreshold is how many consecutive checks must succeed before the service is UP again. Defaults to 1.
+	SuccessThreshold int `json:"success_threshold" binding:"omitempty,min=1,max=10" example:"2"`
+	// Recheck confirms a change of status with an immediate re-check by another worker.
+	Recheck bool `json:"recheck" example:"true"`
+	// LatencyWarnMs is the response time in milliseconds over which the service is DEGRADED. 0 disables it.
+	LatencyWarnMs int `json:"latency_warn_ms" binding:"omitempty,min=0" example:"2000"`
+	// LatencyCriticalMs is the response time in milliseconds over which the service is DOWN. 0 disables it.
+	LatencyCriticalMs int `json:"latency_critical_ms" binding:"omitempty,min=0" example:"8000"`
+}
+
+// Validate checks that the target fields required by the service's check type are set.
+func (dto RegisterServiceDTO) Validate() error {
+	if dto.Auth != nil {
+		if dto.Type != "" && dto.Type != CheckTypeHTTP {
+			return errors.New("auth is only supported for http checks")
+		}
+		if err := dto.Auth.Validate(); err != nil {
+			return err
+		}
+	}
+	if dto.GracePeriod > 0 && dto.Type != CheckTypeHeartbeat {
+		return errors.New("grace_period is only supported for heartbeat services")
+	}
+	if dto.Recheck && dto.Type == CheckTypeHeartbeat {
+		return errors.New("recheck is not supported for heartbeat services")
+	}
+	if (dto.LatencyWarnMs > 0 || dto.LatencyCriticalMs > 0) && dto.Type == CheckTypeHeartbeat {
+		return errors.New("latency thresholds are not supported for heartbeat services")
+	}
+	if dto.LatencyWarnMs > 0 && dto.LatencyCriticalMs > 0 && dto.LatencyWarnMs >= dto.LatencyCriticalMs {
+		return errors.New("latency_warn_ms must be below latency_critical_ms")
+	}
+
+	switch dto.Type {
+	case "", CheckTypeHTTP:
+		if dto.URL == "" {
+			return errors.New("url is required for http checks")
+		}
+		if dto.Config.HTTP != nil && dto.Config.HTTP.Assertions != nil {
+			return dto.Config.HTTP.Assertions.Validate()
+		}
+	case CheckTypeTCP:
+		if dto.Host == "" || dto.Port == 0 {
+			return errors.New("host and port are required for tcp checks")
+		}
+	case CheckTypeDNS:
+		if dto.Host == "" {
+			return errors.New("host is required for dns checks")
+		}
+	case CheckTypeTLS:
+		if dto.Host == "" {
+			return errors.New("host is required for tls checks")
+		}
+		if dto.Config.TLS != nil {
+			return dto.Config.TLS.Validate()
+		}
+	case CheckTypeGRPC:
+		if dto.Host == "" || dto.Port == 0 {
+			return errors.New("host and port are required for grpc checks")
+		}
+	}
+	return nil
+}
+
+// UpdateServiceDTO is a partial update: only the fields present in the request
+// are changed. A service's check type cannot be changed.
+type UpdateServiceDTO struct {
+	Name             *string      `json:"name" binding:"omitempty,min=1" example:"My Service"`
+	URL              *string      `json:"url" binding:"omitempty,url" example:"https://example.com"`
+	Host             *string      `json:"host" example:"db.internal"`
+	Port             *int         `json:"port" binding:"omitempty,min=1,max=65535" example:"5432"`
+	Config           *CheckConfig `json:"config"`
+	Auth             *HTTPAuth    `json:"auth,omitempty"`
+	Timeout          *int         `json:"timeout" binding:"omitempty,min=1,max=60" example:"5"`
+	CheckInterval    *int         `json:"check_interval" binding:"omitempty,min=1" example:"60"`
+	GracePeriod      *int         `json:"grace_period" binding:"omitempty,min=0" example:"300"`
+	Tags             *[]string    `json:"tags" binding:"omitempty,max=20,dive,min=1,max=64"`
+	FailureThreshold *int         `json:"failure_threshold" binding:"omitempty,min=1,max=10" example:"3"`
+	SuccessThreshold *int         `json:"success_threshold" binding:"omitempty,min=1,max=10" example:"2"`
+	Recheck          *bool        `json:"recheck" example:"true"`
+
+	LatencyWarnMs     *int `json:"latency_warn_ms" binding:"omitempty,min=0" example:"2000"`
+	LatencyCriticalMs *int `json:"latency_critical_ms" binding:"omitempty,min=0" example:"8000"`
+}
+
+// Apply copies the fields present in the update onto service.
+func (dto UpdateServiceDTO) Apply(service *Service) {
+	if dto.Name != nil {
+		service.Name = *dto.Name
+	}
+	if dto.URL != nil {
+		service.URL = *dto.URL
+	}
+	if dto.Host != nil {
+		service.Host = *dto.Host
+	}
+	if dto.Port != nil {
+		service.Port = *dto.Port
+	}
+	if dto.Config != nil {
+		service.Config = *dto.Config
+	}
+	if dto.Timeout != nil {
+		service.Timeout = *dto.Timeout
+	}
+	if dto.CheckInterval != nil {
+		service.CheckInterval = *dto.CheckInterval
+	}
+	if dto.GracePeriod != nil {
+		service.GracePeriod = *dto.GracePeriod
+	}
+	if dto.Tags != nil {
+		service.Tags = *dto.Tags
+	}
+	if dto.FailureThreshold != nil {
+		service.FailureThreshold = *dto.FailureThreshold
+	}
+	if dto.SuccessThreshold != nil {
+		service.SuccessThreshold = *dto.SuccessThreshold
+	}
+	if dto.Recheck != nil {
+		service.Recheck = *dto.Recheck
+	}
+	if dto.LatencyWarnMs != nil {
+		service.LatencyWarnMs = *dto.LatencyWarnMs
+	}
+	if dto.LatencyCriticalMs != nil {
+		service.LatencyCriticalMs = *dto.LatencyCriticalMs
+	}
+}
+
+func (a HTTPAuth) Validate() error {
+	switch a.Type {
+	case "basic":
+		if a.Username == "" {
+			return errors.New("username is required for basic auth")
+		}
+	case "bearer":
+		if a.Token == "" {
+			return errors.New("token is required for bearer auth")
+		}
+	}
+	return nil
 }
 
 type HealthCheck struct {
-	ID        int       `json:"id" db:"id"`
-	ServiceID int       `json:"service_id" db:"service_id"`
-	Status    string    `json:"status" db:"status"`
-	Latency   int       `json:"latency" db:"latency"`
-	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	ID        int                    `json:"id" db:"id"`
+	ServiceID int                    `json:"service_id" db:"service_id"`
+	Status    Status                 `json:"status" db:"status"`
+	Latency   int                    `json:"latency" db:"latency"`
+	Details   map[string]interface{} `json:"details,omitempty" db:"details"`
+	CreatedAt time.Time              `json:"created_at" db:"created_at"`
+
+	// StatusCode is the HTTP status of the response, if there was one.
+	StatusCode int `json:"status_code,omitempty" db:"status_code" example:"503"`
+	// ErrorClass, Message and BodySnippet explain a check that was not UP.
+	ErrorClass  ErrorClass `json:"error_class,omitempty" db:"error_class" example:"http_status"`
+	Message     string     `json:"message,omitempty" db:"message" example:"unexpected status 503 Service Unavailable, expected 2xx"`
+	BodySnippet string     `json:"body_snippet,omitempty" db:"body_snippet" example:"upstream connect error"`
+	// Timing is only recorded by HTTP checks.
+	Timing *Timing `json:"timing,omitempty" db:"timing"`
 }
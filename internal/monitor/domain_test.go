@@ -0,0 +1,146 @@
+package monitor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegisterServiceDTO_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		dto     RegisterServiceDTO
+		wantErr bool
+	}{
+		{
+			name: "http with url",
+			dto:  RegisterServiceDTO{URL: "https://example.com"},
+		},
+		{
+			name:    "http without url",
+			dto:     RegisterServiceDTO{Type: CheckTypeHTTP},
+			wantErr: true,
+		},
+		{
+			name: "http with valid assertions",
+			dto: RegisterServiceDTO{URL: "https://example.com", Config: CheckConfig{HTTP: &HTTPCheckConfig{
+				Assertions: &HTTPAssertions{StatusCodes: []string{"2xx"}, BodyRegex: "ok"},
+			}}},
+		},
+		{
+			name: "http with invalid body regex",
+			dto: RegisterServiceDTO{URL: "https://example.com", Config: CheckConfig{HTTP: &HTTPCheckConfig{
+				Assertions: &HTTPAssertions{BodyRegex: "("},
+			}}},
+			wantErr: true,
+		},
+		{
+			name: "http with bearer auth",
+			dto:  RegisterServiceDTO{URL: "https://example.com", Auth: &HTTPAuth{Type: "bearer", Token: "t0ken"}},
+		},
+		{
+			name:    "bearer auth without token",
+			dto:     RegisterServiceDTO{URL: "https://example.com", Auth: &HTTPAuth{Type: "bearer"}},
+			wantErr: true,
+		},
+		{
+			name:    "basic auth without username",
+			dto:     RegisterServiceDTO{URL: "https://example.com", Auth: &HTTPAuth{Type: "basic", Password: "pw"}},
+			wantErr: true,
+		},
+		{
+			name:    "auth on a tcp check",
+			dto:     RegisterServiceDTO{Type: CheckTypeTCP, Host: "db.internal", Port: 5432, Auth: &HTTPAuth{Type: "bearer", Token: "t0ken"}},
+			wantErr: true,
+		},
+		{
+			name: "tcp with host and port",
+			dto:  RegisterServiceDTO{Type: CheckTypeTCP, Host: "db.internal", Port: 5432},
+		},
+		{
+			name:    "tcp without port",
+			dto:     RegisterServiceDTO{Type: CheckTypeTCP, Host: "db.internal"},
+			wantErr: true,
+		},
+		{
+			name: "dns with host",
+			dto:  RegisterServiceDTO{Type: CheckTypeDNS, Host: "example.com"},
+		},
+		{
+			name:    "dns without host",
+			dto:     RegisterServiceDTO{Type: CheckTypeDNS},
+			wantErr: true,
+		},
+		{
+			name: "tls with host",
+			dto:  RegisterServiceDTO{Type: CheckTypeTLS, Host: "example.com"},
+		},
+		{
+			name: "tls with thresholds",
+			dto:  RegisterServiceDTO{Type: CheckTypeTLS, Host: "example.com", Config: CheckConfig{TLS: &TLSCheckConfig{WarnDays: 30, CriticalDays: 10}}},
+		},
+		{
+			name:    "tls warning no earlier than critical",
+			dto:     RegisterServiceDTO{Type: CheckTypeTLS, Host: "example.com", Config: CheckConfig{TLS: &TLSCheckConfig{WarnDays: 7, CriticalDays: 7}}},
+			wantErr: true,
+		},
+		{
+			name:    "tls critical above the default warning",
+			dto:     RegisterServiceDTO{Type: CheckTypeTLS, Host: "example.com", Config: CheckConfig{TLS: &TLSCheckConfig{CriticalDays: 20}}},
+			wantErr: true,
+		},
+		{
+			name:    "tls without host",
+			dto:     RegisterServiceDTO{Type: CheckTypeTLS, Port: 443},
+			wantErr: true,
+		},
+		{
+			name: "grpc with host and port",
+			dto:  RegisterServiceDTO{Type: CheckTypeGRPC, Host: "orders.internal", Port: 50051},
+		},
+		{
+			name: "heartbeat needs no target",
+			dto:  RegisterServiceDTO{Type: CheckTypeHeartbeat, GracePeriod: 300},
+		},
+		{
+			name:    "grace period on an http check",
+			dto:     RegisterServiceDTO{URL: "https://example.com", GracePeriod: 300},
+			wantErr: true,
+		},
+		{
+			name:    "recheck on a heartbeat",
+			dto:     RegisterServiceDTO{Type: CheckTypeHeartbeat, Recheck: true},
+			wantErr: true,
+		},
+		{
+			name: "latency thresholds",
+			dto:  RegisterServiceDTO{URL: "https://example.com", LatencyWarnMs: 2000, LatencyCriticalMs: 8000},
+		},
+		{
+			name:    "latency warning over critical",
+			dto:     RegisterServiceDTO{URL: "https://example.com", LatencyWarnMs: 8000, LatencyCriticalMs: 2000},
+			wantErr: true,
+		},
+		{
+			name:    "latency threshold on a heartbeat",
+			dto:     RegisterServiceDTO{Type: CheckTypeHeartbeat, LatencyWarnMs: 2000},
+			wantErr: true,
+		},
+		{
+			name:    "grpc without port",
+			dto:     RegisterServiceDTO{Type: CheckTypeGRPC, Host: "orders.internal"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.dto.Validate()
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
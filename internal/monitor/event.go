@@ -9,9 +9,24 @@ type Event interface {
 
 type StatusChangeEvent struct {
 	ServiceID int
-	OldStatus string
-	NewStatus string
+	UserID    int
+	OrgID     int `json:",omitempty"`
+	OldStatus Status
+	NewStatus Status
+	// Latency is the duration of the check that changed the status, in milliseconds.
+	Latency int
+	// Message is why the check that changed the status failed, if it did.
+	Message   string `json:",omitempty"`
 	Timestamp time.Time
+	// Since is when checks started reporting NewStatus; it is before Timestamp
+	// when the change took several checks to confirm.
+	Since time.Time
+
+	// StatusCode, ErrorClass and BodySnippet detail the failure as on the
+	// health check.
+	StatusCode  int        `json:",omitempty"`
+	ErrorClass  ErrorClass `json:",omitempty"`
+	BodySnippet string     `json:",omitempty"`
 }
 
 func (e StatusChangeEvent) Type() string {
@@ -48,8 +48,8 @@ func TestEventBus_PublishAndSubscribe(t *testing.T) {
 		sce, ok := received.(StatusChangeEvent)
 		assert.True(t, ok)
 		assert.Equal(t, 1, sce.ServiceID)
-		assert.Equal(t, "UP", sce.OldStatus)
-		assert.Equal(t, "DOWN", sce.NewStatus)
+		assert.Equal(t, StatusUp, sce.OldStatus)
+		assert.Equal(t, StatusDown, sce.NewStatus)
 	case <-time.After(1 * time.Second):
 		t.Fatal("Timeout waiting for event")
 	}
@@ -0,0 +1,85 @@
+package monitor
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"net"
+	"strings"
+	"syscall"
+	"unicode/utf8"
+)
+
+// ErrorClass is the kind of failure behind a check that was not UP, so that
+// failures can be told apart without parsing their messages.
+type ErrorClass string
+
+const (
+	ErrorClassDNS               ErrorClass = "dns"
+	ErrorClassConnectionRefused ErrorClass = "connection_refused"
+	// ErrorClassConnection is any other network failure, such as a reset.
+	ErrorClassConnection ErrorClass = "connection"
+	ErrorClassTimeout    ErrorClass = "timeout"
+	ErrorClassTLS        ErrorClass = "tls"
+	// ErrorClassHTTPStatus is a response whose status code was not expected.
+	ErrorClassHTTPStatus ErrorClass = "http_status"
+	ErrorClassAssertion  ErrorClass = "assertion_failed"
+	// ErrorClassUnhealthy is a target that answered that it is not serving.
+	ErrorClassUnhealthy       ErrorClass = "unhealthy"
+	ErrorClassSlowResponse    ErrorClass = "slow_response"
+	ErrorClassMissedHeartbeat ErrorClass = "missed_heartbeat"
+	ErrorClassOther           ErrorClass = "error"
+)
+
+// maxBodySnippetBytes bounds the response body kept with a failed check.
+const maxBodySnippetBytes = 512
+
+// classifyError tells what kind of failure err, returned while probing a
+// target, is.
+func classifyError(err error) ErrorClass {
+	if err == nil {
+		return ""
+	}
+
+	var netErr net.Error
+	if errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout()) {
+		return ErrorClassTimeout
+	}
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		return ErrorClassDNS
+	}
+	if errors.Is(err, syscall.ECONNREFUSED) {
+		return ErrorClassConnectionRefused
+	}
+
+	var (
+		verificationErr *tls.CertificateVerificationError
+		recordErr       tls.RecordHeaderError
+		alertErr        tls.AlertError
+		authorityErr    x509.UnknownAuthorityError
+		hostnameErr     x509.HostnameError
+		invalidErr      x509.CertificateInvalidError
+	)
+	if errors.As(err, &verificationErr) || errors.As(err, &recordErr) || errors.As(err, &alertErr) ||
+		errors.As(err, &authorityErr) || errors.As(err, &hostnameErr) || errors.As(err, &invalidErr) {
+		return ErrorClassTLS
+	}
+
+	var opErr *net.OpError
+	if errors.As(err, &opErr) {
+		return ErrorClassConnection
+	}
+	return ErrorClassOther
+}
+
+// bodySnippet returns the start of a response body as valid UTF-8 without NUL
+// bytes, so that it can be stored as text whatever the body holds.
+func bodySnippet(body []byte) string {
+	if len(body) > maxBodySnippetBytes {
+		body = body[:maxBodySnippetBytes]
+	}
+	snippet := strings.ToValidUTF8(string(body), string(utf8.RuneError))
+	return strings.ReplaceAll(snippet, "\x00", "")
+}
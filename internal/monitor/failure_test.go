@@ -0,0 +1,44 @@
+package monitor
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClassifyError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want ErrorClass
+	}{
+		{"no error", nil, ""},
+		{"deadline", fmt.Errorf("probe: %w", context.DeadlineExceeded), ErrorClassTimeout},
+		{"dns", &net.DNSError{Err: "no such host", Name: "nowhere.invalid", IsNotFound: true}, ErrorClassDNS},
+		{"dns timeout", &net.DNSError{Err: "i/o timeout", Name: "slow.example", IsTimeout: true}, ErrorClassTimeout},
+		{"refused", &net.OpError{Op: "dial", Net: "tcp", Err: os.NewSyscallError("connect", syscall.ECONNREFUSED)}, ErrorClassConnectionRefused},
+		{"reset", &net.OpError{Op: "read", Net: "tcp", Err: os.NewSyscallError("read", syscall.ECONNRESET)}, ErrorClassConnection},
+		{"untrusted certificate", fmt.Errorf("tls: %w", x509.UnknownAuthorityError{}), ErrorClassTLS},
+		{"other", errors.New("unsupported protocol scheme"), ErrorClassOther},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, classifyError(tt.err))
+		})
+	}
+}
+
+func TestBodySnippet(t *testing.T) {
+	assert.Equal(t, "upstream connect error", bodySnippet([]byte("upstream connect error")))
+	assert.Equal(t, strings.Repeat("a", maxBodySnippetBytes), bodySnippet([]byte(strings.Repeat("a", 2*maxBodySnippetBytes))))
+	assert.Equal(t, "ok�", bodySnippet([]byte("o\x00k\xff")))
+}
@@ -1,37 +1,73 @@
 package monitor
 
 import (
+	"errors"
 	"health-checker/internal/middleware"
 	"net/http"
-	"os"
 	"strconv"
-	"strings"
 
 	"github.com/gin-gonic/gin"
-	"github.com/golang-jwt/jwt/v5"
 	"go.uber.org/zap"
 	"golang.org/x/net/websocket"
 )
 
 type Handler struct {
-	service *MonitoringService
-	hub     *WsHub
-	logger  *zap.Logger
+	service  *MonitoringService
+	hub      *WsHub
+	denylist middleware.Denylist
+	logger   *zap.Logger
+	roles    middleware.RoleResolver
+	keys     middleware.APIKeyVerifier
 }
 
-func NewHandler(service *MonitoringService, hub *WsHub, logger *zap.Logger) *Handler {
+func NewHandler(service *MonitoringService, hub *WsHub, denylist middleware.Denylist, logger *zap.Logger) *Handler {
 	return &Handler{
-		service: service,
-		hub:     hub,
-		logger:  logger,
+		service:  service,
+		hub:      hub,
+		denylist: denylist,
+		logger:   logger,
 	}
 }
 
+// SetRoleResolver lets requests act in an organization, authorized by the
+// caller's role in it. Without one only personal services are reachable. It
+// must be called before RegisterRoutes.
+func (h *Handler) SetRoleResolver(roles middleware.RoleResolver) {
+	h.roles = roles
+}
+
+// SetAPIKeyVerifier lets machine clients call the service routes with an API
+// key instead of a JWT. It must be called before RegisterRoutes.
+func (h *Handler) SetAPIKeyVerifier(keys middleware.APIKeyVerifier) {
+	h.keys = keys
+}
+
+// RegisterRoutes registers the service routes. Viewers can read services and
+// their history; changing them requires the editor role.
 func (h *Handler) RegisterRoutes(rg *gin.RouterGroup) {
-	rg.GET("/ws", h.HandleWebSocketGin)
-	rg.POST("", h.RegisterService, middleware.AuthMiddleware())
-	rg.GET("", h.ListServices, middleware.AuthMiddleware())
-	rg.GET("/:serviceId/health-checks", h.GetHealthChecks, middleware.AuthMiddleware())
+	// Live updates are for people: API keys are not accepted on the WebSocket.
+	rg.GET("/ws", middleware.AuthMiddleware(h.denylist, nil), middleware.Authorize(h.roles, middleware.RoleViewer), h.HandleWebSocketGin)
+
+	authenticated := rg.Group("", middleware.AuthMiddleware(h.denylist, h.keys))
+	viewer := authenticated.Group("", middleware.Authorize(h.roles, middleware.RoleViewer))
+	viewer.GET("", h.ListServices)
+	viewer.GET("/:serviceId", h.GetService)
+	viewer.GET("/:serviceId/health-checks", h.GetHealthChecks)
+	viewer.GET("/:serviceId/uptime", h.GetUptime)
+	viewer.GET("/:serviceId/latency", h.GetLatency)
+
+	editor := authenticated.Group("", middleware.Authorize(h.roles, middleware.RoleEditor))
+	editor.POST("", h.RegisterService)
+	editor.PATCH("/:serviceId", h.UpdateService)
+	editor.DELETE("/:serviceId", h.DeleteService)
+	editor.POST("/:serviceId/pause", h.PauseService)
+	editor.POST("/:serviceId/resume", h.ResumeService)
+}
+
+// RegisterHeartbeatRoutes exposes the ping endpoint of heartbeat services. It is
+// unauthenticated: the token in the URL is the credential.
+func (h *Handler) RegisterHeartbeatRoutes(rg *gin.RouterGroup) {
+	rg.POST("/:token", h.Heartbeat)
 }
 
 // RegisterService godoc
@@ -43,8 +79,10 @@ func (h *Handler) RegisterRoutes(rg *gin.RouterGroup) {
 //		@Accept			json
 //		@Produce		json
 //		@Param			service	body		RegisterServiceDTO	true	"Service data"
-//		@Success		200		{object}	map[string]string	"Service registered successfully"
+//		@Param			X-Organization-ID	header		int	false	"Organization to act in"
+//		@Success		200		{object}	Service				"Registered service"
 //		@Failure		400		{object}	map[string]string	"Bad request"
+//		@Failure		403			{object}	map[string]string	"Forbidden"
 //		@Failure		500		{object}	map[string]string	"Internal server error"
 //		@Router			/services [post]
 func (h *Handler) RegisterService(ctx *gin.Context) {
@@ -55,13 +93,25 @@ func (h *Handler) RegisterService(ctx *gin.Context) {
 		return
 	}
 
-	if err := h.service.Register(ctx.Request.Context(), body); err != nil {
+	if err := body.Validate(); err != nil {
+		h.logger.Error("invalid service target", zap.Error(err))
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	owner, ok := h.owner(ctx)
+	if !ok {
+		return
+	}
+
+	service, err := h.service.Register(ctx.Request.Context(), owner, body)
+	if err != nil {
 		h.logger.Error("failed to register service", zap.Error(err))
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	ctx.JSON(http.StatusOK, gin.H{"message": "Service registered successfully"})
+	ctx.JSON(http.StatusOK, service)
 }
 
 // ListServices godoc
@@ -71,11 +121,18 @@ func (h *Handler) RegisterService(ctx *gin.Context) {
 //		@Description	Retrieve a list of all services registered for health monitoring
 //		@Tags			services
 //		@Produce		json
+//		@Param			X-Organization-ID	header		int	false	"Organization to act in"
 //		@Success		200		{array}		Service	"List of registered services"
+//		@Failure		403			{object}	map[string]string	"Forbidden"
 //		@Failure		500		{object}	map[string]string	"Internal server error"
 //		@Router			/services [get]
 func (h *Handler) ListServices(ctx *gin.Context) {
-	services, err := h.service.ListServices(ctx.Request.Context())
+	owner, ok := h.owner(ctx)
+	if !ok {
+		return
+	}
+
+	services, err := h.service.ListServices(ctx.Request.Context(), owner)
 	if err != nil {
 		h.logger.Error("failed to list services", zap.Error(err))
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
@@ -85,6 +142,219 @@ func (h *Handler) ListServices(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, services)
 }
 
+// GetService godoc
+//
+//	 @Security BearerAuth
+//		@Summary		Get a service
+//		@Description	Retrieve a single registered service by its ID
+//		@Tags			services
+//		@Produce		json
+//		@Param			serviceId	path		int					true	"Service ID"
+//		@Param			X-Organization-ID	header		int	false	"Organization to act in"
+//		@Success		200			{object}	Service				"Service"
+//		@Failure		400			{object}	map[string]string	"Bad request"
+//		@Failure		403			{object}	map[string]string	"Forbidden"
+//		@Failure		404			{object}	map[string]string	"Service not found"
+//		@Failure		500			{object}	map[string]string	"Internal server error"
+//		@Router			/services/{serviceId} [get]
+func (h *Handler) GetService(ctx *gin.Context) {
+	owner, ok := h.owner(ctx)
+	if !ok {
+		return
+	}
+	serviceID, ok := h.serviceID(ctx)
+	if !ok {
+		return
+	}
+
+	service, err := h.service.GetService(ctx.Request.Context(), owner, serviceID)
+	if err != nil {
+		h.respondServiceError(ctx, "failed to get service", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, service)
+}
+
+// UpdateService godoc
+//
+//	 @Security BearerAuth
+//		@Summary		Update a service
+//		@Description	Change the fields present in the request body; the check type cannot be changed. Changing check_interval or grace_period reschedules the next check, or a heartbeat's deadline from its last ping
+//		@Tags			services
+//		@Accept			json
+//		@Produce		json
+//		@Param			serviceId	path		int					true	"Service ID"
+//		@Param			service		body		UpdateServiceDTO	true	"Fields to change"
+//		@Param			X-Organization-ID	header		int	false	"Organization to act in"
+//		@Success		200			{object}	Service				"Updated service"
+//		@Failure		400			{object}	map[string]string	"Bad request"
+//		@Failure		403			{object}	map[string]string	"Forbidden"
+//		@Failure		404			{object}	map[string]string	"Service not found"
+//		@Failure		500			{object}	map[string]string	"Internal server error"
+//		@Router			/services/{serviceId} [patch]
+func (h *Handler) UpdateService(ctx *gin.Context) {
+	owner, ok := h.owner(ctx)
+	if !ok {
+		return
+	}
+	serviceID, ok := h.serviceID(ctx)
+	if !ok {
+		return
+	}
+
+	var body UpdateServiceDTO
+	if err := ctx.ShouldBindJSON(&body); err != nil {
+		h.logger.Error("failed to bind json", zap.Error(err))
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	service, err := h.service.UpdateService(ctx.Request.Context(), owner, serviceID, body)
+	if err != nil {
+		h.respondServiceError(ctx, "failed to update service", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, service)
+}
+
+// DeleteService godoc
+//
+//	 @Security BearerAuth
+//		@Summary		Delete a service
+//		@Description	Stop monitoring a service and delete its health check history
+//		@Tags			services
+//		@Param			serviceId	path		int					true	"Service ID"
+//		@Param			X-Organization-ID	header		int	false	"Organization to act in"
+//		@Success		204			"Service deleted"
+//		@Failure		400			{object}	map[string]string	"Bad request"
+//		@Failure		403			{object}	map[string]string	"Forbidden"
+//		@Failure		404			{object}	map[string]string	"Service not found"
+//		@Failure		500			{object}	map[string]string	"Internal server error"
+//		@Router			/services/{serviceId} [delete]
+func (h *Handler) DeleteService(ctx *gin.Context) {
+	owner, ok := h.owner(ctx)
+	if !ok {
+		return
+	}
+	serviceID, ok := h.serviceID(ctx)
+	if !ok {
+		return
+	}
+
+	if err := h.service.DeleteService(ctx.Request.Context(), owner, serviceID); err != nil {
+		h.respondServiceError(ctx, "failed to delete service", err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// PauseService godoc
+//
+//	 @Security BearerAuth
+//		@Summary		Pause a service
+//		@Description	Stop scheduling checks for a service until it is resumed
+//		@Tags			services
+//		@Produce		json
+//		@Param			serviceId	path		int					true	"Service ID"
+//		@Param			X-Organization-ID	header		int	false	"Organization to act in"
+//		@Success		200			{object}	Service				"Paused service"
+//		@Failure		400			{object}	map[string]string	"Bad request"
+//		@Failure		403			{object}	map[string]string	"Forbidden"
+//		@Failure		404			{object}	map[string]string	"Service not found"
+//		@Failure		500			{object}	map[string]string	"Internal server error"
+//		@Router			/services/{serviceId}/pause [post]
+func (h *Handler) PauseService(ctx *gin.Context) {
+	owner, ok := h.owner(ctx)
+	if !ok {
+		return
+	}
+	serviceID, ok := h.serviceID(ctx)
+	if !ok {
+		return
+	}
+
+	service, err := h.service.PauseService(ctx.Request.Context(), owner, serviceID)
+	if err != nil {
+		h.respondServiceError(ctx, "failed to pause service", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, service)
+}
+
+// ResumeService godoc
+//
+//	 @Security BearerAuth
+//		@Summary		Resume a service
+//		@Description	Resume checks for a paused service; it is checked on the next scheduler tick
+//		@Tags			services
+//		@Produce		json
+//		@Param			serviceId	path		int					true	"Service ID"
+//		@Param			X-Organization-ID	header		int	false	"Organization to act in"
+//		@Success		200			{object}	Service				"Resumed service"
+//		@Failure		400			{object}	map[string]string	"Bad request"
+//		@Failure		403			{object}	map[string]string	"Forbidden"
+//		@Failure		404			{object}	map[string]string	"Service not found"
+//		@Failure		500			{object}	map[string]string	"Internal server error"
+//		@Router			/services/{serviceId}/resume [post]
+func (h *Handler) ResumeService(ctx *gin.Context) {
+	owner, ok := h.owner(ctx)
+	if !ok {
+		return
+	}
+	serviceID, ok := h.serviceID(ctx)
+	if !ok {
+		return
+	}
+
+	service, err := h.service.ResumeService(ctx.Request.Context(), owner, serviceID)
+	if err != nil {
+		h.respondServiceError(ctx, "failed to resume service", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, service)
+}
+
+// owner returns the authenticated user and the organization selected by
+// middleware.Authorize, responding 401 when the request carries no user.
+func (h *Handler) owner(ctx *gin.Context) (Owner, bool) {
+	userID, ok := middleware.UserID(ctx.Request.Context())
+	if !ok {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return Owner{}, false
+	}
+	orgID, _ := middleware.OrgID(ctx.Request.Context())
+	return Owner{UserID: userID, OrgID: orgID}, true
+}
+
+// serviceID parses the serviceId path parameter, responding 400 when it is not an integer.
+func (h *Handler) serviceID(ctx *gin.Context) (int, bool) {
+	serviceID, err := strconv.Atoi(ctx.Param("serviceId"))
+	if err != nil {
+		h.logger.Error("serviceId must be an integer", zap.Error(err))
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "serviceId must be an integer"})
+		return 0, false
+	}
+	return serviceID, true
+}
+
+// respondServiceError maps service errors onto HTTP status codes.
+func (h *Handler) respondServiceError(ctx *gin.Context, msg string, err error) {
+	switch {
+	case errors.Is(err, ErrServiceNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, ErrInvalidService), errors.Is(err, ErrInvalidWindow):
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		h.logger.Error(msg, zap.Error(err))
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
 // GetHealthChecks godoc
 //
 //	 @Security BearerAuth
@@ -95,11 +365,19 @@ func (h *Handler) ListServices(ctx *gin.Context) {
 //		@Param			serviceId	path		int	true	"Service ID"
 //		@Param			page		query		int	false	"Page number"	default(1)
 //		@Param			limit		query		int	false	"Number of records per page"	default(10)
+//		@Param			X-Organization-ID	header		int	false	"Organization to act in"
 //		@Success		200			{array}		HealthCheck	"List of health checks"
 //		@Failure		400			{object}	map[string]string	"Bad request"
+//		@Failure		403			{object}	map[string]string	"Forbidden"
+//		@Failure		404			{object}	map[string]string	"Service not found"
 //		@Failure		500			{object}	map[string]string	"Internal server error"
 //		@Router			/services/{serviceId}/health-checks [get]
 func (h *Handler) GetHealthChecks(ctx *gin.Context) {
+	owner, ok := h.owner(ctx)
+	if !ok {
+		return
+	}
+
 	serviceID, ok := ctx.Params.Get("serviceId")
 	if !ok {
 		h.logger.Error("invalid serviceId")
@@ -136,36 +414,132 @@ func (h *Handler) GetHealthChecks(ctx *gin.Context) {
 		return
 	}
 
-	checks, err := h.service.GetHealthChecksByServiceID(ctx.Request.Context(), serviceIDInt, pageInt, limitInt)
+	checks, err := h.service.GetHealthChecksByServiceID(ctx.Request.Context(), owner, serviceIDInt, pageInt, limitInt)
 	if err != nil {
-		h.logger.Error("failed to get health checks", zap.Error(err))
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		h.respondServiceError(ctx, "failed to get health checks", err)
 		return
 	}
 
 	ctx.JSON(http.StatusOK, checks)
 }
 
-func (h *Handler) HandleWebSocketGin(c *gin.Context) {
-	authHeader := c.GetHeader("Authorization")
-	token := strings.TrimPrefix(authHeader, "Bearer ")
+// GetUptime godoc
+//
+//	 @Security BearerAuth
+//		@Summary		Get the uptime of a service
+//		@Description	Compute the uptime percentage of a service from its health checks, and its incidents, MTTR and MTBF from its incident log, over the last 24h, 7d or 30d or between from and to
+//		@Tags			services
+//		@Produce		json
+//		@Param			serviceId	path		int		true	"Service ID"
+//		@Param			window		query		string	false	"24h, 7d or 30d, ending now"	default(24h)
+//		@Param			from		query		string	false	"Start of a custom window, RFC 3339"
+//		@Param			to			query		string	false	"End of a custom window, RFC 3339; defaults to now"
+//		@Param			X-Organization-ID	header		int	false	"Organization to act in"
+//		@Success		200			{object}	UptimeReport		"Uptime report"
+//		@Failure		400			{object}	map[string]string	"Bad request"
+//		@Failure		403			{object}	map[string]string	"Forbidden"
+//		@Failure		404			{object}	map[string]string	"Service not found"
+//		@Failure		500			{object}	map[string]string	"Internal server error"
+//		@Router			/services/{serviceId}/uptime [get]
+func (h *Handler) GetUptime(ctx *gin.Context) {
+	owner, ok := h.owner(ctx)
+	if !ok {
+		return
+	}
+	serviceID, ok := h.serviceID(ctx)
+	if !ok {
+		return
+	}
+
+	var window ReportWindow
+	if err := ctx.ShouldBindQuery(&window); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
 
-	if token == "" {
-		h.logger.Error("Unauthorized - no token")
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized - no token"})
+	report, err := h.service.GetUptime(ctx.Request.Context(), owner, serviceID, window)
+	if err != nil {
+		h.respondServiceError(ctx, "failed to get uptime", err)
 		return
 	}
 
-	parsedToken, err := jwt.Parse(token, func(t *jwt.Token) (interface{}, error) {
-		return []byte(os.Getenv("JWT_SECRET")), nil
-	})
+	ctx.JSON(http.StatusOK, report)
+}
 
-	if err != nil || !parsedToken.Valid {
-		h.logger.Error("Invalid token", zap.Error(err))
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token", "details": err.Error()})
+// GetLatency godoc
+//
+//	 @Security BearerAuth
+//		@Summary		Get the latency percentiles of a service
+//		@Description	Compute the min, max, mean, p50, p90, p95 and p99 latency of a service over the last 24h, 7d or 30d or between from and to, overall and in time buckets for charting
+//		@Tags			services
+//		@Produce		json
+//		@Param			serviceId	path		int		true	"Service ID"
+//		@Param			window		query		string	false	"24h, 7d or 30d, ending now"	default(24h)
+//		@Param			from		query		string	false	"Start of a custom window, RFC 3339"
+//		@Param			to			query		string	false	"End of a custom window, RFC 3339; defaults to now"
+//		@Param			bucket		query		string	false	"1m, 5m, 15m, 1h, 6h or 1d; defaults to the smallest giving at most 300 buckets"
+//		@Param			X-Organization-ID	header		int	false	"Organization to act in"
+//		@Success		200			{object}	LatencyReport		"Latency report"
+//		@Failure		400			{object}	map[string]string	"Bad request, or a bucket under 1h reaching past the retention period"
+//		@Failure		403			{object}	map[string]string	"Forbidden"
+//		@Failure		404			{object}	map[string]string	"Service not found"
+//		@Failure		500			{object}	map[string]string	"Internal server error"
+//		@Router			/services/{serviceId}/latency [get]
+func (h *Handler) GetLatency(ctx *gin.Context) {
+	owner, ok := h.owner(ctx)
+	if !ok {
 		return
 	}
+	serviceID, ok := h.serviceID(ctx)
+	if !ok {
+		return
+	}
+
+	var query LatencyQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	report, err := h.service.GetLatency(ctx.Request.Context(), owner, serviceID, query)
+	if err != nil {
+		h.respondServiceError(ctx, "failed to get latency", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, report)
+}
 
+// Heartbeat godoc
+//
+//	@Summary		Ping a heartbeat service
+//	@Description	Record a heartbeat for the service owning the token. A heartbeat service goes DOWN when no ping arrives within its check interval plus grace period.
+//	@Tags			heartbeats
+//	@Produce		json
+//	@Param			token	path		string				true	"Heartbeat token"
+//	@Success		200		{object}	map[string]string	"Heartbeat received"
+//	@Failure		404		{object}	map[string]string	"Unknown heartbeat token"
+//	@Failure		500		{object}	map[string]string	"Internal server error"
+//	@Router			/heartbeats/{token} [post]
+func (h *Handler) Heartbeat(ctx *gin.Context) {
+	err := h.service.RecordHeartbeat(ctx.Request.Context(), ctx.Param("token"))
+	if errors.Is(err, ErrServiceNotFound) {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "unknown heartbeat token"})
+		return
+	}
+	if err != nil {
+		h.logger.Error("failed to record heartbeat", zap.Error(err))
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "Heartbeat received"})
+}
+
+// HandleWebSocketGin upgrades the connection once the route's middleware has
+// authenticated the caller and, for an organization's services (?org_id=),
+// checked they are at least a viewer.
+func (h *Handler) HandleWebSocketGin(c *gin.Context) {
 	websocket.Handler(h.HandleWebSocket).ServeHTTP(c.Writer, c.Request)
 }
 
@@ -173,13 +547,20 @@ func (h *Handler) HandleWebSocketGin(c *gin.Context) {
 // @Security BearerAuth
 //
 //	 @Summary		Handle WebSocket connections for real-time updates
-//		@Description	Establish a WebSocket connection to receive real-time status updates
+//		@Description	Establish a WebSocket connection to receive real-time status updates of your services, or of an organization's services when org_id is given
 //		@Tags			services
+//		@Param			org_id	query		int		false	"Organization to watch"
 //		@Success		101	{string}	string	"Switching Protocols"
 //		@Failure		400	{object}	map[string]string	"Bad request"
+//		@Failure		401	{object}	map[string]string	"Unauthorized"
+//		@Failure		403	{object}	map[string]string	"Forbidden"
 //		@Router			/services/ws [get]
 func (h *Handler) HandleWebSocket(conn *websocket.Conn) {
 	client := NewWsClient(conn, h.hub, h.logger)
+	// Clients only receive events for the personal services of their user, or
+	// for the services of the organization they connected to.
+	client.userID, _ = middleware.UserID(conn.Request().Context())
+	client.orgID, _ = middleware.OrgID(conn.Request().Context())
 	h.hub.register <- client
 
 	go client.WritePump()
@@ -1,19 +1,26 @@
 package monitor
 
 import (
+	"context"
+	"health-checker/internal/middleware"
+	"health-checker/internal/middleware/middlewaretest"
 	"net/http"
 	"net/http/httptest"
+	"os"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"go.uber.org/zap"
 )
 
 func TestHandler_RegisterRoutes(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
-	handler := &Handler{}
+	handler := &Handler{denylist: middlewaretest.Denylist{}}
 	router := gin.New()
 	rg := router.Group("/api/v1/services")
 
@@ -27,34 +34,169 @@ func TestHandler_RegisterRoutes(t *testing.T) {
 	assert.NotEmpty(t, routes)
 }
 
-func TestHandleWebSocketGin_NoToken(t *testing.T) {
-	// Create minimal handler for testing
-	service := &MonitoringService{}
-	hub := NewWsHub(zap.NewNop())
-	handler := NewHandler(service, hub, zap.NewNop())
+func TestHandler_RegisterRoutes_RequireAuth(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockRepo := new(MockRepository)
+	handler := NewHandler(NewService(mockRepo, zap.NewNop()), NewWsHub(zap.NewNop()), middlewaretest.Denylist{}, zap.NewNop())
+	router := gin.New()
+	handler.RegisterRoutes(router.Group("/api/v1/services"))
+
+	for _, route := range []struct{ method, path string }{
+		{"POST", "/api/v1/services"},
+		{"GET", "/api/v1/services"},
+		{"GET", "/api/v1/services/1"},
+		{"PATCH", "/api/v1/services/1"},
+		{"DELETE", "/api/v1/services/1"},
+		{"POST", "/api/v1/services/1/pause"},
+		{"POST", "/api/v1/services/1/resume"},
+		{"GET", "/api/v1/services/1/health-checks"},
+		{"GET", "/api/v1/services/1/uptime"},
+		{"GET", "/api/v1/services/1/latency"},
+		{"GET", "/api/v1/services/ws"},
+	} {
+		req := httptest.NewRequest(route.method, route.path, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code, "%s %s", route.method, route.path)
+	}
+	mockRepo.AssertNotCalled(t, "DeleteService")
+}
+
+type stubRoles map[int]middleware.Role
+
+func (s stubRoles) MemberRole(ctx context.Context, orgID, userID int) (middleware.Role, error) {
+	return s[userID], nil
+}
+
+func TestHandler_RegisterRoutes_Roles(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	os.Setenv("JWT_SECRET", "test-secret")
+	defer os.Unsetenv("JWT_SECRET")
+
+	const viewerID, editorID, outsiderID = 2, 3, 4
+	orgOwner := func(userID int) Owner { return Owner{UserID: userID, OrgID: 7} }
+
+	mockRepo := new(MockRepository)
+	mockRepo.On("ListServices", mock.Anything, orgOwner(viewerID)).Return([]Service{{ID: 1, OrganizationID: 7}}, nil)
+	mockRepo.On("DeleteService", mock.Anything, orgOwner(editorID), 1).Return(nil)
+
+	handler := NewHandler(NewService(mockRepo, zap.NewNop()), NewWsHub(zap.NewNop()), middlewaretest.Denylist{}, zap.NewNop())
+	handler.SetRoleResolver(stubRoles{viewerID: middleware.RoleViewer, editorID: middleware.RoleEditor})
+	router := gin.New()
+	handler.RegisterRoutes(router.Group("/api/v1/services"))
+
+	request := func(method, path string, userID int) *httptest.ResponseRecorder {
+		token, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+			"user_id": userID,
+			"exp":     time.Now().Add(time.Hour).Unix(),
+		}).SignedString([]byte("test-secret"))
+
+		req := httptest.NewRequest(method, path, nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		req.Header.Set(middleware.OrganizationHeader, "7")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	assert.Equal(t, http.StatusOK, request("GET", "/api/v1/services", viewerID).Code)
+	assert.Equal(t, http.StatusForbidden, request("DELETE", "/api/v1/services/1", viewerID).Code)
+	assert.Equal(t, http.StatusNoContent, request("DELETE", "/api/v1/services/1", editorID).Code)
+	assert.Equal(t, http.StatusForbidden, request("GET", "/api/v1/services", outsiderID).Code)
+	assert.Equal(t, http.StatusForbidden, request("GET", "/api/v1/services/ws?org_id=8", outsiderID).Code)
+	mockRepo.AssertExpectations(t)
+}
+
+type stubKeys map[string]middleware.APIKeyPrincipal
+
+func (s stubKeys) VerifyAPIKey(ctx context.Context, key string) (middleware.APIKeyPrincipal, error) {
+	principal, ok := s[key]
+	if !ok {
+		return middleware.APIKeyPrincipal{}, middleware.ErrInvalidAPIKey
+	}
+	return principal, nil
+}
+
+func TestHandler_RegisterRoutes_APIKey(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockRepo := new(MockRepository)
+	mockRepo.On("ListServices", mock.Anything, Owner{UserID: 2}).Return([]Service{{ID: 1}}, nil)
+	mockRepo.On("DeleteService", mock.Anything, Owner{UserID: 2, OrgID: 7}, 1).Return(nil)
+
+	handler := NewHandler(NewService(mockRepo, zap.NewNop()), NewWsHub(zap.NewNop()), middlewaretest.Denylist{}, zap.NewNop())
+	handler.SetRoleResolver(stubRoles{2: middleware.RoleAdmin})
+	handler.SetAPIKeyVerifier(stubKeys{
+		"hc_read":  {KeyID: 1, UserID: 2, Scopes: []string{middleware.ScopeServicesRead}},
+		"hc_write": {KeyID: 2, UserID: 2, OrgID: 7, Scopes: []string{middleware.ScopeServicesWrite}},
+	})
+	router := gin.New()
+	handler.RegisterRoutes(router.Group("/api/v1/services"))
+
+	request := func(method, path, key string) int {
+		req := httptest.NewRequest(method, path, nil)
+		req.Header.Set("Authorization", "Bearer "+key)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	assert.Equal(t, http.StatusOK, request("GET", "/api/v1/services", "hc_read"))
+	assert.Equal(t, http.StatusForbidden, request("DELETE", "/api/v1/services/1", "hc_read"))
+	assert.Equal(t, http.StatusNoContent, request("DELETE", "/api/v1/services/1", "hc_write"))
+	assert.Equal(t, http.StatusUnauthorized, request("GET", "/api/v1/services", "hc_unknown"))
+	mockRepo.AssertExpectations(t)
+}
+
+// webSocketRequest asks the registered routes for a WebSocket upgrade with the
+// given Authorization header.
+func webSocketRequest(handler *Handler, authorization string) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	handler.RegisterRoutes(router.Group("/api/v1/services"))
 
+	req := httptest.NewRequest("GET", "/api/v1/services/ws", nil)
+	if authorization != "" {
+		req.Header.Set("Authorization", authorization)
+	}
 	w := httptest.NewRecorder()
-	c, _ := gin.CreateTestContext(w)
-	c.Request, _ = http.NewRequest("GET", "/ws", nil)
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestHandleWebSocketGin_NoToken(t *testing.T) {
+	handler := NewHandler(&MonitoringService{}, NewWsHub(zap.NewNop()), middlewaretest.Denylist{}, zap.NewNop())
 
-	handler.HandleWebSocketGin(c)
+	w := webSocketRequest(handler, "")
 
 	assert.Equal(t, http.StatusUnauthorized, w.Code)
 }
 
 func TestHandleWebSocketGin_InvalidToken(t *testing.T) {
-	service := &MonitoringService{}
-	hub := NewWsHub(zap.NewNop())
-	handler := NewHandler(service, hub, zap.NewNop())
+	handler := NewHandler(&MonitoringService{}, NewWsHub(zap.NewNop()), middlewaretest.Denylist{}, zap.NewNop())
 
-	w := httptest.NewRecorder()
-	c, _ := gin.CreateTestContext(w)
-	c.Request, _ = http.NewRequest("GET", "/ws", nil)
-	c.Request.Header.Set("Authorization", "Bearer invalid-token")
+	w := webSocketRequest(handler, "Bearer invalid-token")
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestHandleWebSocketGin_RevokedToken(t *testing.T) {
+	os.Setenv("JWT_SECRET", "test-secret")
+	defer os.Unsetenv("JWT_SECRET")
+
+	handler := NewHandler(&MonitoringService{}, NewWsHub(zap.NewNop()), middlewaretest.Revoked("logged-out"), zap.NewNop())
+	token, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"user_id": 1,
+		"jti":     "logged-out",
+		"exp":     time.Now().Add(time.Hour).Unix(),
+	}).SignedString([]byte("test-secret"))
 
-	handler.HandleWebSocketGin(c)
+	w := webSocketRequest(handler, "Bearer "+token)
 
 	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Contains(t, w.Body.String(), "Token revoked")
 }
 
 func TestHandleWebSocket(t *testing.T) {
@@ -62,7 +204,7 @@ func TestHandleWebSocket(t *testing.T) {
 	// For unit testing, we can verify the function exists
 	service := &MonitoringService{}
 	hub := NewWsHub(zap.NewNop())
-	handler := NewHandler(service, hub, zap.NewNop())
+	handler := NewHandler(service, hub, middlewaretest.Denylist{}, zap.NewNop())
 	assert.NotNil(t, handler)
 	assert.NotNil(t, handler.HandleWebSocket)
 }
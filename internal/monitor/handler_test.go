@@ -5,9 +5,12 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"health-checker/internal/middleware"
+	"health-checker/internal/middleware/middlewaretest"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
@@ -19,13 +22,33 @@ type MockRepository struct {
 	mock.Mock
 }
 
-func (m *MockRepository) Create(ctx context.Context, service Service) error {
+func (m *MockRepository) Create(ctx context.Context, service Service) (Service, error) {
 	args := m.Called(ctx, service)
+	return args.Get(0).(Service), args.Error(1)
+}
+
+func (m *MockRepository) GetService(ctx context.Context, owner Owner, id int) (Service, error) {
+	args := m.Called(ctx, owner, id)
+	return args.Get(0).(Service), args.Error(1)
+}
+
+func (m *MockRepository) UpdateService(ctx context.Context, owner Owner, service Service) (Service, error) {
+	args := m.Called(ctx, owner, service)
+	return args.Get(0).(Service), args.Error(1)
+}
+
+func (m *MockRepository) DeleteService(ctx context.Context, owner Owner, id int) error {
+	args := m.Called(ctx, owner, id)
 	return args.Error(0)
 }
 
-func (m *MockRepository) ListServices(ctx context.Context) ([]Service, error) {
-	args := m.Called(ctx)
+func (m *MockRepository) SetPaused(ctx context.Context, owner Owner, id int, paused bool) (Service, error) {
+	args := m.Called(ctx, owner, id, paused)
+	return args.Get(0).(Service), args.Error(1)
+}
+
+func (m *MockRepository) ListServices(ctx context.Context, owner Owner) ([]Service, error) {
+	args := m.Called(ctx, owner)
 	return args.Get(0).([]Service), args.Error(1)
 }
 
@@ -52,9 +75,74 @@ func (m *MockRepository) GetLatestHealthCheck(ctx context.Context, serviceID int
 	return args.Get(0).(*HealthCheck), args.Error(1)
 }
 
+func (m *MockRepository) GetStatusState(ctx context.Context, serviceID int) (StatusState, error) {
+	args := m.Called(ctx, serviceID)
+	return args.Get(0).(StatusState), args.Error(1)
+}
+
+func (m *MockRepository) SaveStatusState(ctx context.Context, serviceID int, state StatusState) error {
+	args := m.Called(ctx, serviceID, state)
+	return args.Error(0)
+}
+
+func (m *MockRepository) RecordHeartbeat(ctx context.Context, token string) (Service, error) {
+	args := m.Called(ctx, token)
+	return args.Get(0).(Service), args.Error(1)
+}
+
+func (m *MockRepository) HeartbeatOverdue(ctx context.Context, serviceID int) (bool, error) {
+	args := m.Called(ctx, serviceID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockRepository) GetLatencyStats(ctx context.Context, serviceID int, from, to time.Time, bucket time.Duration) (LatencyStats, []LatencyBucket, error) {
+	args := m.Called(ctx, serviceID, from, to, bucket)
+	return args.Get(0).(LatencyStats), args.Get(1).([]LatencyBucket), args.Error(2)
+}
+
+func (m *MockRepository) NextRollupStart(ctx context.Context, resolution RollupResolution) (time.Time, error) {
+	args := m.Called(ctx, resolution)
+	return args.Get(0).(time.Time), args.Error(1)
+}
+
+func (m *MockRepository) RollupHealthChecks(ctx context.Context, resolution RollupResolution, from, to time.Time) (int64, error) {
+	args := m.Called(ctx, resolution, from, to)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockRepository) GetRollups(ctx context.Context, serviceID int, resolution RollupResolution, from, to time.Time) ([]Rollup, error) {
+	args := m.Called(ctx, serviceID, resolution, from, to)
+	return args.Get(0).([]Rollup), args.Error(1)
+}
+
+func (m *MockRepository) DeleteHealthChecksBefore(ctx context.Context, before time.Time, limit int) (int64, error) {
+	args := m.Called(ctx, before, limit)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockRepository) GetStatusPeriods(ctx context.Context, serviceID int, from, to time.Time) ([]StatusPeriod, error) {
+	args := m.Called(ctx, serviceID, from, to)
+	return args.Get(0).([]StatusPeriod), args.Error(1)
+}
+
+func (m *MockRepository) GetIncidentSpans(ctx context.Context, serviceID int, from, to time.Time) ([]IncidentSpan, error) {
+	args := m.Called(ctx, serviceID, from, to)
+	return args.Get(0).([]IncidentSpan), args.Error(1)
+}
+
+// testUserID is the authenticated user of requests served by setupRouter.
+const testUserID = 1
+
+// testOwner is the personal scope of testUserID.
+var testOwner = Owner{UserID: testUserID}
+
 func setupRouter() *gin.Engine {
 	gin.SetMode(gin.TestMode)
-	return gin.Default()
+	r := gin.Default()
+	r.Use(func(c *gin.Context) {
+		c.Request = c.Request.WithContext(middleware.ContextWithUserID(c.Request.Context(), testUserID))
+	})
+	return r
 }
 
 func TestRegisterService(t *testing.T) {
@@ -62,9 +150,10 @@ func TestRegisterService(t *testing.T) {
 		mockRepo := new(MockRepository)
 		service := NewService(mockRepo, zap.L())
 		hub := NewWsHub(zap.L())
-		handler := NewHandler(service, hub, zap.NewNop())
+		handler := NewHandler(service, hub, middlewaretest.Denylist{}, zap.NewNop())
 
-		mockRepo.On("Create", mock.Anything, mock.AnythingOfType("monitor.Service")).Return(nil)
+		mockRepo.On("Create", mock.Anything, mock.AnythingOfType("monitor.Service")).
+			Return(Service{ID: 1, Name: "Test Service", URL: "http://example.com", CheckInterval: 60}, nil)
 
 		r := setupRouter()
 		r.POST("/services", handler.RegisterService)
@@ -80,6 +169,10 @@ func TestRegisterService(t *testing.T) {
 		r.ServeHTTP(w, req)
 
 		assert.Equal(t, http.StatusOK, w.Code)
+		var response Service
+		json.Unmarshal(w.Body.Bytes(), &response)
+		assert.Equal(t, 1, response.ID)
+		assert.Equal(t, "Test Service", response.Name)
 		mockRepo.AssertExpectations(t)
 	})
 
@@ -87,7 +180,7 @@ func TestRegisterService(t *testing.T) {
 		mockRepo := new(MockRepository)
 		service := NewService(mockRepo, zap.L())
 		hub := NewWsHub(zap.L())
-		handler := NewHandler(service, hub, zap.NewNop())
+		handler := NewHandler(service, hub, middlewaretest.Denylist{}, zap.NewNop())
 
 		r := setupRouter()
 		r.POST("/services", handler.RegisterService)
@@ -103,9 +196,9 @@ func TestRegisterService(t *testing.T) {
 		mockRepo := new(MockRepository)
 		service := NewService(mockRepo, zap.L())
 		hub := NewWsHub(zap.L())
-		handler := NewHandler(service, hub, zap.NewNop())
+		handler := NewHandler(service, hub, middlewaretest.Denylist{}, zap.NewNop())
 
-		mockRepo.On("Create", mock.Anything, mock.AnythingOfType("monitor.Service")).Return(errors.New("db error"))
+		mockRepo.On("Create", mock.Anything, mock.AnythingOfType("monitor.Service")).Return(Service{}, errors.New("db error"))
 
 		r := setupRouter()
 		r.POST("/services", handler.RegisterService)
@@ -125,18 +218,76 @@ func TestRegisterService(t *testing.T) {
 	})
 }
 
+func TestRegisterService_TCP(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		service := NewService(mockRepo, zap.L())
+		handler := NewHandler(service, NewWsHub(zap.L()), middlewaretest.Denylist{}, zap.NewNop())
+
+		mockRepo.On("Create", mock.Anything, mock.MatchedBy(func(s Service) bool {
+			return s.Type == CheckTypeTCP && s.Host == "db.internal" && s.Port == 5432 &&
+				s.Config.TCP != nil && s.Config.TCP.Expect == "+PONG"
+		})).Return(Service{ID: 1}, nil)
+
+		r := setupRouter()
+		r.POST("/services", handler.RegisterService)
+
+		body := `{"name":"Redis","type":"tcp","host":"db.internal","port":5432,"check_interval":30,"config":{"tcp":{"send":"PING\r\n","expect":"+PONG"}}}`
+		req, _ := http.NewRequest("POST", "/services", bytes.NewBufferString(body))
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("MissingPort", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		service := NewService(mockRepo, zap.L())
+		handler := NewHandler(service, NewWsHub(zap.L()), middlewaretest.Denylist{}, zap.NewNop())
+
+		r := setupRouter()
+		r.POST("/services", handler.RegisterService)
+
+		body := `{"name":"Redis","type":"tcp","host":"db.internal","check_interval":30}`
+		req, _ := http.NewRequest("POST", "/services", bytes.NewBufferString(body))
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		mockRepo.AssertNotCalled(t, "Create")
+	})
+
+	t.Run("HTTPWithoutURL", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		service := NewService(mockRepo, zap.L())
+		handler := NewHandler(service, NewWsHub(zap.L()), middlewaretest.Denylist{}, zap.NewNop())
+
+		r := setupRouter()
+		r.POST("/services", handler.RegisterService)
+
+		body := `{"name":"Site","check_interval":30}`
+		req, _ := http.NewRequest("POST", "/services", bytes.NewBufferString(body))
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		mockRepo.AssertNotCalled(t, "Create")
+	})
+}
+
 func TestListServices(t *testing.T) {
 	t.Run("Success", func(t *testing.T) {
 		mockRepo := new(MockRepository)
 		service := NewService(mockRepo, zap.L())
 		hub := NewWsHub(zap.L())
-		handler := NewHandler(service, hub, zap.NewNop())
+		handler := NewHandler(service, hub, middlewaretest.Denylist{}, zap.NewNop())
 
 		expectedServices := []Service{
 			{ID: 1, Name: "Service 1", URL: "http://s1.com", CheckInterval: 60},
 			{ID: 2, Name: "Service 2", URL: "http://s2.com", CheckInterval: 120},
 		}
-		mockRepo.On("ListServices", mock.Anything).Return(expectedServices, nil)
+		mockRepo.On("ListServices", mock.Anything, testOwner).Return(expectedServices, nil)
 
 		r := setupRouter()
 		r.GET("/services", handler.ListServices)
@@ -159,9 +310,9 @@ func TestListServices(t *testing.T) {
 		mockRepo := new(MockRepository)
 		service := NewService(mockRepo, zap.L())
 		hub := NewWsHub(zap.L())
-		handler := NewHandler(service, hub, zap.NewNop())
+		handler := NewHandler(service, hub, middlewaretest.Denylist{}, zap.NewNop())
 
-		mockRepo.On("ListServices", mock.Anything).Return([]Service{}, errors.New("db error"))
+		mockRepo.On("ListServices", mock.Anything, testOwner).Return([]Service{}, errors.New("db error"))
 
 		r := setupRouter()
 		r.GET("/services", handler.ListServices)
@@ -173,6 +324,23 @@ func TestListServices(t *testing.T) {
 		assert.Equal(t, http.StatusInternalServerError, w.Code)
 		mockRepo.AssertExpectations(t)
 	})
+
+	t.Run("NoUser", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		service := NewService(mockRepo, zap.L())
+		handler := NewHandler(service, NewWsHub(zap.L()), middlewaretest.Denylist{}, zap.NewNop())
+
+		gin.SetMode(gin.TestMode)
+		r := gin.New()
+		r.GET("/services", handler.ListServices)
+
+		req, _ := http.NewRequest("GET", "/services", nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+		mockRepo.AssertNotCalled(t, "ListServices")
+	})
 }
 
 func TestGetHealthChecks(t *testing.T) {
@@ -180,11 +348,12 @@ func TestGetHealthChecks(t *testing.T) {
 		mockRepo := new(MockRepository)
 		service := NewService(mockRepo, zap.L())
 		hub := NewWsHub(zap.L())
-		handler := NewHandler(service, hub, zap.NewNop())
+		handler := NewHandler(service, hub, middlewaretest.Denylist{}, zap.NewNop())
 
 		expectedChecks := []HealthCheck{
 			{ID: 1, ServiceID: 1, Status: "UP", Latency: 100},
 		}
+		mockRepo.On("GetService", mock.Anything, testOwner, 1).Return(Service{ID: 1, UserID: testUserID}, nil)
 		mockRepo.On("GetHealthChecksByServiceID", mock.Anything, 1, 1, 10).Return(expectedChecks, nil)
 
 		r := setupRouter()
@@ -202,11 +371,12 @@ func TestGetHealthChecks(t *testing.T) {
 		mockRepo := new(MockRepository)
 		service := NewService(mockRepo, zap.L())
 		hub := NewWsHub(zap.L())
-		handler := NewHandler(service, hub, zap.NewNop())
+		handler := NewHandler(service, hub, middlewaretest.Denylist{}, zap.NewNop())
 
 		expectedChecks := []HealthCheck{
 			{ID: 1, ServiceID: 1, Status: "UP", Latency: 100},
 		}
+		mockRepo.On("GetService", mock.Anything, testOwner, 1).Return(Service{ID: 1, UserID: testUserID}, nil)
 		mockRepo.On("GetHealthChecksByServiceID", mock.Anything, 1, 2, 20).Return(expectedChecks, nil)
 
 		r := setupRouter()
@@ -220,11 +390,37 @@ func TestGetHealthChecks(t *testing.T) {
 		mockRepo.AssertExpectations(t)
 	})
 
+	t.Run("Success_WithFailure", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		service := NewService(mockRepo, zap.L())
+		hub := NewWsHub(zap.L())
+		handler := NewHandler(service, hub, middlewaretest.Denylist{}, zap.NewNop())
+
+		expectedChecks := []HealthCheck{
+			{ID: 1, ServiceID: 1, Status: StatusDown, Latency: 100, StatusCode: 503, ErrorClass: ErrorClassHTTPStatus,
+				Message: "unexpected status 503 Service Unavailable, expected 2xx", BodySnippet: "upstream connect error"},
+		}
+		mockRepo.On("GetService", mock.Anything, testOwner, 1).Return(Service{ID: 1, UserID: testUserID}, nil)
+		mockRepo.On("GetHealthChecksByServiceID", mock.Anything, 1, 1, 10).Return(expectedChecks, nil)
+
+		r := setupRouter()
+		r.GET("/services/:serviceId/health-checks", handler.GetHealthChecks)
+
+		req, _ := http.NewRequest("GET", "/services/1/health-checks", nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"status_code":503`)
+		assert.Contains(t, w.Body.String(), `"error_class":"http_status"`)
+		assert.Contains(t, w.Body.String(), `"body_snippet":"upstream connect error"`)
+	})
+
 	t.Run("InvalidServiceID", func(t *testing.T) {
 		mockRepo := new(MockRepository)
 		service := NewService(mockRepo, zap.L())
 		hub := NewWsHub(zap.L())
-		handler := NewHandler(service, hub, zap.NewNop())
+		handler := NewHandler(service, hub, middlewaretest.Denylist{}, zap.NewNop())
 
 		r := setupRouter()
 		r.GET("/services/:serviceId/health-checks", handler.GetHealthChecks)
@@ -240,7 +436,7 @@ func TestGetHealthChecks(t *testing.T) {
 		mockRepo := new(MockRepository)
 		service := NewService(mockRepo, zap.L())
 		hub := NewWsHub(zap.L())
-		handler := NewHandler(service, hub, zap.NewNop())
+		handler := NewHandler(service, hub, middlewaretest.Denylist{}, zap.NewNop())
 
 		r := setupRouter()
 		r.GET("/services/:serviceId/health-checks", handler.GetHealthChecks)
@@ -256,7 +452,7 @@ func TestGetHealthChecks(t *testing.T) {
 		mockRepo := new(MockRepository)
 		service := NewService(mockRepo, zap.L())
 		hub := NewWsHub(zap.L())
-		handler := NewHandler(service, hub, zap.NewNop())
+		handler := NewHandler(service, hub, middlewaretest.Denylist{}, zap.NewNop())
 
 		r := setupRouter()
 		r.GET("/services/:serviceId/health-checks", handler.GetHealthChecks)
@@ -268,12 +464,32 @@ func TestGetHealthChecks(t *testing.T) {
 		assert.Equal(t, http.StatusBadRequest, w.Code)
 	})
 
+	t.Run("NotOwned", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		service := NewService(mockRepo, zap.L())
+		hub := NewWsHub(zap.L())
+		handler := NewHandler(service, hub, middlewaretest.Denylist{}, zap.NewNop())
+
+		mockRepo.On("GetService", mock.Anything, testOwner, 2).Return(Service{}, ErrServiceNotFound)
+
+		r := setupRouter()
+		r.GET("/services/:serviceId/health-checks", handler.GetHealthChecks)
+
+		req, _ := http.NewRequest("GET", "/services/2/health-checks", nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		mockRepo.AssertNotCalled(t, "GetHealthChecksByServiceID")
+	})
+
 	t.Run("InternalServerError", func(t *testing.T) {
 		mockRepo := new(MockRepository)
 		service := NewService(mockRepo, zap.L())
 		hub := NewWsHub(zap.L())
-		handler := NewHandler(service, hub, zap.NewNop())
+		handler := NewHandler(service, hub, middlewaretest.Denylist{}, zap.NewNop())
 
+		mockRepo.On("GetService", mock.Anything, testOwner, 1).Return(Service{ID: 1, UserID: testUserID}, nil)
 		mockRepo.On("GetHealthChecksByServiceID", mock.Anything, 1, 1, 10).Return([]HealthCheck{}, errors.New("db error"))
 
 		r := setupRouter()
@@ -287,3 +503,373 @@ func TestGetHealthChecks(t *testing.T) {
 		mockRepo.AssertExpectations(t)
 	})
 }
+
+func TestGetUptime(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		handler := NewHandler(NewService(mockRepo, zap.L()), NewWsHub(zap.L()), middlewaretest.Denylist{}, zap.NewNop())
+
+		from := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+		to := from.Add(2 * time.Hour)
+		mockRepo.On("GetService", mock.Anything, testOwner, 1).Return(Service{ID: 1, UserID: testUserID}, nil)
+		mockRepo.On("GetStatusPeriods", mock.Anything, 1, from, to).Return([]StatusPeriod{
+			{Status: StatusUp, StartedAt: from, EndedAt: from.Add(90 * time.Minute)},
+			{Status: StatusDown, StartedAt: from.Add(90 * time.Minute), EndedAt: to},
+		}, nil)
+		mockRepo.On("GetIncidentSpans", mock.Anything, 1, from, to).Return([]IncidentSpan{
+			{StartedAt: from.Add(92 * time.Minute)},
+		}, nil)
+
+		r := setupRouter()
+		r.GET("/services/:serviceId/uptime", handler.GetUptime)
+
+		req, _ := http.NewRequest("GET", "/services/1/uptime?from=2024-05-01T00:00:00Z&to=2024-05-01T02:00:00Z", nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"uptime_percent":75`)
+		assert.Contains(t, w.Body.String(), `"incidents":1`)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("InvalidWindow", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		handler := NewHandler(NewService(mockRepo, zap.L()), NewWsHub(zap.L()), middlewaretest.Denylist{}, zap.NewNop())
+
+		r := setupRouter()
+		r.GET("/services/:serviceId/uptime", handler.GetUptime)
+
+		for _, query := range []string{"window=1y", "window=7d&from=2024-05-01T00:00:00Z", "from=yesterday"} {
+			req, _ := http.NewRequest("GET", "/services/1/uptime?"+query, nil)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code, query)
+		}
+		mockRepo.AssertNotCalled(t, "GetStatusPeriods")
+	})
+
+	t.Run("BeforeRetention", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		service := NewService(mockRepo, zap.L())
+		service.SetRawRetention(7 * 24 * time.Hour)
+		handler := NewHandler(service, NewWsHub(zap.L()), middlewaretest.Denylist{}, zap.NewNop())
+
+		from := RollupDaily.truncate(time.Now().AddDate(0, 0, -20))
+		to := from.Add(2 * time.Hour)
+		mockRepo.On("GetService", mock.Anything, testOwner, 1).Return(Service{ID: 1, UserID: testUserID}, nil)
+		mockRepo.On("GetRollups", mock.Anything, 1, RollupHourly, from, to).Return([]Rollup{
+			{Start: from, End: from.Add(time.Hour), CheckCount: 60},
+			{Start: from.Add(time.Hour), End: to, CheckCount: 60, FailureCount: 30},
+		}, nil)
+		mockRepo.On("GetIncidentSpans", mock.Anything, 1, from, to).Return([]IncidentSpan{}, nil)
+
+		r := setupRouter()
+		r.GET("/services/:serviceId/uptime", handler.GetUptime)
+
+		query := "from=" + from.Format(time.RFC3339) + "&to=" + to.Format(time.RFC3339)
+		req, _ := http.NewRequest("GET", "/services/1/uptime?"+query, nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"uptime_percent":75`)
+		mockRepo.AssertExpectations(t)
+		mockRepo.AssertNotCalled(t, "GetStatusPeriods")
+	})
+
+	t.Run("NotOwned", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		handler := NewHandler(NewService(mockRepo, zap.L()), NewWsHub(zap.L()), middlewaretest.Denylist{}, zap.NewNop())
+		mockRepo.On("GetService", mock.Anything, testOwner, 2).Return(Service{}, ErrServiceNotFound)
+
+		r := setupRouter()
+		r.GET("/services/:serviceId/uptime", handler.GetUptime)
+
+		req, _ := http.NewRequest("GET", "/services/2/uptime?window=30d", nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		mockRepo.AssertNotCalled(t, "GetStatusPeriods")
+	})
+}
+
+func TestGetLatency(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		handler := NewHandler(NewService(mockRepo, zap.L()), NewWsHub(zap.L()), middlewaretest.Denylist{}, zap.NewNop())
+
+		from := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+		to := from.Add(2 * time.Hour)
+		mockRepo.On("GetService", mock.Anything, testOwner, 1).Return(Service{ID: 1, UserID: testUserID}, nil)
+		mockRepo.On("GetLatencyStats", mock.Anything, 1, from, to, 15*time.Minute).Return(
+			LatencyStats{Count: 240, MinMs: 80, MaxMs: 900, MeanMs: 130.5, P50Ms: 110, P90Ms: 200, P95Ms: 300, P99Ms: 850},
+			[]LatencyBucket{{Start: from, LatencyStats: LatencyStats{Count: 30, MinMs: 80, MaxMs: 150, P50Ms: 100}}},
+			nil,
+		)
+
+		r := setupRouter()
+		r.GET("/services/:serviceId/latency", handler.GetLatency)
+
+		req, _ := http.NewRequest("GET", "/services/1/latency?from=2024-05-01T00:00:00Z&to=2024-05-01T02:00:00Z&bucket=15m", nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"bucket_seconds":900`)
+		assert.Contains(t, w.Body.String(), `"p99_ms":850`)
+		assert.Contains(t, w.Body.String(), `"start":"2024-05-01T00:00:00Z"`)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("InvalidQuery", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		handler := NewHandler(NewService(mockRepo, zap.L()), NewWsHub(zap.L()), middlewaretest.Denylist{}, zap.NewNop())
+
+		r := setupRouter()
+		r.GET("/services/:serviceId/latency", handler.GetLatency)
+
+		for _, query := range []string{"bucket=2m", "window=30d&bucket=1m", "window=1y"} {
+			req, _ := http.NewRequest("GET", "/services/1/latency?"+query, nil)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code, query)
+		}
+		mockRepo.AssertNotCalled(t, "GetLatencyStats")
+	})
+
+	t.Run("BeforeRetention", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		service := NewService(mockRepo, zap.L())
+		service.SetRawRetention(7 * 24 * time.Hour)
+		handler := NewHandler(service, NewWsHub(zap.L()), middlewaretest.Denylist{}, zap.NewNop())
+
+		cutoff := RollupDaily.truncate(time.Now().AddDate(0, 0, -7))
+		mockRepo.On("GetService", mock.Anything, testOwner, 1).Return(Service{ID: 1, UserID: testUserID}, nil)
+		mockRepo.On("GetRollups", mock.Anything, 1, RollupHourly, mock.Anything, cutoff).Return([]Rollup{
+			{Start: cutoff.Add(-time.Hour), End: cutoff, Latency: LatencyStats{Count: 60, MinMs: 100, MaxMs: 100, MeanMs: 100, P99Ms: 100}},
+		}, nil)
+		mockRepo.On("GetLatencyStats", mock.Anything, 1, cutoff, mock.Anything, 6*time.Hour).Return(
+			LatencyStats{Count: 20, MinMs: 300, MaxMs: 300, MeanMs: 300, P99Ms: 300},
+			[]LatencyBucket{{Start: cutoff, LatencyStats: LatencyStats{Count: 20, MinMs: 300, MaxMs: 300, MeanMs: 300, P99Ms: 300}}},
+			nil,
+		)
+
+		r := setupRouter()
+		r.GET("/services/:serviceId/latency", handler.GetLatency)
+
+		req, _ := http.NewRequest("GET", "/services/1/latency?window=30d", nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"overall":{"count":80,"min_ms":100,"max_ms":300,"mean_ms":150`)
+		assert.Contains(t, w.Body.String(), `"start":"`+cutoff.Add(-6*time.Hour).Format(time.RFC3339)+`"`)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("BucketBeforeRetention", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		service := NewService(mockRepo, zap.L())
+		service.SetRawRetention(7 * 24 * time.Hour)
+		handler := NewHandler(service, NewWsHub(zap.L()), middlewaretest.Denylist{}, zap.NewNop())
+
+		r := setupRouter()
+		r.GET("/services/:serviceId/latency", handler.GetLatency)
+
+		from := time.Now().AddDate(0, 0, -10).UTC().Format(time.RFC3339)
+		req, _ := http.NewRequest("GET", "/services/1/latency?bucket=5m&from="+from, nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "5m buckets are only available")
+		mockRepo.AssertNotCalled(t, "GetRollups")
+		mockRepo.AssertNotCalled(t, "GetLatencyStats")
+	})
+
+	t.Run("NotOwned", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		handler := NewHandler(NewService(mockRepo, zap.L()), NewWsHub(zap.L()), middlewaretest.Denylist{}, zap.NewNop())
+		mockRepo.On("GetService", mock.Anything, testOwner, 2).Return(Service{}, ErrServiceNotFound)
+
+		r := setupRouter()
+		r.GET("/services/:serviceId/latency", handler.GetLatency)
+
+		req, _ := http.NewRequest("GET", "/services/2/latency", nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		mockRepo.AssertNotCalled(t, "GetLatencyStats")
+	})
+}
+
+func TestHeartbeat(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		service := NewService(mockRepo, zap.L())
+		handler := NewHandler(service, NewWsHub(zap.L()), middlewaretest.Denylist{}, zap.NewNop())
+
+		mockRepo.On("RecordHeartbeat", mock.Anything, "abc123").Return(Service{ID: 3, Type: CheckTypeHeartbeat}, nil)
+		mockRepo.On("GetStatusState", mock.Anything, 3).Return(StatusState{}, nil)
+		mockRepo.On("SaveStatusState", mock.Anything, 3, mock.Anything).Return(nil)
+		mockRepo.On("CreateHealthCheck", mock.Anything, mock.Anything).Return(nil)
+
+		r := setupRouter()
+		handler.RegisterHeartbeatRoutes(r.Group("/heartbeats"))
+
+		req, _ := http.NewRequest("POST", "/heartbeats/abc123", nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("UnknownToken", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		service := NewService(mockRepo, zap.L())
+		handler := NewHandler(service, NewWsHub(zap.L()), middlewaretest.Denylist{}, zap.NewNop())
+
+		mockRepo.On("RecordHeartbeat", mock.Anything, "nope").Return(Service{}, ErrServiceNotFound)
+
+		r := setupRouter()
+		handler.RegisterHeartbeatRoutes(r.Group("/heartbeats"))
+
+		req, _ := http.NewRequest("POST", "/heartbeats/nope", nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
+
+func TestServiceCRUD(t *testing.T) {
+	newHandler := func() (*Handler, *MockRepository, *gin.Engine) {
+		mockRepo := new(MockRepository)
+		handler := NewHandler(NewService(mockRepo, zap.L()), NewWsHub(zap.L()), middlewaretest.Denylist{}, zap.NewNop())
+
+		r := setupRouter()
+		r.GET("/services/:serviceId", handler.GetService)
+		r.PATCH("/services/:serviceId", handler.UpdateService)
+		r.DELETE("/services/:serviceId", handler.DeleteService)
+		r.POST("/services/:serviceId/pause", handler.PauseService)
+		r.POST("/services/:serviceId/resume", handler.ResumeService)
+		return handler, mockRepo, r
+	}
+	serve := func(r *gin.Engine, method, path, body string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest(method, path, bytes.NewBufferString(body))
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+	existing := Service{ID: 5, Name: "API", Type: CheckTypeHTTP, URL: "http://api.example.com", CheckInterval: 60, Timeout: 5}
+
+	t.Run("Get", func(t *testing.T) {
+		_, mockRepo, r := newHandler()
+		mockRepo.On("GetService", mock.Anything, testOwner, 5).Return(existing, nil)
+
+		w := serve(r, "GET", "/services/5", "")
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response Service
+		json.Unmarshal(w.Body.Bytes(), &response)
+		assert.Equal(t, existing.Name, response.Name)
+	})
+
+	t.Run("GetNotFound", func(t *testing.T) {
+		_, mockRepo, r := newHandler()
+		mockRepo.On("GetService", mock.Anything, testOwner, 6).Return(Service{}, ErrServiceNotFound)
+
+		w := serve(r, "GET", "/services/6", "")
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("GetInvalidID", func(t *testing.T) {
+		_, _, r := newHandler()
+
+		w := serve(r, "GET", "/services/abc", "")
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("Update", func(t *testing.T) {
+		_, mockRepo, r := newHandler()
+		mockRepo.On("GetService", mock.Anything, testOwner, 5).Return(existing, nil)
+		mockRepo.On("UpdateService", mock.Anything, testOwner, mock.MatchedBy(func(s Service) bool {
+			return s.ID == 5 && s.CheckInterval == 300 && s.Name == "API" && s.URL == existing.URL
+		})).Return(Service{ID: 5, Name: "API", CheckInterval: 300}, nil)
+
+		w := serve(r, "PATCH", "/services/5", `{"check_interval": 300}`)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("UpdateInvalidTarget", func(t *testing.T) {
+		_, mockRepo, r := newHandler()
+		mockRepo.On("GetService", mock.Anything, testOwner, 5).Return(existing, nil)
+
+		w := serve(r, "PATCH", "/services/5", `{"grace_period": 60}`)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		mockRepo.AssertNotCalled(t, "UpdateService")
+	})
+
+	t.Run("UpdateBadBody", func(t *testing.T) {
+		_, mockRepo, r := newHandler()
+
+		w := serve(r, "PATCH", "/services/5", `{"check_interval": 0}`)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		mockRepo.AssertNotCalled(t, "GetService")
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		_, mockRepo, r := newHandler()
+		mockRepo.On("DeleteService", mock.Anything, testOwner, 5).Return(nil)
+
+		w := serve(r, "DELETE", "/services/5", "")
+
+		assert.Equal(t, http.StatusNoContent, w.Code)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("DeleteNotFound", func(t *testing.T) {
+		_, mockRepo, r := newHandler()
+		mockRepo.On("DeleteService", mock.Anything, testOwner, 5).Return(ErrServiceNotFound)
+
+		w := serve(r, "DELETE", "/services/5", "")
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("PauseAndResume", func(t *testing.T) {
+		_, mockRepo, r := newHandler()
+		mockRepo.On("SetPaused", mock.Anything, testOwner, 5, true).Return(Service{ID: 5, Paused: true}, nil)
+		mockRepo.On("SetPaused", mock.Anything, testOwner, 5, false).Return(Service{ID: 5}, nil)
+
+		w := serve(r, "POST", "/services/5/pause", "")
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"paused":true`)
+
+		w = serve(r, "POST", "/services/5/resume", "")
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"paused":false`)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("InternalServerError", func(t *testing.T) {
+		_, mockRepo, r := newHandler()
+		mockRepo.On("SetPaused", mock.Anything, testOwner, 5, true).Return(Service{}, errors.New("db error"))
+
+		w := serve(r, "POST", "/services/5/pause", "")
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+	})
+}
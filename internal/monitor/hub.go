@@ -10,15 +10,32 @@ import (
 type WsHub struct {
 	clients    map[*WsClient]bool
 	broadcast  chan []byte
+	userEvents chan userMessage
 	register   chan *WsClient
 	unregister chan *WsClient
 	log        *zap.Logger
 }
 
+// userMessage is delivered only to the clients watching one organization, or
+// to the clients of one user watching their personal services when orgID is 0.
+type userMessage struct {
+	userID int
+	orgID  int
+	data   []byte
+}
+
+func (m userMessage) deliverableTo(client *WsClient) bool {
+	if m.orgID != 0 {
+		return client.orgID == m.orgID
+	}
+	return client.orgID == 0 && client.userID == m.userID
+}
+
 func NewWsHub(log *zap.Logger) *WsHub {
 	return &WsHub{
 		clients:    make(map[*WsClient]bool),
 		broadcast:  make(chan []byte, 256),
+		userEvents: make(chan userMessage, 256),
 		register:   make(chan *WsClient),
 		unregister: make(chan *WsClient),
 		log:        log,
@@ -41,17 +58,28 @@ func (h *WsHub) Run(ctx context.Context) {
 			}
 		case message := <-h.broadcast:
 			for client := range h.clients {
-				select {
-				case client.send <- message:
-				default:
-					close(client.send)
-					delete(h.clients, client)
+				h.deliver(client, message)
+			}
+		case message := <-h.userEvents:
+			for client := range h.clients {
+				if message.deliverableTo(client) {
+					h.deliver(client, message.data)
 				}
 			}
 		}
 	}
 }
 
+// deliver queues message for client, dropping clients that cannot keep up.
+func (h *WsHub) deliver(client *WsClient, message []byte) {
+	select {
+	case client.send <- message:
+	default:
+		close(client.send)
+		delete(h.clients, client)
+	}
+}
+
 func (h *WsHub) shutdown() {
 	for client := range h.clients {
 		close(client.send)
@@ -59,11 +87,13 @@ func (h *WsHub) shutdown() {
 	}
 }
 
+// BroadcastStatusChange sends event to the connected clients of the service's
+// owner, or of its organization's members watching that organization.
 func (h *WsHub) BroadcastStatusChange(event StatusChangeEvent) error {
 	data, err := json.Marshal(event)
 	if err != nil {
 		return err
 	}
-	h.broadcast <- data
+	h.userEvents <- userMessage{userID: event.UserID, orgID: event.OrgID, data: data}
 	return nil
 }
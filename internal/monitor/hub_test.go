@@ -158,6 +158,68 @@ func TestWsHub_BroadcastStatusChange(t *testing.T) {
 	}
 }
 
+func TestWsHub_BroadcastStatusChange_OnlyOwner(t *testing.T) {
+	hub := NewWsHub(zap.NewNop())
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go hub.Run(ctx)
+
+	owner := &WsClient{send: make(chan []byte, 256), hub: hub, userID: 1}
+	other := &WsClient{send: make(chan []byte, 256), hub: hub, userID: 2}
+	hub.register <- owner
+	hub.register <- other
+	time.Sleep(50 * time.Millisecond)
+
+	err := hub.BroadcastStatusChange(StatusChangeEvent{ServiceID: 1, UserID: 1, OldStatus: "UP", NewStatus: "DOWN"})
+	assert.NoError(t, err)
+
+	select {
+	case msg := <-owner.send:
+		assert.Contains(t, string(msg), "ServiceID")
+	case <-time.After(1 * time.Second):
+		t.Fatal("Owner should receive status change event")
+	}
+
+	select {
+	case <-other.send:
+		t.Fatal("Other users should not receive the event")
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestWsHub_BroadcastStatusChange_Organization(t *testing.T) {
+	hub := NewWsHub(zap.NewNop())
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go hub.Run(ctx)
+
+	member := &WsClient{send: make(chan []byte, 256), hub: hub, userID: 2, orgID: 7}
+	personal := &WsClient{send: make(chan []byte, 256), hub: hub, userID: 1}
+	otherOrg := &WsClient{send: make(chan []byte, 256), hub: hub, userID: 1, orgID: 8}
+	hub.register <- member
+	hub.register <- personal
+	hub.register <- otherOrg
+	time.Sleep(50 * time.Millisecond)
+
+	err := hub.BroadcastStatusChange(StatusChangeEvent{ServiceID: 1, UserID: 1, OrgID: 7, OldStatus: "UP", NewStatus: "DOWN"})
+	assert.NoError(t, err)
+
+	select {
+	case msg := <-member.send:
+		assert.Contains(t, string(msg), `"OrgID":7`)
+	case <-time.After(1 * time.Second):
+		t.Fatal("Organization members should receive the event")
+	}
+
+	select {
+	case <-personal.send:
+		t.Fatal("Clients watching personal services should not receive organization events")
+	case <-otherOrg.send:
+		t.Fatal("Clients watching another organization should not receive the event")
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
 func TestWsHub_ClientDisconnect(t *testing.T) {
 	logger := zap.NewNop()
 	hub := NewWsHub(logger)
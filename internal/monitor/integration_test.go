@@ -5,6 +5,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"strconv"
 	"testing"
 	"time"
 
@@ -58,6 +59,7 @@ func TestIntegration_SchedulerToWorkerFlow(t *testing.T) {
 
 	// Setup repository
 	repo := NewRepository(pool)
+	userID := createTestUser(t, ctx, pool, "integration-user")
 
 	// Create a test HTTP server
 	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -69,16 +71,17 @@ func TestIntegration_SchedulerToWorkerFlow(t *testing.T) {
 	service := Service{
 		Name:          "test-service-integration",
 		URL:           testServer.URL,
+		UserID:        userID,
 		CheckInterval: 10,
 		NextRunAt:     time.Now().Local(),
 		CreatedAt:     time.Now().Local(),
 	}
 
-	err = repo.Create(ctx, service)
+	_, err = repo.Create(ctx, service)
 	require.NoError(t, err)
 
 	// Get the created service
-	services, err := repo.ListServices(ctx)
+	services, err := repo.ListServices(ctx, Owner{UserID: userID})
 	require.NoError(t, err)
 	require.NotEmpty(t, services)
 	createdService := &services[0]
@@ -127,7 +130,7 @@ func TestIntegration_SchedulerToWorkerFlow(t *testing.T) {
 	checks, err := repo.GetHealthChecksByServiceID(ctx, createdService.ID, 1, 10)
 	require.NoError(t, err)
 	assert.Len(t, checks, 1)
-	assert.Equal(t, "UP", checks[0].Status)
+	assert.Equal(t, StatusUp, checks[0].Status)
 	assert.Equal(t, createdService.ID, checks[0].ServiceID)
 }
 
@@ -175,6 +178,7 @@ func TestIntegration_StatusChangeEvent(t *testing.T) {
 
 	// Setup repository
 	repo := NewRepository(pool)
+	userID := createTestUser(t, ctx, pool, "integration-user")
 
 	// Create first test HTTP server (returns 200)
 	testServerOK := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -186,16 +190,17 @@ func TestIntegration_StatusChangeEvent(t *testing.T) {
 	service := Service{
 		Name:          "test-service-status-change",
 		URL:           testServerOK.URL,
+		UserID:        userID,
 		CheckInterval: 10,
 		NextRunAt:     time.Now().Local(),
 		CreatedAt:     time.Now().Local(),
 	}
 
-	err = repo.Create(ctx, service)
+	_, err = repo.Create(ctx, service)
 	require.NoError(t, err)
 
 	// Get the created service
-	services, err := repo.ListServices(ctx)
+	services, err := repo.ListServices(ctx, Owner{UserID: userID})
 	require.NoError(t, err)
 	require.NotEmpty(t, services)
 	createdService := &services[0]
@@ -215,7 +220,7 @@ func TestIntegration_StatusChangeEvent(t *testing.T) {
 	// Verify first check
 	checks, err := repo.GetHealthChecksByServiceID(ctx, createdService.ID, 1, 10)
 	require.NoError(t, err)
-	assert.Equal(t, "UP", checks[0].Status)
+	assert.Equal(t, StatusUp, checks[0].Status)
 
 	// Change URL to failing server
 	testServerFail := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -232,8 +237,8 @@ func TestIntegration_StatusChangeEvent(t *testing.T) {
 	select {
 	case event := <-eventChan:
 		assert.Equal(t, createdService.ID, event.ServiceID)
-		assert.Equal(t, "UP", event.OldStatus)
-		assert.Equal(t, "DOWN", event.NewStatus)
+		assert.Equal(t, StatusUp, event.OldStatus)
+		assert.Equal(t, StatusDown, event.NewStatus)
 	case <-time.After(2 * time.Second):
 		t.Fatal("Timeout waiting for status change event")
 	}
@@ -242,7 +247,86 @@ func TestIntegration_StatusChangeEvent(t *testing.T) {
 	checks, err = repo.GetHealthChecksByServiceID(ctx, createdService.ID, 1, 10)
 	require.NoError(t, err)
 	assert.Len(t, checks, 2)
-	assert.Equal(t, "DOWN", checks[0].Status)
+	assert.Equal(t, StatusDown, checks[0].Status)
+}
+
+func TestIntegration_RecheckConfirmsFailure(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test")
+	}
+
+	SetupTestDatabase(t)
+	ctx := context.Background()
+
+	pool, err := pgxpool.New(ctx, getTestDatabaseURL())
+	require.NoError(t, err)
+	defer pool.Close()
+
+	rdb := redis.NewClient(&redis.Options{Addr: getTestRedisURL()})
+	defer rdb.Close()
+	rdb.Del(ctx, HealthCheckStream)
+
+	eventBus := NewInMemoryEventBus(zap.NewNop())
+	eventChan := make(chan StatusChangeEvent, 1)
+	eventBus.Subscribe("StatusChange", func(ctx context.Context, event Event) {
+		if sce, ok := event.(StatusChangeEvent); ok {
+			eventChan <- sce
+		}
+	})
+
+	repo := NewRepository(pool)
+	userID := createTestUser(t, ctx, pool, "recheck-user")
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer failing.Close()
+
+	service, err := repo.Create(ctx, Service{
+		Name:             "test-service-recheck",
+		URL:              failing.URL,
+		UserID:           userID,
+		CheckInterval:    60,
+		FailureThreshold: 1,
+		SuccessThreshold: 1,
+		Recheck:          true,
+		NextRunAt:        time.Now().Add(time.Minute),
+	})
+	require.NoError(t, err)
+	defer cleanupService(t, ctx, repo, service.ID)
+	require.NoError(t, repo.SaveStatusState(ctx, service.ID, StatusState{Status: "UP"}))
+
+	worker := NewWorker(rdb, repo, zap.NewNop(), eventBus)
+	jobData := map[string]interface{}{
+		"service_id": strconv.Itoa(service.ID),
+		"url":        failing.URL,
+		"recheck":    "1",
+	}
+	require.NoError(t, worker.processJob(ctx, jobData))
+
+	// The failure is not trusted yet; a re-check is queued instead.
+	select {
+	case event := <-eventChan:
+		t.Fatalf("unexpected status change before the re-check: %+v", event)
+	case <-time.After(200 * time.Millisecond):
+	}
+	msgs, err := rdb.XRange(ctx, HealthCheckStream, "-", "+").Result()
+	require.NoError(t, err)
+	require.Len(t, msgs, 1)
+	assert.Equal(t, "1", msgs[0].Values["attempt"])
+
+	require.NoError(t, worker.processJob(ctx, msgs[0].Values))
+	select {
+	case event := <-eventChan:
+		assert.Equal(t, StatusUp, event.OldStatus)
+		assert.Equal(t, StatusDown, event.NewStatus)
+	case <-time.After(2 * time.Second):
+		t.Fatal("Timeout waiting for status change event")
+	}
+
+	// A confirmed change is not re-checked again.
+	msgs, err = rdb.XRange(ctx, HealthCheckStream, "-", "+").Result()
+	require.NoError(t, err)
+	assert.Len(t, msgs, 1)
 }
 
 func TestIntegration_RepositoryOperations(t *testing.T) {
@@ -266,21 +350,23 @@ func TestIntegration_RepositoryOperations(t *testing.T) {
 	require.NoError(t, err)
 
 	repo := NewRepository(pool)
+	userID := createTestUser(t, ctx, pool, "integration-user")
 
 	// Test Create
 	service := Service{
 		Name:          "test-repo-service",
 		URL:           "http://example.com",
+		UserID:        userID,
 		CheckInterval: 30,
 		NextRunAt:     time.Now().Local(),
 		CreatedAt:     time.Now().Local(),
 	}
 
-	err = repo.Create(ctx, service)
+	_, err = repo.Create(ctx, service)
 	require.NoError(t, err)
 
 	// Test ListServices
-	services, err := repo.ListServices(ctx)
+	services, err := repo.ListServices(ctx, Owner{UserID: userID})
 	require.NoError(t, err)
 	assert.NotEmpty(t, services)
 	created := services[0]
@@ -300,13 +386,13 @@ func TestIntegration_RepositoryOperations(t *testing.T) {
 	checks, err := repo.GetHealthChecksByServiceID(ctx, created.ID, 1, 10)
 	require.NoError(t, err)
 	assert.Len(t, checks, 1)
-	assert.Equal(t, "UP", checks[0].Status)
+	assert.Equal(t, StatusUp, checks[0].Status)
 
 	// Test GetLatestHealthCheck
 	latest, err := repo.GetLatestHealthCheck(ctx, created.ID)
 	require.NoError(t, err)
 	assert.NotNil(t, latest)
-	assert.Equal(t, "UP", latest.Status)
+	assert.Equal(t, StatusUp, latest.Status)
 
 	// Create another check
 	check2 := HealthCheck{
@@ -321,7 +407,7 @@ func TestIntegration_RepositoryOperations(t *testing.T) {
 	// Verify latest is now DOWN
 	latest, err = repo.GetLatestHealthCheck(ctx, created.ID)
 	require.NoError(t, err)
-	assert.Equal(t, "DOWN", latest.Status)
+	assert.Equal(t, StatusDown, latest.Status)
 
 	// Test ClaimDueServices
 	claimed, err := repo.ClaimDueServices(ctx)
@@ -0,0 +1,108 @@
+package monitor
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// StatusRecorder persists check results and publishes a StatusChangeEvent
+// whenever they confirm a change of a service's status: as many consecutive
+// checks as the job's threshold must agree on the new status. It is shared by
+// the worker and by passive monitors such as heartbeats.
+type StatusRecorder struct {
+	repo     Repository
+	eventBus EventBus
+	log      *zap.Logger
+}
+
+func NewStatusRecorder(repo Repository, eventBus EventBus, log *zap.Logger) *StatusRecorder {
+	return &StatusRecorder{repo: repo, eventBus: eventBus, log: log}
+}
+
+// Record stores result as the latest check of the job's service and returns
+// the service's status state after it.
+func (r *StatusRecorder) Record(ctx context.Context, job CheckJob, result CheckResult) (StatusState, error) {
+	serviceID := job.ServiceID
+	now := time.Now().Local()
+
+	previous, stateErr := r.repo.GetStatusState(ctx, serviceID)
+	if stateErr != nil {
+		r.log.Warn("failed to get status state", zap.Error(stateErr))
+	}
+
+	status := result.Status
+	check := HealthCheck{
+		ServiceID: serviceID,
+		Status:    status,
+		CreatedAt: now,
+		Latency:   int(result.Latency.Milliseconds()),
+		Details:   result.Details,
+
+		StatusCode:  result.StatusCode,
+		ErrorClass:  result.ErrorClass,
+		Message:     result.Message,
+		BodySnippet: result.BodySnippet,
+		Timing:      result.Timing,
+	}
+
+	if err := r.repo.CreateHealthCheck(ctx, check); err != nil {
+		return StatusState{}, err
+	}
+	// Without the previous state a change cannot be told apart from a flap.
+	if stateErr != nil {
+		return StatusState{}, nil
+	}
+
+	// A heartbeat service is expected to ping from the start, so it begins UP
+	// and a beat missed since its creation is a change like any other.
+	if previous.Status == "" && job.Type == CheckTypeHeartbeat {
+		previous.Status = StatusUp
+	}
+
+	since := now
+	if previous.PendingStatus == status && previous.PendingSince != nil {
+		since = *previous.PendingSince
+	}
+	state, changed := previous.Observe(status, now, job.threshold(status))
+	if err := r.repo.SaveStatusState(ctx, serviceID, state); err != nil {
+		return StatusState{}, err
+	}
+	if state.Confirming() {
+		r.log.Debug("status change awaits confirmation",
+			zap.Int("service_id", serviceID),
+			zap.String("status", string(state.PendingStatus)),
+			zap.Int("checks", state.PendingCount),
+		)
+	}
+
+	if r.eventBus != nil && changed {
+		event := StatusChangeEvent{
+			ServiceID: serviceID,
+			UserID:    job.UserID,
+			OrgID:     job.OrgID,
+			OldStatus: previous.Status,
+			NewStatus: status,
+			Latency:   check.Latency,
+			Message:   result.Message,
+			Timestamp: now,
+			Since:     since,
+
+			StatusCode:  result.StatusCode,
+			ErrorClass:  result.ErrorClass,
+			BodySnippet: result.BodySnippet,
+		}
+		if err := r.eventBus.Publish(ctx, event); err != nil {
+			r.log.Error("failed to publish status change event", zap.Error(err))
+		} else {
+			r.log.Info("status change detected",
+				zap.Int("service_id", serviceID),
+				zap.String("old_status", string(previous.Status)),
+				zap.String("new_status", string(status)),
+			)
+		}
+	}
+
+	return state, nil
+}
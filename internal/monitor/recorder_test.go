@@ -0,0 +1,190 @@
+package monitor
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+)
+
+func TestStatusRecorder_Record(t *testing.T) {
+	t.Run("publishes on status change", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		mockEventBus := new(MockEventBus)
+		recorder := NewStatusRecorder(mockRepo, mockEventBus, zap.NewNop())
+
+		mockRepo.On("GetStatusState", mock.Anything, 4).Return(StatusState{Status: "DOWN"}, nil)
+		mockRepo.On("SaveStatusState", mock.Anything, 4, mock.Anything).Return(nil)
+		mockRepo.On("CreateHealthCheck", mock.Anything, mock.MatchedBy(func(check HealthCheck) bool {
+			return check.ServiceID == 4 && check.Status == "UP"
+		})).Return(nil)
+		mockEventBus.On("Publish", mock.Anything, mock.MatchedBy(func(event StatusChangeEvent) bool {
+			return event.ServiceID == 4 && event.UserID == 9 && event.OldStatus == "DOWN" && event.NewStatus == "UP"
+		})).Return(nil)
+
+		_, err := recorder.Record(context.Background(), CheckJob{ServiceID: 4, UserID: 9}, CheckResult{Status: "UP"})
+
+		assert.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+		mockEventBus.AssertExpectations(t)
+	})
+
+	t.Run("event carries the failure", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		mockEventBus := new(MockEventBus)
+		recorder := NewStatusRecorder(mockRepo, mockEventBus, zap.NewNop())
+
+		mockRepo.On("GetStatusState", mock.Anything, 4).Return(StatusState{Status: "UP"}, nil)
+		mockRepo.On("SaveStatusState", mock.Anything, 4, mock.Anything).Return(nil)
+		mockRepo.On("CreateHealthCheck", mock.Anything, mock.MatchedBy(func(check HealthCheck) bool {
+			return check.StatusCode == 503 && check.ErrorClass == ErrorClassHTTPStatus &&
+				check.Message == "unexpected status 503 Service Unavailable, expected 2xx" && check.BodySnippet == "upstream connect error"
+		})).Return(nil)
+		mockEventBus.On("Publish", mock.Anything, mock.MatchedBy(func(event StatusChangeEvent) bool {
+			return event.NewStatus == StatusDown && event.Message == "unexpected status 503 Service Unavailable, expected 2xx" &&
+				event.Latency == 1500 && event.StatusCode == 503 && event.ErrorClass == ErrorClassHTTPStatus &&
+				event.BodySnippet == "upstream connect error"
+		})).Return(nil)
+
+		_, err := recorder.Record(context.Background(), CheckJob{ServiceID: 4, UserID: 9}, CheckResult{
+			Status:      StatusDown,
+			Latency:     1500 * time.Millisecond,
+			Message:     "unexpected status 503 Service Unavailable, expected 2xx",
+			StatusCode:  503,
+			ErrorClass:  ErrorClassHTTPStatus,
+			BodySnippet: "upstream connect error",
+		})
+
+		assert.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+		mockEventBus.AssertExpectations(t)
+	})
+
+	t.Run("publishes degradation", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		mockEventBus := new(MockEventBus)
+		recorder := NewStatusRecorder(mockRepo, mockEventBus, zap.NewNop())
+
+		mockRepo.On("GetStatusState", mock.Anything, 4).Return(StatusState{Status: StatusUp}, nil)
+		mockRepo.On("SaveStatusState", mock.Anything, 4, mock.Anything).Return(nil)
+		mockRepo.On("CreateHealthCheck", mock.Anything, mock.Anything).Return(nil)
+		mockEventBus.On("Publish", mock.Anything, mock.MatchedBy(func(event StatusChangeEvent) bool {
+			return event.OldStatus == StatusUp && event.NewStatus == StatusDegraded
+		})).Return(nil)
+
+		_, err := recorder.Record(context.Background(), CheckJob{ServiceID: 4, UserID: 9},
+			CheckResult{Status: StatusDegraded, Latency: 3 * time.Second})
+
+		assert.NoError(t, err)
+		mockEventBus.AssertExpectations(t)
+	})
+
+	t.Run("waits for the failure threshold", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		mockEventBus := new(MockEventBus)
+		recorder := NewStatusRecorder(mockRepo, mockEventBus, zap.NewNop())
+
+		mockRepo.On("GetStatusState", mock.Anything, 4).Return(StatusState{Status: "UP"}, nil)
+		mockRepo.On("CreateHealthCheck", mock.Anything, mock.Anything).Return(nil)
+		mockRepo.On("SaveStatusState", mock.Anything, 4, mock.MatchedBy(func(state StatusState) bool {
+			return state.Status == "UP" && state.PendingStatus == "DOWN" && state.PendingCount == 1
+		})).Return(nil)
+
+		state, err := recorder.Record(context.Background(), CheckJob{ServiceID: 4, FailureThreshold: 2}, CheckResult{Status: "DOWN"})
+
+		assert.NoError(t, err)
+		assert.True(t, state.Confirming())
+		mockRepo.AssertExpectations(t)
+		mockEventBus.AssertNotCalled(t, "Publish")
+	})
+
+	t.Run("publishes once the threshold is reached", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		mockEventBus := new(MockEventBus)
+		recorder := NewStatusRecorder(mockRepo, mockEventBus, zap.NewNop())
+
+		firstFailure := time.Now().Add(-time.Minute)
+		mockRepo.On("GetStatusState", mock.Anything, 4).
+			Return(StatusState{Status: "UP", PendingStatus: "DOWN", PendingCount: 1, PendingSince: &firstFailure}, nil)
+		mockRepo.On("CreateHealthCheck", mock.Anything, mock.Anything).Return(nil)
+		mockRepo.On("SaveStatusState", mock.Anything, 4, StatusState{Status: "DOWN"}).Return(nil)
+		mockEventBus.On("Publish", mock.Anything, mock.MatchedBy(func(event StatusChangeEvent) bool {
+			return event.OldStatus == "UP" && event.NewStatus == "DOWN" && event.Since.Equal(firstFailure)
+		})).Return(nil)
+
+		state, err := recorder.Record(context.Background(), CheckJob{ServiceID: 4, FailureThreshold: 2}, CheckResult{Status: "DOWN"})
+
+		assert.NoError(t, err)
+		assert.False(t, state.Confirming())
+		mockRepo.AssertExpectations(t)
+		mockEventBus.AssertExpectations(t)
+	})
+
+	t.Run("heartbeat missed since creation publishes", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		mockEventBus := new(MockEventBus)
+		recorder := NewStatusRecorder(mockRepo, mockEventBus, zap.NewNop())
+
+		mockRepo.On("GetStatusState", mock.Anything, 4).Return(StatusState{}, nil)
+		mockRepo.On("CreateHealthCheck", mock.Anything, mock.Anything).Return(nil)
+		mockRepo.On("SaveStatusState", mock.Anything, 4, StatusState{Status: StatusDown}).Return(nil)
+		mockEventBus.On("Publish", mock.Anything, mock.MatchedBy(func(event StatusChangeEvent) bool {
+			return event.OldStatus == StatusUp && event.NewStatus == StatusDown && event.ErrorClass == ErrorClassMissedHeartbeat
+		})).Return(nil)
+
+		job := CheckJob{ServiceID: 4, UserID: 9, Type: CheckTypeHeartbeat}
+		_, err := recorder.Record(context.Background(), job, NewHeartbeatChecker(stubDeadlines{overdue: true}).Check(context.Background(), job))
+
+		assert.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+		mockEventBus.AssertExpectations(t)
+	})
+
+	t.Run("first check of a probed service is adopted", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		mockEventBus := new(MockEventBus)
+		recorder := NewStatusRecorder(mockRepo, mockEventBus, zap.NewNop())
+
+		mockRepo.On("GetStatusState", mock.Anything, 4).Return(StatusState{}, nil)
+		mockRepo.On("CreateHealthCheck", mock.Anything, mock.Anything).Return(nil)
+		mockRepo.On("SaveStatusState", mock.Anything, 4, StatusState{Status: StatusDown}).Return(nil)
+
+		_, err := recorder.Record(context.Background(), CheckJob{ServiceID: 4, UserID: 9}, CheckResult{Status: StatusDown})
+
+		assert.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+		mockEventBus.AssertNotCalled(t, "Publish")
+	})
+
+	t.Run("without event bus only persists", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		recorder := NewStatusRecorder(mockRepo, nil, zap.NewNop())
+
+		mockRepo.On("GetStatusState", mock.Anything, 4).Return(StatusState{Status: "DOWN"}, nil)
+		mockRepo.On("SaveStatusState", mock.Anything, 4, mock.Anything).Return(nil)
+		mockRepo.On("CreateHealthCheck", mock.Anything, mock.Anything).Return(nil)
+
+		_, err := recorder.Record(context.Background(), CheckJob{ServiceID: 4, UserID: 9}, CheckResult{Status: "UP"})
+
+		assert.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("persist error is returned", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		mockEventBus := new(MockEventBus)
+		recorder := NewStatusRecorder(mockRepo, mockEventBus, zap.NewNop())
+
+		mockRepo.On("GetStatusState", mock.Anything, 4).Return(StatusState{Status: "DOWN"}, nil)
+		mockRepo.On("CreateHealthCheck", mock.Anything, mock.Anything).Return(errors.New("db error"))
+
+		_, err := recorder.Record(context.Background(), CheckJob{ServiceID: 4, UserID: 9}, CheckResult{Status: "UP"})
+
+		assert.Error(t, err)
+		mockEventBus.AssertNotCalled(t, "Publish")
+	})
+}
@@ -0,0 +1,287 @@
+package monitor
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+)
+
+var ErrInvalidWindow = errors.New("invalid report window")
+
+// maxReportRange bounds custom report windows, which are computed from raw checks.
+const maxReportRange = 366 * 24 * time.Hour
+
+// reportWindows are the preset windows a report can cover, ending now.
+var reportWindows = map[string]time.Duration{
+	"24h": 24 * time.Hour,
+	"7d":  7 * 24 * time.Hour,
+	"30d": 30 * 24 * time.Hour,
+}
+
+// ReportWindow selects the time range of a report: a preset window ending
+// now, or a custom range from From to To, which defaults to now. Without
+// either it is the last 24 hours.
+type ReportWindow struct {
+	Window string     `form:"window" binding:"omitempty,oneof=24h 7d 30d"`
+	From   *time.Time `form:"from" time_format:"2006-01-02T15:04:05Z07:00"`
+	To     *time.Time `form:"to" time_format:"2006-01-02T15:04:05Z07:00"`
+}
+
+// Range returns the start and end of the window. Errors wrap ErrInvalidWindow.
+func (w ReportWindow) Range(now time.Time) (time.Time, time.Time, error) {
+	if w.Window != "" && (w.From != nil || w.To != nil) {
+		return time.Time{}, time.Time{}, fmt.Errorf("%w: window cannot be combined with from and to", ErrInvalidWindow)
+	}
+	if w.From == nil {
+		if w.To != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("%w: to requires from", ErrInvalidWindow)
+		}
+		window := w.Window
+		if window == "" {
+			window = "24h"
+		}
+		return now.Add(-reportWindows[window]), now, nil
+	}
+
+	from, to := *w.From, now
+	if w.To != nil && w.To.Before(now) {
+		to = *w.To
+	}
+	switch {
+	case !from.Before(to):
+		return time.Time{}, time.Time{}, fmt.Errorf("%w: from must be before to", ErrInvalidWindow)
+	case to.Sub(from) > maxReportRange:
+		return time.Time{}, time.Time{}, fmt.Errorf("%w: a report covers at most 366 days", ErrInvalidWindow)
+	}
+	return from, to, nil
+}
+
+// StatusPeriod is a stretch of time during which every check of a service
+// reported the same status.
+type StatusPeriod struct {
+	Status    Status
+	StartedAt time.Time
+	EndedAt   time.Time
+}
+
+func (p StatusPeriod) duration() time.Duration {
+	return p.EndedAt.Sub(p.StartedAt)
+}
+
+// IncidentSpan is when an incident of a service started and, once it is
+// over, when it was resolved.
+type IncidentSpan struct {
+	StartedAt  time.Time
+	ResolvedAt *time.Time
+}
+
+// UptimeReport is the availability of a service over a window. Uptime is
+// computed from its health checks, each check's status being taken to hold
+// until the next one, and from the hourly rollups of the checks that are no
+// longer kept, each hour being DOWN or DEGRADED for the share of its checks
+// that were; incidents are those recorded once the service was confirmed DOWN.
+type UptimeReport struct {
+	ServiceID int       `json:"service_id" example:"1"`
+	From      time.Time `json:"from"`
+	To        time.Time `json:"to"`
+	// UptimePercent is the share of the monitored time the service was not
+	// DOWN; DEGRADED counts as up. It is null when no check covers the window.
+	UptimePercent *float64 `json:"uptime_percent" example:"99.95"`
+	// MonitoredSeconds is the part of the window covered by checks; it is
+	// shorter than the window when the service was created during it.
+	MonitoredSeconds int64 `json:"monitored_seconds" example:"604800"`
+	DowntimeSeconds  int64 `json:"downtime_seconds" example:"302"`
+	DegradedSeconds  int64 `json:"degraded_seconds" example:"1200"`
+	// Incidents is how many incidents were open during the window, counting
+	// one already under way when it started.
+	Incidents int `json:"incidents" example:"2"`
+	// MTTRSeconds is the mean time to resolve the incidents that were resolved
+	// in the window, from start to resolution. It is null without any.
+	MTTRSeconds *int64 `json:"mttr_seconds" example:"151"`
+	// MTBFSeconds is the mean time between failures: the time the service was
+	// up divided by the number of incidents. It is null without incidents.
+	MTBFSeconds *int64 `json:"mtbf_seconds" example:"302249"`
+}
+
+// newUptimeReport summarizes the rollups, status periods and incidents of a
+// service between from and to. The rollups cover the start of the window
+// and the periods the rest of it.
+func newUptimeReport(serviceID int, from, to time.Time, rollups []Rollup, periods []StatusPeriod, incidents []IncidentSpan) UptimeReport {
+	report := UptimeReport{ServiceID: serviceID, From: from, To: to, Incidents: len(incidents)}
+
+	var monitored, down, degraded time.Duration
+	for _, rollup := range rollups {
+		start, end := rollup.Start, rollup.End
+		if start.Before(from) {
+			start = from
+		}
+		if end.After(to) {
+			end = to
+		}
+		if rollup.CheckCount == 0 || !start.Before(end) {
+			continue
+		}
+		span := end.Sub(start)
+		monitored += span
+		down += span * time.Duration(rollup.FailureCount) / time.Duration(rollup.CheckCount)
+		degraded += span * time.Duration(rollup.DegradedCount) / time.Duration(rollup.CheckCount)
+	}
+	for _, period := range periods {
+		monitored += period.duration()
+		switch period.Status {
+		case StatusDown:
+			down += period.duration()
+		case StatusDegraded:
+			degraded += period.duration()
+		}
+	}
+
+	var repair time.Duration
+	resolved := 0
+	for _, incident := range incidents {
+		if incident.ResolvedAt == nil || incident.ResolvedAt.After(to) {
+			continue
+		}
+		repair += incident.ResolvedAt.Sub(incident.StartedAt)
+		resolved++
+	}
+
+	report.MonitoredSeconds = int64(monitored.Seconds())
+	report.DowntimeSeconds = int64(down.Seconds())
+	report.DegradedSeconds = int64(degraded.Seconds())
+	if monitored > 0 {
+		uptime := 100 * float64(monitored-down) / float64(monitored)
+		report.UptimePercent = &uptime
+	}
+	if resolved > 0 {
+		mttr := int64((repair / time.Duration(resolved)).Seconds())
+		report.MTTRSeconds = &mttr
+	}
+	if report.Incidents > 0 {
+		mtbf := int64(((monitored - down) / time.Duration(report.Incidents)).Seconds())
+		report.MTBFSeconds = &mtbf
+	}
+	return report
+}
+
+// latencyBuckets are the bucket sizes a latency series can be split into.
+var latencyBuckets = map[string]time.Duration{
+	"1m":  time.Minute,
+	"5m":  5 * time.Minute,
+	"15m": 15 * time.Minute,
+	"1h":  time.Hour,
+	"6h":  6 * time.Hour,
+	"1d":  24 * time.Hour,
+}
+
+// maxLatencyBuckets bounds how many points a latency series has.
+const maxLatencyBuckets = 1000
+
+// LatencyQuery selects the window of a latency report and the size of its
+// buckets. Without a bucket size, the smallest one giving at most 300 buckets
+// is used.
+type LatencyQuery struct {
+	ReportWindow
+	Bucket string `form:"bucket" binding:"omitempty,oneof=1m 5m 15m 1h 6h 1d"`
+}
+
+// bucketSize returns the bucket size for a window of span, which is at least
+// minSize. Errors wrap ErrInvalidWindow.
+func (q LatencyQuery) bucketSize(span, minSize time.Duration) (time.Duration, error) {
+	if q.Bucket != "" {
+		size := latencyBuckets[q.Bucket]
+		if size < minSize {
+			return 0, fmt.Errorf("%w: %s buckets are only available over the health checks still kept, this window needs %s or more", ErrInvalidWindow, q.Bucket, minSize)
+		}
+		if span/size > maxLatencyBuckets {
+			return 0, fmt.Errorf("%w: %s buckets over this window exceed %d", ErrInvalidWindow, q.Bucket, maxLatencyBuckets)
+		}
+		return size, nil
+	}
+	for _, size := range []time.Duration{time.Minute, 5 * time.Minute, 15 * time.Minute, time.Hour, 6 * time.Hour} {
+		if size >= minSize && span/size <= 300 {
+			return size, nil
+		}
+	}
+	return 24 * time.Hour, nil
+}
+
+// LatencyStats summarizes the latency of a set of checks, in milliseconds.
+type LatencyStats struct {
+	Count  int     `json:"count" example:"288"`
+	MinMs  int     `json:"min_ms" example:"84"`
+	MaxMs  int     `json:"max_ms" example:"2310"`
+	MeanMs float64 `json:"mean_ms" example:"161.4"`
+	P50Ms  int     `json:"p50_ms" example:"120"`
+	P90Ms  int     `json:"p90_ms" example:"240"`
+	P95Ms  int     `json:"p95_ms" example:"410"`
+	P99Ms  int     `json:"p99_ms" example:"1900"`
+}
+
+// mergeLatency combines the latency of two sets of checks. Min, max and mean
+// are exact; percentiles are averaged weighted by count, which approximates
+// those of the combined set.
+func mergeLatency(a, b LatencyStats) LatencyStats {
+	if a.Count == 0 {
+		return b
+	}
+	if b.Count == 0 {
+		return a
+	}
+	count := a.Count + b.Count
+	weigh := func(x, y int) int {
+		return int(math.Round(float64(x*a.Count+y*b.Count) / float64(count)))
+	}
+	return LatencyStats{
+		Count:  count,
+		MinMs:  min(a.MinMs, b.MinMs),
+		MaxMs:  max(a.MaxMs, b.MaxMs),
+		MeanMs: (a.MeanMs*float64(a.Count) + b.MeanMs*float64(b.Count)) / float64(count),
+		P50Ms:  weigh(a.P50Ms, b.P50Ms),
+		P90Ms:  weigh(a.P90Ms, b.P90Ms),
+		P95Ms:  weigh(a.P95Ms, b.P95Ms),
+		P99Ms:  weigh(a.P99Ms, b.P99Ms),
+	}
+}
+
+// rollupLatency summarizes the latency of rollups, overall and merged into
+// buckets of the given size aligned on the Unix epoch. The rollups must be in
+// order and no larger than a bucket.
+func rollupLatency(rollups []Rollup, bucket time.Duration) (LatencyStats, []LatencyBucket) {
+	var overall LatencyStats
+	buckets := []LatencyBucket{}
+	for _, rollup := range rollups {
+		if rollup.Latency.Count == 0 {
+			continue
+		}
+		overall = mergeLatency(overall, rollup.Latency)
+		start := rollup.Start.UTC().Truncate(bucket)
+		if n := len(buckets); n > 0 && buckets[n-1].Start.Equal(start) {
+			buckets[n-1].LatencyStats = mergeLatency(buckets[n-1].LatencyStats, rollup.Latency)
+			continue
+		}
+		buckets = append(buckets, LatencyBucket{Start: start, LatencyStats: rollup.Latency})
+	}
+	return overall, buckets
+}
+
+// LatencyBucket is the latency of the checks that ran from Start for one
+// bucket size.
+type LatencyBucket struct {
+	Start time.Time `json:"start"`
+	LatencyStats
+}
+
+// LatencyReport is the latency of a service over a window, overall and as a
+// time series. Buckets without checks are left out. Where the health checks
+// are no longer kept it is computed from their rollups, so percentiles are
+// approximate there.
+type LatencyReport struct {
+	ServiceID     int             `json:"service_id" example:"1"`
+	From          time.Time       `json:"from"`
+	To            time.Time       `json:"to"`
+	BucketSeconds int             `json:"bucket_seconds" example:"300"`
+	Overall       LatencyStats    `json:"overall"`
+	Buckets       []LatencyBucket `json:"buckets"`
+}
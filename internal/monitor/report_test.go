@@ -0,0 +1,215 @@
+package monitor
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReportWindow_Range(t *testing.T) {
+	now := time.Date(2024, 5, 8, 12, 0, 0, 0, time.UTC)
+	at := func(days int) *time.Time {
+		t := now.AddDate(0, 0, days)
+		return &t
+	}
+
+	tests := []struct {
+		name     string
+		window   ReportWindow
+		from, to time.Time
+		wantErr  bool
+	}{
+		{name: "defaults to 24h", window: ReportWindow{}, from: now.Add(-24 * time.Hour), to: now},
+		{name: "preset", window: ReportWindow{Window: "7d"}, from: *at(-7), to: now},
+		{name: "custom", window: ReportWindow{From: at(-10), To: at(-3)}, from: *at(-10), to: *at(-3)},
+		{name: "custom until now", window: ReportWindow{From: at(-3)}, from: *at(-3), to: now},
+		{name: "custom ending in the future", window: ReportWindow{From: at(-3), To: at(2)}, from: *at(-3), to: now},
+		{name: "preset and custom", window: ReportWindow{Window: "7d", From: at(-3)}, wantErr: true},
+		{name: "to without from", window: ReportWindow{To: at(-3)}, wantErr: true},
+		{name: "from after to", window: ReportWindow{From: at(-3), To: at(-10)}, wantErr: true},
+		{name: "over a year", window: ReportWindow{From: at(-400)}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			from, to, err := tt.window.Range(now)
+			if tt.wantErr {
+				assert.True(t, errors.Is(err, ErrInvalidWindow))
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.from, from)
+			assert.Equal(t, tt.to, to)
+		})
+	}
+}
+
+func TestNewUptimeReport(t *testing.T) {
+	from := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	to := from.Add(10 * time.Hour)
+	hour := func(h float64) time.Time { return from.Add(time.Duration(h * float64(time.Hour))) }
+	resolvedAt := func(h float64) *time.Time { at := hour(h); return &at }
+
+	t.Run("outages and degradation", func(t *testing.T) {
+		report := newUptimeReport(3, from, to, nil, []StatusPeriod{
+			{Status: StatusUp, StartedAt: hour(0), EndedAt: hour(2)},
+			{Status: StatusDown, StartedAt: hour(2), EndedAt: hour(2.5)},
+			{Status: StatusDegraded, StartedAt: hour(2.5), EndedAt: hour(4)},
+			{Status: StatusUp, StartedAt: hour(4), EndedAt: hour(8)},
+			{Status: StatusDown, StartedAt: hour(8), EndedAt: hour(9)},
+			{Status: StatusUp, StartedAt: hour(9), EndedAt: hour(10)},
+		}, []IncidentSpan{
+			{StartedAt: hour(2.25), ResolvedAt: resolvedAt(2.5)},
+			{StartedAt: hour(8.25), ResolvedAt: resolvedAt(9)},
+		})
+
+		assert.Equal(t, 3, report.ServiceID)
+		assert.Equal(t, int64(36000), report.MonitoredSeconds)
+		assert.Equal(t, int64(5400), report.DowntimeSeconds)
+		assert.Equal(t, int64(5400), report.DegradedSeconds)
+		require.NotNil(t, report.UptimePercent)
+		assert.InDelta(t, 85, *report.UptimePercent, 0.001)
+		assert.Equal(t, 2, report.Incidents)
+		require.NotNil(t, report.MTTRSeconds)
+		assert.Equal(t, int64(1800), *report.MTTRSeconds)
+		require.NotNil(t, report.MTBFSeconds)
+		assert.Equal(t, int64(15300), *report.MTBFSeconds)
+	})
+
+	t.Run("unconfirmed failures are not incidents", func(t *testing.T) {
+		report := newUptimeReport(3, from, to, nil, []StatusPeriod{
+			{Status: StatusUp, StartedAt: hour(0), EndedAt: hour(5)},
+			{Status: StatusDown, StartedAt: hour(5), EndedAt: hour(5.5)},
+			{Status: StatusUp, StartedAt: hour(5.5), EndedAt: hour(10)},
+		}, nil)
+
+		assert.Equal(t, int64(1800), report.DowntimeSeconds)
+		assert.Zero(t, report.Incidents)
+		assert.Nil(t, report.MTTRSeconds)
+		assert.Nil(t, report.MTBFSeconds)
+	})
+
+	t.Run("incident started before the window", func(t *testing.T) {
+		report := newUptimeReport(3, from, to, nil, []StatusPeriod{
+			{Status: StatusDown, StartedAt: hour(0), EndedAt: hour(1)},
+			{Status: StatusUp, StartedAt: hour(1), EndedAt: hour(10)},
+		}, []IncidentSpan{
+			{StartedAt: hour(-1), ResolvedAt: resolvedAt(1)},
+		})
+
+		assert.Equal(t, 1, report.Incidents)
+		require.NotNil(t, report.MTTRSeconds)
+		assert.Equal(t, int64(2*3600), *report.MTTRSeconds)
+	})
+
+	t.Run("ongoing incident has not been resolved", func(t *testing.T) {
+		report := newUptimeReport(3, from, to, nil, []StatusPeriod{
+			{Status: StatusUp, StartedAt: hour(0), EndedAt: hour(9)},
+			{Status: StatusDown, StartedAt: hour(9), EndedAt: hour(10)},
+		}, []IncidentSpan{
+			{StartedAt: hour(9.25)},
+		})
+
+		assert.Equal(t, 1, report.Incidents)
+		assert.Nil(t, report.MTTRSeconds)
+		require.NotNil(t, report.MTBFSeconds)
+		assert.Equal(t, int64(9*3600), *report.MTBFSeconds)
+	})
+
+	t.Run("rollups before the checks", func(t *testing.T) {
+		report := newUptimeReport(3, hour(-0.5), to, []Rollup{
+			{Start: hour(-1), End: hour(0), CheckCount: 60, FailureCount: 30},
+			{Start: hour(0), End: hour(1), CheckCount: 60, FailureCount: 6, DegradedCount: 12},
+			{Start: hour(1), End: hour(2)},
+		}, []StatusPeriod{
+			{Status: StatusUp, StartedAt: hour(2), EndedAt: hour(10)},
+		}, nil)
+
+		// Only the half of the first hour inside the window counts; the
+		// hour without checks is not monitored.
+		assert.Equal(t, int64(9.5*3600), report.MonitoredSeconds)
+		assert.Equal(t, int64(0.25*3600+0.1*3600), report.DowntimeSeconds)
+		assert.Equal(t, int64(0.2*3600), report.DegradedSeconds)
+	})
+
+	t.Run("no checks", func(t *testing.T) {
+		report := newUptimeReport(3, from, to, nil, nil, nil)
+
+		assert.Nil(t, report.UptimePercent)
+		assert.Zero(t, report.Incidents)
+		assert.Nil(t, report.MTTRSeconds)
+		assert.Nil(t, report.MTBFSeconds)
+	})
+}
+
+func TestLatencyQuery_BucketSize(t *testing.T) {
+	tests := []struct {
+		name    string
+		bucket  string
+		span    time.Duration
+		minSize time.Duration
+		want    time.Duration
+		wantErr bool
+	}{
+		{name: "default for 24h", span: 24 * time.Hour, want: 5 * time.Minute},
+		{name: "default for 7d", span: 7 * 24 * time.Hour, want: time.Hour},
+		{name: "default for 30d", span: 30 * 24 * time.Hour, want: 6 * time.Hour},
+		{name: "default for a year", span: 366 * 24 * time.Hour, want: 24 * time.Hour},
+		{name: "default for an hour", span: time.Hour, want: time.Minute},
+		{name: "explicit", bucket: "15m", span: 24 * time.Hour, want: 15 * time.Minute},
+		{name: "too many buckets", bucket: "1m", span: 7 * 24 * time.Hour, wantErr: true},
+		{name: "default over rollups", span: 24 * time.Hour, minSize: time.Hour, want: time.Hour},
+		{name: "explicit over rollups", bucket: "6h", span: 24 * time.Hour, minSize: time.Hour, want: 6 * time.Hour},
+		{name: "too small for rollups", bucket: "5m", span: 24 * time.Hour, minSize: time.Hour, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := LatencyQuery{Bucket: tt.bucket}.bucketSize(tt.span, tt.minSize)
+			if tt.wantErr {
+				assert.True(t, errors.Is(err, ErrInvalidWindow))
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestMergeLatency(t *testing.T) {
+	a := LatencyStats{Count: 3, MinMs: 100, MaxMs: 300, MeanMs: 200, P50Ms: 200, P90Ms: 300, P95Ms: 300, P99Ms: 300}
+	b := LatencyStats{Count: 1, MinMs: 40, MaxMs: 40, MeanMs: 40, P50Ms: 40, P90Ms: 40, P95Ms: 40, P99Ms: 40}
+
+	assert.Equal(t, LatencyStats{Count: 4, MinMs: 40, MaxMs: 300, MeanMs: 160, P50Ms: 160, P90Ms: 235, P95Ms: 235, P99Ms: 235}, mergeLatency(a, b))
+	assert.Equal(t, a, mergeLatency(a, LatencyStats{}))
+	assert.Equal(t, b, mergeLatency(LatencyStats{}, b))
+}
+
+func TestRollupLatency(t *testing.T) {
+	from := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	hour := func(h int) time.Time { return from.Add(time.Duration(h) * time.Hour) }
+	stats := func(count, ms int) LatencyStats {
+		return LatencyStats{Count: count, MinMs: ms, MaxMs: ms, MeanMs: float64(ms), P50Ms: ms, P90Ms: ms, P95Ms: ms, P99Ms: ms}
+	}
+
+	overall, buckets := rollupLatency([]Rollup{
+		{Start: hour(4), Latency: stats(1, 100)},
+		{Start: hour(5), Latency: stats(3, 200)},
+		{Start: hour(6)},
+		{Start: hour(7), Latency: stats(2, 50)},
+	}, 6*time.Hour)
+
+	assert.Equal(t, 6, overall.Count)
+	assert.Equal(t, 50, overall.MinMs)
+	assert.Equal(t, 200, overall.MaxMs)
+	assert.InDelta(t, 133.3, overall.MeanMs, 0.1)
+	require.Len(t, buckets, 2)
+	assert.Equal(t, hour(0), buckets[0].Start)
+	assert.Equal(t, 4, buckets[0].Count)
+	assert.Equal(t, 175.0, buckets[0].MeanMs)
+	assert.Equal(t, hour(6), buckets[1].Start)
+	assert.Equal(t, stats(2, 50), buckets[1].LatencyStats)
+}
@@ -2,18 +2,53 @@ package monitor
 
 import (
 	"context"
+	"fmt"
+	"time"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
 type Repository interface {
-	Create(ctx context.Context, service Service) error
-	ListServices(ctx context.Context) ([]Service, error)
+	Create(ctx context.Context, service Service) (Service, error)
+	ListServices(ctx context.Context, owner Owner) ([]Service, error)
+	GetService(ctx context.Context, owner Owner, id int) (Service, error)
+	UpdateService(ctx context.Context, owner Owner, service Service) (Service, error)
+	DeleteService(ctx context.Context, owner Owner, id int) error
+	SetPaused(ctx context.Context, owner Owner, id int, paused bool) (Service, error)
 	ClaimDueServices(ctx context.Context) ([]Service, error)
 	CreateHealthCheck(ctx context.Context, check HealthCheck) error
 	GetHealthChecksByServiceID(ctx context.Context, serviceID, page, limit int) ([]HealthCheck, error)
 	GetLatestHealthCheck(ctx context.Context, serviceID int) (*HealthCheck, error)
+	GetStatusState(ctx context.Context, serviceID int) (StatusState, error)
+	SaveStatusState(ctx context.Context, serviceID int, state StatusState) error
+	RecordHeartbeat(ctx context.Context, token string) (Service, error)
+	HeartbeatOverdue(ctx context.Context, serviceID int) (bool, error)
+	GetStatusPeriods(ctx context.Context, serviceID int, from, to time.Time) ([]StatusPeriod, error)
+	GetIncidentSpans(ctx context.Context, serviceID int, from, to time.Time) ([]IncidentSpan, error)
+	GetLatencyStats(ctx context.Context, serviceID int, from, to time.Time, bucket time.Duration) (LatencyStats, []LatencyBucket, error)
+	NextRollupStart(ctx context.Context, resolution RollupResolution) (time.Time, error)
+	RollupHealthChecks(ctx context.Context, resolution RollupResolution, from, to time.Time) (int64, error)
+	GetRollups(ctx context.Context, serviceID int, resolution RollupResolution, from, to time.Time) ([]Rollup, error)
+	DeleteHealthChecksBefore(ctx context.Context, before time.Time, limit int) (int64, error)
+}
+
+const serviceColumns = `id, coalesce(user_id, 0), coalesce(organization_id, 0), name, type, url, host, port, config, timeout, auth_encrypted, check_interval, paused, next_run_at,
+	created_at, coalesce(heartbeat_token, ''), grace_period, last_ping_at, tags, coalesce(status, ''), failure_threshold,
+	success_threshold, recheck, latency_warn_ms, latency_critical_ms`
+
+const healthCheckColumns = `id, service_id, status, latency, details, created_at, coalesce(status_code, 0),
+	coalesce(error_class, ''), coalesce(message, ''), coalesce(body_snippet, ''), timing`
+
+// answeredCheck matches the checks whose latency is a response time: those
+// that failed before getting an answer only measure how long failing took,
+// while those too slow to be UP are kept.
+const answeredCheck = `(status <> 'DOWN' or error_class = 'slow_response')`
+
+// ownedBy restricts a query to the services of an Owner whose OrgID and
+// UserID are bound at positions arg and arg+1.
+func ownedBy(arg int) string {
+	return fmt.Sprintf("coalesce(organization_id, 0) = $%d and ($%d <> 0 or user_id = $%d)", arg, arg, arg+1)
 }
 
 type PostgresRepository struct {
@@ -24,23 +59,30 @@ func NewRepository(db *pgxpool.Pool) Repository {
 	return &PostgresRepository{db: db}
 }
 
-func (r *PostgresRepository) Create(ctx context.Context, service Service) error {
+func (r *PostgresRepository) Create(ctx context.Context, service Service) (Service, error) {
 	query := `
-		INSERT INTO services (name, url, check_interval, next_run_at)
-		VALUES ($1, $2, $3, $4)
+		INSERT INTO services (user_id, name, type, url, host, port, config, timeout, auth_encrypted, check_interval,
+			next_run_at, heartbeat_token, grace_period, organization_id, tags, failure_threshold, success_threshold, recheck,
+			latency_warn_ms, latency_critical_ms)
+		VALUES (nullif($1, 0), $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, nullif($12, ''), $13, nullif($14, 0), $15, $16,
+			$17, $18, $19, $20)
+		returning ` + serviceColumns + `
 	`
 
-	_, err := r.db.Exec(ctx, query, service.Name, service.URL, service.CheckInterval, service.NextRunAt)
-	return err
+	return scanService(r.db.QueryRow(ctx, query, service.UserID, service.Name, service.Type, service.URL, service.Host,
+		service.Port, service.Config, service.Timeout, service.EncryptedAuth, service.CheckInterval, service.NextRunAt,
+		service.HeartbeatToken, service.GracePeriod, service.OrganizationID, tagsOf(service), service.FailureThreshold,
+		service.SuccessThreshold, service.Recheck, service.LatencyWarnMs, service.LatencyCriticalMs))
 }
 
-func (r *PostgresRepository) ListServices(ctx context.Context) ([]Service, error) {
+func (r *PostgresRepository) ListServices(ctx context.Context, owner Owner) ([]Service, error) {
 	query := `
-		SELECT id, name, url, check_interval, next_run_at, created_at
+		SELECT ` + serviceColumns + `
 		FROM services
+		WHERE ` + ownedBy(1) + `
 		order by created_at desc
 	`
-	rows, err := r.db.Query(ctx, query)
+	rows, err := r.db.Query(ctx, query, owner.OrgID, owner.UserID)
 	if err != nil {
 		return nil, err
 	}
@@ -48,8 +90,7 @@ func (r *PostgresRepository) ListServices(ctx context.Context) ([]Service, error
 
 	var services []Service
 	for rows.Next() {
-		var service Service
-		err := rows.Scan(&service.ID, &service.Name, &service.URL, &service.CheckInterval, &service.NextRunAt, &service.CreatedAt)
+		service, err := scanService(rows)
 		if err != nil {
 			return nil, err
 		}
@@ -59,16 +100,95 @@ func (r *PostgresRepository) ListServices(ctx context.Context) ([]Service, error
 	return services, nil
 }
 
+func (r *PostgresRepository) GetService(ctx context.Context, owner Owner, id int) (Service, error) {
+	query := `
+		SELECT ` + serviceColumns + `
+		FROM services
+		WHERE id = $1 AND ` + ownedBy(2) + `
+	`
+	service, err := scanService(r.db.QueryRow(ctx, query, id, owner.OrgID, owner.UserID))
+	if err == pgx.ErrNoRows {
+		return Service{}, ErrServiceNotFound
+	}
+	return service, err
+}
+
+// UpdateService saves the editable fields of service. The check type and pause
+// state are left untouched. Changing the check interval or grace period
+// reschedules the service one interval from now, or for a heartbeat service
+// one interval plus grace period after its last ping, or its creation when it
+// never pinged.
+func (r *PostgresRepository) UpdateService(ctx context.Context, owner Owner, service Service) (Service, error) {
+	query := `
+		update services
+		set name = $2, url = $3, host = $4, port = $5, config = $6, timeout = $7, auth_encrypted = $8,
+			check_interval = $9, grace_period = $10, tags = $11, failure_threshold = $12, success_threshold = $13,
+			recheck = $14, latency_warn_ms = $15, latency_critical_ms = $16,
+			next_run_at = case
+				when check_interval = $9 and grace_period = $10 then next_run_at
+				when type = 'heartbeat' then coalesce(last_ping_at, created_at) + make_interval(secs => $9 + $10)
+				else now() + make_interval(secs => $9)
+			end
+		where id = $1 and ` + ownedBy(17) + `
+		returning ` + serviceColumns + `
+	`
+	updated, err := scanService(r.db.QueryRow(ctx, query, service.ID, service.Name, service.URL, service.Host,
+		service.Port, service.Config, service.Timeout, service.EncryptedAuth, service.CheckInterval, service.GracePeriod,
+		tagsOf(service), service.FailureThreshold, service.SuccessThreshold, service.Recheck,
+		service.LatencyWarnMs, service.LatencyCriticalMs, owner.OrgID, owner.UserID))
+	if err == pgx.ErrNoRows {
+		return Service{}, ErrServiceNotFound
+	}
+	return updated, err
+}
+
+// DeleteService removes a service; its health checks are removed by the foreign key cascade.
+func (r *PostgresRepository) DeleteService(ctx context.Context, owner Owner, id int) error {
+	tag, err := r.db.Exec(ctx, `DELETE FROM services WHERE id = $1 AND `+ownedBy(2), id, owner.OrgID, owner.UserID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrServiceNotFound
+	}
+	return nil
+}
+
+// SetPaused pauses or resumes a service. Resuming makes a probed service due
+// immediately and gives a heartbeat service a fresh deadline.
+func (r *PostgresRepository) SetPaused(ctx context.Context, owner Owner, id int, paused bool) (Service, error) {
+	query := `
+		update services
+		set paused = $2,
+			next_run_at = case
+				when $2 then next_run_at
+				when type = 'heartbeat' then now() + make_interval(secs => check_interval + grace_period)
+				else now()
+			end
+		where id = $1 and ` + ownedBy(3) + `
+		returning ` + serviceColumns + `
+	`
+	service, err := scanService(r.db.QueryRow(ctx, query, id, paused, owner.OrgID, owner.UserID))
+	if err == pgx.ErrNoRows {
+		return Service{}, ErrServiceNotFound
+	}
+	return service, err
+}
+
+// ClaimDueServices reschedules and returns the services due for a check.
+// Services without an owner, registered before ownership existed, are skipped
+// since nobody could see their results.
 func (r *PostgresRepository) ClaimDueServices(ctx context.Context) ([]Service, error) {
 	query := `
 		update services 
-		set next_run_at = now() + make_interval(secs => check_interval)
+		set next_run_at = now() + make_interval(secs => check_interval + grace_period)
 		where id in (
 			select id from services 
-			where next_run_at <= now()
+			where next_run_at <= now() and not paused
+				and (user_id is not null or organization_id is not null)
 			for update skip locked
 		)
-		returning id, name, url, check_interval, next_run_at, created_at
+		returning ` + serviceColumns + `
 	`
 	tx, err := r.db.BeginTx(ctx, pgx.TxOptions{})
 	if err != nil {
@@ -85,8 +205,7 @@ func (r *PostgresRepository) ClaimDueServices(ctx context.Context) ([]Service, e
 
 	var services []Service
 	for rows.Next() {
-		var service Service
-		err := rows.Scan(&service.ID, &service.Name, &service.URL, &service.CheckInterval, &service.NextRunAt, &service.CreatedAt)
+		service, err := scanService(rows)
 		if err != nil {
 			return nil, err
 		}
@@ -98,16 +217,18 @@ func (r *PostgresRepository) ClaimDueServices(ctx context.Context) ([]Service, e
 
 func (r *PostgresRepository) CreateHealthCheck(ctx context.Context, check HealthCheck) error {
 	query := `
-		INSERT INTO health_checks (service_id, status, latency)
-		VALUES ($1, $2, $3)
+		INSERT INTO health_checks (service_id, status, latency, details, status_code, error_class, message, body_snippet,
+			timing)
+		VALUES ($1, $2, $3, $4, nullif($5, 0), nullif($6, ''), nullif($7, ''), nullif($8, ''), $9)
 	`
-	_, err := r.db.Exec(ctx, query, check.ServiceID, check.Status, check.Latency)
+	_, err := r.db.Exec(ctx, query, check.ServiceID, check.Status, check.Latency, check.Details, check.StatusCode,
+		check.ErrorClass, check.Message, check.BodySnippet, check.Timing)
 	return err
 }
 
 func (r *PostgresRepository) GetHealthChecksByServiceID(ctx context.Context, serviceID, page, limit int) ([]HealthCheck, error) {
 	query := `
-		SELECT id, service_id, status, latency, created_at
+		SELECT ` + healthCheckColumns + `
 		FROM health_checks
 		WHERE service_id = $1
 		ORDER BY created_at DESC
@@ -121,8 +242,7 @@ func (r *PostgresRepository) GetHealthChecksByServiceID(ctx context.Context, ser
 
 	var checks []HealthCheck
 	for rows.Next() {
-		var check HealthCheck
-		err := rows.Scan(&check.ID, &check.ServiceID, &check.Status, &check.Latency, &check.CreatedAt)
+		check, err := scanHealthCheck(rows)
 		if err != nil {
 			return nil, err
 		}
@@ -134,14 +254,13 @@ func (r *PostgresRepository) GetHealthChecksByServiceID(ctx context.Context, ser
 
 func (r *PostgresRepository) GetLatestHealthCheck(ctx context.Context, serviceID int) (*HealthCheck, error) {
 	query := `
-		SELECT id, service_id, status, latency, created_at
+		SELECT ` + healthCheckColumns + `
 		FROM health_checks
 		WHERE service_id = $1
 		ORDER BY created_at DESC
 		LIMIT 1
 	`
-	var check HealthCheck
-	err := r.db.QueryRow(ctx, query, serviceID).Scan(&check.ID, &check.ServiceID, &check.Status, &check.Latency, &check.CreatedAt)
+	check, err := scanHealthCheck(r.db.QueryRow(ctx, query, serviceID))
 	if err != nil {
 		if err == pgx.ErrNoRows {
 			return nil, nil
@@ -151,3 +270,301 @@ func (r *PostgresRepository) GetLatestHealthCheck(ctx context.Context, serviceID
 
 	return &check, nil
 }
+
+// GetStatusState returns ErrServiceNotFound when the service does not exist.
+func (r *PostgresRepository) GetStatusState(ctx context.Context, serviceID int) (StatusState, error) {
+	query := `
+		select coalesce(status, ''), coalesce(pending_status, ''), pending_count, pending_since
+		from services
+		where id = $1
+	`
+	var state StatusState
+	err := r.db.QueryRow(ctx, query, serviceID).Scan(&state.Status, &state.PendingStatus, &state.PendingCount,
+		&state.PendingSince)
+	if err == pgx.ErrNoRows {
+		return StatusState{}, ErrServiceNotFound
+	}
+	return state, err
+}
+
+func (r *PostgresRepository) SaveStatusState(ctx context.Context, serviceID int, state StatusState) error {
+	query := `
+		update services
+		set status = nullif($2, ''), pending_status = nullif($3, ''), pending_count = $4, pending_since = $5
+		where id = $1
+	`
+	_, err := r.db.Exec(ctx, query, serviceID, state.Status, state.PendingStatus, state.PendingCount, state.PendingSince)
+	return err
+}
+
+// RecordHeartbeat stores a ping and pushes the heartbeat service's deadline out
+// by another interval plus grace period.
+func (r *PostgresRepository) RecordHeartbeat(ctx context.Context, token string) (Service, error) {
+	query := `
+		update services
+		set last_ping_at = now(), next_run_at = now() + make_interval(secs => check_interval + grace_period)
+		where heartbeat_token = $1 and type = 'heartbeat'
+		returning ` + serviceColumns + `
+	`
+	service, err := scanService(r.db.QueryRow(ctx, query, token))
+	if err == pgx.ErrNoRows {
+		return Service{}, ErrServiceNotFound
+	}
+	return service, err
+}
+
+// HeartbeatOverdue reports whether the heartbeat service's last ping, or its
+// creation when it never pinged, is older than its interval plus grace period.
+func (r *PostgresRepository) HeartbeatOverdue(ctx context.Context, serviceID int) (bool, error) {
+	query := `
+		select coalesce(last_ping_at, created_at) + make_interval(secs => check_interval + grace_period) <= now()
+		from services
+		where id = $1 and type = 'heartbeat'
+	`
+	var overdue bool
+	err := r.db.QueryRow(ctx, query, serviceID).Scan(&overdue)
+	if err == pgx.ErrNoRows {
+		return false, ErrServiceNotFound
+	}
+	return overdue, err
+}
+
+// GetStatusPeriods returns the stretches of time between from and to during
+// which the service's checks agreed, in order. The status at from is that of
+// the last check before it; the last period ends at to.
+func (r *PostgresRepository) GetStatusPeriods(ctx context.Context, serviceID int, from, to time.Time) ([]StatusPeriod, error) {
+	query := `
+		with checks as (
+			select status, created_at, lag(status) over (order by created_at) as previous_status
+			from health_checks
+			where service_id = $1 and created_at < $3 and created_at >= coalesce(
+				(select max(created_at) from health_checks where service_id = $1 and created_at <= $2), $2)
+		), changes as (
+			select status, created_at from checks where previous_status is distinct from status
+		)
+		select status, greatest(created_at, $2), lead(created_at, 1, $3) over (order by created_at)
+		from changes
+		order by created_at
+	`
+	rows, err := r.db.Query(ctx, query, serviceID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var periods []StatusPeriod
+	for rows.Next() {
+		var period StatusPeriod
+		if err := rows.Scan(&period.Status, &period.StartedAt, &period.EndedAt); err != nil {
+			return nil, err
+		}
+		periods = append(periods, period)
+	}
+	return periods, rows.Err()
+}
+
+// GetIncidentSpans returns the incidents of the service that were open at
+// some point between from and to, in the order they started.
+func (r *PostgresRepository) GetIncidentSpans(ctx context.Context, serviceID int, from, to time.Time) ([]IncidentSpan, error) {
+	query := `
+		select started_at, resolved_at
+		from incidents
+		where service_id = $1 and started_at < $3 and (resolved_at is null or resolved_at > $2)
+		order by started_at
+	`
+	rows, err := r.db.Query(ctx, query, serviceID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var incidents []IncidentSpan
+	for rows.Next() {
+		var incident IncidentSpan
+		if err := rows.Scan(&incident.StartedAt, &incident.ResolvedAt); err != nil {
+			return nil, err
+		}
+		incidents = append(incidents, incident)
+	}
+	return incidents, rows.Err()
+}
+
+// GetLatencyStats summarizes the latency of the service's checks between from
+// and to, overall and in buckets of the given size aligned on the Unix epoch.
+// Only answered checks are counted.
+func (r *PostgresRepository) GetLatencyStats(ctx context.Context, serviceID int, from, to time.Time, bucket time.Duration) (LatencyStats, []LatencyBucket, error) {
+	query := `
+		select to_timestamp(floor(extract(epoch from created_at) / $4) * $4) as bucket, count(*),
+			coalesce(min(latency), 0), coalesce(max(latency), 0), coalesce(avg(latency), 0)::float8,
+			coalesce(percentile_disc(array[0.5, 0.9, 0.95, 0.99]) within group (order by latency), array[0, 0, 0, 0])
+		from health_checks
+		where service_id = $1 and created_at >= $2 and created_at < $3
+			and ` + answeredCheck + `
+		group by grouping sets ((1), ())
+		order by 1 nulls first
+	`
+	rows, err := r.db.Query(ctx, query, serviceID, from, to, int(bucket.Seconds()))
+	if err != nil {
+		return LatencyStats{}, nil, err
+	}
+	defer rows.Close()
+
+	var overall LatencyStats
+	buckets := []LatencyBucket{}
+	for rows.Next() {
+		var (
+			start       *time.Time
+			stats       LatencyStats
+			percentiles []int
+		)
+		if err := rows.Scan(&start, &stats.Count, &stats.MinMs, &stats.MaxMs, &stats.MeanMs, &percentiles); err != nil {
+			return LatencyStats{}, nil, err
+		}
+		stats.P50Ms, stats.P90Ms, stats.P95Ms, stats.P99Ms = percentiles[0], percentiles[1], percentiles[2], percentiles[3]
+		// The grand total of the grouping sets has no bucket.
+		if start == nil {
+			overall = stats
+			continue
+		}
+		buckets = append(buckets, LatencyBucket{Start: *start, LatencyStats: stats})
+	}
+	return overall, buckets, rows.Err()
+}
+
+// NextRollupStart returns where rolling up health checks at resolution should
+// resume: the bucket after the latest rolled up one, or the bucket of the
+// oldest check. It returns the zero time when there is nothing to roll up.
+func (r *PostgresRepository) NextRollupStart(ctx context.Context, resolution RollupResolution) (time.Time, error) {
+	query := fmt.Sprintf(`
+		select coalesce(
+			(select max(bucket_start) + make_interval(secs => $1) from %s),
+			(select date_trunc('%s', created_at, 'UTC') from health_checks order by id limit 1)
+		)
+	`, resolution.table(), resolution)
+	var start *time.Time
+	if err := r.db.QueryRow(ctx, query, resolution.size().Seconds()).Scan(&start); err != nil {
+		return time.Time{}, err
+	}
+	if start == nil {
+		return time.Time{}, nil
+	}
+	return *start, nil
+}
+
+// RollupHealthChecks aggregates the health checks created between from and to
+// into buckets of the resolution, replacing the buckets already rolled up. It
+// returns the number of buckets written.
+func (r *PostgresRepository) RollupHealthChecks(ctx context.Context, resolution RollupResolution, from, to time.Time) (int64, error) {
+	query := fmt.Sprintf(`
+		insert into %s (service_id, bucket_start, check_count, failure_count, degraded_count, latency_count,
+			latency_min, latency_max, latency_mean, latency_p50, latency_p90, latency_p95, latency_p99)
+		select service_id, bucket_start, check_count, failure_count, degraded_count, latency_count,
+			latency_min, latency_max, latency_mean, percentiles[1], percentiles[2], percentiles[3], percentiles[4]
+		from (
+			select service_id, date_trunc('%s', created_at, 'UTC') as bucket_start, count(*) as check_count,
+				count(*) filter (where status = 'DOWN') as failure_count,
+				count(*) filter (where status = 'DEGRADED') as degraded_count,
+				count(*) filter (where %s) as latency_count,
+				min(latency) filter (where %s) as latency_min,
+				max(latency) filter (where %s) as latency_max,
+				(avg(latency) filter (where %s))::float8 as latency_mean,
+				percentile_disc(array[0.5, 0.9, 0.95, 0.99]) within group (order by latency) filter (where %s) as percentiles
+			from health_checks
+			where created_at >= $1 and created_at < $2
+			group by 1, 2
+		) as buckets
+		on conflict (service_id, bucket_start) do update set
+			check_count = excluded.check_count,
+			failure_count = excluded.failure_count,
+			degraded_count = excluded.degraded_count,
+			latency_count = excluded.latency_count,
+			latency_min = excluded.latency_min,
+			latency_max = excluded.latency_max,
+			latency_mean = excluded.latency_mean,
+			latency_p50 = excluded.latency_p50,
+			latency_p90 = excluded.latency_p90,
+			latency_p95 = excluded.latency_p95,
+			latency_p99 = excluded.latency_p99
+	`, resolution.table(), resolution, answeredCheck, answeredCheck, answeredCheck, answeredCheck, answeredCheck)
+	tag, err := r.db.Exec(ctx, query, from, to)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
+
+// GetRollups returns the service's rollups at resolution whose buckets overlap
+// from to to, in order.
+func (r *PostgresRepository) GetRollups(ctx context.Context, serviceID int, resolution RollupResolution, from, to time.Time) ([]Rollup, error) {
+	query := fmt.Sprintf(`
+		select bucket_start, check_count, failure_count, degraded_count, latency_count,
+			coalesce(latency_min, 0), coalesce(latency_max, 0), coalesce(latency_mean, 0),
+			coalesce(latency_p50, 0), coalesce(latency_p90, 0), coalesce(latency_p95, 0), coalesce(latency_p99, 0)
+		from %s
+		where service_id = $1 and bucket_start >= date_trunc('%s', $2::timestamptz, 'UTC') and bucket_start < $3
+		order by bucket_start
+	`, resolution.table(), resolution)
+	rows, err := r.db.Query(ctx, query, serviceID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var rollups []Rollup
+	for rows.Next() {
+		var rollup Rollup
+		if err := rows.Scan(
+			&rollup.Start, &rollup.CheckCount, &rollup.FailureCount, &rollup.DegradedCount, &rollup.Latency.Count,
+			&rollup.Latency.MinMs, &rollup.Latency.MaxMs, &rollup.Latency.MeanMs,
+			&rollup.Latency.P50Ms, &rollup.Latency.P90Ms, &rollup.Latency.P95Ms, &rollup.Latency.P99Ms,
+		); err != nil {
+			return nil, err
+		}
+		rollup.End = rollup.Start.Add(resolution.size())
+		rollups = append(rollups, rollup)
+	}
+	return rollups, rows.Err()
+}
+
+// DeleteHealthChecksBefore deletes up to limit health checks created before
+// before and returns how many it deleted.
+func (r *PostgresRepository) DeleteHealthChecksBefore(ctx context.Context, before time.Time, limit int) (int64, error) {
+	query := `
+		delete from health_checks
+		where id in (select id from health_checks where created_at < $1 limit $2)
+	`
+	tag, err := r.db.Exec(ctx, query, before, limit)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
+
+func scanService(row pgx.Row) (Service, error) {
+	var service Service
+	err := row.Scan(
+		&service.ID, &service.UserID, &service.OrganizationID, &service.Name, &service.Type, &service.URL, &service.Host, &service.Port,
+		&service.Config, &service.Timeout, &service.EncryptedAuth, &service.CheckInterval, &service.Paused,
+		&service.NextRunAt, &service.CreatedAt, &service.HeartbeatToken, &service.GracePeriod, &service.LastPingAt,
+		&service.Tags, &service.Status, &service.FailureThreshold, &service.SuccessThreshold, &service.Recheck,
+		&service.LatencyWarnMs, &service.LatencyCriticalMs,
+	)
+	return service, err
+}
+
+func scanHealthCheck(row pgx.Row) (HealthCheck, error) {
+	var check HealthCheck
+	err := row.Scan(
+		&check.ID, &check.ServiceID, &check.Status, &check.Latency, &check.Details, &check.CreatedAt,
+		&check.StatusCode, &check.ErrorClass, &check.Message, &check.BodySnippet, &check.Timing,
+	)
+	return check, err
+}
+
+// tagsOf returns the service's tags for the NOT NULL tags column.
+func tagsOf(service Service) []string {
+	if service.Tags == nil {
+		return []string{}
+	}
+	return service.Tags
+}
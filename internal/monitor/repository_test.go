@@ -34,21 +34,23 @@ func TestRepository_Integration(t *testing.T) {
 	defer pool.Close()
 
 	repo := NewRepository(pool)
+	userID := createTestUser(t, ctx, pool, "repository-user")
 
 	t.Run("Create", func(t *testing.T) {
 		service := Service{
 			Name:          "test-create-service",
 			URL:           "http://test-create.com",
+			UserID:        userID,
 			CheckInterval: 30,
 			NextRunAt:     time.Now().Add(30 * time.Second),
 		}
 
-		err := repo.Create(ctx, service)
+		_, err := repo.Create(ctx, service)
 		assert.NoError(t, err)
 	})
 
 	t.Run("ListServices", func(t *testing.T) {
-		services, err := repo.ListServices(ctx)
+		services, err := repo.ListServices(ctx, Owner{UserID: userID})
 		assert.NoError(t, err)
 		assert.NotNil(t, services)
 	})
@@ -58,14 +60,15 @@ func TestRepository_Integration(t *testing.T) {
 		service := Service{
 			Name:          "test-health-check-service",
 			URL:           "http://test-hc.com",
+			UserID:        userID,
 			CheckInterval: 60,
 			NextRunAt:     time.Now().Add(60 * time.Second),
 		}
-		err := repo.Create(ctx, service)
+		_, err := repo.Create(ctx, service)
 		require.NoError(t, err)
 
 		// Get the service
-		services, err := repo.ListServices(ctx)
+		services, err := repo.ListServices(ctx, Owner{UserID: userID})
 		require.NoError(t, err)
 		require.NotEmpty(t, services)
 
@@ -88,13 +91,14 @@ func TestRepository_Integration(t *testing.T) {
 		service := Service{
 			Name:          "test-get-hc-service",
 			URL:           "http://test-get-hc.com",
+			UserID:        userID,
 			CheckInterval: 45,
 			NextRunAt:     time.Now().Add(45 * time.Second),
 		}
-		err := repo.Create(ctx, service)
+		_, err := repo.Create(ctx, service)
 		require.NoError(t, err)
 
-		services, err := repo.ListServices(ctx)
+		services, err := repo.ListServices(ctx, Owner{UserID: userID})
 		require.NoError(t, err)
 		require.NotEmpty(t, services)
 		serviceID := services[0].ID
@@ -120,13 +124,14 @@ func TestRepository_Integration(t *testing.T) {
 		service := Service{
 			Name:          "test-latest-hc-service",
 			URL:           "http://test-latest-hc.com",
+			UserID:        userID,
 			CheckInterval: 20,
 			NextRunAt:     time.Now().Add(20 * time.Second),
 		}
-		err := repo.Create(ctx, service)
+		_, err := repo.Create(ctx, service)
 		require.NoError(t, err)
 
-		services, err := repo.ListServices(ctx)
+		services, err := repo.ListServices(ctx, Owner{UserID: userID})
 		require.NoError(t, err)
 		require.NotEmpty(t, services)
 		serviceID := services[0].ID
@@ -134,7 +139,7 @@ func TestRepository_Integration(t *testing.T) {
 		// Create first check
 		check1 := HealthCheck{
 			ServiceID: serviceID,
-			Status:    "UP",
+			Status:    StatusUp,
 			Latency:   100,
 			CreatedAt: time.Now().Add(-2 * time.Minute),
 		}
@@ -143,10 +148,15 @@ func TestRepository_Integration(t *testing.T) {
 
 		// Create second check (latest)
 		check2 := HealthCheck{
-			ServiceID: serviceID,
-			Status:    "DOWN",
-			Latency:   300,
-			CreatedAt: time.Now(),
+			ServiceID:   serviceID,
+			Status:      StatusDown,
+			Latency:     300,
+			CreatedAt:   time.Now(),
+			StatusCode:  503,
+			ErrorClass:  ErrorClassHTTPStatus,
+			Message:     "unexpected status 503 Service Unavailable, expected 2xx",
+			BodySnippet: "upstream connect error",
+			Timing:      &Timing{DNSMs: 12, ConnectMs: 30, TLSMs: 45, TTFBMs: 200, TransferMs: 13},
 		}
 		err = repo.CreateHealthCheck(ctx, check2)
 		require.NoError(t, err)
@@ -155,8 +165,13 @@ func TestRepository_Integration(t *testing.T) {
 		latest, err := repo.GetLatestHealthCheck(ctx, serviceID)
 		assert.NoError(t, err)
 		assert.NotNil(t, latest)
-		assert.Equal(t, "DOWN", latest.Status)
+		assert.Equal(t, StatusDown, latest.Status)
 		assert.Equal(t, serviceID, latest.ServiceID)
+		assert.Equal(t, 503, latest.StatusCode)
+		assert.Equal(t, ErrorClassHTTPStatus, latest.ErrorClass)
+		assert.Equal(t, check2.Message, latest.Message)
+		assert.Equal(t, "upstream connect error", latest.BodySnippet)
+		assert.Equal(t, check2.Timing, latest.Timing)
 	})
 
 	t.Run("ClaimDueServices", func(t *testing.T) {
@@ -164,10 +179,11 @@ func TestRepository_Integration(t *testing.T) {
 		service := Service{
 			Name:          "test-claim-due-service",
 			URL:           "http://test-claim-due.com",
+			UserID:        userID,
 			CheckInterval: 15,
 			NextRunAt:     time.Now().Add(-5 * time.Second), // Past due
 		}
-		err := repo.Create(ctx, service)
+		_, err := repo.Create(ctx, service)
 		require.NoError(t, err)
 
 		// Claim due services
@@ -182,6 +198,20 @@ func TestRepository_Integration(t *testing.T) {
 		}
 	})
 
+	t.Run("ClaimDueServices_SkipsOwnerless", func(t *testing.T) {
+		var ownerlessID int
+		err := pool.QueryRow(ctx, `insert into services (name, url, check_interval, next_run_at)
+			values ('test-ownerless', 'http://test-ownerless.com', 15, now() - interval '5 seconds') returning id`).Scan(&ownerlessID)
+		require.NoError(t, err)
+		defer pool.Exec(ctx, `delete from services where id = $1`, ownerlessID)
+
+		dueServices, err := repo.ClaimDueServices(ctx)
+		require.NoError(t, err)
+		for _, service := range dueServices {
+			assert.NotEqual(t, ownerlessID, service.ID)
+		}
+	})
+
 	t.Run("GetLatestHealthCheck_NotFound", func(t *testing.T) {
 		// Try to get health check for non-existent service
 		latest, err := repo.GetLatestHealthCheck(ctx, 999999)
@@ -189,4 +219,340 @@ func TestRepository_Integration(t *testing.T) {
 		assert.NoError(t, err)
 		assert.Nil(t, latest)
 	})
+
+	t.Run("StatusState", func(t *testing.T) {
+		created, err := repo.Create(ctx, Service{
+			Name:             "test-status-state",
+			URL:              "http://test-status-state.com",
+			UserID:           userID,
+			CheckInterval:    30,
+			FailureThreshold: 3,
+			SuccessThreshold: 2,
+			Recheck:          true,
+			NextRunAt:        time.Now().Add(30 * time.Second),
+
+			LatencyWarnMs:     2000,
+			LatencyCriticalMs: 8000,
+		})
+		require.NoError(t, err)
+		defer cleanupService(t, ctx, repo, created.ID)
+		assert.Equal(t, 3, created.FailureThreshold)
+		assert.True(t, created.Recheck)
+		assert.Equal(t, 2000, created.LatencyWarnMs)
+		assert.Equal(t, 8000, created.LatencyCriticalMs)
+
+		state, err := repo.GetStatusState(ctx, created.ID)
+		require.NoError(t, err)
+		assert.Equal(t, StatusState{}, state)
+
+		since := time.Now().Truncate(time.Second)
+		pending := StatusState{Status: StatusUp, PendingStatus: StatusDown, PendingCount: 2, PendingSince: &since}
+		require.NoError(t, repo.SaveStatusState(ctx, created.ID, pending))
+		state, err = repo.GetStatusState(ctx, created.ID)
+		require.NoError(t, err)
+		assert.Equal(t, 2, state.PendingCount)
+		assert.True(t, state.PendingSince.Equal(since))
+
+		service, err := repo.GetService(ctx, Owner{UserID: userID}, created.ID)
+		require.NoError(t, err)
+		assert.Equal(t, StatusUp, service.Status)
+
+		_, err = repo.GetStatusState(ctx, 999999)
+		assert.ErrorIs(t, err, ErrServiceNotFound)
+	})
+
+	t.Run("HeartbeatOverdue", func(t *testing.T) {
+		created, err := repo.Create(ctx, Service{
+			Name:           "test-heartbeat-overdue",
+			Type:           CheckTypeHeartbeat,
+			UserID:         userID,
+			CheckInterval:  60,
+			GracePeriod:    30,
+			HeartbeatToken: "test-heartbeat-overdue",
+			NextRunAt:      time.Now().Add(90 * time.Second),
+		})
+		require.NoError(t, err)
+		defer cleanupService(t, ctx, repo, created.ID)
+
+		overdue, err := repo.HeartbeatOverdue(ctx, created.ID)
+		require.NoError(t, err)
+		assert.False(t, overdue)
+
+		_, err = pool.Exec(ctx, `update services set created_at = now() - interval '2 minutes' where id = $1`, created.ID)
+		require.NoError(t, err)
+		overdue, err = repo.HeartbeatOverdue(ctx, created.ID)
+		require.NoError(t, err)
+		assert.True(t, overdue)
+
+		_, err = repo.RecordHeartbeat(ctx, "test-heartbeat-overdue")
+		require.NoError(t, err)
+		overdue, err = repo.HeartbeatOverdue(ctx, created.ID)
+		require.NoError(t, err)
+		assert.False(t, overdue)
+	})
+
+	t.Run("UpdateService_Reschedules", func(t *testing.T) {
+		created, err := repo.Create(ctx, Service{
+			Name:          "test-update-reschedules",
+			URL:           "http://test-update-reschedules.com",
+			UserID:        userID,
+			CheckInterval: 3600,
+			NextRunAt:     time.Now().Add(time.Hour),
+		})
+		require.NoError(t, err)
+		defer cleanupService(t, ctx, repo, created.ID)
+
+		created.Name = "test-update-reschedules-renamed"
+		updated, err := repo.UpdateService(ctx, Owner{UserID: userID}, created)
+		require.NoError(t, err)
+		assert.WithinDuration(t, created.NextRunAt, updated.NextRunAt, time.Second)
+
+		updated.CheckInterval = 60
+		updated, err = repo.UpdateService(ctx, Owner{UserID: userID}, updated)
+		require.NoError(t, err)
+		assert.WithinDuration(t, time.Now().Add(time.Minute), updated.NextRunAt, 5*time.Second)
+
+		heartbeat, err := repo.Create(ctx, Service{
+			Name:           "test-update-reschedules-heartbeat",
+			Type:           CheckTypeHeartbeat,
+			UserID:         userID,
+			CheckInterval:  60,
+			HeartbeatToken: "test-update-reschedules",
+			NextRunAt:      time.Now().Add(time.Minute),
+		})
+		require.NoError(t, err)
+		defer cleanupService(t, ctx, repo, heartbeat.ID)
+
+		pinged, err := repo.RecordHeartbeat(ctx, "test-update-reschedules")
+		require.NoError(t, err)
+		pinged.GracePeriod = 600
+		updated, err = repo.UpdateService(ctx, Owner{UserID: userID}, pinged)
+		require.NoError(t, err)
+		require.NotNil(t, pinged.LastPingAt)
+		assert.WithinDuration(t, pinged.LastPingAt.Add(11*time.Minute), updated.NextRunAt, time.Second)
+	})
+
+	t.Run("GetStatusPeriods", func(t *testing.T) {
+		created, err := repo.Create(ctx, Service{
+			Name:          "test-status-periods",
+			URL:           "http://test-status-periods.com",
+			UserID:        userID,
+			CheckInterval: 60,
+			NextRunAt:     time.Now().Add(time.Minute),
+		})
+		require.NoError(t, err)
+		defer cleanupService(t, ctx, repo, created.ID)
+
+		from := time.Now().Add(-time.Hour).Truncate(time.Second)
+		to := from.Add(30 * time.Minute)
+		// The check before the window sets the status at its start.
+		for _, check := range []struct {
+			status Status
+			at     time.Duration
+		}{
+			{StatusDown, -2 * time.Minute},
+			{StatusUp, 5 * time.Minute},
+			{StatusUp, 10 * time.Minute},
+			{StatusDown, 20 * time.Minute},
+			{StatusUp, 40 * time.Minute},
+		} {
+			_, err := pool.Exec(ctx, `insert into health_checks (service_id, status, latency, created_at) values ($1, $2, 10, $3)`,
+				created.ID, check.status, from.Add(check.at))
+			require.NoError(t, err)
+		}
+
+		periods, err := repo.GetStatusPeriods(ctx, created.ID, from, to)
+		require.NoError(t, err)
+		require.Len(t, periods, 3)
+		assert.Equal(t, StatusDown, periods[0].Status)
+		assert.True(t, periods[0].StartedAt.Equal(from))
+		assert.True(t, periods[0].EndedAt.Equal(from.Add(5*time.Minute)))
+		assert.Equal(t, StatusUp, periods[1].Status)
+		assert.True(t, periods[1].EndedAt.Equal(from.Add(20*time.Minute)))
+		assert.Equal(t, StatusDown, periods[2].Status)
+		assert.True(t, periods[2].EndedAt.Equal(to))
+	})
+
+	t.Run("GetIncidentSpans", func(t *testing.T) {
+		created, err := repo.Create(ctx, Service{
+			Name:          "test-incident-spans",
+			URL:           "http://test-incident-spans.com",
+			UserID:        userID,
+			CheckInterval: 60,
+			NextRunAt:     time.Now().Add(time.Minute),
+		})
+		require.NoError(t, err)
+		defer cleanupService(t, ctx, repo, created.ID)
+
+		from := time.Now().Add(-time.Hour).Truncate(time.Second)
+		to := from.Add(30 * time.Minute)
+		// Only the incident resolved before the window and the one started
+		// after it are left out.
+		for _, incident := range []struct {
+			started  time.Duration
+			resolved *time.Duration
+		}{
+			{-20 * time.Minute, durationPtr(-10 * time.Minute)},
+			{-5 * time.Minute, durationPtr(5 * time.Minute)},
+			{10 * time.Minute, durationPtr(40 * time.Minute)},
+			{35 * time.Minute, durationPtr(45 * time.Minute)},
+			{50 * time.Minute, nil},
+		} {
+			var resolvedAt *time.Time
+			if incident.resolved != nil {
+				at := from.Add(*incident.resolved)
+				resolvedAt = &at
+			}
+			_, err := pool.Exec(ctx, `insert into incidents (service_id, started_at, resolved_at) values ($1, $2, $3)`,
+				created.ID, from.Add(incident.started), resolvedAt)
+			require.NoError(t, err)
+		}
+
+		incidents, err := repo.GetIncidentSpans(ctx, created.ID, from, to)
+		require.NoError(t, err)
+		require.Len(t, incidents, 2)
+		assert.True(t, incidents[0].StartedAt.Equal(from.Add(-5*time.Minute)))
+		assert.True(t, incidents[1].StartedAt.Equal(from.Add(10*time.Minute)))
+		require.NotNil(t, incidents[1].ResolvedAt)
+		assert.True(t, incidents[1].ResolvedAt.Equal(from.Add(40*time.Minute)))
+	})
+
+	t.Run("GetLatencyStats", func(t *testing.T) {
+		created, err := repo.Create(ctx, Service{
+			Name:          "test-latency-stats",
+			URL:           "http://test-latency-stats.com",
+			UserID:        userID,
+			CheckInterval: 60,
+			NextRunAt:     time.Now().Add(time.Minute),
+		})
+		require.NoError(t, err)
+		defer cleanupService(t, ctx, repo, created.ID)
+
+		from := time.Now().Add(-time.Hour).Truncate(time.Hour)
+		to := from.Add(time.Hour)
+		for _, check := range []struct {
+			status  Status
+			latency int
+			class   string
+			at      time.Duration
+		}{
+			{StatusUp, 100, "", time.Minute},
+			{StatusUp, 200, "", 2 * time.Minute},
+			{StatusDegraded, 600, "slow_response", 3 * time.Minute},
+			{StatusDown, 5, "connection_refused", 4 * time.Minute},
+			{StatusDown, 3000, "slow_response", 31 * time.Minute},
+			{StatusUp, 50, "", 90 * time.Minute},
+		} {
+			_, err := pool.Exec(ctx, `insert into health_checks (service_id, status, latency, error_class, created_at) values ($1, $2, $3, nullif($4, ''), $5)`,
+				created.ID, check.status, check.latency, check.class, from.Add(check.at))
+			require.NoError(t, err)
+		}
+
+		overall, buckets, err := repo.GetLatencyStats(ctx, created.ID, from, to, 30*time.Minute)
+		require.NoError(t, err)
+		assert.Equal(t, 4, overall.Count)
+		assert.Equal(t, 100, overall.MinMs)
+		assert.Equal(t, 3000, overall.MaxMs)
+		assert.InDelta(t, 975, overall.MeanMs, 0.001)
+		assert.Equal(t, 200, overall.P50Ms)
+		assert.Equal(t, 3000, overall.P99Ms)
+		require.Len(t, buckets, 2)
+		assert.True(t, buckets[0].Start.Equal(from))
+		assert.Equal(t, 3, buckets[0].Count)
+		assert.True(t, buckets[1].Start.Equal(from.Add(30*time.Minute)))
+		assert.Equal(t, 3000, buckets[1].MaxMs)
+	})
+
+	t.Run("Rollups", func(t *testing.T) {
+		created, err := repo.Create(ctx, Service{
+			Name:          "test-rollups",
+			URL:           "http://test-rollups.com",
+			UserID:        userID,
+			CheckInterval: 60,
+			NextRunAt:     time.Now().Add(time.Minute),
+		})
+		require.NoError(t, err)
+		defer cleanupService(t, ctx, repo, created.ID)
+
+		day := RollupDaily.truncate(time.Now().AddDate(0, 0, -3))
+		for _, check := range []struct {
+			status  Status
+			latency int
+			class   string
+			at      time.Duration
+		}{
+			{StatusUp, 100, "", 10 * time.Minute},
+			{StatusDegraded, 700, "slow_response", 20 * time.Minute},
+			{StatusDown, 5, "connection_refused", 30 * time.Minute},
+			{StatusUp, 300, "", 5 * time.Hour},
+		} {
+			_, err := pool.Exec(ctx, `insert into health_checks (service_id, status, latency, error_class, created_at) values ($1, $2, $3, nullif($4, ''), $5)`,
+				created.ID, check.status, check.latency, check.class, day.Add(check.at))
+			require.NoError(t, err)
+		}
+
+		_, err = repo.RollupHealthChecks(ctx, RollupHourly, day, day.Add(24*time.Hour))
+		require.NoError(t, err)
+		// Rolling up again replaces the buckets instead of adding to them.
+		_, err = repo.RollupHealthChecks(ctx, RollupDaily, day, day.Add(24*time.Hour))
+		require.NoError(t, err)
+		_, err = repo.RollupHealthChecks(ctx, RollupDaily, day, day.Add(24*time.Hour))
+		require.NoError(t, err)
+
+		var checks, failures, degraded, p50 int
+		var minLatency *int
+		err = pool.QueryRow(ctx, `select check_count, failure_count, degraded_count, latency_min, latency_p50
+			from health_check_rollups_hourly where service_id = $1 and bucket_start = $2`, created.ID, day).
+			Scan(&checks, &failures, &degraded, &minLatency, &p50)
+		require.NoError(t, err)
+		assert.Equal(t, 3, checks)
+		assert.Equal(t, 1, failures)
+		assert.Equal(t, 1, degraded)
+		require.NotNil(t, minLatency)
+		assert.Equal(t, 100, *minLatency)
+		assert.Equal(t, 100, p50)
+
+		var maxLatency int
+		err = pool.QueryRow(ctx, `select check_count, latency_max from health_check_rollups_daily where service_id = $1 and bucket_start = $2`,
+			created.ID, day).Scan(&checks, &maxLatency)
+		require.NoError(t, err)
+		assert.Equal(t, 4, checks)
+		assert.Equal(t, 700, maxLatency)
+
+		// The bucket from falls in is included.
+		rollups, err := repo.GetRollups(ctx, created.ID, RollupHourly, day.Add(30*time.Minute), day.Add(5*time.Hour))
+		require.NoError(t, err)
+		require.Len(t, rollups, 1)
+		assert.True(t, rollups[0].Start.Equal(day))
+		assert.True(t, rollups[0].End.Equal(day.Add(time.Hour)))
+		assert.Equal(t, 3, rollups[0].CheckCount)
+		assert.Equal(t, 2, rollups[0].Latency.Count)
+		assert.Equal(t, 700, rollups[0].Latency.MaxMs)
+
+		rollups, err = repo.GetRollups(ctx, created.ID, RollupDaily, day, day.Add(24*time.Hour))
+		require.NoError(t, err)
+		require.Len(t, rollups, 1)
+		assert.Equal(t, 3, rollups[0].Latency.Count)
+
+		next, err := repo.NextRollupStart(ctx, RollupDaily)
+		require.NoError(t, err)
+		assert.False(t, next.Before(day.Add(24*time.Hour)))
+
+		deleted, err := repo.DeleteHealthChecksBefore(ctx, day.Add(time.Hour), 2)
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), deleted)
+		// Older checks of other services may be deleted too.
+		for deleted == 2 {
+			deleted, err = repo.DeleteHealthChecksBefore(ctx, day.Add(time.Hour), 2)
+			require.NoError(t, err)
+		}
+
+		var left int
+		require.NoError(t, pool.QueryRow(ctx, `select count(*) from health_checks where service_id = $1`, created.ID).Scan(&left))
+		assert.Equal(t, 1, left)
+	})
+}
+
+func durationPtr(d time.Duration) *time.Duration {
+	return &d
 }
@@ -0,0 +1,180 @@
+package monitor
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const (
+	defaultRetentionInterval = time.Hour
+	// rollupDelay leaves checks still being written at the end of a bucket
+	// time to commit before the bucket is rolled up.
+	rollupDelay = time.Minute
+	// rollupChunk bounds how much of health_checks one rollup statement scans,
+	// so that catching up on a large backlog does not run as one huge query.
+	rollupChunk = 24 * time.Hour
+	// retentionBatchSize bounds how many health checks one delete removes.
+	retentionBatchSize = 10000
+)
+
+// RollupResolution is the bucket size of a health check rollup, named after
+// the date_trunc field that computes it.
+type RollupResolution string
+
+const (
+	RollupHourly RollupResolution = "hour"
+	RollupDaily  RollupResolution = "day"
+)
+
+func (r RollupResolution) size() time.Duration {
+	if r == RollupDaily {
+		return 24 * time.Hour
+	}
+	return time.Hour
+}
+
+func (r RollupResolution) table() string {
+	if r == RollupDaily {
+		return "health_check_rollups_daily"
+	}
+	return "health_check_rollups_hourly"
+}
+
+// truncate returns the start, in UTC, of the bucket t falls in.
+func (r RollupResolution) truncate(t time.Time) time.Time {
+	return t.UTC().Truncate(r.size())
+}
+
+// retentionCutoff returns the time before which health checks kept for
+// rawRetention are deleted: their expiry rounded down to a day, so that the
+// older ones are all in a daily rollup.
+func retentionCutoff(now time.Time, rawRetention time.Duration) time.Time {
+	return RollupDaily.truncate(now.Add(-rawRetention))
+}
+
+// Rollup aggregates the health checks of a service created in one bucket of
+// a RollupResolution, from Start to End.
+type Rollup struct {
+	Start         time.Time
+	End           time.Time
+	CheckCount    int
+	FailureCount  int
+	DegradedCount int
+	// Latency summarizes the answered checks, Count of them.
+	Latency LatencyStats
+}
+
+// Retention rolls health checks up into hourly and daily aggregates and
+// deletes the raw checks older than rawRetention once they are rolled up.
+// Every step is idempotent, so several instances may run it at once.
+type Retention struct {
+	repo         Repository
+	log          *zap.Logger
+	rawRetention time.Duration
+	interval     time.Duration
+	now          func() time.Time
+}
+
+// NewRetention returns a Retention keeping raw health checks for rawRetention;
+// zero keeps them forever while still rolling them up.
+func NewRetention(repo Repository, rawRetention time.Duration, logger *zap.Logger) *Retention {
+	return &Retention{
+		repo:         repo,
+		log:          logger,
+		rawRetention: rawRetention,
+		interval:     defaultRetentionInterval,
+		now:          time.Now,
+	}
+}
+
+// Run applies retention right away and then every interval until ctx is done.
+func (r *Retention) Run(ctx context.Context) {
+	r.log.Info("Retention started", zap.Duration("raw_retention", r.rawRetention))
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		r.apply(ctx)
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// apply rolls up every complete bucket and then deletes expired checks.
+func (r *Retention) apply(ctx context.Context) {
+	now := r.now()
+	for _, resolution := range []RollupResolution{RollupHourly, RollupDaily} {
+		if err := r.rollup(ctx, resolution, now); err != nil {
+			r.log.Error("failed to roll up health checks", zap.String("resolution", string(resolution)), zap.Error(err))
+			return
+		}
+	}
+	if r.rawRetention <= 0 {
+		return
+	}
+	if err := r.deleteExpired(ctx, now); err != nil {
+		r.log.Error("failed to delete expired health checks", zap.Error(err))
+	}
+}
+
+// rollup rolls up the complete buckets of resolution from where it left off.
+func (r *Retention) rollup(ctx context.Context, resolution RollupResolution, now time.Time) error {
+	start, err := r.repo.NextRollupStart(ctx, resolution)
+	if err != nil || start.IsZero() {
+		return err
+	}
+
+	end := resolution.truncate(now.Add(-rollupDelay))
+	var buckets int64
+	for start.Before(end) {
+		chunkEnd := start.Add(rollupChunk)
+		if chunkEnd.After(end) {
+			chunkEnd = end
+		}
+		n, err := r.repo.RollupHealthChecks(ctx, resolution, start, chunkEnd)
+		if err != nil {
+			return err
+		}
+		buckets += n
+		start = chunkEnd
+	}
+	if buckets > 0 {
+		r.log.Debug("rolled up health checks", zap.String("resolution", string(resolution)), zap.Int64("buckets", buckets))
+	}
+	return nil
+}
+
+// deleteExpired deletes the checks older than the raw retention, rounded down
+// to a day, in batches. Checks that are not in a daily rollup yet are kept
+// whatever their age.
+func (r *Retention) deleteExpired(ctx context.Context, now time.Time) error {
+	cutoff := retentionCutoff(now, r.rawRetention)
+	rolledUp, err := r.repo.NextRollupStart(ctx, RollupDaily)
+	if err != nil || rolledUp.IsZero() {
+		return err
+	}
+	if rolledUp.Before(cutoff) {
+		cutoff = rolledUp
+	}
+
+	var deleted int64
+	for ctx.Err() == nil {
+		n, err := r.repo.DeleteHealthChecksBefore(ctx, cutoff, retentionBatchSize)
+		if err != nil {
+			return err
+		}
+		deleted += n
+		if n < retentionBatchSize {
+			break
+		}
+	}
+	if deleted > 0 {
+		r.log.Info("deleted expired health checks", zap.Int64("count", deleted), zap.Time("before", cutoff))
+	}
+	return ctx.Err()
+}
@@ -0,0 +1,84 @@
+package monitor
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+)
+
+func TestRetention_Apply(t *testing.T) {
+	now := time.Date(2024, 5, 8, 12, 30, 0, 0, time.UTC)
+	day := func(d int) time.Time { return time.Date(2024, 5, d, 0, 0, 0, 0, time.UTC) }
+	newRetention := func(repo Repository, rawRetention time.Duration) *Retention {
+		retention := NewRetention(repo, rawRetention, zap.NewNop())
+		retention.now = func() time.Time { return now }
+		return retention
+	}
+
+	t.Run("rolls up complete buckets in chunks and deletes in batches", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		hourlyStart := day(7).Add(10 * time.Hour)
+		mockRepo.On("NextRollupStart", mock.Anything, RollupHourly).Return(hourlyStart, nil)
+		mockRepo.On("RollupHealthChecks", mock.Anything, RollupHourly, hourlyStart, day(8).Add(10*time.Hour)).Return(int64(24), nil).Once()
+		mockRepo.On("RollupHealthChecks", mock.Anything, RollupHourly, day(8).Add(10*time.Hour), day(8).Add(12*time.Hour)).Return(int64(2), nil).Once()
+		mockRepo.On("NextRollupStart", mock.Anything, RollupDaily).Return(day(7), nil)
+		mockRepo.On("RollupHealthChecks", mock.Anything, RollupDaily, day(7), day(8)).Return(int64(1), nil).Once()
+		mockRepo.On("DeleteHealthChecksBefore", mock.Anything, day(1), retentionBatchSize).Return(int64(retentionBatchSize), nil).Once()
+		mockRepo.On("DeleteHealthChecksBefore", mock.Anything, day(1), retentionBatchSize).Return(int64(3), nil).Once()
+
+		newRetention(mockRepo, 7*24*time.Hour).apply(context.Background())
+
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("stops when rolling up fails", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		mockRepo.On("NextRollupStart", mock.Anything, RollupHourly).Return(day(8).Add(12*time.Hour), nil)
+		mockRepo.On("NextRollupStart", mock.Anything, RollupDaily).Return(day(5), nil)
+		mockRepo.On("RollupHealthChecks", mock.Anything, RollupDaily, day(5), day(6)).Return(int64(0), errors.New("db down")).Once()
+
+		newRetention(mockRepo, 24*time.Hour).apply(context.Background())
+
+		mockRepo.AssertNotCalled(t, "DeleteHealthChecksBefore", mock.Anything, mock.Anything, mock.Anything)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("deletes only up to the daily rollups", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		mockRepo.On("NextRollupStart", mock.Anything, RollupHourly).Return(day(8).Add(12*time.Hour), nil)
+		// No check ran on May 6 and 7, so the daily rollups do not go past them.
+		mockRepo.On("NextRollupStart", mock.Anything, RollupDaily).Return(day(6), nil)
+		mockRepo.On("RollupHealthChecks", mock.Anything, RollupDaily, day(6), day(7)).Return(int64(0), nil).Once()
+		mockRepo.On("RollupHealthChecks", mock.Anything, RollupDaily, day(7), day(8)).Return(int64(0), nil).Once()
+		mockRepo.On("DeleteHealthChecksBefore", mock.Anything, day(6), retentionBatchSize).Return(int64(0), nil).Once()
+
+		newRetention(mockRepo, 24*time.Hour).apply(context.Background())
+
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("keeps raw checks forever without retention", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		mockRepo.On("NextRollupStart", mock.Anything, RollupHourly).Return(day(8).Add(12*time.Hour), nil)
+		mockRepo.On("NextRollupStart", mock.Anything, RollupDaily).Return(day(8), nil)
+
+		newRetention(mockRepo, 0).apply(context.Background())
+
+		mockRepo.AssertNotCalled(t, "DeleteHealthChecksBefore", mock.Anything, mock.Anything, mock.Anything)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("nothing to roll up", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		mockRepo.On("NextRollupStart", mock.Anything, mock.Anything).Return(time.Time{}, nil)
+
+		newRetention(mockRepo, 24*time.Hour).apply(context.Background())
+
+		mockRepo.AssertNotCalled(t, "RollupHealthChecks", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		mockRepo.AssertNotCalled(t, "DeleteHealthChecksBefore", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
@@ -2,6 +2,7 @@ package monitor
 
 import (
 	"context"
+	"encoding/json"
 	"time"
 
 	"github.com/go-redis/redis/v8"
@@ -61,11 +62,31 @@ func (s *Scheduler) Start(ctx context.Context) {
 }
 
 func (s *Scheduler) Enqueue(ctx context.Context, service Service) error {
+	config, err := json.Marshal(service.Config)
+	if err != nil {
+		return err
+	}
+
 	if err := s.rdb.XAdd(ctx, &redis.XAddArgs{
 		Stream: s.stream,
 		Values: map[string]interface{}{
 			"service_id": service.ID,
+			"user_id":    service.UserID,
+			"org_id":     service.OrganizationID,
+			"type":       service.Type,
 			"url":        service.URL,
+			"host":       service.Host,
+			"port":       service.Port,
+			"config":     string(config),
+			"timeout":    service.Timeout,
+			"auth":       service.EncryptedAuth,
+
+			"failure_threshold": service.FailureThreshold,
+			"success_threshold": service.SuccessThreshold,
+			"recheck":           service.Recheck,
+
+			"latency_warn_ms":     service.LatencyWarnMs,
+			"latency_critical_ms": service.LatencyCriticalMs,
 		},
 	}).Err(); err != nil {
 		return err
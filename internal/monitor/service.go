@@ -2,38 +2,294 @@ package monitor
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"health-checker/internal/secrets"
 	"time"
 
 	"go.uber.org/zap"
 )
 
 type MonitoringService struct {
-	repo Repository
-	log  *zap.Logger
+	repo     Repository
+	log      *zap.Logger
+	recorder *StatusRecorder
+	// rawRetention is how long raw health checks are kept; zero keeps them
+	// forever.
+	rawRetention time.Duration
 }
 
 func NewService(repo Repository, log *zap.Logger) *MonitoringService {
-	return &MonitoringService{repo: repo, log: log}
+	return &MonitoringService{repo: repo, log: log, recorder: NewStatusRecorder(repo, nil, log)}
 }
 
-func (s *MonitoringService) Register(ctx context.Context, dto RegisterServiceDTO) error {
+// SetEventBus makes status changes recorded by the service, such as a heartbeat
+// recovering, publish a StatusChangeEvent.
+func (s *MonitoringService) SetEventBus(eventBus EventBus) {
+	s.recorder = NewStatusRecorder(s.repo, eventBus, s.log)
+}
+
+// SetRawRetention makes reports read the rollups for the part of a window
+// older than the raw health checks kept for rawRetention.
+func (s *MonitoringService) SetRawRetention(rawRetention time.Duration) {
+	s.rawRetention = rawRetention
+}
+
+// Register creates a service registered by owner.UserID, belonging to
+// owner.OrgID when set.
+func (s *MonitoringService) Register(ctx context.Context, owner Owner, dto RegisterServiceDTO) (Service, error) {
+	checkType := dto.Type
+	if checkType == "" {
+		checkType = CheckTypeHTTP
+	}
+
+	timeout := dto.Timeout
+	if timeout == 0 {
+		timeout = int(defaultCheckTimeout / time.Second)
+	}
+
 	service := Service{
-		Name:          dto.Name,
-		URL:           dto.URL,
-		CheckInterval: dto.CheckInterval,
-		NextRunAt:     time.Now().Local().Add(time.Second * time.Duration(dto.CheckInterval)),
+		UserID:         owner.UserID,
+		OrganizationID: owner.OrgID,
+		Name:           dto.Name,
+		Type:           checkType,
+		URL:            dto.URL,
+		Host:           dto.Host,
+		Port:           dto.Port,
+		Config:         dto.Config,
+		Timeout:        timeout,
+		CheckInterval:  dto.CheckInterval,
+		Tags:           dto.Tags,
+		NextRunAt:      time.Now().Local().Add(time.Second * time.Duration(dto.CheckInterval)),
+
+		FailureThreshold: max(dto.FailureThreshold, 1),
+		SuccessThreshold: max(dto.SuccessThreshold, 1),
+		Recheck:          dto.Recheck,
+
+		LatencyWarnMs:     dto.LatencyWarnMs,
+		LatencyCriticalMs: dto.LatencyCriticalMs,
+	}
+
+	if checkType == CheckTypeHeartbeat {
+		token, err := newHeartbeatToken()
+		if err != nil {
+			return Service{}, err
+		}
+		service.HeartbeatToken = token
+		service.GracePeriod = dto.GracePeriod
+		service.NextRunAt = service.NextRunAt.Add(time.Second * time.Duration(dto.GracePeriod))
+	}
+
+	if dto.Auth != nil {
+		var err error
+		if service.EncryptedAuth, err = encryptAuth(*dto.Auth); err != nil {
+			return Service{}, err
+		}
 	}
+
 	return s.repo.Create(ctx, service)
 }
 
-func (s *MonitoringService) ListServices(ctx context.Context) ([]Service, error) {
-	return s.repo.ListServices(ctx)
+func (s *MonitoringService) GetService(ctx context.Context, owner Owner, id int) (Service, error) {
+	return s.repo.GetService(ctx, owner, id)
+}
+
+// UpdateService applies a partial update and re-validates the resulting target.
+// Validation failures wrap ErrInvalidService.
+func (s *MonitoringService) UpdateService(ctx context.Context, owner Owner, id int, dto UpdateServiceDTO) (Service, error) {
+	service, err := s.repo.GetService(ctx, owner, id)
+	if err != nil {
+		return Service{}, err
+	}
+
+	dto.Apply(&service)
+	target := RegisterServiceDTO{
+		Type:        service.Type,
+		URL:         service.URL,
+		Host:        service.Host,
+		Port:        service.Port,
+		Config:      service.Config,
+		Auth:        dto.Auth,
+		GracePeriod: service.GracePeriod,
+		Recheck:     service.Recheck,
+
+		LatencyWarnMs:     service.LatencyWarnMs,
+		LatencyCriticalMs: service.LatencyCriticalMs,
+	}
+	if err := target.Validate(); err != nil {
+		return Service{}, fmt.Errorf("%w: %v", ErrInvalidService, err)
+	}
+
+	if dto.Auth != nil {
+		if service.EncryptedAuth, err = encryptAuth(*dto.Auth); err != nil {
+			return Service{}, err
+		}
+	}
+
+	return s.repo.UpdateService(ctx, owner, service)
+}
+
+func (s *MonitoringService) DeleteService(ctx context.Context, owner Owner, id int) error {
+	return s.repo.DeleteService(ctx, owner, id)
+}
+
+func (s *MonitoringService) PauseService(ctx context.Context, owner Owner, id int) (Service, error) {
+	return s.repo.SetPaused(ctx, owner, id, true)
+}
+
+func (s *MonitoringService) ResumeService(ctx context.Context, owner Owner, id int) (Service, error) {
+	return s.repo.SetPaused(ctx, owner, id, false)
+}
+
+func (s *MonitoringService) ListServices(ctx context.Context, owner Owner) ([]Service, error) {
+	return s.repo.ListServices(ctx, owner)
 }
 
 func (s *MonitoringService) ClaimDueServices(ctx context.Context) ([]Service, error) {
 	return s.repo.ClaimDueServices(ctx)
 }
 
-func (s *MonitoringService) GetHealthChecksByServiceID(ctx context.Context, serviceID, page, limit int) ([]HealthCheck, error) {
+// GetHealthChecksByServiceID returns ErrServiceNotFound unless owner owns the service.
+func (s *MonitoringService) GetHealthChecksByServiceID(ctx context.Context, owner Owner, serviceID, page, limit int) ([]HealthCheck, error) {
+	if _, err := s.repo.GetService(ctx, owner, serviceID); err != nil {
+		return nil, err
+	}
 	return s.repo.GetHealthChecksByServiceID(ctx, serviceID, page, limit)
-}
\ No newline at end of file
+}
+
+// GetUptime reports the availability of a service over a window. It returns
+// ErrServiceNotFound unless owner owns the service.
+func (s *MonitoringService) GetUptime(ctx context.Context, owner Owner, serviceID int, window ReportWindow) (UptimeReport, error) {
+	now := time.Now()
+	from, to, err := window.Range(now)
+	if err != nil {
+		return UptimeReport{}, err
+	}
+	if _, err := s.repo.GetService(ctx, owner, serviceID); err != nil {
+		return UptimeReport{}, err
+	}
+	rawFrom := s.rawFrom(from, to, now)
+	var rollups []Rollup
+	if from.Before(rawFrom) {
+		if rollups, err = s.repo.GetRollups(ctx, serviceID, RollupHourly, from, rawFrom); err != nil {
+			return UptimeReport{}, err
+		}
+	}
+	var periods []StatusPeriod
+	if rawFrom.Before(to) {
+		if periods, err = s.repo.GetStatusPeriods(ctx, serviceID, rawFrom, to); err != nil {
+			return UptimeReport{}, err
+		}
+	}
+	incidents, err := s.repo.GetIncidentSpans(ctx, serviceID, from, to)
+	if err != nil {
+		return UptimeReport{}, err
+	}
+	return newUptimeReport(serviceID, from, to, rollups, periods, incidents), nil
+}
+
+// GetLatency reports the latency percentiles of a service over a window,
+// overall and as a time series. It returns ErrServiceNotFound unless owner
+// owns the service.
+func (s *MonitoringService) GetLatency(ctx context.Context, owner Owner, serviceID int, query LatencyQuery) (LatencyReport, error) {
+	now := time.Now()
+	from, to, err := query.Range(now)
+	if err != nil {
+		return LatencyReport{}, err
+	}
+	rawFrom := s.rawFrom(from, to, now)
+	var minBucket time.Duration
+	if from.Before(rawFrom) {
+		minBucket = RollupHourly.size()
+	}
+	bucket, err := query.bucketSize(to.Sub(from), minBucket)
+	if err != nil {
+		return LatencyReport{}, err
+	}
+	if _, err := s.repo.GetService(ctx, owner, serviceID); err != nil {
+		return LatencyReport{}, err
+	}
+
+	var overall LatencyStats
+	buckets := []LatencyBucket{}
+	if from.Before(rawFrom) {
+		resolution := RollupHourly
+		if bucket%RollupDaily.size() == 0 {
+			resolution = RollupDaily
+		}
+		rollups, err := s.repo.GetRollups(ctx, serviceID, resolution, from, rawFrom)
+		if err != nil {
+			return LatencyReport{}, err
+		}
+		overall, buckets = rollupLatency(rollups, bucket)
+	}
+	if rawFrom.Before(to) {
+		rawOverall, rawBuckets, err := s.repo.GetLatencyStats(ctx, serviceID, rawFrom, to, bucket)
+		if err != nil {
+			return LatencyReport{}, err
+		}
+		overall = mergeLatency(overall, rawOverall)
+		buckets = append(buckets, rawBuckets...)
+	}
+	return LatencyReport{
+		ServiceID:     serviceID,
+		From:          from,
+		To:            to,
+		BucketSeconds: int(bucket.Seconds()),
+		Overall:       overall,
+		Buckets:       buckets,
+	}, nil
+}
+
+// rawFrom returns where the raw health checks kept cover the window from
+// to to: reports read the rollups before it and the checks after it.
+func (s *MonitoringService) rawFrom(from, to, now time.Time) time.Time {
+	if s.rawRetention <= 0 {
+		return from
+	}
+	cutoff := retentionCutoff(now, s.rawRetention)
+	switch {
+	case !from.Before(cutoff):
+		return from
+	case to.Before(cutoff):
+		return to
+	}
+	return cutoff
+}
+
+// RecordHeartbeat handles a ping for the heartbeat service identified by token
+// and records it as an UP check.
+func (s *MonitoringService) RecordHeartbeat(ctx context.Context, token string) error {
+	service, err := s.repo.RecordHeartbeat(ctx, token)
+	if err != nil {
+		return err
+	}
+	job := CheckJob{
+		ServiceID:        service.ID,
+		UserID:           service.UserID,
+		OrgID:            service.OrganizationID,
+		Type:             service.Type,
+		FailureThreshold: service.FailureThreshold,
+		SuccessThreshold: service.SuccessThreshold,
+	}
+	_, err = s.recorder.Record(ctx, job, CheckResult{Status: StatusUp})
+	return err
+}
+
+func encryptAuth(auth HTTPAuth) (string, error) {
+	plaintext, err := json.Marshal(auth)
+	if err != nil {
+		return "", err
+	}
+	return secrets.Encrypt(plaintext)
+}
+
+func newHeartbeatToken() (string, error) {
+	token := make([]byte, 24)
+	if _, err := rand.Read(token); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(token), nil
+}
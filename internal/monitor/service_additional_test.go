@@ -117,9 +117,9 @@ func TestService_Register_WithScheduler(t *testing.T) {
 			s.URL == dto.URL &&
 			s.CheckInterval == dto.CheckInterval &&
 			!s.NextRunAt.IsZero()
-	})).Return(nil)
+	})).Return(Service{ID: 1}, nil)
 
-	err := service.Register(ctx, dto)
+	_, err := service.Register(ctx, Owner{UserID: 1}, dto)
 	assert.NoError(t, err)
 	mockRepo.AssertExpectations(t)
 }
@@ -138,10 +138,11 @@ func TestService_GetHealthChecksByServiceID_WithPagination(t *testing.T) {
 		{ID: 2, ServiceID: serviceID, Status: "DOWN", Latency: 200, CreatedAt: time.Now()},
 	}
 
+	mockRepo.On("GetService", ctx, Owner{UserID: 1}, serviceID).Return(Service{ID: serviceID, UserID: 1}, nil)
 	mockRepo.On("GetHealthChecksByServiceID", ctx, serviceID, page, limit).
 		Return(expectedChecks, nil)
 
-	result, err := service.GetHealthChecksByServiceID(ctx, serviceID, page, limit)
+	result, err := service.GetHealthChecksByServiceID(ctx, Owner{UserID: 1}, serviceID, page, limit)
 	assert.NoError(t, err)
 	assert.Equal(t, expectedChecks, result)
 	assert.Len(t, result, 2)
@@ -157,10 +158,11 @@ func TestService_GetHealthChecksByServiceID_Empty(t *testing.T) {
 	page := 1
 	limit := 10
 
+	mockRepo.On("GetService", ctx, Owner{UserID: 1}, serviceID).Return(Service{ID: serviceID, UserID: 1}, nil)
 	mockRepo.On("GetHealthChecksByServiceID", ctx, serviceID, page, limit).
 		Return([]HealthCheck{}, nil)
 
-	result, err := service.GetHealthChecksByServiceID(ctx, serviceID, page, limit)
+	result, err := service.GetHealthChecksByServiceID(ctx, Owner{UserID: 1}, serviceID, page, limit)
 	assert.NoError(t, err)
 	assert.Empty(t, result)
 	mockRepo.AssertExpectations(t)
@@ -175,10 +177,11 @@ func TestService_GetHealthChecksByServiceID_Error(t *testing.T) {
 	page := 1
 	limit := 10
 
+	mockRepo.On("GetService", ctx, Owner{UserID: 1}, serviceID).Return(Service{ID: serviceID, UserID: 1}, nil)
 	mockRepo.On("GetHealthChecksByServiceID", ctx, serviceID, page, limit).
 		Return([]HealthCheck{}, errors.New("database connection error"))
 
-	result, err := service.GetHealthChecksByServiceID(ctx, serviceID, page, limit)
+	result, err := service.GetHealthChecksByServiceID(ctx, Owner{UserID: 1}, serviceID, page, limit)
 	assert.Error(t, err)
 	assert.Empty(t, result)
 	assert.EqualError(t, err, "database connection error")
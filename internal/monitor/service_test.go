@@ -3,7 +3,10 @@ package monitor
 import (
 	"context"
 	"errors"
+	"health-checker/internal/secrets"
+	"os"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -25,13 +28,96 @@ func TestService_Register(t *testing.T) {
 		// We ignore CreatedAt exact time match
 		mockRepo.On("Create", mock.Anything, mock.MatchedBy(func(s Service) bool {
 			return s.Name == dto.Name && s.URL == dto.URL && s.CheckInterval == dto.CheckInterval
-		})).Return(nil)
+		})).Return(Service{ID: 1}, nil)
+
+		_, err := service.Register(context.Background(), Owner{UserID: 1}, dto)
+		assert.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("DefaultsThresholds", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		service := NewService(mockRepo, zap.L())
+
+		mockRepo.On("Create", mock.Anything, mock.MatchedBy(func(s Service) bool {
+			return s.FailureThreshold == 3 && s.SuccessThreshold == 1 && s.Recheck
+		})).Return(Service{ID: 1}, nil)
+
+		_, err := service.Register(context.Background(), Owner{UserID: 1}, RegisterServiceDTO{
+			Name:             "Flaky API",
+			URL:              "http://example.com",
+			CheckInterval:    60,
+			FailureThreshold: 3,
+			Recheck:          true,
+		})
+		assert.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("InOrganization", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		service := NewService(mockRepo, zap.L())
 
-		err := service.Register(context.Background(), dto)
+		mockRepo.On("Create", mock.Anything, mock.MatchedBy(func(s Service) bool {
+			return s.UserID == 1 && s.OrganizationID == 7
+		})).Return(Service{ID: 1, UserID: 1, OrganizationID: 7}, nil)
+
+		_, err := service.Register(context.Background(), Owner{UserID: 1, OrgID: 7}, RegisterServiceDTO{
+			Name:          "Shared API",
+			URL:           "http://example.com",
+			CheckInterval: 60,
+		})
 		assert.NoError(t, err)
 		mockRepo.AssertExpectations(t)
 	})
 
+	t.Run("EncryptsAuth", func(t *testing.T) {
+		os.Setenv("ENCRYPTION_KEY", "test-key")
+		defer os.Unsetenv("ENCRYPTION_KEY")
+
+		mockRepo := new(MockRepository)
+		service := NewService(mockRepo, zap.L())
+
+		dto := RegisterServiceDTO{
+			Name:          "Private API",
+			URL:           "http://example.com",
+			Auth:          &HTTPAuth{Type: "bearer", Token: "s3cret"},
+			Timeout:       10,
+			CheckInterval: 60,
+		}
+
+		var created Service
+		mockRepo.On("Create", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			created = args.Get(1).(Service)
+		}).Return(Service{ID: 1}, nil)
+
+		_, err := service.Register(context.Background(), Owner{UserID: 1}, dto)
+		assert.NoError(t, err)
+		assert.Equal(t, 10, created.Timeout)
+		assert.NotEmpty(t, created.EncryptedAuth)
+		assert.NotContains(t, created.EncryptedAuth, "s3cret")
+
+		plaintext, err := secrets.Decrypt(created.EncryptedAuth)
+		assert.NoError(t, err)
+		assert.JSONEq(t, `{"type":"bearer","token":"s3cret"}`, string(plaintext))
+	})
+
+	t.Run("AuthWithoutKey", func(t *testing.T) {
+		os.Unsetenv("ENCRYPTION_KEY")
+
+		mockRepo := new(MockRepository)
+		service := NewService(mockRepo, zap.L())
+
+		_, err := service.Register(context.Background(), Owner{UserID: 1}, RegisterServiceDTO{
+			Name:          "Private API",
+			URL:           "http://example.com",
+			Auth:          &HTTPAuth{Type: "basic", Username: "admin"},
+			CheckInterval: 60,
+		})
+		assert.ErrorIs(t, err, secrets.ErrNoKey)
+		mockRepo.AssertNotCalled(t, "Create")
+	})
+
 	t.Run("RepoError", func(t *testing.T) {
 		mockRepo := new(MockRepository)
 		service := NewService(mockRepo, zap.L())
@@ -42,9 +128,9 @@ func TestService_Register(t *testing.T) {
 			CheckInterval: 60,
 		}
 
-		mockRepo.On("Create", mock.Anything, mock.Anything).Return(errors.New("db error"))
+		mockRepo.On("Create", mock.Anything, mock.Anything).Return(Service{}, errors.New("db error"))
 
-		err := service.Register(context.Background(), dto)
+		_, err := service.Register(context.Background(), Owner{UserID: 1}, dto)
 		assert.Error(t, err)
 		mockRepo.AssertExpectations(t)
 	})
@@ -60,9 +146,9 @@ func TestService_ListServices(t *testing.T) {
 			{ID: 2, Name: "S2", URL: "u2", CheckInterval: 20},
 		}
 
-		mockRepo.On("ListServices", mock.Anything).Return(expectedServices, nil)
+		mockRepo.On("ListServices", mock.Anything, Owner{UserID: 1}).Return(expectedServices, nil)
 
-		services, err := service.ListServices(context.Background())
+		services, err := service.ListServices(context.Background(), Owner{UserID: 1})
 		assert.NoError(t, err)
 		assert.Equal(t, expectedServices, services)
 		mockRepo.AssertExpectations(t)
@@ -72,10 +158,132 @@ func TestService_ListServices(t *testing.T) {
 		mockRepo := new(MockRepository)
 		service := NewService(mockRepo, zap.L())
 
-		mockRepo.On("ListServices", mock.Anything).Return([]Service{}, errors.New("db error"))
+		mockRepo.On("ListServices", mock.Anything, Owner{UserID: 1}).Return([]Service{}, errors.New("db error"))
 
-		_, err := service.ListServices(context.Background())
+		_, err := service.ListServices(context.Background(), Owner{UserID: 1})
 		assert.Error(t, err)
 		mockRepo.AssertExpectations(t)
 	})
 }
+
+func TestService_Register_Heartbeat(t *testing.T) {
+	mockRepo := new(MockRepository)
+	service := NewService(mockRepo, zap.L())
+
+	var created Service
+	mockRepo.On("Create", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		created = args.Get(1).(Service)
+	}).Return(Service{ID: 1}, nil)
+
+	before := time.Now()
+	_, err := service.Register(context.Background(), Owner{UserID: 1}, RegisterServiceDTO{
+		Name:          "Nightly backup",
+		Type:          CheckTypeHeartbeat,
+		CheckInterval: 3600,
+		GracePeriod:   600,
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, CheckTypeHeartbeat, created.Type)
+	assert.Len(t, created.HeartbeatToken, 48)
+	assert.Equal(t, 600, created.GracePeriod)
+	assert.WithinDuration(t, before.Add(4200*time.Second), created.NextRunAt, 5*time.Second)
+}
+
+func TestService_RecordHeartbeat(t *testing.T) {
+	t.Run("records UP and publishes recovery", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		mockEventBus := new(MockEventBus)
+		service := NewService(mockRepo, zap.L())
+		service.SetEventBus(mockEventBus)
+
+		mockRepo.On("RecordHeartbeat", mock.Anything, "abc123").Return(Service{ID: 9, Type: CheckTypeHeartbeat}, nil)
+		mockRepo.On("GetStatusState", mock.Anything, 9).Return(StatusState{Status: "DOWN"}, nil)
+		mockRepo.On("SaveStatusState", mock.Anything, 9, mock.Anything).Return(nil)
+		mockRepo.On("CreateHealthCheck", mock.Anything, mock.MatchedBy(func(check HealthCheck) bool {
+			return check.ServiceID == 9 && check.Status == "UP"
+		})).Return(nil)
+		mockEventBus.On("Publish", mock.Anything, mock.MatchedBy(func(event StatusChangeEvent) bool {
+			return event.ServiceID == 9 && event.NewStatus == "UP"
+		})).Return(nil)
+
+		err := service.RecordHeartbeat(context.Background(), "abc123")
+
+		assert.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+		mockEventBus.AssertExpectations(t)
+	})
+
+	t.Run("unknown token", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		service := NewService(mockRepo, zap.L())
+
+		mockRepo.On("RecordHeartbeat", mock.Anything, "nope").Return(Service{}, ErrServiceNotFound)
+
+		err := service.RecordHeartbeat(context.Background(), "nope")
+
+		assert.ErrorIs(t, err, ErrServiceNotFound)
+		mockRepo.AssertNotCalled(t, "CreateHealthCheck")
+	})
+}
+
+func TestService_UpdateService(t *testing.T) {
+	existing := Service{ID: 2, Name: "Cache", Type: CheckTypeTCP, Host: "redis.internal", Port: 6379, CheckInterval: 30}
+
+	t.Run("AppliesPartialUpdate", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		service := NewService(mockRepo, zap.L())
+
+		mockRepo.On("GetService", mock.Anything, Owner{UserID: 1}, 2).Return(existing, nil)
+		mockRepo.On("UpdateService", mock.Anything, Owner{UserID: 1}, mock.MatchedBy(func(s Service) bool {
+			return s.Port == 6380 && s.Host == "redis.internal" && s.Name == "Cache"
+		})).Return(Service{ID: 2, Port: 6380}, nil)
+
+		port := 6380
+		updated, err := service.UpdateService(context.Background(), Owner{UserID: 1}, 2, UpdateServiceDTO{Port: &port})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 6380, updated.Port)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("ReplacesTags", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		service := NewService(mockRepo, zap.L())
+
+		mockRepo.On("GetService", mock.Anything, Owner{UserID: 1}, 2).Return(existing, nil)
+		mockRepo.On("UpdateService", mock.Anything, Owner{UserID: 1}, mock.MatchedBy(func(s Service) bool {
+			return len(s.Tags) == 2 && s.Tags[0] == "cache" && s.Tags[1] == "prod"
+		})).Return(Service{ID: 2, Tags: []string{"cache", "prod"}}, nil)
+
+		tags := []string{"cache", "prod"}
+		_, err := service.UpdateService(context.Background(), Owner{UserID: 1}, 2, UpdateServiceDTO{Tags: &tags})
+
+		assert.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("RejectsInvalidTarget", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		service := NewService(mockRepo, zap.L())
+
+		mockRepo.On("GetService", mock.Anything, Owner{UserID: 1}, 2).Return(existing, nil)
+
+		host := ""
+		_, err := service.UpdateService(context.Background(), Owner{UserID: 1}, 2, UpdateServiceDTO{Host: &host})
+
+		assert.ErrorIs(t, err, ErrInvalidService)
+		mockRepo.AssertNotCalled(t, "UpdateService")
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		service := NewService(mockRepo, zap.L())
+
+		mockRepo.On("GetService", mock.Anything, Owner{UserID: 1}, 3).Return(Service{}, ErrServiceNotFound)
+
+		_, err := service.UpdateService(context.Background(), Owner{UserID: 1}, 3, UpdateServiceDTO{})
+
+		assert.ErrorIs(t, err, ErrServiceNotFound)
+	})
+}
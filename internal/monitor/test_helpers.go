@@ -30,3 +30,18 @@ func SetupTestDatabase(t *testing.T) {
 		t.Fatalf("Failed to run migrations: %v", err)
 	}
 }
+
+// createTestUser inserts (or reuses) a user to own test services and returns its id
+func createTestUser(t *testing.T, ctx context.Context, db *pgxpool.Pool, username string) int {
+	t.Helper()
+
+	var id int
+	err := db.QueryRow(ctx, `
+		insert into users (username, password) values ($1, 'not-a-hash')
+		on conflict (username) do update set username = excluded.username
+		returning id`, username).Scan(&id)
+	if err != nil {
+		t.Fatalf("Failed to create test user: %v", err)
+	}
+	return id
+}
@@ -0,0 +1,92 @@
+package monitor
+
+import (
+	"crypto/tls"
+	"net/http/httptrace"
+	"sync"
+	"time"
+)
+
+// Timing is how long each phase of an HTTP check took, in milliseconds. A
+// phase the request skipped, such as DNS for an IP address, TLS for plain
+// HTTP, or connecting over a reused connection, is 0.
+type Timing struct {
+	DNSMs     int `json:"dns_ms" example:"12"`
+	ConnectMs int `json:"connect_ms" example:"30"`
+	TLSMs     int `json:"tls_ms" example:"45"`
+	// TTFBMs is from the request being sent to the first byte of the response,
+	// i.e. how long the application took to answer.
+	TTFBMs     int `json:"ttfb_ms" example:"850"`
+	TransferMs int `json:"transfer_ms" example:"4"`
+}
+
+// httpTrace collects the phase timestamps of a request. Its hooks may be
+// called from the transport's goroutines, hence the lock.
+type httpTrace struct {
+	mu sync.Mutex
+	at phaseTimes
+}
+
+type phaseTimes struct {
+	dnsStart     time.Time
+	dnsDone      time.Time
+	connectStart time.Time
+	connectDone  time.Time
+	tlsStart     time.Time
+	tlsDone      time.Time
+	wroteRequest time.Time
+	firstByte    time.Time
+}
+
+// clientTrace records the phases of the last hop of a request, so that after
+// redirects the timing describes the response that was checked.
+func (t *httpTrace) clientTrace() *httptrace.ClientTrace {
+	return &httptrace.ClientTrace{
+		GetConn: func(hostPort string) {
+			t.mu.Lock()
+			t.at = phaseTimes{}
+			t.mu.Unlock()
+		},
+		DNSStart: func(httptrace.DNSStartInfo) { t.set(&t.at.dnsStart) },
+		DNSDone:  func(httptrace.DNSDoneInfo) { t.set(&t.at.dnsDone) },
+		ConnectStart: func(network, addr string) {
+			t.set(&t.at.connectStart)
+		},
+		ConnectDone: func(network, addr string, err error) {
+			if err == nil {
+				t.set(&t.at.connectDone)
+			}
+		},
+		TLSHandshakeStart:    func() { t.set(&t.at.tlsStart) },
+		TLSHandshakeDone:     func(tls.ConnectionState, error) { t.set(&t.at.tlsDone) },
+		WroteRequest:         func(httptrace.WroteRequestInfo) { t.set(&t.at.wroteRequest) },
+		GotFirstResponseByte: func() { t.set(&t.at.firstByte) },
+	}
+}
+
+func (t *httpTrace) set(field *time.Time) {
+	t.mu.Lock()
+	*field = time.Now()
+	t.mu.Unlock()
+}
+
+// timing returns the phases of a request whose response was read by done.
+func (t *httpTrace) timing(done time.Time) *Timing {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return &Timing{
+		DNSMs:      millisBetween(t.at.dnsStart, t.at.dnsDone),
+		ConnectMs:  millisBetween(t.at.connectStart, t.at.connectDone),
+		TLSMs:      millisBetween(t.at.tlsStart, t.at.tlsDone),
+		TTFBMs:     millisBetween(t.at.wroteRequest, t.at.firstByte),
+		TransferMs: millisBetween(t.at.firstByte, done),
+	}
+}
+
+// millisBetween is 0 unless both ends of a phase were reached.
+func millisBetween(from, to time.Time) int {
+	if from.IsZero() || to.IsZero() || to.Before(from) {
+		return 0
+	}
+	return int(to.Sub(from).Milliseconds())
+}
@@ -2,7 +2,10 @@ package monitor
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"maps"
 	"net/http"
 	"strconv"
 	"strings"
@@ -14,6 +17,9 @@ import (
 
 const HealthCheckGroup = "health_checkers"
 
+// defaultCheckTimeout applies to jobs that do not carry a per-service timeout.
+const defaultCheckTimeout = 5 * time.Second
+
 type Worker struct {
 	rdb      *redis.Client
 	repo     Repository
@@ -23,22 +29,27 @@ type Worker struct {
 	consumer string
 	eventBus EventBus
 
-	httpClient *http.Client
+	checkers *CheckerRegistry
 }
 
 func NewWorker(rdb *redis.Client, repo Repository, logger *zap.Logger, eventBus EventBus) *Worker {
 	return &Worker{
-		rdb:        rdb,
-		repo:       repo,
-		log:        logger,
-		eventBus:   eventBus,
-		stream:     HealthCheckStream,
-		group:      HealthCheckGroup,
-		consumer:   "worker_1",
-		httpClient: &http.Client{},
+		rdb:      rdb,
+		repo:     repo,
+		log:      logger,
+		eventBus: eventBus,
+		stream:   HealthCheckStream,
+		group:    HealthCheckGroup,
+		consumer: "worker_1",
+		checkers: DefaultCheckers(&http.Client{}, repo),
 	}
 }
 
+// RegisterChecker adds or replaces the Checker used for a check type.
+func (w *Worker) RegisterChecker(checkType string, checker Checker) {
+	w.checkers.Register(checkType, checker)
+}
+
 func (w *Worker) Run(ctx context.Context) {
 	w.ensureConsumerGroup(ctx)
 
@@ -88,71 +99,139 @@ func (w *Worker) ensureConsumerGroup(ctx context.Context) {
 }
 
 func (w *Worker) processJob(parentCtx context.Context, service map[string]interface{}) error {
-	ctx, cancel := context.WithTimeout(parentCtx, 5*time.Second)
+	job, err := parseCheckJob(service)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(parentCtx, job.Timeout)
 	defer cancel()
 
-	start := time.Now().Local()
+	var result CheckResult
+	if checker, ok := w.checkers.Get(job.Type); ok {
+		result = job.applyLatency(checker.Check(ctx, job))
+	} else {
+		// Redelivering the job cannot help, so it is recorded as a failed
+		// check and acked like any other.
+		w.log.Error("unsupported check type", zap.Int("service_id", job.ServiceID), zap.String("type", job.Type))
+		result = CheckResult{
+			Status:     StatusDown,
+			ErrorClass: ErrorClassOther,
+			Message:    fmt.Sprintf("unsupported check type %q", job.Type),
+		}
+	}
+	if result.Status != StatusUp {
+		w.log.Debug("check failed",
+			zap.Int("service_id", job.ServiceID),
+			zap.String("type", job.Type),
+			zap.String("message", result.Message),
+		)
+	}
 
-	serviceID, err := toInt(service["service_id"])
+	// Record outside the check's deadline so a timed-out probe is still persisted.
+	state, err := NewStatusRecorder(w.repo, w.eventBus, w.log).Record(parentCtx, job, result)
 	if err != nil {
 		return err
 	}
-	url, ok := service["url"].(string)
-	if !ok {
-		return errors.New("failed to parse url")
-	}
 
-	previousStatus, err := w.repo.GetLatestHealthCheck(ctx, serviceID)
-	if err != nil {
-		w.log.Warn("failed to get latest health check", zap.Error(err))
+	if job.Recheck && state.Confirming() && job.Attempt < job.threshold(state.PendingStatus)-1 {
+		if err := w.recheck(parentCtx, service, job.Attempt+1); err != nil {
+			w.log.Error("failed to enqueue re-check", zap.Int("service_id", job.ServiceID), zap.Error(err))
+		}
 	}
+	return nil
+}
 
-	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+// recheck enqueues a job again right away, so that whichever worker reads it
+// next confirms or rejects the pending change of status.
+func (w *Worker) recheck(ctx context.Context, values map[string]interface{}, attempt int) error {
+	job := maps.Clone(values)
+	job["attempt"] = attempt
+	return w.rdb.XAdd(ctx, &redis.XAddArgs{Stream: w.stream, Values: job}).Err()
+}
+
+func parseCheckJob(values map[string]interface{}) (CheckJob, error) {
+	serviceID, err := toInt(values["service_id"])
 	if err != nil {
-		return err
+		return CheckJob{}, err
 	}
-
-	status := "DOWN"
-	resp, err := w.httpClient.Do(req)
-	lat := time.Since(start).Milliseconds()
-	if err == nil && resp.StatusCode >= 200 && resp.StatusCode < 300 {
-		status = "UP"
+	url, ok := values["url"].(string)
+	if !ok {
+		return CheckJob{}, errors.New("failed to parse url")
 	}
 
-	if resp != nil {
-		defer resp.Body.Close()
+	// Jobs enqueued before check types existed carry no type and are HTTP checks.
+	checkType, _ := values["type"].(string)
+	if checkType == "" {
+		checkType = CheckTypeHTTP
 	}
 
-	check := HealthCheck{
+	job := CheckJob{
 		ServiceID: serviceID,
-		Status:    status,
-		CreatedAt: time.Now().Local(),
-		Latency:   int(lat),
+		Type:      checkType,
+		URL:       url,
+		Timeout:   defaultCheckTimeout,
 	}
-
-	if err := w.repo.CreateHealthCheck(ctx, check); err != nil {
-		return err
+	job.Host, _ = values["host"].(string)
+	if userID, ok := values["user_id"]; ok {
+		if job.UserID, err = toInt(userID); err != nil {
+			return CheckJob{}, errors.New("failed to parse user id")
+		}
 	}
-
-	if previousStatus != nil && previousStatus.Status != status {
-		event := StatusChangeEvent{
-			ServiceID: serviceID,
-			OldStatus: previousStatus.Status,
-			NewStatus: status,
-			Timestamp: time.Now().Local(),
+	if orgID, ok := values["org_id"]; ok {
+		if job.OrgID, err = toInt(orgID); err != nil {
+			return CheckJob{}, errors.New("failed to parse org id")
+		}
+	}
+	job.EncryptedAuth, _ = values["auth"].(string)
+	if timeout, ok := values["timeout"]; ok {
+		seconds, err := toInt(timeout)
+		if err != nil {
+			return CheckJob{}, errors.New("failed to parse timeout")
 		}
-		if err := w.eventBus.Publish(ctx, event); err != nil {
-			w.log.Error("failed to publish status change event", zap.Error(err))
-		} else {
-			w.log.Info("status change detected",
-				zap.Int("service_id", serviceID),
-				zap.String("old_status", previousStatus.Status),
-				zap.String("new_status", status),
-			)
+		if seconds > 0 {
+			job.Timeout = time.Duration(seconds) * time.Second
+		}
+	}
+	if port, ok := values["port"]; ok {
+		if job.Port, err = toInt(port); err != nil {
+			return CheckJob{}, errors.New("failed to parse port")
+		}
+	}
+	if job.FailureThreshold, err = optionalInt(values, "failure_threshold"); err != nil {
+		return CheckJob{}, errors.New("failed to parse failure threshold")
+	}
+	if job.SuccessThreshold, err = optionalInt(values, "success_threshold"); err != nil {
+		return CheckJob{}, errors.New("failed to parse success threshold")
+	}
+	if job.LatencyWarnMs, err = optionalInt(values, "latency_warn_ms"); err != nil {
+		return CheckJob{}, errors.New("failed to parse latency warning threshold")
+	}
+	if job.LatencyCriticalMs, err = optionalInt(values, "latency_critical_ms"); err != nil {
+		return CheckJob{}, errors.New("failed to parse latency critical threshold")
+	}
+	if job.Attempt, err = optionalInt(values, "attempt"); err != nil {
+		return CheckJob{}, errors.New("failed to parse attempt")
+	}
+	if recheck, ok := values["recheck"].(string); ok {
+		job.Recheck, _ = strconv.ParseBool(recheck)
+	}
+	if config, ok := values["config"].(string); ok && config != "" {
+		if err := json.Unmarshal([]byte(config), &job.Config); err != nil {
+			return CheckJob{}, fmt.Errorf("failed to parse config: %w", err)
 		}
 	}
 
-	return nil
+	return job, nil
+}
+
+// optionalInt parses values[key], which jobs enqueued by older schedulers lack.
+func optionalInt(values map[string]interface{}, key string) (int, error) {
+	v, ok := values[key]
+	if !ok {
+		return 0, nil
+	}
+	return toInt(v)
 }
 
 func toInt(v interface{}) (int, error) {
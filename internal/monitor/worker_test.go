@@ -30,7 +30,9 @@ func TestNewWorker(t *testing.T) {
 	assert.Equal(t, mockEventBus, worker.eventBus)
 	assert.Equal(t, HealthCheckStream, worker.stream)
 	assert.Equal(t, HealthCheckGroup, worker.group)
-	assert.NotNil(t, worker.httpClient)
+	checker, ok := worker.checkers.Get(CheckTypeHTTP)
+	assert.True(t, ok)
+	assert.IsType(t, &HTTPChecker{}, checker)
 }
 
 func TestProcessJob_Success_UP(t *testing.T) {
@@ -44,10 +46,10 @@ func TestProcessJob_Success_UP(t *testing.T) {
 	mockEventBus := new(MockEventBus)
 
 	worker := &Worker{
-		repo:       mockRepo,
-		eventBus:   mockEventBus,
-		log:        zap.NewNop(),
-		httpClient: &http.Client{Timeout: 5 * time.Second},
+		repo:     mockRepo,
+		eventBus: mockEventBus,
+		log:      zap.NewNop(),
+		checkers: DefaultCheckers(&http.Client{Timeout: 5 * time.Second}, mockRepo),
 	}
 
 	ctx := context.Background()
@@ -56,7 +58,8 @@ func TestProcessJob_Success_UP(t *testing.T) {
 		"url":        server.URL,
 	}
 
-	mockRepo.On("GetLatestHealthCheck", mock.Anything, 1).Return(nil, nil)
+	mockRepo.On("GetStatusState", mock.Anything, 1).Return(StatusState{}, nil)
+	mockRepo.On("SaveStatusState", mock.Anything, 1, mock.Anything).Return(nil)
 	mockRepo.On("CreateHealthCheck", mock.Anything, mock.MatchedBy(func(check HealthCheck) bool {
 		return check.ServiceID == 1 && check.Status == "UP" && check.Latency >= 0
 	})).Return(nil)
@@ -79,10 +82,10 @@ func TestProcessJob_Success_DOWN(t *testing.T) {
 	mockEventBus := new(MockEventBus)
 
 	worker := &Worker{
-		repo:       mockRepo,
-		eventBus:   mockEventBus,
-		log:        zap.NewNop(),
-		httpClient: &http.Client{Timeout: 5 * time.Second},
+		repo:     mockRepo,
+		eventBus: mockEventBus,
+		log:      zap.NewNop(),
+		checkers: DefaultCheckers(&http.Client{Timeout: 5 * time.Second}, mockRepo),
 	}
 
 	ctx := context.Background()
@@ -91,7 +94,8 @@ func TestProcessJob_Success_DOWN(t *testing.T) {
 		"url":        server.URL,
 	}
 
-	mockRepo.On("GetLatestHealthCheck", mock.Anything, 1).Return(nil, nil)
+	mockRepo.On("GetStatusState", mock.Anything, 1).Return(StatusState{}, nil)
+	mockRepo.On("SaveStatusState", mock.Anything, 1, mock.Anything).Return(nil)
 	mockRepo.On("CreateHealthCheck", mock.Anything, mock.MatchedBy(func(check HealthCheck) bool {
 		return check.ServiceID == 1 && check.Status == "DOWN"
 	})).Return(nil)
@@ -114,10 +118,10 @@ func TestProcessJob_HTTPTimeout(t *testing.T) {
 	mockEventBus := new(MockEventBus)
 
 	worker := &Worker{
-		repo:       mockRepo,
-		eventBus:   mockEventBus,
-		log:        zap.NewNop(),
-		httpClient: &http.Client{Timeout: 100 * time.Millisecond},
+		repo:     mockRepo,
+		eventBus: mockEventBus,
+		log:      zap.NewNop(),
+		checkers: DefaultCheckers(&http.Client{Timeout: 100 * time.Millisecond}, mockRepo),
 	}
 
 	ctx := context.Background()
@@ -126,7 +130,8 @@ func TestProcessJob_HTTPTimeout(t *testing.T) {
 		"url":        server.URL,
 	}
 
-	mockRepo.On("GetLatestHealthCheck", mock.Anything, 1).Return(nil, nil)
+	mockRepo.On("GetStatusState", mock.Anything, 1).Return(StatusState{}, nil)
+	mockRepo.On("SaveStatusState", mock.Anything, 1, mock.Anything).Return(nil)
 	mockRepo.On("CreateHealthCheck", mock.Anything, mock.MatchedBy(func(check HealthCheck) bool {
 		return check.ServiceID == 1 && check.Status == "DOWN" // Timeout should mark as DOWN
 	})).Return(nil)
@@ -148,10 +153,10 @@ func TestProcessJob_StatusChange_UPtoDOWN(t *testing.T) {
 	mockEventBus := new(MockEventBus)
 
 	worker := &Worker{
-		repo:       mockRepo,
-		eventBus:   mockEventBus,
-		log:        zap.NewNop(),
-		httpClient: &http.Client{Timeout: 5 * time.Second},
+		repo:     mockRepo,
+		eventBus: mockEventBus,
+		log:      zap.NewNop(),
+		checkers: DefaultCheckers(&http.Client{Timeout: 5 * time.Second}, mockRepo),
 	}
 
 	ctx := context.Background()
@@ -160,13 +165,10 @@ func TestProcessJob_StatusChange_UPtoDOWN(t *testing.T) {
 		"url":        server.URL,
 	}
 
-	previousCheck := &HealthCheck{
-		ServiceID: 1,
-		Status:    "UP",
-		CreatedAt: time.Now().Add(-1 * time.Minute),
-	}
+	previous := StatusState{Status: "UP"}
 
-	mockRepo.On("GetLatestHealthCheck", mock.Anything, 1).Return(previousCheck, nil)
+	mockRepo.On("GetStatusState", mock.Anything, 1).Return(previous, nil)
+	mockRepo.On("SaveStatusState", mock.Anything, 1, mock.Anything).Return(nil)
 	mockRepo.On("CreateHealthCheck", mock.Anything, mock.MatchedBy(func(check HealthCheck) bool {
 		return check.ServiceID == 1 && check.Status == "DOWN"
 	})).Return(nil)
@@ -192,10 +194,10 @@ func TestProcessJob_StatusChange_DOWNtoUP(t *testing.T) {
 	mockEventBus := new(MockEventBus)
 
 	worker := &Worker{
-		repo:       mockRepo,
-		eventBus:   mockEventBus,
-		log:        zap.NewNop(),
-		httpClient: &http.Client{Timeout: 5 * time.Second},
+		repo:     mockRepo,
+		eventBus: mockEventBus,
+		log:      zap.NewNop(),
+		checkers: DefaultCheckers(&http.Client{Timeout: 5 * time.Second}, mockRepo),
 	}
 
 	ctx := context.Background()
@@ -204,13 +206,10 @@ func TestProcessJob_StatusChange_DOWNtoUP(t *testing.T) {
 		"url":        server.URL,
 	}
 
-	previousCheck := &HealthCheck{
-		ServiceID: 1,
-		Status:    "DOWN",
-		CreatedAt: time.Now().Add(-1 * time.Minute),
-	}
+	previous := StatusState{Status: "DOWN"}
 
-	mockRepo.On("GetLatestHealthCheck", mock.Anything, 1).Return(previousCheck, nil)
+	mockRepo.On("GetStatusState", mock.Anything, 1).Return(previous, nil)
+	mockRepo.On("SaveStatusState", mock.Anything, 1, mock.Anything).Return(nil)
 	mockRepo.On("CreateHealthCheck", mock.Anything, mock.MatchedBy(func(check HealthCheck) bool {
 		return check.ServiceID == 1 && check.Status == "UP"
 	})).Return(nil)
@@ -236,10 +235,10 @@ func TestProcessJob_NoStatusChange(t *testing.T) {
 	mockEventBus := new(MockEventBus)
 
 	worker := &Worker{
-		repo:       mockRepo,
-		eventBus:   mockEventBus,
-		log:        zap.NewNop(),
-		httpClient: &http.Client{Timeout: 5 * time.Second},
+		repo:     mockRepo,
+		eventBus: mockEventBus,
+		log:      zap.NewNop(),
+		checkers: DefaultCheckers(&http.Client{Timeout: 5 * time.Second}, mockRepo),
 	}
 
 	ctx := context.Background()
@@ -248,13 +247,10 @@ func TestProcessJob_NoStatusChange(t *testing.T) {
 		"url":        server.URL,
 	}
 
-	previousCheck := &HealthCheck{
-		ServiceID: 1,
-		Status:    "UP", // Same status
-		CreatedAt: time.Now().Add(-1 * time.Minute),
-	}
+	previous := StatusState{Status: "UP"}
 
-	mockRepo.On("GetLatestHealthCheck", mock.Anything, 1).Return(previousCheck, nil)
+	mockRepo.On("GetStatusState", mock.Anything, 1).Return(previous, nil)
+	mockRepo.On("SaveStatusState", mock.Anything, 1, mock.Anything).Return(nil)
 	mockRepo.On("CreateHealthCheck", mock.Anything, mock.MatchedBy(func(check HealthCheck) bool {
 		return check.ServiceID == 1 && check.Status == "UP"
 	})).Return(nil)
@@ -271,10 +267,10 @@ func TestProcessJob_InvalidServiceID(t *testing.T) {
 	mockEventBus := new(MockEventBus)
 
 	worker := &Worker{
-		repo:       mockRepo,
-		eventBus:   mockEventBus,
-		log:        zap.NewNop(),
-		httpClient: &http.Client{},
+		repo:     mockRepo,
+		eventBus: mockEventBus,
+		log:      zap.NewNop(),
+		checkers: DefaultCheckers(&http.Client{}, mockRepo),
 	}
 
 	ctx := context.Background()
@@ -286,7 +282,7 @@ func TestProcessJob_InvalidServiceID(t *testing.T) {
 	err := worker.processJob(ctx, service)
 
 	assert.Error(t, err)
-	mockRepo.AssertNotCalled(t, "GetLatestHealthCheck")
+	mockRepo.AssertNotCalled(t, "GetStatusState")
 	mockRepo.AssertNotCalled(t, "CreateHealthCheck")
 }
 
@@ -295,10 +291,10 @@ func TestProcessJob_InvalidURL(t *testing.T) {
 	mockEventBus := new(MockEventBus)
 
 	worker := &Worker{
-		repo:       mockRepo,
-		eventBus:   mockEventBus,
-		log:        zap.NewNop(),
-		httpClient: &http.Client{},
+		repo:     mockRepo,
+		eventBus: mockEventBus,
+		log:      zap.NewNop(),
+		checkers: DefaultCheckers(&http.Client{}, mockRepo),
 	}
 
 	ctx := context.Background()
@@ -310,7 +306,7 @@ func TestProcessJob_InvalidURL(t *testing.T) {
 	err := worker.processJob(ctx, service)
 
 	assert.Error(t, err)
-	mockRepo.AssertNotCalled(t, "GetLatestHealthCheck")
+	mockRepo.AssertNotCalled(t, "GetStatusState")
 	mockRepo.AssertNotCalled(t, "CreateHealthCheck")
 }
 
@@ -324,10 +320,10 @@ func TestProcessJob_CreateHealthCheckError(t *testing.T) {
 	mockEventBus := new(MockEventBus)
 
 	worker := &Worker{
-		repo:       mockRepo,
-		eventBus:   mockEventBus,
-		log:        zap.NewNop(),
-		httpClient: &http.Client{Timeout: 5 * time.Second},
+		repo:     mockRepo,
+		eventBus: mockEventBus,
+		log:      zap.NewNop(),
+		checkers: DefaultCheckers(&http.Client{Timeout: 5 * time.Second}, mockRepo),
 	}
 
 	ctx := context.Background()
@@ -336,7 +332,7 @@ func TestProcessJob_CreateHealthCheckError(t *testing.T) {
 		"url":        server.URL,
 	}
 
-	mockRepo.On("GetLatestHealthCheck", mock.Anything, 1).Return(nil, nil)
+	mockRepo.On("GetStatusState", mock.Anything, 1).Return(StatusState{}, nil)
 	mockRepo.On("CreateHealthCheck", mock.Anything, mock.Anything).
 		Return(errors.New("database error"))
 
@@ -345,6 +341,244 @@ func TestProcessJob_CreateHealthCheckError(t *testing.T) {
 	assert.Error(t, err)
 	assert.Equal(t, "database error", err.Error())
 	mockRepo.AssertExpectations(t)
+	mockRepo.AssertNotCalled(t, "SaveStatusState")
+}
+
+func TestProcessJob_DispatchesByType(t *testing.T) {
+	mockRepo := new(MockRepository)
+	mockEventBus := new(MockEventBus)
+
+	checker := &stubChecker{result: CheckResult{
+		Status:  StatusUp,
+		Latency: 12 * time.Millisecond,
+		Details: map[string]interface{}{"probe": "stub"},
+	}}
+	checkers := NewCheckerRegistry()
+	checkers.Register("stub", checker)
+
+	worker := &Worker{
+		repo:     mockRepo,
+		eventBus: mockEventBus,
+		log:      zap.NewNop(),
+		checkers: checkers,
+	}
+
+	service := map[string]interface{}{
+		"service_id": "1",
+		"type":       "stub",
+		"url":        "stub://target",
+	}
+
+	mockRepo.On("GetStatusState", mock.Anything, 1).Return(StatusState{}, nil)
+	mockRepo.On("SaveStatusState", mock.Anything, 1, mock.Anything).Return(nil)
+	mockRepo.On("CreateHealthCheck", mock.Anything, mock.MatchedBy(func(check HealthCheck) bool {
+		return check.ServiceID == 1 && check.Status == StatusUp && check.Latency == 12 && check.Details["probe"] == "stub"
+	})).Return(nil)
+
+	err := worker.processJob(context.Background(), service)
+
+	assert.NoError(t, err)
+	assert.Len(t, checker.jobs, 1)
+	assert.Equal(t, CheckJob{ServiceID: 1, Type: "stub", URL: "stub://target", Timeout: defaultCheckTimeout}, checker.jobs[0])
+	mockRepo.AssertExpectations(t)
+}
+
+func TestProcessJob_UnsupportedType(t *testing.T) {
+	mockRepo := new(MockRepository)
+	mockEventBus := new(MockEventBus)
+
+	worker := &Worker{
+		repo:     mockRepo,
+		eventBus: mockEventBus,
+		log:      zap.NewNop(),
+		checkers: NewCheckerRegistry(),
+	}
+
+	service := map[string]interface{}{
+		"service_id": "1",
+		"type":       "carrier-pigeon",
+		"url":        "http://example.com",
+	}
+
+	mockRepo.On("GetStatusState", mock.Anything, 1).Return(StatusState{}, nil)
+	mockRepo.On("SaveStatusState", mock.Anything, 1, mock.Anything).Return(nil)
+	mockRepo.On("CreateHealthCheck", mock.Anything, mock.MatchedBy(func(check HealthCheck) bool {
+		return check.ServiceID == 1 && check.Status == StatusDown && check.ErrorClass == ErrorClassOther &&
+			check.Message == `unsupported check type "carrier-pigeon"`
+	})).Return(nil)
+	mockEventBus.On("Publish", mock.Anything, mock.Anything).Return(nil).Maybe()
+
+	// The job is not left pending: it is recorded and can be acked.
+	err := worker.processJob(context.Background(), service)
+
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestParseCheckJob_DefaultsToHTTP(t *testing.T) {
+	job, err := parseCheckJob(map[string]interface{}{
+		"service_id": "7",
+		"url":        "http://example.com",
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, CheckJob{ServiceID: 7, Type: CheckTypeHTTP, URL: "http://example.com", Timeout: defaultCheckTimeout}, job)
+}
+
+func TestParseCheckJob_TimeoutAndAuth(t *testing.T) {
+	job, err := parseCheckJob(map[string]interface{}{
+		"service_id": "7",
+		"url":        "http://example.com",
+		"timeout":    "12",
+		"auth":       "ciphertext",
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 12*time.Second, job.Timeout)
+	assert.Equal(t, "ciphertext", job.EncryptedAuth)
+
+	_, err = parseCheckJob(map[string]interface{}{
+		"service_id": "7",
+		"url":        "http://example.com",
+		"timeout":    "soon",
+	})
+	assert.Error(t, err)
+}
+
+func TestParseCheckJob_Confirmation(t *testing.T) {
+	job, err := parseCheckJob(map[string]interface{}{
+		"service_id":        "7",
+		"url":               "http://example.com",
+		"failure_threshold": "3",
+		"success_threshold": "2",
+		"recheck":           "1",
+		"attempt":           "1",
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 3, job.FailureThreshold)
+	assert.Equal(t, 2, job.SuccessThreshold)
+	assert.True(t, job.Recheck)
+	assert.Equal(t, 1, job.Attempt)
+
+	_, err = parseCheckJob(map[string]interface{}{
+		"service_id":        "7",
+		"url":               "http://example.com",
+		"failure_threshold": "many",
+	})
+	assert.Error(t, err)
+}
+
+func TestCheckJob_Threshold(t *testing.T) {
+	assert.Equal(t, 1, CheckJob{}.threshold(StatusDown))
+	assert.Equal(t, 3, CheckJob{FailureThreshold: 3, SuccessThreshold: 1}.threshold(StatusDown))
+	assert.Equal(t, 1, CheckJob{FailureThreshold: 3, SuccessThreshold: 1}.threshold(StatusUp))
+	assert.Equal(t, 3, CheckJob{FailureThreshold: 3, SuccessThreshold: 1}.threshold(StatusDegraded))
+	assert.Equal(t, 2, CheckJob{FailureThreshold: 1, Recheck: true}.threshold(StatusDown))
+	assert.Equal(t, 4, CheckJob{FailureThreshold: 4, Recheck: true}.threshold(StatusDown))
+}
+
+func TestParseCheckJob_Latency(t *testing.T) {
+	job, err := parseCheckJob(map[string]interface{}{
+		"service_id":          "7",
+		"url":                 "http://example.com",
+		"latency_warn_ms":     "2000",
+		"latency_critical_ms": "8000",
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2000, job.LatencyWarnMs)
+	assert.Equal(t, 8000, job.LatencyCriticalMs)
+
+	_, err = parseCheckJob(map[string]interface{}{
+		"service_id":      "7",
+		"url":             "http://example.com",
+		"latency_warn_ms": "slow",
+	})
+	assert.Error(t, err)
+}
+
+func TestCheckJob_ApplyLatency(t *testing.T) {
+	job := CheckJob{LatencyWarnMs: 2000, LatencyCriticalMs: 8000}
+	tests := []struct {
+		name    string
+		job     CheckJob
+		result  CheckResult
+		want    Status
+		message string
+	}{
+		{"fast is UP", job, CheckResult{Status: StatusUp, Latency: 300 * time.Millisecond}, StatusUp, ""},
+		{"over warning is DEGRADED", job, CheckResult{Status: StatusUp, Latency: 2 * time.Second}, StatusDegraded,
+			"responded in 2000ms, over the warning latency of 2000ms"},
+		{"over critical is DOWN", job, CheckResult{Status: StatusUp, Latency: 9 * time.Second}, StatusDown,
+			"responded in 9000ms, over the critical latency of 8000ms"},
+		{"degraded over critical is DOWN", job, CheckResult{Status: StatusDegraded, Latency: 9 * time.Second}, StatusDown,
+			"responded in 9000ms, over the critical latency of 8000ms"},
+		{"degraded over warning keeps its message", job, CheckResult{Status: StatusDegraded, Latency: 3 * time.Second, Message: "certificate expires in 5 days"},
+			StatusDegraded, "certificate expires in 5 days"},
+		{"down keeps its message", job, CheckResult{Status: StatusDown, Latency: 9 * time.Second, Message: "unexpected status code 503"},
+			StatusDown, "unexpected status code 503"},
+		{"disabled thresholds", CheckJob{}, CheckResult{Status: StatusUp, Latency: time.Minute}, StatusUp, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := tt.job.applyLatency(tt.result)
+			assert.Equal(t, tt.want, result.Status)
+			assert.Equal(t, tt.message, result.Message)
+			assert.Equal(t, tt.result.Latency, result.Latency)
+		})
+	}
+}
+
+func TestParseCheckJob_Owner(t *testing.T) {
+	job, err := parseCheckJob(map[string]interface{}{
+		"service_id": "7",
+		"user_id":    "42",
+		"org_id":     "7",
+		"url":        "http://example.com",
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 42, job.UserID)
+	assert.Equal(t, 7, job.OrgID)
+
+	_, err = parseCheckJob(map[string]interface{}{
+		"service_id": "7",
+		"user_id":    "someone",
+		"url":        "http://example.com",
+	})
+	assert.Error(t, err)
+}
+
+func TestParseCheckJob_TCPTarget(t *testing.T) {
+	job, err := parseCheckJob(map[string]interface{}{
+		"service_id": "3",
+		"type":       CheckTypeTCP,
+		"url":        "",
+		"host":       "db.internal",
+		"port":       "5432",
+		"config":     `{"tcp":{"send":"PING\r\n","expect":"+PONG"}}`,
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "db.internal", job.Host)
+	assert.Equal(t, 5432, job.Port)
+	if assert.NotNil(t, job.Config.TCP) {
+		assert.Equal(t, "PING\r\n", job.Config.TCP.Send)
+		assert.Equal(t, "+PONG", job.Config.TCP.Expect)
+	}
+}
+
+func TestParseCheckJob_InvalidConfig(t *testing.T) {
+	_, err := parseCheckJob(map[string]interface{}{
+		"service_id": "3",
+		"type":       CheckTypeTCP,
+		"url":        "",
+		"config":     "{not json",
+	})
+
+	assert.Error(t, err)
 }
 
 func TestToInt(t *testing.T) {
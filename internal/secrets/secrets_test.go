@@ -0,0 +1,59 @@
+package secrets
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncryptDecrypt(t *testing.T) {
+	os.Setenv("ENCRYPTION_KEY", "test-key")
+	defer os.Unsetenv("ENCRYPTION_KEY")
+
+	encrypted, err := Encrypt([]byte("s3cret"))
+	require.NoError(t, err)
+	assert.NotContains(t, encrypted, "s3cret")
+
+	again, err := Encrypt([]byte("s3cret"))
+	require.NoError(t, err)
+	assert.NotEqual(t, encrypted, again, "nonce should make every ciphertext unique")
+
+	decrypted, err := Decrypt(encrypted)
+	require.NoError(t, err)
+	assert.Equal(t, "s3cret", string(decrypted))
+}
+
+func TestDecrypt_WrongKey(t *testing.T) {
+	os.Setenv("ENCRYPTION_KEY", "first-key")
+	encrypted, err := Encrypt([]byte("s3cret"))
+	require.NoError(t, err)
+
+	os.Setenv("ENCRYPTION_KEY", "second-key")
+	defer os.Unsetenv("ENCRYPTION_KEY")
+
+	_, err = Decrypt(encrypted)
+	assert.Error(t, err)
+}
+
+func TestDecrypt_Malformed(t *testing.T) {
+	os.Setenv("ENCRYPTION_KEY", "test-key")
+	defer os.Unsetenv("ENCRYPTION_KEY")
+
+	_, err := Decrypt("not base64!")
+	assert.Error(t, err)
+
+	_, err = Decrypt("c2hvcnQ=")
+	assert.Error(t, err)
+}
+
+func TestNoKey(t *testing.T) {
+	os.Unsetenv("ENCRYPTION_KEY")
+
+	_, err := Encrypt([]byte("s3cret"))
+	assert.ErrorIs(t, err, ErrNoKey)
+
+	_, err = Decrypt("anything")
+	assert.ErrorIs(t, err, ErrNoKey)
+}